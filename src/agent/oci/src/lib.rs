@@ -190,6 +190,12 @@ pub struct Hooks {
     pub poststart: Vec<Hook>,
     #[serde(default, skip_serializing_if = "Vec::is_empty")]
     pub poststop: Vec<Hook>,
+    #[serde(
+        default,
+        rename = "startContainer",
+        skip_serializing_if = "Vec::is_empty"
+    )]
+    pub start_container: Vec<Hook>,
 }
 
 #[derive(Serialize, Deserialize, Debug, Default, Clone, PartialEq)]
@@ -1006,6 +1012,11 @@ mod tests {
                             "-f"
                         ]
                     }
+                ],
+                "startContainer": [
+                    {
+                        "path": "/usr/bin/refresh-ldcache"
+                    }
                 ]
             },
             "linux": {
@@ -1381,6 +1392,12 @@ mod tests {
                     env: vec![],
                     timeout: None,
                 }],
+                start_container: vec![crate::Hook {
+                    path: "/usr/bin/refresh-ldcache".to_string(),
+                    args: vec![],
+                    env: vec![],
+                    timeout: None,
+                }],
             }),
             annotations: [
                 ("com.example.key1".to_string(), "value1".to_string()),
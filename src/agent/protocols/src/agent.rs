@@ -8019,6 +8019,7 @@ pub struct CreateSandboxRequest {
     pub sandbox_id: ::std::string::String,
     pub guest_hook_path: ::std::string::String,
     pub kernel_modules: ::protobuf::RepeatedField<KernelModule>,
+    pub sysctls: ::std::collections::HashMap<::std::string::String, ::std::string::String>,
     // special fields
     pub unknown_fields: ::protobuf::UnknownFields,
     pub cached_size: ::protobuf::CachedSize,
@@ -8202,6 +8203,31 @@ impl CreateSandboxRequest {
     pub fn take_kernel_modules(&mut self) -> ::protobuf::RepeatedField<KernelModule> {
         ::std::mem::replace(&mut self.kernel_modules, ::protobuf::RepeatedField::new())
     }
+
+    // repeated .grpc.CreateSandboxRequest.SysctlsEntry sysctls = 8;
+
+
+    pub fn get_sysctls(&self) -> &::std::collections::HashMap<::std::string::String, ::std::string::String> {
+        &self.sysctls
+    }
+    pub fn clear_sysctls(&mut self) {
+        self.sysctls.clear();
+    }
+
+    // Param is passed by value, moved
+    pub fn set_sysctls(&mut self, v: ::std::collections::HashMap<::std::string::String, ::std::string::String>) {
+        self.sysctls = v;
+    }
+
+    // Mutable pointer to the field.
+    pub fn mut_sysctls(&mut self) -> &mut ::std::collections::HashMap<::std::string::String, ::std::string::String> {
+        &mut self.sysctls
+    }
+
+    // Take field
+    pub fn take_sysctls(&mut self) -> ::std::collections::HashMap<::std::string::String, ::std::string::String> {
+        ::std::mem::replace(&mut self.sysctls, ::std::collections::HashMap::new())
+    }
 }
 
 impl ::protobuf::Message for CreateSandboxRequest {
@@ -8248,6 +8274,9 @@ impl ::protobuf::Message for CreateSandboxRequest {
                 7 => {
                     ::protobuf::rt::read_repeated_message_into(wire_type, is, &mut self.kernel_modules)?;
                 },
+                8 => {
+                    ::protobuf::rt::read_map_into::<::protobuf::types::ProtobufTypeString, ::protobuf::types::ProtobufTypeString>(wire_type, is, &mut self.sysctls)?;
+                },
                 _ => {
                     ::protobuf::rt::read_unknown_or_skip_group(field_number, wire_type, is, self.mut_unknown_fields())?;
                 },
@@ -8283,6 +8312,7 @@ impl ::protobuf::Message for CreateSandboxRequest {
             let len = value.compute_size();
             my_size += 1 + ::protobuf::rt::compute_raw_varint32_size(len) + len;
         };
+        my_size += ::protobuf::rt::compute_map_size::<::protobuf::types::ProtobufTypeString, ::protobuf::types::ProtobufTypeString>(8, &self.sysctls);
         my_size += ::protobuf::rt::unknown_fields_size(self.get_unknown_fields());
         self.cached_size.set(my_size);
         my_size
@@ -8314,6 +8344,7 @@ impl ::protobuf::Message for CreateSandboxRequest {
             os.write_raw_varint32(v.get_cached_size())?;
             v.write_to_with_cached_sizes(os)?;
         };
+        ::protobuf::rt::write_map_with_cached_sizes::<::protobuf::types::ProtobufTypeString, ::protobuf::types::ProtobufTypeString>(8, &self.sysctls, os)?;
         os.write_unknown_fields(self.get_unknown_fields())?;
         ::std::result::Result::Ok(())
     }
@@ -8388,6 +8419,11 @@ impl ::protobuf::Message for CreateSandboxRequest {
                     |m: &CreateSandboxRequest| { &m.kernel_modules },
                     |m: &mut CreateSandboxRequest| { &mut m.kernel_modules },
                 ));
+                fields.push(::protobuf::reflect::accessor::make_map_accessor::<_, ::protobuf::types::ProtobufTypeString, ::protobuf::types::ProtobufTypeString>(
+                    "sysctls",
+                    |m: &CreateSandboxRequest| { &m.sysctls },
+                    |m: &mut CreateSandboxRequest| { &mut m.sysctls },
+                ));
                 ::protobuf::reflect::MessageDescriptor::new_pb_name::<CreateSandboxRequest>(
                     "CreateSandboxRequest",
                     fields,
@@ -8414,6 +8450,7 @@ impl ::protobuf::Clear for CreateSandboxRequest {
         self.sandbox_id.clear();
         self.guest_hook_path.clear();
         self.kernel_modules.clear();
+        self.sysctls.clear();
         self.unknown_fields.clear();
     }
 }
@@ -13322,24 +13359,27 @@ static file_descriptor_proto_data: &'static [u8] = b"\
     \x06execId\x12\x10\n\x03row\x18\x03\x20\x01(\rR\x03row\x12\x16\n\x06colu\
     mn\x18\x04\x20\x01(\rR\x06column\"B\n\x0cKernelModule\x12\x12\n\x04name\
     \x18\x01\x20\x01(\tR\x04name\x12\x1e\n\nparameters\x18\x02\x20\x03(\tR\n\
-    parameters\"\x96\x02\n\x14CreateSandboxRequest\x12\x1a\n\x08hostname\x18\
+    parameters\"\x95\x03\n\x14CreateSandboxRequest\x12\x1a\n\x08hostname\x18\
     \x01\x20\x01(\tR\x08hostname\x12\x10\n\x03dns\x18\x02\x20\x03(\tR\x03dns\
     \x12)\n\x08storages\x18\x03\x20\x03(\x0b2\r.grpc.StorageR\x08storages\
     \x12#\n\rsandbox_pidns\x18\x04\x20\x01(\x08R\x0csandboxPidns\x12\x1d\n\n\
     sandbox_id\x18\x05\x20\x01(\tR\tsandboxId\x12&\n\x0fguest_hook_path\x18\
     \x06\x20\x01(\tR\rguestHookPath\x129\n\x0ekernel_modules\x18\x07\x20\x03\
-    (\x0b2\x12.grpc.KernelModuleR\rkernelModules\"\x17\n\x15DestroySandboxRe\
-    quest\">\n\nInterfaces\x120\n\nInterfaces\x18\x01\x20\x03(\x0b2\x10.type\
-    s.InterfaceR\nInterfaces\".\n\x06Routes\x12$\n\x06Routes\x18\x01\x20\x03\
-    (\x0b2\x0c.types.RouteR\x06Routes\"H\n\x16UpdateInterfaceRequest\x12.\n\
-    \tinterface\x18\x01\x20\x01(\x0b2\x10.types.InterfaceR\tinterface\";\n\
-    \x13UpdateRoutesRequest\x12$\n\x06routes\x18\x01\x20\x01(\x0b2\x0c.grpc.\
-    RoutesR\x06routes\"\x17\n\x15ListInterfacesRequest\"\x13\n\x11ListRoutes\
-    Request\"F\n\x0cARPNeighbors\x126\n\x0cARPNeighbors\x18\x01\x20\x03(\x0b\
-    2\x12.types.ARPNeighborR\x0cARPNeighbors\"J\n\x16AddARPNeighborsRequest\
-    \x120\n\tneighbors\x18\x01\x20\x01(\x0b2\x12.grpc.ARPNeighborsR\tneighbo\
-    rs\"]\n\x13OnlineCPUMemRequest\x12\x12\n\x04wait\x18\x01\x20\x01(\x08R\
-    \x04wait\x12\x17\n\x07nb_cpus\x18\x02\x20\x01(\rR\x06nbCpus\x12\x19\n\
+    (\x0b2\x12.grpc.KernelModuleR\rkernelModules\x12A\n\x07sysctls\x18\x08\
+    \x20\x03(\x0b2'.grpc.CreateSandboxRequest.SysctlsEntryR\x07sysctls\x1a:\
+    \n\x0cSysctlsEntry\x12\x10\n\x03key\x18\x01\x20\x01(\tR\x03key\x12\x14\n\
+    \x05value\x18\x02\x20\x01(\tR\x05value:\x028\x01\"\x17\n\x15DestroySandb\
+    oxRequest\">\n\nInterfaces\x120\n\nInterfaces\x18\x01\x20\x03(\x0b2\x10.\
+    types.InterfaceR\nInterfaces\".\n\x06Routes\x12$\n\x06Routes\x18\x01\x20\
+    \x03(\x0b2\x0c.types.RouteR\x06Routes\"H\n\x16UpdateInterfaceRequest\x12\
+    .\n\tinterface\x18\x01\x20\x01(\x0b2\x10.types.InterfaceR\tinterface\";\
+    \n\x13UpdateRoutesRequest\x12$\n\x06routes\x18\x01\x20\x01(\x0b2\x0c.grp\
+    c.RoutesR\x06routes\"\x17\n\x15ListInterfacesRequest\"\x13\n\x11ListRout\
+    esRequest\"F\n\x0cARPNeighbors\x126\n\x0cARPNeighbors\x18\x01\x20\x03(\
+    \x0b2\x12.types.ARPNeighborR\x0cARPNeighbors\"J\n\x16AddARPNeighborsRequ\
+    est\x120\n\tneighbors\x18\x01\x20\x01(\x0b2\x12.grpc.ARPNeighborsR\tneig\
+    hbors\"]\n\x13OnlineCPUMemRequest\x12\x12\n\x04wait\x18\x01\x20\x01(\x08\
+    R\x04wait\x12\x17\n\x07nb_cpus\x18\x02\x20\x01(\rR\x06nbCpus\x12\x19\n\
     \x08cpu_only\x18\x03\x20\x01(\x08R\x07cpuOnly\",\n\x16ReseedRandomDevReq\
     uest\x12\x12\n\x04data\x18\x02\x20\x01(\x0cR\x04data\"\xc8\x01\n\x0cAgen\
     tDetails\x12\x18\n\x07version\x18\x01\x20\x01(\tR\x07version\x12\x1f\n\
@@ -13415,8 +13455,8 @@ static file_descriptor_proto_data: &'static [u8] = b"\
     \x16.google.protobuf.Empty\x129\n\x08CopyFile\x12\x15.grpc.CopyFileReque\
     st\x1a\x16.google.protobuf.Empty\x127\n\x0bGetOOMEvent\x12\x18.grpc.GetO\
     OMEventRequest\x1a\x0e.grpc.OOMEventB`Z^github.com/kata-containers/kata-\
-    containers/src/runtime/virtcontainers/pkg/agent/protocols/grpcJ\xdb\xac\
-    \x01\n\x07\x12\x05\x07\0\x83\x04\x01\nm\n\x01\x0c\x12\x03\x07\0\x122c\n\
+    containers/src/runtime/virtcontainers/pkg/agent/protocols/grpcJ\xa6\xae\
+    \x01\n\x07\x12\x05\x07\0\x86\x04\x01\nm\n\x01\x0c\x12\x03\x07\0\x122c\n\
     \x20Copyright\x202017\x20HyperHQ\x20Inc.\n\x20Copyright\x202019\x20Ant\
     \x20Financial\n\n\x20SPDX-License-Identifier:\x20Apache-2.0\n\n\n\x08\n\
     \x01\x08\x12\x03\t\0u\n\t\n\x02\x08\x0b\x12\x03\t\0u\n\x08\n\x01\x02\x12\
@@ -13937,7 +13977,7 @@ static file_descriptor_proto_data: &'static [u8] = b"\
     \n\x05\x04\x1f\x02\x01\x04\x12\x04\x9b\x02\x08\x10\n\r\n\x05\x04\x1f\x02\
     \x01\x05\x12\x04\x9b\x02\x11\x17\n\r\n\x05\x04\x1f\x02\x01\x01\x12\x04\
     \x9b\x02\x18\"\n\r\n\x05\x04\x1f\x02\x01\x03\x12\x04\x9b\x02%&\n\x0c\n\
-    \x02\x04\x20\x12\x06\x9e\x02\0\xb1\x02\x01\n\x0b\n\x03\x04\x20\x01\x12\
+    \x02\x04\x20\x12\x06\x9e\x02\0\xb4\x02\x01\n\x0b\n\x03\x04\x20\x01\x12\
     \x04\x9e\x02\x08\x1c\n\x0c\n\x04\x04\x20\x02\0\x12\x04\x9f\x02\x08\x1c\n\
     \x0f\n\x05\x04\x20\x02\0\x04\x12\x06\x9f\x02\x08\x9e\x02\x1e\n\r\n\x05\
     \x04\x20\x02\0\x05\x12\x04\x9f\x02\x08\x0e\n\r\n\x05\x04\x20\x02\0\x01\
@@ -13978,311 +14018,318 @@ static file_descriptor_proto_data: &'static [u8] = b"\
     \x20be\x20loaded\x20in\x20the\x20guest\x20kernel.\n\n\r\n\x05\x04\x20\
     \x02\x06\x04\x12\x04\xb0\x02\x08\x10\n\r\n\x05\x04\x20\x02\x06\x06\x12\
     \x04\xb0\x02\x11\x1d\n\r\n\x05\x04\x20\x02\x06\x01\x12\x04\xb0\x02\x1e,\
-    \n\r\n\x05\x04\x20\x02\x06\x03\x12\x04\xb0\x02/0\n\x0c\n\x02\x04!\x12\
-    \x06\xb3\x02\0\xb4\x02\x01\n\x0b\n\x03\x04!\x01\x12\x04\xb3\x02\x08\x1d\
-    \n\x0c\n\x02\x04\"\x12\x06\xb6\x02\0\xb8\x02\x01\n\x0b\n\x03\x04\"\x01\
-    \x12\x04\xb6\x02\x08\x12\n\x0c\n\x04\x04\"\x02\0\x12\x04\xb7\x02\x080\n\
-    \r\n\x05\x04\"\x02\0\x04\x12\x04\xb7\x02\x08\x10\n\r\n\x05\x04\"\x02\0\
-    \x06\x12\x04\xb7\x02\x11\x20\n\r\n\x05\x04\"\x02\0\x01\x12\x04\xb7\x02!+\
-    \n\r\n\x05\x04\"\x02\0\x03\x12\x04\xb7\x02./\n\x0c\n\x02\x04#\x12\x06\
-    \xba\x02\0\xbc\x02\x01\n\x0b\n\x03\x04#\x01\x12\x04\xba\x02\x08\x0e\n\
-    \x0c\n\x04\x04#\x02\0\x12\x04\xbb\x02\x08(\n\r\n\x05\x04#\x02\0\x04\x12\
-    \x04\xbb\x02\x08\x10\n\r\n\x05\x04#\x02\0\x06\x12\x04\xbb\x02\x11\x1c\n\
-    \r\n\x05\x04#\x02\0\x01\x12\x04\xbb\x02\x1d#\n\r\n\x05\x04#\x02\0\x03\
-    \x12\x04\xbb\x02&'\n\x0c\n\x02\x04$\x12\x06\xbe\x02\0\xc0\x02\x01\n\x0b\
-    \n\x03\x04$\x01\x12\x04\xbe\x02\x08\x1e\n\x0c\n\x04\x04$\x02\0\x12\x04\
-    \xbf\x02\x08&\n\x0f\n\x05\x04$\x02\0\x04\x12\x06\xbf\x02\x08\xbe\x02\x20\
-    \n\r\n\x05\x04$\x02\0\x06\x12\x04\xbf\x02\x08\x17\n\r\n\x05\x04$\x02\0\
-    \x01\x12\x04\xbf\x02\x18!\n\r\n\x05\x04$\x02\0\x03\x12\x04\xbf\x02$%\n\
-    \x0c\n\x02\x04%\x12\x06\xc2\x02\0\xc4\x02\x01\n\x0b\n\x03\x04%\x01\x12\
-    \x04\xc2\x02\x08\x1b\n\x0c\n\x04\x04%\x02\0\x12\x04\xc3\x02\x08\x1a\n\
-    \x0f\n\x05\x04%\x02\0\x04\x12\x06\xc3\x02\x08\xc2\x02\x1d\n\r\n\x05\x04%\
-    \x02\0\x06\x12\x04\xc3\x02\x08\x0e\n\r\n\x05\x04%\x02\0\x01\x12\x04\xc3\
-    \x02\x0f\x15\n\r\n\x05\x04%\x02\0\x03\x12\x04\xc3\x02\x18\x19\n\x0c\n\
-    \x02\x04&\x12\x06\xc6\x02\0\xc7\x02\x01\n\x0b\n\x03\x04&\x01\x12\x04\xc6\
-    \x02\x08\x1d\n\x0c\n\x02\x04'\x12\x06\xc9\x02\0\xca\x02\x01\n\x0b\n\x03\
-    \x04'\x01\x12\x04\xc9\x02\x08\x19\n\x0c\n\x02\x04(\x12\x06\xcc\x02\0\xce\
-    \x02\x01\n\x0b\n\x03\x04(\x01\x12\x04\xcc\x02\x08\x14\n\x0c\n\x04\x04(\
-    \x02\0\x12\x04\xcd\x02\x073\n\r\n\x05\x04(\x02\0\x04\x12\x04\xcd\x02\x07\
-    \x0f\n\r\n\x05\x04(\x02\0\x06\x12\x04\xcd\x02\x10!\n\r\n\x05\x04(\x02\0\
-    \x01\x12\x04\xcd\x02\".\n\r\n\x05\x04(\x02\0\x03\x12\x04\xcd\x0212\n\x0c\
-    \n\x02\x04)\x12\x06\xd0\x02\0\xd2\x02\x01\n\x0b\n\x03\x04)\x01\x12\x04\
-    \xd0\x02\x08\x1e\n\x0c\n\x04\x04)\x02\0\x12\x04\xd1\x02\x07\"\n\x0f\n\
-    \x05\x04)\x02\0\x04\x12\x06\xd1\x02\x07\xd0\x02\x20\n\r\n\x05\x04)\x02\0\
-    \x06\x12\x04\xd1\x02\x07\x13\n\r\n\x05\x04)\x02\0\x01\x12\x04\xd1\x02\
-    \x14\x1d\n\r\n\x05\x04)\x02\0\x03\x12\x04\xd1\x02\x20!\n\x0c\n\x02\x04*\
-    \x12\x06\xd4\x02\0\xdf\x02\x01\n\x0b\n\x03\x04*\x01\x12\x04\xd4\x02\x08\
-    \x1b\n\xf6\x01\n\x04\x04*\x02\0\x12\x04\xd8\x02\x08\x16\x1a\xe7\x01\x20W\
-    ait\x20specifies\x20if\x20the\x20caller\x20waits\x20for\x20the\x20agent\
-    \x20to\x20online\x20all\x20resources.\n\x20If\x20true\x20the\x20agent\
-    \x20returns\x20once\x20all\x20resources\x20have\x20been\x20connected,\
-    \x20otherwise\x20all\n\x20resources\x20are\x20connected\x20asynchronousl\
-    y\x20and\x20the\x20agent\x20returns\x20immediately.\n\n\x0f\n\x05\x04*\
-    \x02\0\x04\x12\x06\xd8\x02\x08\xd4\x02\x1d\n\r\n\x05\x04*\x02\0\x05\x12\
-    \x04\xd8\x02\x08\x0c\n\r\n\x05\x04*\x02\0\x01\x12\x04\xd8\x02\r\x11\n\r\
-    \n\x05\x04*\x02\0\x03\x12\x04\xd8\x02\x14\x15\n`\n\x04\x04*\x02\x01\x12\
-    \x04\xdb\x02\x08\x1b\x1aR\x20NbCpus\x20specifies\x20the\x20number\x20of\
-    \x20CPUs\x20that\x20were\x20added\x20and\x20the\x20agent\x20has\x20to\
-    \x20online.\n\n\x0f\n\x05\x04*\x02\x01\x04\x12\x06\xdb\x02\x08\xd8\x02\
-    \x16\n\r\n\x05\x04*\x02\x01\x05\x12\x04\xdb\x02\x08\x0e\n\r\n\x05\x04*\
-    \x02\x01\x01\x12\x04\xdb\x02\x0f\x16\n\r\n\x05\x04*\x02\x01\x03\x12\x04\
-    \xdb\x02\x19\x1a\nA\n\x04\x04*\x02\x02\x12\x04\xde\x02\x08\x1a\x1a3\x20C\
-    puOnly\x20specifies\x20whether\x20only\x20online\x20CPU\x20or\x20not.\n\
-    \n\x0f\n\x05\x04*\x02\x02\x04\x12\x06\xde\x02\x08\xdb\x02\x1b\n\r\n\x05\
-    \x04*\x02\x02\x05\x12\x04\xde\x02\x08\x0c\n\r\n\x05\x04*\x02\x02\x01\x12\
-    \x04\xde\x02\r\x15\n\r\n\x05\x04*\x02\x02\x03\x12\x04\xde\x02\x18\x19\n\
-    \x0c\n\x02\x04+\x12\x06\xe1\x02\0\xe4\x02\x01\n\x0b\n\x03\x04+\x01\x12\
-    \x04\xe1\x02\x08\x1e\nM\n\x04\x04+\x02\0\x12\x04\xe3\x02\x08\x17\x1a?\
-    \x20Data\x20specifies\x20the\x20random\x20data\x20used\x20to\x20reseed\
-    \x20the\x20guest\x20crng.\n\n\x0f\n\x05\x04+\x02\0\x04\x12\x06\xe3\x02\
-    \x08\xe1\x02\x20\n\r\n\x05\x04+\x02\0\x05\x12\x04\xe3\x02\x08\r\n\r\n\
-    \x05\x04+\x02\0\x01\x12\x04\xe3\x02\x0e\x12\n\r\n\x05\x04+\x02\0\x03\x12\
-    \x04\xe3\x02\x15\x16\nX\n\x02\x04,\x12\x06\xe7\x02\0\xf7\x02\x01\x1aJ\
-    \x20AgentDetails\x20provides\x20information\x20to\x20the\x20client\x20ab\
-    out\x20the\x20running\x20agent.\n\n\x0b\n\x03\x04,\x01\x12\x04\xe7\x02\
-    \x08\x14\nC\n\x04\x04,\x02\0\x12\x04\xe9\x02\x08\x1b\x1a5\x20Semantic\
-    \x20version\x20of\x20agent\x20(see\x20https://semver.org).\n\n\x0f\n\x05\
-    \x04,\x02\0\x04\x12\x06\xe9\x02\x08\xe7\x02\x16\n\r\n\x05\x04,\x02\0\x05\
-    \x12\x04\xe9\x02\x08\x0e\n\r\n\x05\x04,\x02\0\x01\x12\x04\xe9\x02\x0f\
-    \x16\n\r\n\x05\x04,\x02\0\x03\x12\x04\xe9\x02\x19\x1a\n5\n\x04\x04,\x02\
-    \x01\x12\x04\xec\x02\x08\x1d\x1a'\x20Set\x20if\x20the\x20agent\x20is\x20\
-    running\x20as\x20PID\x201.\n\n\x0f\n\x05\x04,\x02\x01\x04\x12\x06\xec\
-    \x02\x08\xe9\x02\x1b\n\r\n\x05\x04,\x02\x01\x05\x12\x04\xec\x02\x08\x0c\
-    \n\r\n\x05\x04,\x02\x01\x01\x12\x04\xec\x02\r\x18\n\r\n\x05\x04,\x02\x01\
-    \x03\x12\x04\xec\x02\x1b\x1c\n2\n\x04\x04,\x02\x02\x12\x04\xef\x02\x08,\
-    \x1a$\x20List\x20of\x20available\x20device\x20handlers.\n\n\r\n\x05\x04,\
-    \x02\x02\x04\x12\x04\xef\x02\x08\x10\n\r\n\x05\x04,\x02\x02\x05\x12\x04\
-    \xef\x02\x11\x17\n\r\n\x05\x04,\x02\x02\x01\x12\x04\xef\x02\x18'\n\r\n\
-    \x05\x04,\x02\x02\x03\x12\x04\xef\x02*+\n3\n\x04\x04,\x02\x03\x12\x04\
-    \xf2\x02\x08-\x1a%\x20List\x20of\x20available\x20storage\x20handlers.\n\
-    \n\r\n\x05\x04,\x02\x03\x04\x12\x04\xf2\x02\x08\x10\n\r\n\x05\x04,\x02\
-    \x03\x05\x12\x04\xf2\x02\x11\x17\n\r\n\x05\x04,\x02\x03\x01\x12\x04\xf2\
-    \x02\x18(\n\r\n\x05\x04,\x02\x03\x03\x12\x04\xf2\x02+,\np\n\x04\x04,\x02\
-    \x04\x12\x04\xf6\x02\x08\"\x1ab\x20Set\x20only\x20if\x20the\x20agent\x20\
-    is\x20built\x20with\x20seccomp\x20support\x20and\x20the\x20guest\n\x20en\
-    vironment\x20supports\x20seccomp.\n\n\x0f\n\x05\x04,\x02\x04\x04\x12\x06\
-    \xf6\x02\x08\xf2\x02-\n\r\n\x05\x04,\x02\x04\x05\x12\x04\xf6\x02\x08\x0c\
-    \n\r\n\x05\x04,\x02\x04\x01\x12\x04\xf6\x02\r\x1d\n\r\n\x05\x04,\x02\x04\
-    \x03\x12\x04\xf6\x02\x20!\n\x0c\n\x02\x04-\x12\x06\xf9\x02\0\x83\x03\x01\
-    \n\x0b\n\x03\x04-\x01\x12\x04\xf9\x02\x08\x1b\n\xd5\x01\n\x04\x04-\x02\0\
-    \x12\x04\xfd\x02\x08\x20\x1a\xc6\x01\x20MemBlockSize\x20asks\x20server\
-    \x20to\x20return\x20the\x20system\x20memory\x20block\x20size\x20that\x20\
-    can\x20be\x20used\n\x20for\x20memory\x20hotplug\x20alignment.\x20Typical\
-    ly\x20the\x20server\x20returns\x20what's\x20in\n\x20/sys/devices/system/\
-    memory/block_size_bytes.\n\n\x0f\n\x05\x04-\x02\0\x04\x12\x06\xfd\x02\
-    \x08\xf9\x02\x1d\n\r\n\x05\x04-\x02\0\x05\x12\x04\xfd\x02\x08\x0c\n\r\n\
-    \x05\x04-\x02\0\x01\x12\x04\xfd\x02\r\x1b\n\r\n\x05\x04-\x02\0\x03\x12\
-    \x04\xfd\x02\x1e\x1f\n\xd1\x01\n\x04\x04-\x02\x01\x12\x04\x82\x03\x08#\
-    \x1a\xc2\x01\x20MemoryHotplugProbe\x20asks\x20server\x20to\x20return\x20\
-    whether\x20guest\x20kernel\x20supports\x20memory\x20hotplug\n\x20via\x20\
-    probeinterface.\x20Typically\x20the\x20server\x20will\x20check\x20if\x20\
-    the\x20path\n\x20/sys/devices/system/memory/probe\x20exists.\n\n\x0f\n\
-    \x05\x04-\x02\x01\x04\x12\x06\x82\x03\x08\xfd\x02\x20\n\r\n\x05\x04-\x02\
-    \x01\x05\x12\x04\x82\x03\x08\x0c\n\r\n\x05\x04-\x02\x01\x01\x12\x04\x82\
-    \x03\r\x1e\n\r\n\x05\x04-\x02\x01\x03\x12\x04\x82\x03!\"\n\x0c\n\x02\x04\
-    .\x12\x06\x85\x03\0\x8c\x03\x01\n\x0b\n\x03\x04.\x01\x12\x04\x85\x03\x08\
-    \x1c\nP\n\x04\x04.\x02\0\x12\x04\x87\x03\x08(\x1aB\x20MemBlockSizeBytes\
-    \x20returns\x20the\x20system\x20memory\x20block\x20size\x20in\x20bytes.\
-    \n\n\x0f\n\x05\x04.\x02\0\x04\x12\x06\x87\x03\x08\x85\x03\x1e\n\r\n\x05\
-    \x04.\x02\0\x05\x12\x04\x87\x03\x08\x0e\n\r\n\x05\x04.\x02\0\x01\x12\x04\
-    \x87\x03\x0f#\n\r\n\x05\x04.\x02\0\x03\x12\x04\x87\x03&'\n\x0c\n\x04\x04\
-    .\x02\x01\x12\x04\x89\x03\x08'\n\x0f\n\x05\x04.\x02\x01\x04\x12\x06\x89\
-    \x03\x08\x87\x03(\n\r\n\x05\x04.\x02\x01\x06\x12\x04\x89\x03\x08\x14\n\r\
-    \n\x05\x04.\x02\x01\x01\x12\x04\x89\x03\x15\"\n\r\n\x05\x04.\x02\x01\x03\
-    \x12\x04\x89\x03%&\n\x0c\n\x04\x04.\x02\x02\x12\x04\x8b\x03\x08+\n\x0f\n\
-    \x05\x04.\x02\x02\x04\x12\x06\x8b\x03\x08\x89\x03'\n\r\n\x05\x04.\x02\
-    \x02\x05\x12\x04\x8b\x03\x08\x0c\n\r\n\x05\x04.\x02\x02\x01\x12\x04\x8b\
-    \x03\r&\n\r\n\x05\x04.\x02\x02\x03\x12\x04\x8b\x03)*\n\x0c\n\x02\x04/\
-    \x12\x06\x8e\x03\0\x92\x03\x01\n\x0b\n\x03\x04/\x01\x12\x04\x8e\x03\x08\
-    \x20\n\xb2\x01\n\x04\x04/\x02\0\x12\x04\x91\x03\x080\x1a\xa3\x01\x20serv\
-    er\x20needs\x20to\x20send\x20the\x20value\x20of\x20memHotplugProbeAddr\
-    \x20into\x20file\x20/sys/devices/system/memory/probe,\n\x20in\x20order\
-    \x20to\x20notify\x20the\x20guest\x20kernel\x20about\x20hot-add\x20memory\
-    \x20event\n\n\r\n\x05\x04/\x02\0\x04\x12\x04\x91\x03\x08\x10\n\r\n\x05\
-    \x04/\x02\0\x05\x12\x04\x91\x03\x11\x17\n\r\n\x05\x04/\x02\0\x01\x12\x04\
-    \x91\x03\x18+\n\r\n\x05\x04/\x02\0\x03\x12\x04\x91\x03./\n\x0c\n\x02\x04\
-    0\x12\x06\x94\x03\0\x99\x03\x01\n\x0b\n\x03\x040\x01\x12\x04\x94\x03\x08\
-    \x1f\n/\n\x04\x040\x02\0\x12\x04\x96\x03\x08\x16\x1a!\x20Sec\x20the\x20s\
-    econd\x20since\x20the\x20Epoch.\n\n\x0f\n\x05\x040\x02\0\x04\x12\x06\x96\
-    \x03\x08\x94\x03!\n\r\n\x05\x040\x02\0\x05\x12\x04\x96\x03\x08\r\n\r\n\
-    \x05\x040\x02\0\x01\x12\x04\x96\x03\x0e\x11\n\r\n\x05\x040\x02\0\x03\x12\
-    \x04\x96\x03\x14\x15\nF\n\x04\x040\x02\x01\x12\x04\x98\x03\x08\x17\x1a8\
-    \x20Usec\x20the\x20microseconds\x20portion\x20of\x20time\x20since\x20the\
-    \x20Epoch.\n\n\x0f\n\x05\x040\x02\x01\x04\x12\x06\x98\x03\x08\x96\x03\
-    \x16\n\r\n\x05\x040\x02\x01\x05\x12\x04\x98\x03\x08\r\n\r\n\x05\x040\x02\
-    \x01\x01\x12\x04\x98\x03\x0e\x12\n\r\n\x05\x040\x02\x01\x03\x12\x04\x98\
-    \x03\x15\x16\n\xa3\x01\n\x02\x041\x12\x06\x9d\x03\0\xb7\x03\x01\x1a\x94\
-    \x01\x20Storage\x20represents\x20both\x20the\x20rootfs\x20of\x20the\x20c\
-    ontainer,\x20and\x20any\x20volume\x20that\n\x20could\x20have\x20been\x20\
-    defined\x20through\x20the\x20Mount\x20list\x20of\x20the\x20OCI\x20specif\
-    ication.\n\n\x0b\n\x03\x041\x01\x12\x04\x9d\x03\x08\x0f\n\x8b\x02\n\x04\
-    \x041\x02\0\x12\x04\xa2\x03\x08\x1a\x1a\xfc\x01\x20Driver\x20is\x20used\
-    \x20to\x20define\x20the\x20way\x20the\x20storage\x20is\x20passed\x20thro\
-    ugh\x20the\n\x20virtual\x20machine.\x20It\x20can\x20be\x20\"9p\",\x20\"b\
-    lk\",\x20or\x20something\x20else,\x20but\x20for\n\x20all\x20cases,\x20th\
-    is\x20will\x20define\x20if\x20some\x20extra\x20steps\x20are\x20required\
-    \x20before\n\x20this\x20storage\x20gets\x20mounted\x20into\x20the\x20con\
-    tainer.\n\n\x0f\n\x05\x041\x02\0\x04\x12\x06\xa2\x03\x08\x9d\x03\x11\n\r\
-    \n\x05\x041\x02\0\x05\x12\x04\xa2\x03\x08\x0e\n\r\n\x05\x041\x02\0\x01\
-    \x12\x04\xa2\x03\x0f\x15\n\r\n\x05\x041\x02\0\x03\x12\x04\xa2\x03\x18\
-    \x19\n\xd0\x01\n\x04\x041\x02\x01\x12\x04\xa6\x03\x08+\x1a\xc1\x01\x20Dr\
-    iverOptions\x20allows\x20the\x20caller\x20to\x20define\x20a\x20list\x20o\
-    f\x20options\x20such\n\x20as\x20block\x20sizes,\x20numbers\x20of\x20luns\
-    ,\x20...\x20which\x20are\x20very\x20specific\x20to\n\x20every\x20device\
-    \x20and\x20cannot\x20be\x20generalized\x20through\x20extra\x20fields.\n\
-    \n\r\n\x05\x041\x02\x01\x04\x12\x04\xa6\x03\x08\x10\n\r\n\x05\x041\x02\
-    \x01\x05\x12\x04\xa6\x03\x11\x17\n\r\n\x05\x041\x02\x01\x01\x12\x04\xa6\
-    \x03\x18&\n\r\n\x05\x041\x02\x01\x03\x12\x04\xa6\x03)*\n\xce\x02\n\x04\
-    \x041\x02\x02\x12\x04\xac\x03\x08\x1a\x1a\xbf\x02\x20Source\x20can\x20be\
-    \x20anything\x20representing\x20the\x20source\x20of\x20the\x20storage.\
-    \x20This\n\x20will\x20be\x20handled\x20by\x20the\x20proper\x20handler\
-    \x20based\x20on\x20the\x20Driver\x20used.\n\x20For\x20instance,\x20it\
-    \x20can\x20be\x20a\x20very\x20simple\x20path\x20if\x20the\x20caller\x20k\
-    nows\x20the\n\x20name\x20of\x20device\x20inside\x20the\x20VM,\x20or\x20i\
-    t\x20can\x20be\x20some\x20sort\x20of\x20identifier\n\x20to\x20let\x20the\
-    \x20agent\x20find\x20the\x20device\x20inside\x20the\x20VM.\n\n\x0f\n\x05\
-    \x041\x02\x02\x04\x12\x06\xac\x03\x08\xa6\x03+\n\r\n\x05\x041\x02\x02\
-    \x05\x12\x04\xac\x03\x08\x0e\n\r\n\x05\x041\x02\x02\x01\x12\x04\xac\x03\
-    \x0f\x15\n\r\n\x05\x041\x02\x02\x03\x12\x04\xac\x03\x18\x19\n\xdb\x01\n\
-    \x04\x041\x02\x03\x12\x04\xb0\x03\x08\x1a\x1a\xcc\x01\x20Fstype\x20repre\
-    sents\x20the\x20filesystem\x20that\x20needs\x20to\x20be\x20used\x20to\
-    \x20mount\x20the\n\x20storage\x20inside\x20the\x20VM.\x20For\x20instance\
-    ,\x20it\x20could\x20be\x20\"xfs\"\x20for\x20block\n\x20device,\x20\"9p\"\
-    \x20for\x20shared\x20filesystem,\x20or\x20\"tmpfs\"\x20for\x20shared\x20\
-    /dev/shm.\n\n\x0f\n\x05\x041\x02\x03\x04\x12\x06\xb0\x03\x08\xac\x03\x1a\
-    \n\r\n\x05\x041\x02\x03\x05\x12\x04\xb0\x03\x08\x0e\n\r\n\x05\x041\x02\
-    \x03\x01\x12\x04\xb0\x03\x0f\x15\n\r\n\x05\x041\x02\x03\x03\x12\x04\xb0\
-    \x03\x18\x19\nw\n\x04\x041\x02\x04\x12\x04\xb3\x03\x08$\x1ai\x20Options\
-    \x20describes\x20the\x20additional\x20options\x20that\x20might\x20be\x20\
-    needed\x20to\n\x20mount\x20properly\x20the\x20storage\x20filesytem.\n\n\
-    \r\n\x05\x041\x02\x04\x04\x12\x04\xb3\x03\x08\x10\n\r\n\x05\x041\x02\x04\
-    \x05\x12\x04\xb3\x03\x11\x17\n\r\n\x05\x041\x02\x04\x01\x12\x04\xb3\x03\
-    \x18\x1f\n\r\n\x05\x041\x02\x04\x03\x12\x04\xb3\x03\"#\na\n\x04\x041\x02\
-    \x05\x12\x04\xb6\x03\x08\x1f\x1aS\x20MountPoint\x20refers\x20to\x20the\
-    \x20path\x20where\x20the\x20storage\x20should\x20be\x20mounted\n\x20insi\
-    de\x20the\x20VM.\n\n\x0f\n\x05\x041\x02\x05\x04\x12\x06\xb6\x03\x08\xb3\
-    \x03$\n\r\n\x05\x041\x02\x05\x05\x12\x04\xb6\x03\x08\x0e\n\r\n\x05\x041\
-    \x02\x05\x01\x12\x04\xb6\x03\x0f\x1a\n\r\n\x05\x041\x02\x05\x03\x12\x04\
-    \xb6\x03\x1d\x1e\n\x88\x01\n\x02\x042\x12\x06\xbb\x03\0\xdb\x03\x01\x1az\
-    \x20Device\x20represents\x20only\x20the\x20devices\x20that\x20could\x20h\
-    ave\x20been\x20defined\x20through\x20the\n\x20Linux\x20Device\x20list\
-    \x20of\x20the\x20OCI\x20specification.\n\n\x0b\n\x03\x042\x01\x12\x04\
-    \xbb\x03\x08\x0e\n\xb0\x01\n\x04\x042\x02\0\x12\x04\xbf\x03\x08\x16\x1a\
-    \xa1\x01\x20Id\x20can\x20be\x20used\x20to\x20identify\x20the\x20device\
-    \x20inside\x20the\x20VM.\x20Some\x20devices\n\x20might\x20not\x20need\
-    \x20it\x20to\x20be\x20identified\x20on\x20the\x20VM,\x20and\x20will\x20r\
-    ely\x20on\x20the\n\x20provided\x20VmPath\x20instead.\n\n\x0f\n\x05\x042\
-    \x02\0\x04\x12\x06\xbf\x03\x08\xbb\x03\x10\n\r\n\x05\x042\x02\0\x05\x12\
-    \x04\xbf\x03\x08\x0e\n\r\n\x05\x042\x02\0\x01\x12\x04\xbf\x03\x0f\x11\n\
-    \r\n\x05\x042\x02\0\x03\x12\x04\xbf\x03\x14\x15\n\xbd\x01\n\x04\x042\x02\
-    \x01\x12\x04\xc4\x03\x08\x18\x1a\xae\x01\x20Type\x20defines\x20the\x20ty\
-    pe\x20of\x20device\x20described.\x20This\x20can\x20be\x20\"blk\",\n\x20\
-    \"scsi\",\x20\"vfio\",\x20...\n\x20Particularly,\x20this\x20should\x20be\
-    \x20used\x20to\x20trigger\x20the\x20use\x20of\x20the\n\x20appropriate\
-    \x20device\x20handler.\n\n\x0f\n\x05\x042\x02\x01\x04\x12\x06\xc4\x03\
-    \x08\xbf\x03\x16\n\r\n\x05\x042\x02\x01\x05\x12\x04\xc4\x03\x08\x0e\n\r\
-    \n\x05\x042\x02\x01\x01\x12\x04\xc4\x03\x0f\x13\n\r\n\x05\x042\x02\x01\
-    \x03\x12\x04\xc4\x03\x16\x17\n\xab\x02\n\x04\x042\x02\x02\x12\x04\xca\
-    \x03\x08\x1b\x1a\x9c\x02\x20VmPath\x20can\x20be\x20used\x20by\x20the\x20\
-    caller\x20to\x20provide\x20directly\x20the\x20path\x20of\n\x20the\x20dev\
-    ice\x20as\x20it\x20will\x20appear\x20inside\x20the\x20VM.\x20For\x20some\
-    \x20devices,\x20the\n\x20device\x20id\x20or\x20the\x20list\x20of\x20opti\
-    ons\x20passed\x20might\x20not\x20be\x20enough\x20to\x20find\n\x20the\x20\
-    device.\x20In\x20those\x20cases,\x20the\x20caller\x20should\x20predict\
-    \x20and\x20provide\n\x20this\x20vm_path.\n\n\x0f\n\x05\x042\x02\x02\x04\
-    \x12\x06\xca\x03\x08\xc4\x03\x18\n\r\n\x05\x042\x02\x02\x05\x12\x04\xca\
-    \x03\x08\x0e\n\r\n\x05\x042\x02\x02\x01\x12\x04\xca\x03\x0f\x16\n\r\n\
-    \x05\x042\x02\x02\x03\x12\x04\xca\x03\x19\x1a\n\xd4\x05\n\x04\x042\x02\
-    \x03\x12\x04\xd6\x03\x08\"\x1a\xc5\x05\x20ContainerPath\x20defines\x20th\
-    e\x20path\x20where\x20the\x20device\x20should\x20be\x20found\x20inside\n\
-    \x20the\x20container.\x20This\x20path\x20should\x20match\x20the\x20path\
-    \x20of\x20the\x20device\x20from\n\x20the\x20device\x20list\x20listed\x20\
-    inside\x20the\x20OCI\x20spec.\x20This\x20is\x20used\x20in\x20order\n\x20\
-    to\x20identify\x20the\x20right\x20device\x20in\x20the\x20spec\x20and\x20\
-    update\x20it\x20with\x20the\n\x20right\x20options\x20such\x20as\x20major\
-    /minor\x20numbers\x20as\x20they\x20appear\x20inside\n\x20the\x20VM\x20fo\
-    r\x20instance.\x20Note\x20that\x20an\x20empty\x20ctr_path\x20should\x20b\
-    e\x20used\n\x20to\x20make\x20sure\x20the\x20device\x20handler\x20inside\
-    \x20the\x20agent\x20is\x20called,\x20but\n\x20no\x20spec\x20update\x20ne\
-    eds\x20to\x20be\x20performed.\x20This\x20has\x20to\x20happen\x20for\x20t\
-    he\n\x20case\x20of\x20rootfs,\x20when\x20a\x20device\x20has\x20to\x20be\
-    \x20waited\x20for\x20after\x20it\x20has\n\x20been\x20hotplugged.\x20An\
-    \x20equivalent\x20Storage\x20entry\x20should\x20be\x20defined\x20if\n\
-    \x20any\x20mount\x20needs\x20to\x20be\x20performed\x20afterwards.\n\n\
-    \x0f\n\x05\x042\x02\x03\x04\x12\x06\xd6\x03\x08\xca\x03\x1b\n\r\n\x05\
-    \x042\x02\x03\x05\x12\x04\xd6\x03\x08\x0e\n\r\n\x05\x042\x02\x03\x01\x12\
-    \x04\xd6\x03\x0f\x1d\n\r\n\x05\x042\x02\x03\x03\x12\x04\xd6\x03\x20!\n\
-    \xca\x01\n\x04\x042\x02\x04\x12\x04\xda\x03\x08$\x1a\xbb\x01\x20Options\
-    \x20allows\x20the\x20caller\x20to\x20define\x20a\x20list\x20of\x20option\
-    s\x20such\x20as\x20block\n\x20sizes,\x20numbers\x20of\x20luns,\x20...\
-    \x20which\x20are\x20very\x20specific\x20to\x20every\x20device\n\x20and\
-    \x20cannot\x20be\x20generalized\x20through\x20extra\x20fields.\n\n\r\n\
-    \x05\x042\x02\x04\x04\x12\x04\xda\x03\x08\x10\n\r\n\x05\x042\x02\x04\x05\
-    \x12\x04\xda\x03\x11\x17\n\r\n\x05\x042\x02\x04\x01\x12\x04\xda\x03\x18\
-    \x1f\n\r\n\x05\x042\x02\x04\x03\x12\x04\xda\x03\"#\n\x0c\n\x02\x043\x12\
-    \x06\xdd\x03\0\xe1\x03\x01\n\x0b\n\x03\x043\x01\x12\x04\xdd\x03\x08\x12\
-    \n\x0c\n\x04\x043\x02\0\x12\x04\xde\x03\x08\x17\n\x0f\n\x05\x043\x02\0\
-    \x04\x12\x06\xde\x03\x08\xdd\x03\x14\n\r\n\x05\x043\x02\0\x05\x12\x04\
-    \xde\x03\x08\x0e\n\r\n\x05\x043\x02\0\x01\x12\x04\xde\x03\x0f\x12\n\r\n\
-    \x05\x043\x02\0\x03\x12\x04\xde\x03\x15\x16\n\x0c\n\x04\x043\x02\x01\x12\
-    \x04\xdf\x03\x08\x17\n\x0f\n\x05\x043\x02\x01\x04\x12\x06\xdf\x03\x08\
-    \xde\x03\x17\n\r\n\x05\x043\x02\x01\x05\x12\x04\xdf\x03\x08\x0e\n\r\n\
-    \x05\x043\x02\x01\x01\x12\x04\xdf\x03\x0f\x12\n\r\n\x05\x043\x02\x01\x03\
-    \x12\x04\xdf\x03\x15\x16\n\x0c\n\x04\x043\x02\x02\x12\x04\xe0\x03\x08+\n\
-    \r\n\x05\x043\x02\x02\x04\x12\x04\xe0\x03\x08\x10\n\r\n\x05\x043\x02\x02\
-    \x05\x12\x04\xe0\x03\x11\x17\n\r\n\x05\x043\x02\x02\x01\x12\x04\xe0\x03\
-    \x18&\n\r\n\x05\x043\x02\x02\x03\x12\x04\xe0\x03)*\n\x0c\n\x02\x044\x12\
-    \x06\xe3\x03\0\xf7\x03\x01\n\x0b\n\x03\x044\x01\x12\x04\xe3\x03\x08\x17\
-    \nj\n\x04\x044\x02\0\x12\x04\xe6\x03\x08\x18\x1a\\\x20Path\x20is\x20the\
-    \x20destination\x20file\x20in\x20the\x20guest.\x20It\x20must\x20be\x20ab\
-    solute,\n\x20canonical\x20and\x20below\x20/run.\n\n\x0f\n\x05\x044\x02\0\
-    \x04\x12\x06\xe6\x03\x08\xe3\x03\x19\n\r\n\x05\x044\x02\0\x05\x12\x04\
-    \xe6\x03\x08\x0e\n\r\n\x05\x044\x02\0\x01\x12\x04\xe6\x03\x0f\x13\n\r\n\
-    \x05\x044\x02\0\x03\x12\x04\xe6\x03\x16\x17\n\xbd\x01\n\x04\x044\x02\x01\
-    \x12\x04\xea\x03\x08\x1c\x1a\xae\x01\x20FileSize\x20is\x20the\x20expecte\
-    d\x20file\x20size,\x20for\x20security\x20reasons\x20write\x20operations\
-    \n\x20are\x20made\x20in\x20a\x20temporary\x20file,\x20once\x20it\x20has\
-    \x20the\x20expected\x20size,\x20it's\x20moved\n\x20to\x20the\x20destinat\
-    ion\x20path.\n\n\x0f\n\x05\x044\x02\x01\x04\x12\x06\xea\x03\x08\xe6\x03\
-    \x18\n\r\n\x05\x044\x02\x01\x05\x12\x04\xea\x03\x08\r\n\r\n\x05\x044\x02\
-    \x01\x01\x12\x04\xea\x03\x0e\x17\n\r\n\x05\x044\x02\x01\x03\x12\x04\xea\
-    \x03\x1a\x1b\n*\n\x04\x044\x02\x02\x12\x04\xec\x03\x08\x1d\x1a\x1c\x20Fi\
-    leMode\x20is\x20the\x20file\x20mode.\n\n\x0f\n\x05\x044\x02\x02\x04\x12\
-    \x06\xec\x03\x08\xea\x03\x1c\n\r\n\x05\x044\x02\x02\x05\x12\x04\xec\x03\
-    \x08\x0e\n\r\n\x05\x044\x02\x02\x01\x12\x04\xec\x03\x0f\x18\n\r\n\x05\
-    \x044\x02\x02\x03\x12\x04\xec\x03\x1b\x1c\nS\n\x04\x044\x02\x03\x12\x04\
-    \xee\x03\x08\x1c\x1aE\x20DirMode\x20is\x20the\x20mode\x20for\x20the\x20p\
-    arent\x20directories\x20of\x20destination\x20path.\n\n\x0f\n\x05\x044\
-    \x02\x03\x04\x12\x06\xee\x03\x08\xec\x03\x1d\n\r\n\x05\x044\x02\x03\x05\
-    \x12\x04\xee\x03\x08\x0e\n\r\n\x05\x044\x02\x03\x01\x12\x04\xee\x03\x0f\
-    \x17\n\r\n\x05\x044\x02\x03\x03\x12\x04\xee\x03\x1a\x1b\n+\n\x04\x044\
-    \x02\x04\x12\x04\xf0\x03\x08\x16\x1a\x1d\x20Uid\x20is\x20the\x20numeric\
-    \x20user\x20id.\n\n\x0f\n\x05\x044\x02\x04\x04\x12\x06\xf0\x03\x08\xee\
-    \x03\x1c\n\r\n\x05\x044\x02\x04\x05\x12\x04\xf0\x03\x08\r\n\r\n\x05\x044\
-    \x02\x04\x01\x12\x04\xf0\x03\x0e\x11\n\r\n\x05\x044\x02\x04\x03\x12\x04\
-    \xf0\x03\x14\x15\n,\n\x04\x044\x02\x05\x12\x04\xf2\x03\x08\x16\x1a\x1e\
-    \x20Gid\x20is\x20the\x20numeric\x20group\x20id.\n\n\x0f\n\x05\x044\x02\
-    \x05\x04\x12\x06\xf2\x03\x08\xf0\x03\x16\n\r\n\x05\x044\x02\x05\x05\x12\
-    \x04\xf2\x03\x08\r\n\r\n\x05\x044\x02\x05\x01\x12\x04\xf2\x03\x0e\x11\n\
-    \r\n\x05\x044\x02\x05\x03\x12\x04\xf2\x03\x14\x15\n4\n\x04\x044\x02\x06\
-    \x12\x04\xf4\x03\x08\x19\x1a&\x20Offset\x20for\x20the\x20next\x20write\
-    \x20operation.\n\n\x0f\n\x05\x044\x02\x06\x04\x12\x06\xf4\x03\x08\xf2\
-    \x03\x16\n\r\n\x05\x044\x02\x06\x05\x12\x04\xf4\x03\x08\r\n\r\n\x05\x044\
-    \x02\x06\x01\x12\x04\xf4\x03\x0e\x14\n\r\n\x05\x044\x02\x06\x03\x12\x04\
-    \xf4\x03\x17\x18\n6\n\x04\x044\x02\x07\x12\x04\xf6\x03\x08\x17\x1a(\x20D\
-    ata\x20to\x20write\x20in\x20the\x20destination\x20file.\n\n\x0f\n\x05\
-    \x044\x02\x07\x04\x12\x06\xf6\x03\x08\xf4\x03\x19\n\r\n\x05\x044\x02\x07\
-    \x05\x12\x04\xf6\x03\x08\r\n\r\n\x05\x044\x02\x07\x01\x12\x04\xf6\x03\
-    \x0e\x12\n\r\n\x05\x044\x02\x07\x03\x12\x04\xf6\x03\x15\x16\n\x0c\n\x02\
-    \x045\x12\x06\xf9\x03\0\xfa\x03\x01\n\x0b\n\x03\x045\x01\x12\x04\xf9\x03\
-    \x08\x1b\n\x0c\n\x02\x046\x12\x06\xfc\x03\0\xfd\x03\x01\n\x0b\n\x03\x046\
-    \x01\x12\x04\xfc\x03\x08\x1a\n\n\n\x02\x047\x12\x04\xff\x03\0\x1d\n\x0b\
-    \n\x03\x047\x01\x12\x04\xff\x03\x08\x1a\n\x0c\n\x02\x048\x12\x06\x81\x04\
-    \0\x83\x04\x01\n\x0b\n\x03\x048\x01\x12\x04\x81\x04\x08\x10\n\x0c\n\x04\
-    \x048\x02\0\x12\x04\x82\x04\x08\x20\n\x0f\n\x05\x048\x02\0\x04\x12\x06\
-    \x82\x04\x08\x81\x04\x12\n\r\n\x05\x048\x02\0\x05\x12\x04\x82\x04\x08\
-    \x0e\n\r\n\x05\x048\x02\0\x01\x12\x04\x82\x04\x0f\x1b\n\r\n\x05\x048\x02\
-    \0\x03\x12\x04\x82\x04\x1e\x1fb\x06proto3\
+    \n\r\n\x05\x04\x20\x02\x06\x03\x12\x04\xb0\x02/0\n\x8a\x01\n\x04\x04\x20\
+    \x02\x07\x12\x04\xb3\x02\x08(\x1a|\x20This\x20field\x20is\x20the\x20list\
+    \x20of\x20non-namespaced\x20sysctls\x20to\x20be\x20applied\x20once\n\x20\
+    to\x20the\x20guest\x20kernel,\x20before\x20any\x20container\x20is\x20cre\
+    ated.\n\n\x0f\n\x05\x04\x20\x02\x07\x04\x12\x06\xb3\x02\x08\xb0\x021\n\r\
+    \n\x05\x04\x20\x02\x07\x06\x12\x04\xb3\x02\x08\x1b\n\r\n\x05\x04\x20\x02\
+    \x07\x01\x12\x04\xb3\x02\x1c#\n\r\n\x05\x04\x20\x02\x07\x03\x12\x04\xb3\
+    \x02&'\n\x0c\n\x02\x04!\x12\x06\xb6\x02\0\xb7\x02\x01\n\x0b\n\x03\x04!\
+    \x01\x12\x04\xb6\x02\x08\x1d\n\x0c\n\x02\x04\"\x12\x06\xb9\x02\0\xbb\x02\
+    \x01\n\x0b\n\x03\x04\"\x01\x12\x04\xb9\x02\x08\x12\n\x0c\n\x04\x04\"\x02\
+    \0\x12\x04\xba\x02\x080\n\r\n\x05\x04\"\x02\0\x04\x12\x04\xba\x02\x08\
+    \x10\n\r\n\x05\x04\"\x02\0\x06\x12\x04\xba\x02\x11\x20\n\r\n\x05\x04\"\
+    \x02\0\x01\x12\x04\xba\x02!+\n\r\n\x05\x04\"\x02\0\x03\x12\x04\xba\x02./\
+    \n\x0c\n\x02\x04#\x12\x06\xbd\x02\0\xbf\x02\x01\n\x0b\n\x03\x04#\x01\x12\
+    \x04\xbd\x02\x08\x0e\n\x0c\n\x04\x04#\x02\0\x12\x04\xbe\x02\x08(\n\r\n\
+    \x05\x04#\x02\0\x04\x12\x04\xbe\x02\x08\x10\n\r\n\x05\x04#\x02\0\x06\x12\
+    \x04\xbe\x02\x11\x1c\n\r\n\x05\x04#\x02\0\x01\x12\x04\xbe\x02\x1d#\n\r\n\
+    \x05\x04#\x02\0\x03\x12\x04\xbe\x02&'\n\x0c\n\x02\x04$\x12\x06\xc1\x02\0\
+    \xc3\x02\x01\n\x0b\n\x03\x04$\x01\x12\x04\xc1\x02\x08\x1e\n\x0c\n\x04\
+    \x04$\x02\0\x12\x04\xc2\x02\x08&\n\x0f\n\x05\x04$\x02\0\x04\x12\x06\xc2\
+    \x02\x08\xc1\x02\x20\n\r\n\x05\x04$\x02\0\x06\x12\x04\xc2\x02\x08\x17\n\
+    \r\n\x05\x04$\x02\0\x01\x12\x04\xc2\x02\x18!\n\r\n\x05\x04$\x02\0\x03\
+    \x12\x04\xc2\x02$%\n\x0c\n\x02\x04%\x12\x06\xc5\x02\0\xc7\x02\x01\n\x0b\
+    \n\x03\x04%\x01\x12\x04\xc5\x02\x08\x1b\n\x0c\n\x04\x04%\x02\0\x12\x04\
+    \xc6\x02\x08\x1a\n\x0f\n\x05\x04%\x02\0\x04\x12\x06\xc6\x02\x08\xc5\x02\
+    \x1d\n\r\n\x05\x04%\x02\0\x06\x12\x04\xc6\x02\x08\x0e\n\r\n\x05\x04%\x02\
+    \0\x01\x12\x04\xc6\x02\x0f\x15\n\r\n\x05\x04%\x02\0\x03\x12\x04\xc6\x02\
+    \x18\x19\n\x0c\n\x02\x04&\x12\x06\xc9\x02\0\xca\x02\x01\n\x0b\n\x03\x04&\
+    \x01\x12\x04\xc9\x02\x08\x1d\n\x0c\n\x02\x04'\x12\x06\xcc\x02\0\xcd\x02\
+    \x01\n\x0b\n\x03\x04'\x01\x12\x04\xcc\x02\x08\x19\n\x0c\n\x02\x04(\x12\
+    \x06\xcf\x02\0\xd1\x02\x01\n\x0b\n\x03\x04(\x01\x12\x04\xcf\x02\x08\x14\
+    \n\x0c\n\x04\x04(\x02\0\x12\x04\xd0\x02\x073\n\r\n\x05\x04(\x02\0\x04\
+    \x12\x04\xd0\x02\x07\x0f\n\r\n\x05\x04(\x02\0\x06\x12\x04\xd0\x02\x10!\n\
+    \r\n\x05\x04(\x02\0\x01\x12\x04\xd0\x02\".\n\r\n\x05\x04(\x02\0\x03\x12\
+    \x04\xd0\x0212\n\x0c\n\x02\x04)\x12\x06\xd3\x02\0\xd5\x02\x01\n\x0b\n\
+    \x03\x04)\x01\x12\x04\xd3\x02\x08\x1e\n\x0c\n\x04\x04)\x02\0\x12\x04\xd4\
+    \x02\x07\"\n\x0f\n\x05\x04)\x02\0\x04\x12\x06\xd4\x02\x07\xd3\x02\x20\n\
+    \r\n\x05\x04)\x02\0\x06\x12\x04\xd4\x02\x07\x13\n\r\n\x05\x04)\x02\0\x01\
+    \x12\x04\xd4\x02\x14\x1d\n\r\n\x05\x04)\x02\0\x03\x12\x04\xd4\x02\x20!\n\
+    \x0c\n\x02\x04*\x12\x06\xd7\x02\0\xe2\x02\x01\n\x0b\n\x03\x04*\x01\x12\
+    \x04\xd7\x02\x08\x1b\n\xf6\x01\n\x04\x04*\x02\0\x12\x04\xdb\x02\x08\x16\
+    \x1a\xe7\x01\x20Wait\x20specifies\x20if\x20the\x20caller\x20waits\x20for\
+    \x20the\x20agent\x20to\x20online\x20all\x20resources.\n\x20If\x20true\
+    \x20the\x20agent\x20returns\x20once\x20all\x20resources\x20have\x20been\
+    \x20connected,\x20otherwise\x20all\n\x20resources\x20are\x20connected\
+    \x20asynchronously\x20and\x20the\x20agent\x20returns\x20immediately.\n\n\
+    \x0f\n\x05\x04*\x02\0\x04\x12\x06\xdb\x02\x08\xd7\x02\x1d\n\r\n\x05\x04*\
+    \x02\0\x05\x12\x04\xdb\x02\x08\x0c\n\r\n\x05\x04*\x02\0\x01\x12\x04\xdb\
+    \x02\r\x11\n\r\n\x05\x04*\x02\0\x03\x12\x04\xdb\x02\x14\x15\n`\n\x04\x04\
+    *\x02\x01\x12\x04\xde\x02\x08\x1b\x1aR\x20NbCpus\x20specifies\x20the\x20\
+    number\x20of\x20CPUs\x20that\x20were\x20added\x20and\x20the\x20agent\x20\
+    has\x20to\x20online.\n\n\x0f\n\x05\x04*\x02\x01\x04\x12\x06\xde\x02\x08\
+    \xdb\x02\x16\n\r\n\x05\x04*\x02\x01\x05\x12\x04\xde\x02\x08\x0e\n\r\n\
+    \x05\x04*\x02\x01\x01\x12\x04\xde\x02\x0f\x16\n\r\n\x05\x04*\x02\x01\x03\
+    \x12\x04\xde\x02\x19\x1a\nA\n\x04\x04*\x02\x02\x12\x04\xe1\x02\x08\x1a\
+    \x1a3\x20CpuOnly\x20specifies\x20whether\x20only\x20online\x20CPU\x20or\
+    \x20not.\n\n\x0f\n\x05\x04*\x02\x02\x04\x12\x06\xe1\x02\x08\xde\x02\x1b\
+    \n\r\n\x05\x04*\x02\x02\x05\x12\x04\xe1\x02\x08\x0c\n\r\n\x05\x04*\x02\
+    \x02\x01\x12\x04\xe1\x02\r\x15\n\r\n\x05\x04*\x02\x02\x03\x12\x04\xe1\
+    \x02\x18\x19\n\x0c\n\x02\x04+\x12\x06\xe4\x02\0\xe7\x02\x01\n\x0b\n\x03\
+    \x04+\x01\x12\x04\xe4\x02\x08\x1e\nM\n\x04\x04+\x02\0\x12\x04\xe6\x02\
+    \x08\x17\x1a?\x20Data\x20specifies\x20the\x20random\x20data\x20used\x20t\
+    o\x20reseed\x20the\x20guest\x20crng.\n\n\x0f\n\x05\x04+\x02\0\x04\x12\
+    \x06\xe6\x02\x08\xe4\x02\x20\n\r\n\x05\x04+\x02\0\x05\x12\x04\xe6\x02\
+    \x08\r\n\r\n\x05\x04+\x02\0\x01\x12\x04\xe6\x02\x0e\x12\n\r\n\x05\x04+\
+    \x02\0\x03\x12\x04\xe6\x02\x15\x16\nX\n\x02\x04,\x12\x06\xea\x02\0\xfa\
+    \x02\x01\x1aJ\x20AgentDetails\x20provides\x20information\x20to\x20the\
+    \x20client\x20about\x20the\x20running\x20agent.\n\n\x0b\n\x03\x04,\x01\
+    \x12\x04\xea\x02\x08\x14\nC\n\x04\x04,\x02\0\x12\x04\xec\x02\x08\x1b\x1a\
+    5\x20Semantic\x20version\x20of\x20agent\x20(see\x20https://semver.org).\
+    \n\n\x0f\n\x05\x04,\x02\0\x04\x12\x06\xec\x02\x08\xea\x02\x16\n\r\n\x05\
+    \x04,\x02\0\x05\x12\x04\xec\x02\x08\x0e\n\r\n\x05\x04,\x02\0\x01\x12\x04\
+    \xec\x02\x0f\x16\n\r\n\x05\x04,\x02\0\x03\x12\x04\xec\x02\x19\x1a\n5\n\
+    \x04\x04,\x02\x01\x12\x04\xef\x02\x08\x1d\x1a'\x20Set\x20if\x20the\x20ag\
+    ent\x20is\x20running\x20as\x20PID\x201.\n\n\x0f\n\x05\x04,\x02\x01\x04\
+    \x12\x06\xef\x02\x08\xec\x02\x1b\n\r\n\x05\x04,\x02\x01\x05\x12\x04\xef\
+    \x02\x08\x0c\n\r\n\x05\x04,\x02\x01\x01\x12\x04\xef\x02\r\x18\n\r\n\x05\
+    \x04,\x02\x01\x03\x12\x04\xef\x02\x1b\x1c\n2\n\x04\x04,\x02\x02\x12\x04\
+    \xf2\x02\x08,\x1a$\x20List\x20of\x20available\x20device\x20handlers.\n\n\
+    \r\n\x05\x04,\x02\x02\x04\x12\x04\xf2\x02\x08\x10\n\r\n\x05\x04,\x02\x02\
+    \x05\x12\x04\xf2\x02\x11\x17\n\r\n\x05\x04,\x02\x02\x01\x12\x04\xf2\x02\
+    \x18'\n\r\n\x05\x04,\x02\x02\x03\x12\x04\xf2\x02*+\n3\n\x04\x04,\x02\x03\
+    \x12\x04\xf5\x02\x08-\x1a%\x20List\x20of\x20available\x20storage\x20hand\
+    lers.\n\n\r\n\x05\x04,\x02\x03\x04\x12\x04\xf5\x02\x08\x10\n\r\n\x05\x04\
+    ,\x02\x03\x05\x12\x04\xf5\x02\x11\x17\n\r\n\x05\x04,\x02\x03\x01\x12\x04\
+    \xf5\x02\x18(\n\r\n\x05\x04,\x02\x03\x03\x12\x04\xf5\x02+,\np\n\x04\x04,\
+    \x02\x04\x12\x04\xf9\x02\x08\"\x1ab\x20Set\x20only\x20if\x20the\x20agent\
+    \x20is\x20built\x20with\x20seccomp\x20support\x20and\x20the\x20guest\n\
+    \x20environment\x20supports\x20seccomp.\n\n\x0f\n\x05\x04,\x02\x04\x04\
+    \x12\x06\xf9\x02\x08\xf5\x02-\n\r\n\x05\x04,\x02\x04\x05\x12\x04\xf9\x02\
+    \x08\x0c\n\r\n\x05\x04,\x02\x04\x01\x12\x04\xf9\x02\r\x1d\n\r\n\x05\x04,\
+    \x02\x04\x03\x12\x04\xf9\x02\x20!\n\x0c\n\x02\x04-\x12\x06\xfc\x02\0\x86\
+    \x03\x01\n\x0b\n\x03\x04-\x01\x12\x04\xfc\x02\x08\x1b\n\xd5\x01\n\x04\
+    \x04-\x02\0\x12\x04\x80\x03\x08\x20\x1a\xc6\x01\x20MemBlockSize\x20asks\
+    \x20server\x20to\x20return\x20the\x20system\x20memory\x20block\x20size\
+    \x20that\x20can\x20be\x20used\n\x20for\x20memory\x20hotplug\x20alignment\
+    .\x20Typically\x20the\x20server\x20returns\x20what's\x20in\n\x20/sys/dev\
+    ices/system/memory/block_size_bytes.\n\n\x0f\n\x05\x04-\x02\0\x04\x12\
+    \x06\x80\x03\x08\xfc\x02\x1d\n\r\n\x05\x04-\x02\0\x05\x12\x04\x80\x03\
+    \x08\x0c\n\r\n\x05\x04-\x02\0\x01\x12\x04\x80\x03\r\x1b\n\r\n\x05\x04-\
+    \x02\0\x03\x12\x04\x80\x03\x1e\x1f\n\xd1\x01\n\x04\x04-\x02\x01\x12\x04\
+    \x85\x03\x08#\x1a\xc2\x01\x20MemoryHotplugProbe\x20asks\x20server\x20to\
+    \x20return\x20whether\x20guest\x20kernel\x20supports\x20memory\x20hotplu\
+    g\n\x20via\x20probeinterface.\x20Typically\x20the\x20server\x20will\x20c\
+    heck\x20if\x20the\x20path\n\x20/sys/devices/system/memory/probe\x20exist\
+    s.\n\n\x0f\n\x05\x04-\x02\x01\x04\x12\x06\x85\x03\x08\x80\x03\x20\n\r\n\
+    \x05\x04-\x02\x01\x05\x12\x04\x85\x03\x08\x0c\n\r\n\x05\x04-\x02\x01\x01\
+    \x12\x04\x85\x03\r\x1e\n\r\n\x05\x04-\x02\x01\x03\x12\x04\x85\x03!\"\n\
+    \x0c\n\x02\x04.\x12\x06\x88\x03\0\x8f\x03\x01\n\x0b\n\x03\x04.\x01\x12\
+    \x04\x88\x03\x08\x1c\nP\n\x04\x04.\x02\0\x12\x04\x8a\x03\x08(\x1aB\x20Me\
+    mBlockSizeBytes\x20returns\x20the\x20system\x20memory\x20block\x20size\
+    \x20in\x20bytes.\n\n\x0f\n\x05\x04.\x02\0\x04\x12\x06\x8a\x03\x08\x88\
+    \x03\x1e\n\r\n\x05\x04.\x02\0\x05\x12\x04\x8a\x03\x08\x0e\n\r\n\x05\x04.\
+    \x02\0\x01\x12\x04\x8a\x03\x0f#\n\r\n\x05\x04.\x02\0\x03\x12\x04\x8a\x03\
+    &'\n\x0c\n\x04\x04.\x02\x01\x12\x04\x8c\x03\x08'\n\x0f\n\x05\x04.\x02\
+    \x01\x04\x12\x06\x8c\x03\x08\x8a\x03(\n\r\n\x05\x04.\x02\x01\x06\x12\x04\
+    \x8c\x03\x08\x14\n\r\n\x05\x04.\x02\x01\x01\x12\x04\x8c\x03\x15\"\n\r\n\
+    \x05\x04.\x02\x01\x03\x12\x04\x8c\x03%&\n\x0c\n\x04\x04.\x02\x02\x12\x04\
+    \x8e\x03\x08+\n\x0f\n\x05\x04.\x02\x02\x04\x12\x06\x8e\x03\x08\x8c\x03'\
+    \n\r\n\x05\x04.\x02\x02\x05\x12\x04\x8e\x03\x08\x0c\n\r\n\x05\x04.\x02\
+    \x02\x01\x12\x04\x8e\x03\r&\n\r\n\x05\x04.\x02\x02\x03\x12\x04\x8e\x03)*\
+    \n\x0c\n\x02\x04/\x12\x06\x91\x03\0\x95\x03\x01\n\x0b\n\x03\x04/\x01\x12\
+    \x04\x91\x03\x08\x20\n\xb2\x01\n\x04\x04/\x02\0\x12\x04\x94\x03\x080\x1a\
+    \xa3\x01\x20server\x20needs\x20to\x20send\x20the\x20value\x20of\x20memHo\
+    tplugProbeAddr\x20into\x20file\x20/sys/devices/system/memory/probe,\n\
+    \x20in\x20order\x20to\x20notify\x20the\x20guest\x20kernel\x20about\x20ho\
+    t-add\x20memory\x20event\n\n\r\n\x05\x04/\x02\0\x04\x12\x04\x94\x03\x08\
+    \x10\n\r\n\x05\x04/\x02\0\x05\x12\x04\x94\x03\x11\x17\n\r\n\x05\x04/\x02\
+    \0\x01\x12\x04\x94\x03\x18+\n\r\n\x05\x04/\x02\0\x03\x12\x04\x94\x03./\n\
+    \x0c\n\x02\x040\x12\x06\x97\x03\0\x9c\x03\x01\n\x0b\n\x03\x040\x01\x12\
+    \x04\x97\x03\x08\x1f\n/\n\x04\x040\x02\0\x12\x04\x99\x03\x08\x16\x1a!\
+    \x20Sec\x20the\x20second\x20since\x20the\x20Epoch.\n\n\x0f\n\x05\x040\
+    \x02\0\x04\x12\x06\x99\x03\x08\x97\x03!\n\r\n\x05\x040\x02\0\x05\x12\x04\
+    \x99\x03\x08\r\n\r\n\x05\x040\x02\0\x01\x12\x04\x99\x03\x0e\x11\n\r\n\
+    \x05\x040\x02\0\x03\x12\x04\x99\x03\x14\x15\nF\n\x04\x040\x02\x01\x12\
+    \x04\x9b\x03\x08\x17\x1a8\x20Usec\x20the\x20microseconds\x20portion\x20o\
+    f\x20time\x20since\x20the\x20Epoch.\n\n\x0f\n\x05\x040\x02\x01\x04\x12\
+    \x06\x9b\x03\x08\x99\x03\x16\n\r\n\x05\x040\x02\x01\x05\x12\x04\x9b\x03\
+    \x08\r\n\r\n\x05\x040\x02\x01\x01\x12\x04\x9b\x03\x0e\x12\n\r\n\x05\x040\
+    \x02\x01\x03\x12\x04\x9b\x03\x15\x16\n\xa3\x01\n\x02\x041\x12\x06\xa0\
+    \x03\0\xba\x03\x01\x1a\x94\x01\x20Storage\x20represents\x20both\x20the\
+    \x20rootfs\x20of\x20the\x20container,\x20and\x20any\x20volume\x20that\n\
+    \x20could\x20have\x20been\x20defined\x20through\x20the\x20Mount\x20list\
+    \x20of\x20the\x20OCI\x20specification.\n\n\x0b\n\x03\x041\x01\x12\x04\
+    \xa0\x03\x08\x0f\n\x8b\x02\n\x04\x041\x02\0\x12\x04\xa5\x03\x08\x1a\x1a\
+    \xfc\x01\x20Driver\x20is\x20used\x20to\x20define\x20the\x20way\x20the\
+    \x20storage\x20is\x20passed\x20through\x20the\n\x20virtual\x20machine.\
+    \x20It\x20can\x20be\x20\"9p\",\x20\"blk\",\x20or\x20something\x20else,\
+    \x20but\x20for\n\x20all\x20cases,\x20this\x20will\x20define\x20if\x20som\
+    e\x20extra\x20steps\x20are\x20required\x20before\n\x20this\x20storage\
+    \x20gets\x20mounted\x20into\x20the\x20container.\n\n\x0f\n\x05\x041\x02\
+    \0\x04\x12\x06\xa5\x03\x08\xa0\x03\x11\n\r\n\x05\x041\x02\0\x05\x12\x04\
+    \xa5\x03\x08\x0e\n\r\n\x05\x041\x02\0\x01\x12\x04\xa5\x03\x0f\x15\n\r\n\
+    \x05\x041\x02\0\x03\x12\x04\xa5\x03\x18\x19\n\xd0\x01\n\x04\x041\x02\x01\
+    \x12\x04\xa9\x03\x08+\x1a\xc1\x01\x20DriverOptions\x20allows\x20the\x20c\
+    aller\x20to\x20define\x20a\x20list\x20of\x20options\x20such\n\x20as\x20b\
+    lock\x20sizes,\x20numbers\x20of\x20luns,\x20...\x20which\x20are\x20very\
+    \x20specific\x20to\n\x20every\x20device\x20and\x20cannot\x20be\x20genera\
+    lized\x20through\x20extra\x20fields.\n\n\r\n\x05\x041\x02\x01\x04\x12\
+    \x04\xa9\x03\x08\x10\n\r\n\x05\x041\x02\x01\x05\x12\x04\xa9\x03\x11\x17\
+    \n\r\n\x05\x041\x02\x01\x01\x12\x04\xa9\x03\x18&\n\r\n\x05\x041\x02\x01\
+    \x03\x12\x04\xa9\x03)*\n\xce\x02\n\x04\x041\x02\x02\x12\x04\xaf\x03\x08\
+    \x1a\x1a\xbf\x02\x20Source\x20can\x20be\x20anything\x20representing\x20t\
+    he\x20source\x20of\x20the\x20storage.\x20This\n\x20will\x20be\x20handled\
+    \x20by\x20the\x20proper\x20handler\x20based\x20on\x20the\x20Driver\x20us\
+    ed.\n\x20For\x20instance,\x20it\x20can\x20be\x20a\x20very\x20simple\x20p\
+    ath\x20if\x20the\x20caller\x20knows\x20the\n\x20name\x20of\x20device\x20\
+    inside\x20the\x20VM,\x20or\x20it\x20can\x20be\x20some\x20sort\x20of\x20i\
+    dentifier\n\x20to\x20let\x20the\x20agent\x20find\x20the\x20device\x20ins\
+    ide\x20the\x20VM.\n\n\x0f\n\x05\x041\x02\x02\x04\x12\x06\xaf\x03\x08\xa9\
+    \x03+\n\r\n\x05\x041\x02\x02\x05\x12\x04\xaf\x03\x08\x0e\n\r\n\x05\x041\
+    \x02\x02\x01\x12\x04\xaf\x03\x0f\x15\n\r\n\x05\x041\x02\x02\x03\x12\x04\
+    \xaf\x03\x18\x19\n\xdb\x01\n\x04\x041\x02\x03\x12\x04\xb3\x03\x08\x1a\
+    \x1a\xcc\x01\x20Fstype\x20represents\x20the\x20filesystem\x20that\x20nee\
+    ds\x20to\x20be\x20used\x20to\x20mount\x20the\n\x20storage\x20inside\x20t\
+    he\x20VM.\x20For\x20instance,\x20it\x20could\x20be\x20\"xfs\"\x20for\x20\
+    block\n\x20device,\x20\"9p\"\x20for\x20shared\x20filesystem,\x20or\x20\"\
+    tmpfs\"\x20for\x20shared\x20/dev/shm.\n\n\x0f\n\x05\x041\x02\x03\x04\x12\
+    \x06\xb3\x03\x08\xaf\x03\x1a\n\r\n\x05\x041\x02\x03\x05\x12\x04\xb3\x03\
+    \x08\x0e\n\r\n\x05\x041\x02\x03\x01\x12\x04\xb3\x03\x0f\x15\n\r\n\x05\
+    \x041\x02\x03\x03\x12\x04\xb3\x03\x18\x19\nw\n\x04\x041\x02\x04\x12\x04\
+    \xb6\x03\x08$\x1ai\x20Options\x20describes\x20the\x20additional\x20optio\
+    ns\x20that\x20might\x20be\x20needed\x20to\n\x20mount\x20properly\x20the\
+    \x20storage\x20filesytem.\n\n\r\n\x05\x041\x02\x04\x04\x12\x04\xb6\x03\
+    \x08\x10\n\r\n\x05\x041\x02\x04\x05\x12\x04\xb6\x03\x11\x17\n\r\n\x05\
+    \x041\x02\x04\x01\x12\x04\xb6\x03\x18\x1f\n\r\n\x05\x041\x02\x04\x03\x12\
+    \x04\xb6\x03\"#\na\n\x04\x041\x02\x05\x12\x04\xb9\x03\x08\x1f\x1aS\x20Mo\
+    untPoint\x20refers\x20to\x20the\x20path\x20where\x20the\x20storage\x20sh\
+    ould\x20be\x20mounted\n\x20inside\x20the\x20VM.\n\n\x0f\n\x05\x041\x02\
+    \x05\x04\x12\x06\xb9\x03\x08\xb6\x03$\n\r\n\x05\x041\x02\x05\x05\x12\x04\
+    \xb9\x03\x08\x0e\n\r\n\x05\x041\x02\x05\x01\x12\x04\xb9\x03\x0f\x1a\n\r\
+    \n\x05\x041\x02\x05\x03\x12\x04\xb9\x03\x1d\x1e\n\x88\x01\n\x02\x042\x12\
+    \x06\xbe\x03\0\xde\x03\x01\x1az\x20Device\x20represents\x20only\x20the\
+    \x20devices\x20that\x20could\x20have\x20been\x20defined\x20through\x20th\
+    e\n\x20Linux\x20Device\x20list\x20of\x20the\x20OCI\x20specification.\n\n\
+    \x0b\n\x03\x042\x01\x12\x04\xbe\x03\x08\x0e\n\xb0\x01\n\x04\x042\x02\0\
+    \x12\x04\xc2\x03\x08\x16\x1a\xa1\x01\x20Id\x20can\x20be\x20used\x20to\
+    \x20identify\x20the\x20device\x20inside\x20the\x20VM.\x20Some\x20devices\
+    \n\x20might\x20not\x20need\x20it\x20to\x20be\x20identified\x20on\x20the\
+    \x20VM,\x20and\x20will\x20rely\x20on\x20the\n\x20provided\x20VmPath\x20i\
+    nstead.\n\n\x0f\n\x05\x042\x02\0\x04\x12\x06\xc2\x03\x08\xbe\x03\x10\n\r\
+    \n\x05\x042\x02\0\x05\x12\x04\xc2\x03\x08\x0e\n\r\n\x05\x042\x02\0\x01\
+    \x12\x04\xc2\x03\x0f\x11\n\r\n\x05\x042\x02\0\x03\x12\x04\xc2\x03\x14\
+    \x15\n\xbd\x01\n\x04\x042\x02\x01\x12\x04\xc7\x03\x08\x18\x1a\xae\x01\
+    \x20Type\x20defines\x20the\x20type\x20of\x20device\x20described.\x20This\
+    \x20can\x20be\x20\"blk\",\n\x20\"scsi\",\x20\"vfio\",\x20...\n\x20Partic\
+    ularly,\x20this\x20should\x20be\x20used\x20to\x20trigger\x20the\x20use\
+    \x20of\x20the\n\x20appropriate\x20device\x20handler.\n\n\x0f\n\x05\x042\
+    \x02\x01\x04\x12\x06\xc7\x03\x08\xc2\x03\x16\n\r\n\x05\x042\x02\x01\x05\
+    \x12\x04\xc7\x03\x08\x0e\n\r\n\x05\x042\x02\x01\x01\x12\x04\xc7\x03\x0f\
+    \x13\n\r\n\x05\x042\x02\x01\x03\x12\x04\xc7\x03\x16\x17\n\xab\x02\n\x04\
+    \x042\x02\x02\x12\x04\xcd\x03\x08\x1b\x1a\x9c\x02\x20VmPath\x20can\x20be\
+    \x20used\x20by\x20the\x20caller\x20to\x20provide\x20directly\x20the\x20p\
+    ath\x20of\n\x20the\x20device\x20as\x20it\x20will\x20appear\x20inside\x20\
+    the\x20VM.\x20For\x20some\x20devices,\x20the\n\x20device\x20id\x20or\x20\
+    the\x20list\x20of\x20options\x20passed\x20might\x20not\x20be\x20enough\
+    \x20to\x20find\n\x20the\x20device.\x20In\x20those\x20cases,\x20the\x20ca\
+    ller\x20should\x20predict\x20and\x20provide\n\x20this\x20vm_path.\n\n\
+    \x0f\n\x05\x042\x02\x02\x04\x12\x06\xcd\x03\x08\xc7\x03\x18\n\r\n\x05\
+    \x042\x02\x02\x05\x12\x04\xcd\x03\x08\x0e\n\r\n\x05\x042\x02\x02\x01\x12\
+    \x04\xcd\x03\x0f\x16\n\r\n\x05\x042\x02\x02\x03\x12\x04\xcd\x03\x19\x1a\
+    \n\xd4\x05\n\x04\x042\x02\x03\x12\x04\xd9\x03\x08\"\x1a\xc5\x05\x20Conta\
+    inerPath\x20defines\x20the\x20path\x20where\x20the\x20device\x20should\
+    \x20be\x20found\x20inside\n\x20the\x20container.\x20This\x20path\x20shou\
+    ld\x20match\x20the\x20path\x20of\x20the\x20device\x20from\n\x20the\x20de\
+    vice\x20list\x20listed\x20inside\x20the\x20OCI\x20spec.\x20This\x20is\
+    \x20used\x20in\x20order\n\x20to\x20identify\x20the\x20right\x20device\
+    \x20in\x20the\x20spec\x20and\x20update\x20it\x20with\x20the\n\x20right\
+    \x20options\x20such\x20as\x20major/minor\x20numbers\x20as\x20they\x20app\
+    ear\x20inside\n\x20the\x20VM\x20for\x20instance.\x20Note\x20that\x20an\
+    \x20empty\x20ctr_path\x20should\x20be\x20used\n\x20to\x20make\x20sure\
+    \x20the\x20device\x20handler\x20inside\x20the\x20agent\x20is\x20called,\
+    \x20but\n\x20no\x20spec\x20update\x20needs\x20to\x20be\x20performed.\x20\
+    This\x20has\x20to\x20happen\x20for\x20the\n\x20case\x20of\x20rootfs,\x20\
+    when\x20a\x20device\x20has\x20to\x20be\x20waited\x20for\x20after\x20it\
+    \x20has\n\x20been\x20hotplugged.\x20An\x20equivalent\x20Storage\x20entry\
+    \x20should\x20be\x20defined\x20if\n\x20any\x20mount\x20needs\x20to\x20be\
+    \x20performed\x20afterwards.\n\n\x0f\n\x05\x042\x02\x03\x04\x12\x06\xd9\
+    \x03\x08\xcd\x03\x1b\n\r\n\x05\x042\x02\x03\x05\x12\x04\xd9\x03\x08\x0e\
+    \n\r\n\x05\x042\x02\x03\x01\x12\x04\xd9\x03\x0f\x1d\n\r\n\x05\x042\x02\
+    \x03\x03\x12\x04\xd9\x03\x20!\n\xca\x01\n\x04\x042\x02\x04\x12\x04\xdd\
+    \x03\x08$\x1a\xbb\x01\x20Options\x20allows\x20the\x20caller\x20to\x20def\
+    ine\x20a\x20list\x20of\x20options\x20such\x20as\x20block\n\x20sizes,\x20\
+    numbers\x20of\x20luns,\x20...\x20which\x20are\x20very\x20specific\x20to\
+    \x20every\x20device\n\x20and\x20cannot\x20be\x20generalized\x20through\
+    \x20extra\x20fields.\n\n\r\n\x05\x042\x02\x04\x04\x12\x04\xdd\x03\x08\
+    \x10\n\r\n\x05\x042\x02\x04\x05\x12\x04\xdd\x03\x11\x17\n\r\n\x05\x042\
+    \x02\x04\x01\x12\x04\xdd\x03\x18\x1f\n\r\n\x05\x042\x02\x04\x03\x12\x04\
+    \xdd\x03\"#\n\x0c\n\x02\x043\x12\x06\xe0\x03\0\xe4\x03\x01\n\x0b\n\x03\
+    \x043\x01\x12\x04\xe0\x03\x08\x12\n\x0c\n\x04\x043\x02\0\x12\x04\xe1\x03\
+    \x08\x17\n\x0f\n\x05\x043\x02\0\x04\x12\x06\xe1\x03\x08\xe0\x03\x14\n\r\
+    \n\x05\x043\x02\0\x05\x12\x04\xe1\x03\x08\x0e\n\r\n\x05\x043\x02\0\x01\
+    \x12\x04\xe1\x03\x0f\x12\n\r\n\x05\x043\x02\0\x03\x12\x04\xe1\x03\x15\
+    \x16\n\x0c\n\x04\x043\x02\x01\x12\x04\xe2\x03\x08\x17\n\x0f\n\x05\x043\
+    \x02\x01\x04\x12\x06\xe2\x03\x08\xe1\x03\x17\n\r\n\x05\x043\x02\x01\x05\
+    \x12\x04\xe2\x03\x08\x0e\n\r\n\x05\x043\x02\x01\x01\x12\x04\xe2\x03\x0f\
+    \x12\n\r\n\x05\x043\x02\x01\x03\x12\x04\xe2\x03\x15\x16\n\x0c\n\x04\x043\
+    \x02\x02\x12\x04\xe3\x03\x08+\n\r\n\x05\x043\x02\x02\x04\x12\x04\xe3\x03\
+    \x08\x10\n\r\n\x05\x043\x02\x02\x05\x12\x04\xe3\x03\x11\x17\n\r\n\x05\
+    \x043\x02\x02\x01\x12\x04\xe3\x03\x18&\n\r\n\x05\x043\x02\x02\x03\x12\
+    \x04\xe3\x03)*\n\x0c\n\x02\x044\x12\x06\xe6\x03\0\xfa\x03\x01\n\x0b\n\
+    \x03\x044\x01\x12\x04\xe6\x03\x08\x17\nj\n\x04\x044\x02\0\x12\x04\xe9\
+    \x03\x08\x18\x1a\\\x20Path\x20is\x20the\x20destination\x20file\x20in\x20\
+    the\x20guest.\x20It\x20must\x20be\x20absolute,\n\x20canonical\x20and\x20\
+    below\x20/run.\n\n\x0f\n\x05\x044\x02\0\x04\x12\x06\xe9\x03\x08\xe6\x03\
+    \x19\n\r\n\x05\x044\x02\0\x05\x12\x04\xe9\x03\x08\x0e\n\r\n\x05\x044\x02\
+    \0\x01\x12\x04\xe9\x03\x0f\x13\n\r\n\x05\x044\x02\0\x03\x12\x04\xe9\x03\
+    \x16\x17\n\xbd\x01\n\x04\x044\x02\x01\x12\x04\xed\x03\x08\x1c\x1a\xae\
+    \x01\x20FileSize\x20is\x20the\x20expected\x20file\x20size,\x20for\x20sec\
+    urity\x20reasons\x20write\x20operations\n\x20are\x20made\x20in\x20a\x20t\
+    emporary\x20file,\x20once\x20it\x20has\x20the\x20expected\x20size,\x20it\
+    's\x20moved\n\x20to\x20the\x20destination\x20path.\n\n\x0f\n\x05\x044\
+    \x02\x01\x04\x12\x06\xed\x03\x08\xe9\x03\x18\n\r\n\x05\x044\x02\x01\x05\
+    \x12\x04\xed\x03\x08\r\n\r\n\x05\x044\x02\x01\x01\x12\x04\xed\x03\x0e\
+    \x17\n\r\n\x05\x044\x02\x01\x03\x12\x04\xed\x03\x1a\x1b\n*\n\x04\x044\
+    \x02\x02\x12\x04\xef\x03\x08\x1d\x1a\x1c\x20FileMode\x20is\x20the\x20fil\
+    e\x20mode.\n\n\x0f\n\x05\x044\x02\x02\x04\x12\x06\xef\x03\x08\xed\x03\
+    \x1c\n\r\n\x05\x044\x02\x02\x05\x12\x04\xef\x03\x08\x0e\n\r\n\x05\x044\
+    \x02\x02\x01\x12\x04\xef\x03\x0f\x18\n\r\n\x05\x044\x02\x02\x03\x12\x04\
+    \xef\x03\x1b\x1c\nS\n\x04\x044\x02\x03\x12\x04\xf1\x03\x08\x1c\x1aE\x20D\
+    irMode\x20is\x20the\x20mode\x20for\x20the\x20parent\x20directories\x20of\
+    \x20destination\x20path.\n\n\x0f\n\x05\x044\x02\x03\x04\x12\x06\xf1\x03\
+    \x08\xef\x03\x1d\n\r\n\x05\x044\x02\x03\x05\x12\x04\xf1\x03\x08\x0e\n\r\
+    \n\x05\x044\x02\x03\x01\x12\x04\xf1\x03\x0f\x17\n\r\n\x05\x044\x02\x03\
+    \x03\x12\x04\xf1\x03\x1a\x1b\n+\n\x04\x044\x02\x04\x12\x04\xf3\x03\x08\
+    \x16\x1a\x1d\x20Uid\x20is\x20the\x20numeric\x20user\x20id.\n\n\x0f\n\x05\
+    \x044\x02\x04\x04\x12\x06\xf3\x03\x08\xf1\x03\x1c\n\r\n\x05\x044\x02\x04\
+    \x05\x12\x04\xf3\x03\x08\r\n\r\n\x05\x044\x02\x04\x01\x12\x04\xf3\x03\
+    \x0e\x11\n\r\n\x05\x044\x02\x04\x03\x12\x04\xf3\x03\x14\x15\n,\n\x04\x04\
+    4\x02\x05\x12\x04\xf5\x03\x08\x16\x1a\x1e\x20Gid\x20is\x20the\x20numeric\
+    \x20group\x20id.\n\n\x0f\n\x05\x044\x02\x05\x04\x12\x06\xf5\x03\x08\xf3\
+    \x03\x16\n\r\n\x05\x044\x02\x05\x05\x12\x04\xf5\x03\x08\r\n\r\n\x05\x044\
+    \x02\x05\x01\x12\x04\xf5\x03\x0e\x11\n\r\n\x05\x044\x02\x05\x03\x12\x04\
+    \xf5\x03\x14\x15\n4\n\x04\x044\x02\x06\x12\x04\xf7\x03\x08\x19\x1a&\x20O\
+    ffset\x20for\x20the\x20next\x20write\x20operation.\n\n\x0f\n\x05\x044\
+    \x02\x06\x04\x12\x06\xf7\x03\x08\xf5\x03\x16\n\r\n\x05\x044\x02\x06\x05\
+    \x12\x04\xf7\x03\x08\r\n\r\n\x05\x044\x02\x06\x01\x12\x04\xf7\x03\x0e\
+    \x14\n\r\n\x05\x044\x02\x06\x03\x12\x04\xf7\x03\x17\x18\n6\n\x04\x044\
+    \x02\x07\x12\x04\xf9\x03\x08\x17\x1a(\x20Data\x20to\x20write\x20in\x20th\
+    e\x20destination\x20file.\n\n\x0f\n\x05\x044\x02\x07\x04\x12\x06\xf9\x03\
+    \x08\xf7\x03\x19\n\r\n\x05\x044\x02\x07\x05\x12\x04\xf9\x03\x08\r\n\r\n\
+    \x05\x044\x02\x07\x01\x12\x04\xf9\x03\x0e\x12\n\r\n\x05\x044\x02\x07\x03\
+    \x12\x04\xf9\x03\x15\x16\n\x0c\n\x02\x045\x12\x06\xfc\x03\0\xfd\x03\x01\
+    \n\x0b\n\x03\x045\x01\x12\x04\xfc\x03\x08\x1b\n\x0c\n\x02\x046\x12\x06\
+    \xff\x03\0\x80\x04\x01\n\x0b\n\x03\x046\x01\x12\x04\xff\x03\x08\x1a\n\n\
+    \n\x02\x047\x12\x04\x82\x04\0\x1d\n\x0b\n\x03\x047\x01\x12\x04\x82\x04\
+    \x08\x1a\n\x0c\n\x02\x048\x12\x06\x84\x04\0\x86\x04\x01\n\x0b\n\x03\x048\
+    \x01\x12\x04\x84\x04\x08\x10\n\x0c\n\x04\x048\x02\0\x12\x04\x85\x04\x08\
+    \x20\n\x0f\n\x05\x048\x02\0\x04\x12\x06\x85\x04\x08\x84\x04\x12\n\r\n\
+    \x05\x048\x02\0\x05\x12\x04\x85\x04\x08\x0e\n\r\n\x05\x048\x02\0\x01\x12\
+    \x04\x85\x04\x0f\x1b\n\r\n\x05\x048\x02\0\x03\x12\x04\x85\x04\x1e\x1fb\
+    \x06proto3\
 ";
 
 static mut file_descriptor_proto_lazy: ::protobuf::lazy::Lazy<::protobuf::descriptor::FileDescriptorProto> = ::protobuf::lazy::Lazy::INIT;
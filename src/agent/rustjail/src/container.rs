@@ -377,6 +377,14 @@ fn do_init_child(cwfd: RawFd) -> Result<()> {
     let cm_str = std::str::from_utf8(&buf)?;
     let cm: FsManager = serde_json::from_str(cm_str)?;
 
+    let mut state = OCIState::default();
+    if init {
+        // the state is passed to the startContainer hooks
+        let buf = read_sync(crfd)?;
+        let state_str = std::str::from_utf8(&buf)?;
+        state = serde_json::from_str(state_str)?;
+    }
+
     let p = if spec.process.is_some() {
         spec.process.as_ref().unwrap()
     } else {
@@ -641,6 +649,18 @@ fn do_init_child(cwfd: RawFd) -> Result<()> {
         unistd::close(fifofd)?;
         let mut buf: &mut [u8] = &mut [0];
         unistd::read(fd, &mut buf)?;
+
+        // run the startContainer hooks in the container namespaces, once
+        // the container has been started and before its process is executed
+        if spec.hooks.is_some() {
+            let logger = Logger::root(slog::Discard, o!());
+            state.pid = unistd::getpid().as_raw();
+            state.status = format!("{:?}", Status::CREATED);
+            let hooks = spec.hooks.as_ref().unwrap();
+            for h in hooks.start_container.iter() {
+                execute_hook(&logger, h, &state)?;
+            }
+        }
     }
 
     do_exec(&args);
@@ -1157,6 +1177,12 @@ fn join_namespaces(
     let cm_str = serde_json::to_string(cm)?;
     write_sync(pwfd, SYNC_DATA, cm_str.as_str())?;
 
+    if p.init {
+        info!(logger, "send oci state from parent to child");
+        let state_str = serde_json::to_string(st)?;
+        write_sync(pwfd, SYNC_DATA, state_str.as_str())?;
+    }
+
     //wait child setup user namespace
     info!(logger, "wait child setup user namespace");
     read_sync(prfd)?;
@@ -195,10 +195,13 @@ fn hooks_grpc_to_oci(h: &grpcHooks) -> ociHooks {
 
     let poststop = hook_grpc_to_oci(h.Poststop.as_ref());
 
+    let start_container = hook_grpc_to_oci(h.StartContainer.as_ref());
+
     ociHooks {
         prestart,
         poststart,
         poststop,
+        start_container,
     }
 }
 
@@ -4,15 +4,26 @@
 //
 
 use libc::{c_uint, major, minor};
+use nix::pty;
+use nix::sys::socket::{self, AddressFamily, SockAddr, SockFlag, SockType};
 use nix::sys::stat;
+use nix::sys::termios;
+use nix::unistd;
 use std::collections::HashMap;
-use std::fs;
+use std::fs::{self, File};
+use std::io::{self, Read, Write};
 use std::os::unix::fs::MetadataExt;
+use std::os::unix::io::{FromRawFd, RawFd};
 use std::path::Path;
 use std::sync::{mpsc, Arc, Mutex};
+use std::thread;
+use std::time::{Duration, Instant};
 
 use crate::linux_abi::*;
-use crate::mount::{DRIVERBLKTYPE, DRIVERMMIOBLKTYPE, DRIVERNVDIMMTYPE, DRIVERSCSITYPE};
+use crate::mount::{
+    DRIVERBLKTYPE, DRIVERCHARSERIALTYPE, DRIVERCHARVSOCKTYPE, DRIVERMMIOBLKTYPE, DRIVERNVDIMMTYPE,
+    DRIVERSCSITYPE, DRIVERVFIOTYPE,
+};
 use crate::sandbox::Sandbox;
 use crate::{AGENT_CONFIG, GLOBAL_DEVICE_WATCHER};
 use oci::{LinuxDeviceCgroup, LinuxResources, Spec};
@@ -28,6 +39,9 @@ macro_rules! sl {
 
 const VM_ROOTFS: &str = "/";
 
+// PCI_MAX_SLOT is the highest slot number on a PCI bus.
+const PCI_MAX_SLOT: u8 = 0x1f;
+
 // DeviceHandler is the type of callback to be defined to handle every type of device driver.
 type DeviceHandler = fn(&Device, &mut Spec, &Arc<Mutex<Sandbox>>) -> Result<()>;
 
@@ -40,6 +54,9 @@ lazy_static! {
         m.insert(DRIVERMMIOBLKTYPE, virtiommio_blk_device_handler);
         m.insert(DRIVERNVDIMMTYPE, virtio_nvdimm_device_handler);
         m.insert(DRIVERSCSITYPE, virtio_scsi_device_handler);
+        m.insert(DRIVERVFIOTYPE, vfio_device_handler);
+        m.insert(DRIVERCHARSERIALTYPE, char_serial_device_handler);
+        m.insert(DRIVERCHARVSOCKTYPE, char_vsock_device_handler);
         m
     };
 }
@@ -53,59 +70,72 @@ pub fn online_device(path: &str) -> Result<()> {
     Ok(())
 }
 
-// get_pci_device_address fetches the complete PCI address in sysfs, based on the PCI
-// identifier provided. This should be in the format: "bridgeAddr/deviceAddr".
-// Here, bridgeAddr is the address at which the bridge is attached on the root bus,
-// while deviceAddr is the address at which the device is attached on the bridge.
-fn get_pci_device_address(pci_id: &str) -> Result<String> {
-    let tokens: Vec<&str> = pci_id.split("/").collect();
-
-    if tokens.len() != 2 {
-        return Err(ErrorKind::ErrorCode(format!(
-            "PCI Identifier for device should be of format [bridgeAddr/deviceAddr], got {}",
-            pci_id
-        ))
-        .into());
-    }
-
-    let bridge_id = tokens[0];
-    let device_id = tokens[1];
-
-    // Deduce the complete bridge address based on the bridge address identifier passed
-    // and the fact that bridges are attached on the main bus with function 0.
-    let pci_bridge_addr = format!("0000:00:{}.0", bridge_id);
+// parse_pci_path parses a PCI path, the list of hexadecimal slots separated by
+// "/" leading from the root bus to the device, e.g. "02/03".
+fn parse_pci_path(pci_path: &str) -> Result<Vec<u8>> {
+    let slots: Vec<u8> = pci_path
+        .split('/')
+        .map(|s| match u8::from_str_radix(s, 16) {
+            Ok(slot) if s.len() <= 2 && slot <= PCI_MAX_SLOT => Ok(slot),
+            _ => Err(ErrorKind::ErrorCode(format!(
+                "PCI path for device should be of format [slot[/slot]...], got {}",
+                pci_path
+            ))),
+        })
+        .collect::<std::result::Result<_, _>>()?;
+
+    Ok(slots)
+}
 
-    // Find out the bus exposed by bridge
-    let bridge_bus_path = format!("{}/{}/pci_bus/", SYSFS_PCI_BUS_PREFIX, pci_bridge_addr);
+// pci_path_to_sysfs resolves a PCI path into the sysfs address of the device,
+// relative to the sysfs PCI devices directory. Every slot but the last one is a
+// bridge, and the next slot is on the bus exposed by this bridge: the bus is
+// found in the pci_bus directory of the bridge. We do not pass devices as
+// multifunction, hence the trailing 0 in the addresses.
+fn pci_path_to_sysfs(sysfs_pci_devices: &str, pci_path: &str) -> Result<String> {
+    let slots = parse_pci_path(pci_path)?;
+
+    let mut bus = "0000:00".to_string();
+    let mut addrs: Vec<String> = Vec::new();
+
+    for (i, slot) in slots.iter().enumerate() {
+        let addr = format!("{}:{:02x}.0", bus, slot);
+
+        if i < slots.len() - 1 {
+            let bridge_bus_path = format!("{}/{}/pci_bus/", sysfs_pci_devices, addr);
+            let entries: Vec<_> = fs::read_dir(&bridge_bus_path)?
+                .map(|res| res.map(|e| e.file_name()))
+                .collect::<std::result::Result<_, io::Error>>()?;
+
+            if entries.len() != 1 {
+                return Err(ErrorKind::ErrorCode(format!(
+                    "Expected an entry for bus in {}, got {} entries instead",
+                    bridge_bus_path,
+                    entries.len()
+                ))
+                .into());
+            }
 
-    let files_slice: Vec<_> = fs::read_dir(&bridge_bus_path)
-        .unwrap()
-        .map(|res| res.unwrap().path())
-        .collect();
-    let bus_num = files_slice.len();
+            bus = entries[0].to_string_lossy().into_owned();
+        }
 
-    if bus_num != 1 {
-        return Err(ErrorKind::ErrorCode(format!(
-            "Expected an entry for bus in {}, got {} entries instead",
-            bridge_bus_path, bus_num
-        ))
-        .into());
+        addrs.push(addr);
     }
 
-    let bus = files_slice[0].file_name().unwrap().to_str().unwrap();
-
-    // Device address is based on the bus of the bridge to which it is attached.
-    // We do not pass devices as multifunction, hence the trailing 0 in the address.
-    let pci_device_addr = format!("{}:{}.0", bus, device_id);
+    Ok(addrs.join("/"))
+}
 
-    let bridge_device_pci_addr = format!("{}/{}", pci_bridge_addr, pci_device_addr);
+// get_pci_device_address fetches the complete PCI address in sysfs, based on the PCI
+// path provided, see pci_path_to_sysfs().
+fn get_pci_device_address(pci_id: &str) -> Result<String> {
+    let pci_addr = pci_path_to_sysfs(SYSFS_PCI_BUS_PREFIX, pci_id)?;
 
     info!(
         sl!(),
-        "Fetched PCI address for device PCIAddr:{}\n", bridge_device_pci_addr
+        "Fetched PCI address for device PCIAddr:{}\n", pci_addr
     );
 
-    Ok(bridge_device_pci_addr)
+    Ok(pci_addr)
 }
 
 fn get_device_name(sandbox: &Arc<Mutex<Sandbox>>, dev_addr: &str) -> Result<String> {
@@ -289,9 +319,10 @@ fn virtiommio_blk_device_handler(
     update_spec_device_list(device, spec)
 }
 
-// device.Id should be the PCI address in the format  "bridgeAddr/deviceAddr".
-// Here, bridgeAddr is the address at which the brige is attached on the root bus,
-// while deviceAddr is the address at which the device is attached on the bridge.
+// device.Id should be the PCI path of the device, in the format "slot[/slot]...",
+// e.g. "bridgeAddr/deviceAddr" where bridgeAddr is the address at which the
+// bridge is attached on the root bus, while deviceAddr is the address at which
+// the device is attached on the bridge.
 fn virtio_blk_device_handler(
     device: &Device,
     spec: &mut Spec,
@@ -325,6 +356,376 @@ fn virtio_nvdimm_device_handler(
     update_spec_device_list(device, spec)
 }
 
+// wait_for_pci_device waits for the device at the given PCI path to show up
+// in sysfs, and returns its sysfs path.
+fn wait_for_pci_device(pci_id: &str) -> Result<String> {
+    let pci_addr = get_pci_device_address(pci_id)?;
+    let dev_path = format!("{}/{}", SYSFS_PCI_BUS_PREFIX, pci_addr);
+
+    rescan_pci_bus()?;
+
+    let hotplug_timeout = AGENT_CONFIG.read().unwrap().hotplug_timeout;
+    let start = Instant::now();
+    while !Path::new(&dev_path).exists() {
+        if start.elapsed() > hotplug_timeout {
+            return Err(ErrorKind::ErrorCode(format!(
+                "Timeout reached after {:?} waiting for PCI device {}",
+                hotplug_timeout, pci_id
+            ))
+            .into());
+        }
+        thread::sleep(Duration::from_millis(100));
+    }
+
+    Ok(dev_path)
+}
+
+// wait_for_virtio_device waits for the virtio device at the given PCI path
+// to be bound to its driver, so that it can be used, e.g. to mount a
+// virtio-fs filesystem by its tag.
+pub fn wait_for_virtio_device(pci_id: &str) -> Result<()> {
+    let dev_path = wait_for_pci_device(pci_id)?;
+
+    let bound = |dev_path: &str| -> bool {
+        fs::read_dir(dev_path)
+            .map(|entries| {
+                entries.filter_map(|e| e.ok()).any(|e| {
+                    e.file_name().to_string_lossy().starts_with("virtio")
+                        && e.path().join("driver").exists()
+                })
+            })
+            .unwrap_or(false)
+    };
+
+    let hotplug_timeout = AGENT_CONFIG.read().unwrap().hotplug_timeout;
+    let start = Instant::now();
+    while !bound(&dev_path) {
+        if start.elapsed() > hotplug_timeout {
+            return Err(ErrorKind::ErrorCode(format!(
+                "Timeout reached after {:?} waiting for virtio device {}",
+                hotplug_timeout, pci_id
+            ))
+            .into());
+        }
+        thread::sleep(Duration::from_millis(100));
+    }
+
+    Ok(())
+}
+
+// wait_for_pmem_device waits for the node of the hotplugged NVDIMM device
+// dev_path, e.g. /dev/pmem1, to show up.
+pub fn wait_for_pmem_device(dev_path: &str) -> Result<()> {
+    let hotplug_timeout = AGENT_CONFIG.read().unwrap().hotplug_timeout;
+    let start = Instant::now();
+    while !Path::new(dev_path).exists() {
+        if start.elapsed() > hotplug_timeout {
+            return Err(ErrorKind::ErrorCode(format!(
+                "Timeout reached after {:?} waiting for NVDIMM device {}",
+                hotplug_timeout, dev_path
+            ))
+            .into());
+        }
+        thread::sleep(Duration::from_millis(100));
+    }
+
+    Ok(())
+}
+
+// pci_iommu_group returns the IOMMU group of the PCI device at dev_path, or
+// None if the guest has no IOMMU.
+fn pci_iommu_group(dev_path: &str) -> Result<Option<String>> {
+    match fs::read_link(format!("{}/iommu_group", dev_path)) {
+        Ok(group) => Ok(group
+            .file_name()
+            .and_then(|name| name.to_str())
+            .map(|name| name.to_string())),
+        Err(e) if e.kind() == io::ErrorKind::NotFound => Ok(None),
+        Err(e) => Err(e.into()),
+    }
+}
+
+// bind_to_vfio binds the PCI device at dev_path to the vfio-pci driver, so
+// that it can be used from the container through /dev/vfio.
+fn bind_to_vfio(dev_path: &str) -> Result<()> {
+    let bdf = match Path::new(dev_path)
+        .file_name()
+        .and_then(|name| name.to_str())
+    {
+        Some(bdf) => bdf.to_string(),
+        None => return Err(ErrorKind::Msg(format!("Invalid PCI device path {}", dev_path)).into()),
+    };
+
+    fs::write(format!("{}/driver_override", dev_path), "vfio-pci")?;
+
+    let driver_unbind = format!("{}/driver/unbind", dev_path);
+    if Path::new(&driver_unbind).exists() {
+        fs::write(&driver_unbind, &bdf)?;
+    }
+
+    fs::write(SYSFS_PCI_DRIVERS_PROBE_FILE, &bdf)?;
+
+    Ok(())
+}
+
+// update_spec_device_path renames the device at container_path in the OCI
+// spec device list.
+fn update_spec_device_path(spec: &mut Spec, container_path: &str, path: &str) {
+    if let Some(linux) = spec.linux.as_mut() {
+        for dev in linux.devices.iter_mut() {
+            if dev.path == container_path {
+                dev.path = path.to_string();
+            }
+        }
+    }
+}
+
+// remove_spec_device removes the device at container_path from the OCI spec
+// device list, along with the devices cgroup rules of its host node.
+fn remove_spec_device(spec: &mut Spec, container_path: &str) {
+    let linux = match spec.linux.as_mut() {
+        None => return,
+        Some(l) => l,
+    };
+
+    let removed: Vec<(i64, i64)> = linux
+        .devices
+        .iter()
+        .filter(|dev| dev.path == container_path)
+        .map(|dev| (dev.major, dev.minor))
+        .collect();
+
+    linux.devices.retain(|dev| dev.path != container_path);
+
+    if let Some(res) = linux.resources.as_mut() {
+        res.devices.retain(|d| {
+            !removed
+                .iter()
+                .any(|(major, minor)| d.major == Some(*major) && d.minor == Some(*minor))
+        });
+    }
+}
+
+// device.Options holds one "hostID=pciID" entry per function of the VFIO
+// group, where pciID is the PCI path "slot[/slot]..." of the function in
+// the guest. The functions are bound to vfio-pci, and the
+// container device is replaced by the guest /dev/vfio/<group> node.
+// When the guest has no IOMMU, the functions are left to the guest kernel
+// drivers: there is no guest VFIO group, and the host /dev/vfio node is
+// removed from the container devices.
+fn vfio_device_handler(
+    device: &Device,
+    spec: &mut Spec,
+    _sandbox: &Arc<Mutex<Sandbox>>,
+) -> Result<()> {
+    let mut group: Option<String> = None;
+
+    for opt in device.options.iter() {
+        let tokens: Vec<&str> = opt.split('=').collect();
+        if tokens.len() != 2 {
+            return Err(ErrorKind::Msg(format!(
+                "Malformed VFIO device option {}, expect hostID=pciID",
+                opt
+            ))
+            .into());
+        }
+
+        let dev_path = wait_for_pci_device(tokens[1])?;
+
+        let dev_group = match pci_iommu_group(&dev_path)? {
+            Some(g) => g,
+            None => {
+                info!(
+                    sl!(),
+                    "no IOMMU group for VFIO device {}, using the guest kernel driver", tokens[0]
+                );
+                remove_spec_device(spec, &device.container_path);
+                return Ok(());
+            }
+        };
+
+        if let Some(g) = group.as_ref() {
+            if *g != dev_group {
+                return Err(ErrorKind::Msg(format!(
+                    "VFIO device functions are in different guest IOMMU groups: {} and {}",
+                    g, dev_group
+                ))
+                .into());
+            }
+        }
+
+        bind_to_vfio(&dev_path)?;
+        group = Some(dev_group);
+    }
+
+    let group = match group {
+        Some(g) => g,
+        None => {
+            return Err(ErrorKind::Msg(format!("No function for VFIO device {:?}", device)).into())
+        }
+    };
+
+    let mut dev = device.clone();
+    dev.vm_path = format!("{}/vfio/{}", SYSTEM_DEV_PATH, group);
+
+    info!(
+        sl!(),
+        "VFIO device {} is guest IOMMU group {}", device.container_path, group
+    );
+
+    update_spec_device_list(&dev, spec)?;
+    update_spec_device_path(spec, &device.container_path, &dev.vm_path);
+
+    Ok(())
+}
+
+// find_virtio_port returns the device name of the virtio-serial port named
+// name, looking for it in the virtio_ports_path sysfs directory.
+fn find_virtio_port(virtio_ports_path: &str, name: &str) -> Result<Option<String>> {
+    let entries = match fs::read_dir(virtio_ports_path) {
+        Ok(entries) => entries,
+        Err(e) if e.kind() == io::ErrorKind::NotFound => return Ok(None),
+        Err(e) => return Err(e.into()),
+    };
+
+    for entry in entries {
+        let entry = entry?;
+
+        let port_name = match fs::read_to_string(entry.path().join("name")) {
+            Ok(port_name) => port_name,
+            Err(_) => continue,
+        };
+
+        if port_name.trim_end() == name {
+            return Ok(entry.file_name().to_str().map(|s| s.to_string()));
+        }
+    }
+
+    Ok(None)
+}
+
+// device.Id is the name of the virtio-serial port the host character device
+// is proxied to. The container device is replaced by the port device.
+fn char_serial_device_handler(
+    device: &Device,
+    spec: &mut Spec,
+    _sandbox: &Arc<Mutex<Sandbox>>,
+) -> Result<()> {
+    let hotplug_timeout = AGENT_CONFIG.read().unwrap().hotplug_timeout;
+    let start = Instant::now();
+
+    let port = loop {
+        if let Some(port) = find_virtio_port(SYSFS_VIRTIO_PORTS_PATH, &device.id)? {
+            break port;
+        }
+
+        if start.elapsed() > hotplug_timeout {
+            return Err(ErrorKind::ErrorCode(format!(
+                "Timeout reached after {:?} waiting for virtio-serial port {}",
+                hotplug_timeout, device.id
+            ))
+            .into());
+        }
+        thread::sleep(Duration::from_millis(100));
+    };
+
+    let mut dev = device.clone();
+    dev.vm_path = format!("{}/{}", SYSTEM_DEV_PATH, port);
+    update_spec_device_list(&dev, spec)
+}
+
+// char_vsock_proxy relays the connections accepted on listenfd to the master
+// end of the pseudo terminal. Only the latest connection receives the data
+// read from the terminal.
+fn char_vsock_proxy(listenfd: RawFd, master: RawFd) -> Result<()> {
+    let conn: Arc<Mutex<Option<File>>> = Arc::new(Mutex::new(None));
+
+    let reader_conn = conn.clone();
+    let mut master_reader = unsafe { File::from_raw_fd(unistd::dup(master)?) };
+    thread::spawn(move || {
+        let mut buf = [0u8; 4096];
+        loop {
+            let n = match master_reader.read(&mut buf) {
+                Ok(0) | Err(_) => break,
+                Ok(n) => n,
+            };
+
+            if let Some(writer) = reader_conn.lock().unwrap().as_mut() {
+                let _ = writer.write_all(&buf[..n]);
+            }
+        }
+    });
+
+    let mut master_writer = unsafe { File::from_raw_fd(master) };
+    loop {
+        let datafd = socket::accept4(listenfd, SockFlag::SOCK_CLOEXEC)?;
+        let mut reader = unsafe { File::from_raw_fd(datafd) };
+
+        *conn.lock().unwrap() = Some(reader.try_clone()?);
+        let _ = io::copy(&mut reader, &mut master_writer);
+        *conn.lock().unwrap() = None;
+    }
+}
+
+// device.Id is the vsock port the runtime connects to in order to proxy the
+// host character device. The container device is replaced by a pseudo
+// terminal fed by the connections to this port.
+fn char_vsock_device_handler(
+    device: &Device,
+    spec: &mut Spec,
+    _sandbox: &Arc<Mutex<Sandbox>>,
+) -> Result<()> {
+    let port = match device.id.parse::<u32>() {
+        Ok(port) => port,
+        Err(_) => {
+            return Err(ErrorKind::Msg(format!(
+                "Invalid vsock port {} for char device {}",
+                device.id, device.container_path
+            ))
+            .into())
+        }
+    };
+
+    let pseudo = pty::openpty(None, None)?;
+
+    let mut attrs = termios::tcgetattr(pseudo.slave)?;
+    termios::cfmakeraw(&mut attrs);
+    termios::tcsetattr(pseudo.slave, termios::SetArg::TCSANOW, &attrs)?;
+
+    let slave_path = fs::read_link(format!("/proc/self/fd/{}", pseudo.slave))?;
+
+    let listenfd = socket::socket(
+        AddressFamily::Vsock,
+        SockType::Stream,
+        SockFlag::SOCK_CLOEXEC,
+        None,
+    )?;
+    let addr = SockAddr::new_vsock(libc::VMADDR_CID_ANY, port);
+    socket::bind(listenfd, &addr)?;
+    socket::listen(listenfd, 1)?;
+
+    let master = pseudo.master;
+    let slave = pseudo.slave;
+    let container_path = device.container_path.clone();
+    thread::spawn(move || {
+        // Keep the slave end open, so that the master end does not hang
+        // up while no process in the container has the device open.
+        let _slave = unsafe { File::from_raw_fd(slave) };
+
+        if let Err(e) = char_vsock_proxy(listenfd, master) {
+            error!(
+                sl!(),
+                "char device {} proxy failed: {:?}", container_path, e
+            );
+        }
+        let _ = unistd::close(listenfd);
+    });
+
+    let mut dev = device.clone();
+    dev.vm_path = slave_path.to_string_lossy().to_string();
+    update_spec_device_list(&dev, spec)
+}
+
 pub fn add_devices(
     devices: &[Device],
     spec: &mut Spec,
@@ -401,7 +802,40 @@ pub fn update_device_cgroup(spec: &mut Spec) -> Result<()> {
 #[cfg(test)]
 mod tests {
     use super::*;
-    use oci::Linux;
+    use oci::{Linux, LinuxDevice};
+    use std::os::unix::fs::symlink;
+    use tempfile::tempdir;
+
+    #[test]
+    fn test_find_virtio_port() {
+        let dir = tempdir().unwrap();
+        let ports_path = dir.path().to_str().unwrap();
+
+        for (port, name) in [
+            ("vport1p1", "agent.channel.0"),
+            ("vport1p2", "kata.chardev.char-1"),
+        ]
+        .iter()
+        {
+            let port_path = dir.path().join(port);
+            fs::create_dir(&port_path).unwrap();
+            fs::write(port_path.join("name"), format!("{}\n", name)).unwrap();
+        }
+        fs::create_dir(dir.path().join("vport1p3")).unwrap();
+
+        assert_eq!(
+            find_virtio_port(ports_path, "kata.chardev.char-1").unwrap(),
+            Some("vport1p2".to_string())
+        );
+        assert_eq!(
+            find_virtio_port(ports_path, "kata.chardev.char-2").unwrap(),
+            None
+        );
+        assert_eq!(
+            find_virtio_port("/nonexistent", "agent.channel.0").unwrap(),
+            None
+        );
+    }
 
     #[test]
     fn test_update_device_cgroup() {
@@ -422,4 +856,124 @@ mod tests {
         assert_eq!(devices[0].major, Some(major));
         assert_eq!(devices[0].minor, Some(minor));
     }
+
+    #[test]
+    fn test_parse_pci_path() {
+        assert_eq!(parse_pci_path("02").unwrap(), vec![0x02]);
+        assert_eq!(parse_pci_path("02/1f/3").unwrap(), vec![0x02, 0x1f, 0x03]);
+
+        for pci_path in &["", "/", "02/", "02//03", "02/20", "002", "xy"] {
+            assert!(parse_pci_path(pci_path).is_err(), "{}", pci_path);
+        }
+    }
+
+    #[test]
+    fn test_pci_path_to_sysfs() {
+        let dir = tempdir().expect("failed to create tmpdir");
+        let sysfs = dir.path().to_str().unwrap();
+
+        // A bridge at 02 on the root bus, exposing bus 01, with a bridge
+        // at 03 exposing bus 02.
+        fs::create_dir_all(format!("{}/0000:00:02.0/pci_bus/0000:01", sysfs)).unwrap();
+        fs::create_dir_all(format!("{}/0000:01:03.0/pci_bus/0000:02", sysfs)).unwrap();
+
+        assert_eq!(pci_path_to_sysfs(sysfs, "05").unwrap(), "0000:00:05.0");
+        assert_eq!(
+            pci_path_to_sysfs(sysfs, "02/04").unwrap(),
+            "0000:00:02.0/0000:01:04.0"
+        );
+        assert_eq!(
+            pci_path_to_sysfs(sysfs, "02/03/1f").unwrap(),
+            "0000:00:02.0/0000:01:03.0/0000:02:1f.0"
+        );
+
+        // 04 is not a bridge.
+        assert!(pci_path_to_sysfs(sysfs, "02/04/01").is_err());
+    }
+
+    #[test]
+    fn test_pci_iommu_group() {
+        let dir = tempdir().expect("failed to create tmpdir");
+        let dev_path = dir.path().join("0000:01:01.0");
+        fs::create_dir(&dev_path).unwrap();
+        let dev_path = dev_path.to_str().unwrap();
+
+        // No IOMMU in the guest
+        assert_eq!(pci_iommu_group(dev_path).unwrap(), None);
+
+        symlink(
+            "../../kernel/iommu_groups/3",
+            format!("{}/iommu_group", dev_path),
+        )
+        .unwrap();
+        assert_eq!(pci_iommu_group(dev_path).unwrap(), Some("3".to_string()));
+    }
+
+    #[test]
+    fn test_update_spec_device_path() {
+        let mut spec = Spec::default();
+        let mut linux = Linux::default();
+        linux.devices = vec![
+            LinuxDevice {
+                path: "/dev/vfio/12".to_string(),
+                ..Default::default()
+            },
+            LinuxDevice {
+                path: "/dev/null".to_string(),
+                ..Default::default()
+            },
+        ];
+        spec.linux = Some(linux);
+
+        update_spec_device_path(&mut spec, "/dev/vfio/12", "/dev/vfio/3");
+
+        let devices = spec.linux.unwrap().devices;
+        assert_eq!(devices[0].path, "/dev/vfio/3");
+        assert_eq!(devices[1].path, "/dev/null");
+    }
+
+    #[test]
+    fn test_remove_spec_device() {
+        let mut spec = Spec::default();
+        let mut linux = Linux::default();
+        linux.devices = vec![
+            LinuxDevice {
+                path: "/dev/vfio/12".to_string(),
+                major: 241,
+                minor: 12,
+                ..Default::default()
+            },
+            LinuxDevice {
+                path: "/dev/null".to_string(),
+                major: 1,
+                minor: 3,
+                ..Default::default()
+            },
+        ];
+        linux.resources = Some(LinuxResources {
+            devices: vec![
+                LinuxDeviceCgroup {
+                    major: Some(241),
+                    minor: Some(12),
+                    ..Default::default()
+                },
+                LinuxDeviceCgroup {
+                    major: Some(1),
+                    minor: Some(3),
+                    ..Default::default()
+                },
+            ],
+            ..Default::default()
+        });
+        spec.linux = Some(linux);
+
+        remove_spec_device(&mut spec, "/dev/vfio/12");
+
+        let linux = spec.linux.unwrap();
+        assert_eq!(linux.devices.len(), 1);
+        assert_eq!(linux.devices[0].path, "/dev/null");
+        let devices = linux.resources.unwrap().devices;
+        assert_eq!(devices.len(), 1);
+        assert_eq!(devices[0].major, Some(1));
+    }
 }
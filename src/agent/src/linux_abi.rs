@@ -9,6 +9,7 @@ pub const SYSFS_DIR: &str = "/sys";
 
 pub const SYSFS_PCI_BUS_PREFIX: &str = "/sys/bus/pci/devices";
 pub const SYSFS_PCI_BUS_RESCAN_FILE: &str = "/sys/bus/pci/rescan";
+pub const SYSFS_PCI_DRIVERS_PROBE_FILE: &str = "/sys/bus/pci/drivers_probe";
 #[cfg(any(
     target_arch = "powerpc64le",
     target_arch = "s390x",
@@ -32,6 +33,8 @@ pub const SCSI_HOST_CHANNEL: &str = "0:0:";
 pub const SCSI_BLOCK_SUFFIX: &str = "block";
 pub const SYSFS_SCSI_HOST_PATH: &str = "/sys/class/scsi_host";
 
+pub const SYSFS_VIRTIO_PORTS_PATH: &str = "/sys/class/virtio-ports";
+
 pub const SYSFS_CGROUPPATH: &str = "/sys/fs/cgroup";
 pub const SYSFS_ONLINE_FILE: &str = "online";
 
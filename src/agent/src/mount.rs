@@ -22,7 +22,10 @@ use regex::Regex;
 use std::fs::File;
 use std::io::{BufRead, BufReader};
 
-use crate::device::{get_pci_device_name, get_scsi_device_name, online_device};
+use crate::device::{
+    get_pci_device_name, get_scsi_device_name, online_device, wait_for_pmem_device,
+    wait_for_virtio_device,
+};
 use crate::linux_abi::*;
 use crate::protocols::agent::Storage;
 use crate::Sandbox;
@@ -34,11 +37,23 @@ pub const DRIVERBLKTYPE: &str = "blk";
 pub const DRIVERMMIOBLKTYPE: &str = "mmioblk";
 pub const DRIVERSCSITYPE: &str = "scsi";
 pub const DRIVERNVDIMMTYPE: &str = "nvdimm";
+pub const DRIVERVFIOTYPE: &str = "vfio";
+pub const DRIVERCHARSERIALTYPE: &str = "char-serial";
+pub const DRIVERCHARVSOCKTYPE: &str = "char-vsock";
 pub const DRIVEREPHEMERALTYPE: &str = "ephemeral";
 pub const DRIVERLOCALTYPE: &str = "local";
+pub const DRIVEROVERLAYFSTYPE: &str = "overlayfs";
+
+// VIRTIOFSPCIPATHOPTION prefixes the driver option giving the PCI path of
+// the hotplugged virtio-fs device of a storage.
+pub const VIRTIOFSPCIPATHOPTION: &str = "pci_path=";
 
 pub const TYPEROOTFS: &str = "rootfs";
 
+// SCRATCHDIR holds the writable directories of the containers whose rootfs
+// is an overlay of image layers, one directory per container.
+pub const SCRATCHDIR: &str = "/run/kata-containers/sandbox/rootfs";
+
 #[cfg_attr(rustfmt, rustfmt_skip)]
 lazy_static! {
     pub static ref FLAGS: HashMap<&'static str, (bool, MsFlags)> = {
@@ -144,6 +159,10 @@ lazy_static! {
         m.insert(DRIVERLOCALTYPE, local);
     let scsi: StorageHandler = virtio_scsi_storage_handler;
         m.insert(DRIVERSCSITYPE, scsi);
+    let nvdimm: StorageHandler = nvdimm_storage_handler;
+        m.insert(DRIVERNVDIMMTYPE, nvdimm);
+    let overlayfs: StorageHandler = overlayfs_storage_handler;
+        m.insert(DRIVEROVERLAYFSTYPE, overlayfs);
         m
     };
 }
@@ -309,12 +328,22 @@ fn virtiommio_blk_storage_handler(
     common_storage_handler(logger, storage)
 }
 
-// virtiofs_storage_handler handles the storage for virtio-fs.
+// virtiofs_storage_handler handles the storage for virtio-fs. The storages
+// of the hotplugged virtio-fs devices are only mounted once the device is
+// bound to its driver.
 fn virtiofs_storage_handler(
     logger: &Logger,
     storage: &Storage,
     _sandbox: Arc<Mutex<Sandbox>>,
 ) -> Result<String> {
+    if let Some(opt) = storage
+        .driver_options
+        .iter()
+        .find(|o| o.starts_with(VIRTIOFSPCIPATHOPTION))
+    {
+        wait_for_virtio_device(&opt[VIRTIOFSPCIPATHOPTION.len()..])?;
+    }
+
     common_storage_handler(logger, storage)
 }
 
@@ -357,6 +386,59 @@ fn virtio_scsi_storage_handler(
     common_storage_handler(logger, &storage)
 }
 
+// nvdimm_storage_handler handles the storage for nvdimm driver. NVDIMM
+// devices hold read-only images, like container image layers, which are
+// shared by the containers of the sandbox: a device is mounted once, and
+// unmounted when the last container using it is removed.
+fn nvdimm_storage_handler(
+    logger: &Logger,
+    storage: &Storage,
+    sandbox: Arc<Mutex<Sandbox>>,
+) -> Result<String> {
+    // The sandbox lock is not held while waiting for the device, so that
+    // the hotplug events can be handled.
+    let new_storage = sandbox
+        .lock()
+        .unwrap()
+        .set_sandbox_storage(&storage.mount_point);
+
+    if !new_storage {
+        return Ok(storage.mount_point.to_string());
+    }
+
+    let res =
+        wait_for_pmem_device(&storage.source).and_then(|_| common_storage_handler(logger, storage));
+
+    if res.is_err() {
+        let _ = sandbox
+            .lock()
+            .unwrap()
+            .unset_sandbox_storage(&storage.mount_point);
+    }
+
+    res
+}
+
+// overlayfs_storage_handler handles the storage for an overlay of image
+// layers, making sure its writable directories exist.
+fn overlayfs_storage_handler(
+    logger: &Logger,
+    storage: &Storage,
+    _sandbox: Arc<Mutex<Sandbox>>,
+) -> Result<String> {
+    for opt in storage.options.iter() {
+        let mut fields = opt.splitn(2, '=');
+        match (fields.next(), fields.next()) {
+            (Some("upperdir"), Some(dir)) | (Some("workdir"), Some(dir)) => {
+                fs::create_dir_all(dir)?;
+            }
+            _ => {}
+        }
+    }
+
+    common_storage_handler(logger, storage)
+}
+
 fn common_storage_handler(logger: &Logger, storage: &Storage) -> Result<String> {
     // Mount the storage device.
     let mount_point = storage.mount_point.to_string();
@@ -31,7 +31,7 @@ use rustjail::process::ProcessOperations;
 
 use crate::device::{add_devices, rescan_pci_bus, update_device_cgroup};
 use crate::linux_abi::*;
-use crate::mount::{add_storages, remove_mounts, STORAGEHANDLERLIST};
+use crate::mount::{add_storages, remove_mounts, SCRATCHDIR, STORAGEHANDLERLIST};
 use crate::namespace::{NSTYPEIPC, NSTYPEPID, NSTYPEUTS};
 use crate::random;
 use crate::sandbox::Sandbox;
@@ -51,10 +51,11 @@ use std::thread;
 use std::time::Duration;
 
 use nix::unistd::{Gid, Uid};
+use std::collections::HashMap;
 use std::fs::{File, OpenOptions};
-use std::io::{BufRead, BufReader};
+use std::io::{BufRead, BufReader, Write};
 use std::os::unix::fs::FileExt;
-use std::path::PathBuf;
+use std::path::{Path, PathBuf};
 
 const CONTAINER_BASE: &str = "/run/kata-containers";
 const MODPROBE_PATH: &str = "/sbin/modprobe";
@@ -186,7 +187,6 @@ impl agentService {
 
     fn do_remove_container(&self, req: protocols::agent::RemoveContainerRequest) -> Result<()> {
         let cid = req.container_id.clone();
-        let mut cmounts: Vec<String> = vec![];
 
         if req.timeout == 0 {
             let s = Arc::clone(&self.sandbox);
@@ -200,25 +200,7 @@ impl agentService {
 
             ctr.destroy()?;
 
-            // Find the sandbox storage used by this container
-            let mounts = sandbox.container_mounts.get(&cid);
-            if mounts.is_some() {
-                let mounts = mounts.unwrap();
-
-                remove_mounts(&mounts)?;
-
-                for m in mounts.iter() {
-                    if sandbox.storages.get(m).is_some() {
-                        cmounts.push(m.to_string());
-                    }
-                }
-            }
-
-            for m in cmounts.iter() {
-                sandbox.unset_and_remove_sandbox_storage(m)?;
-            }
-
-            sandbox.container_mounts.remove(cid.as_str());
+            remove_container_storages(&mut sandbox, &cid)?;
             sandbox.containers.remove(cid.as_str());
 
             return Ok(());
@@ -255,25 +237,7 @@ impl agentService {
         let s = self.sandbox.clone();
         let mut sandbox = s.lock().unwrap();
 
-        // Find the sandbox storage used by this container
-        let mounts = sandbox.container_mounts.get(&cid);
-        if mounts.is_some() {
-            let mounts = mounts.unwrap();
-
-            remove_mounts(&mounts)?;
-
-            for m in mounts.iter() {
-                if sandbox.storages.get(m).is_some() {
-                    cmounts.push(m.to_string());
-                }
-            }
-        }
-
-        for m in cmounts.iter() {
-            sandbox.unset_and_remove_sandbox_storage(m)?;
-        }
-
-        sandbox.container_mounts.remove(&cid);
+        remove_container_storages(&mut sandbox, &cid)?;
         sandbox.containers.remove(cid.as_str());
 
         Ok(())
@@ -631,6 +595,16 @@ impl protocols::agent_ttrpc::AgentService for agentService {
                 resp.process_list = serde_json::to_vec(&pids).unwrap();
                 return Ok(resp);
             }
+            // the exec IDs of the processes, keyed by pid
+            "exec_ids" => {
+                let exec_ids: HashMap<i32, String> = ctr
+                    .processes
+                    .iter()
+                    .map(|(pid, p)| (*pid, p.exec_id.clone()))
+                    .collect();
+                resp.process_list = serde_json::to_vec(&exec_ids).unwrap();
+                return Ok(resp);
+            }
             _ => {
                 return Err(ttrpc::Error::RpcStatus(ttrpc::get_status(
                     ttrpc::Code::INVALID_ARGUMENT,
@@ -666,6 +640,7 @@ impl protocols::agent_ttrpc::AgentService for agentService {
 
         let mut result = String::new();
         result.push_str(lines[0].as_str());
+        result.push('\n');
 
         lines.remove(0);
         for line in &lines {
@@ -684,6 +659,7 @@ impl protocols::agent_ttrpc::AgentService for agentService {
             for p in &pids {
                 if pid == *p {
                     result.push_str(line.as_str());
+                    result.push('\n');
                 }
             }
         }
@@ -1094,6 +1070,16 @@ impl protocols::agent_ttrpc::AgentService for agentService {
                 }
             }
 
+            match set_guest_sysctls(&req.sysctls) {
+                Ok(_) => (),
+                Err(e) => {
+                    return Err(ttrpc::Error::RpcStatus(ttrpc::get_status(
+                        ttrpc::Code::INTERNAL,
+                        e.to_string(),
+                    )))
+                }
+            }
+
             match s.setup_shared_namespaces() {
                 Ok(_) => (),
                 Err(e) => {
@@ -1447,6 +1433,44 @@ pub fn start<S: Into<String>>(s: Arc<Mutex<Sandbox>>, host: S, port: u16) -> ttr
     server
 }
 
+// remove_container_storages removes the mounts of the storages of container
+// cid, and the sandbox storages no other container uses anymore.
+//
+// It's assumed that caller is calling this method after acquiring a lock on
+// sandbox.
+fn remove_container_storages(sandbox: &mut Sandbox, cid: &str) -> Result<()> {
+    let mut mounts: Vec<String> = vec![];
+    let mut storages: Vec<String> = vec![];
+
+    // Find the sandbox storage used by this container
+    if let Some(cmounts) = sandbox.container_mounts.get(cid) {
+        for m in cmounts.iter() {
+            if sandbox.storages.get(m).is_some() {
+                storages.push(m.to_string());
+            } else {
+                mounts.push(m.to_string());
+            }
+        }
+    }
+
+    // The container own mounts, like the overlay of its image layers, are
+    // removed before the sandbox storages they may rely on.
+    remove_mounts(&mounts)?;
+
+    for m in storages.iter() {
+        sandbox.unset_and_remove_sandbox_storage(m)?;
+    }
+
+    let scratch_dir = Path::new(SCRATCHDIR).join(cid);
+    if scratch_dir.exists() {
+        fs::remove_dir_all(scratch_dir)?;
+    }
+
+    sandbox.container_mounts.remove(cid);
+
+    Ok(())
+}
+
 // This function updates the container namespaces configuration based on the
 // sandbox information. When the sandbox is created, it can be setup in a way
 // that all containers will share some specific namespaces. This is the agent
@@ -1642,6 +1666,21 @@ fn setup_bundle(spec: &Spec) -> Result<PathBuf> {
     Ok(olddir)
 }
 
+// set_guest_sysctls applies the non-namespaced sysctls to the guest
+// kernel. Unlike the per-container sysctls, a missing key is an error
+// since the runtime explicitly asked for it.
+fn set_guest_sysctls(sysctls: &HashMap<String, String>) -> Result<()> {
+    for (key, value) in sysctls {
+        info!(sl!(), "set_guest_sysctls {}={}", key, value);
+
+        let name = format!("/proc/sys/{}", key.replace('.', "/"));
+        let mut file = OpenOptions::new().write(true).open(name.as_str())?;
+        file.write_all(value.as_bytes())?;
+    }
+
+    Ok(())
+}
+
 fn load_kernel_module(module: &protocols::agent::KernelModule) -> Result<()> {
     if module.name == "" {
         return Err(ErrorKind::ErrorCode("Kernel module name is empty".to_string()).into());
@@ -0,0 +1,153 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+)
+
+var configSubCmds = []cli.Command{
+	dumpConfigCommand,
+}
+
+var configCLICommand = cli.Command{
+	Name:        "config",
+	Usage:       "show the runtime configuration",
+	Subcommands: configSubCmds,
+	Action: func(context *cli.Context) {
+		cli.ShowSubcommandHelp(context)
+	},
+}
+
+var dumpConfigCommand = cli.Command{
+	Name:  "dump",
+	Usage: "show the configuration merged from the configuration file and its drop-in files",
+	Description: `The dump command shows the configuration the runtime uses: the values of
+   the configuration file, merged with the files of its drop-in directory, the
+   configuration file name with a .d suffix, in lexical order, and the file each
+   value comes from.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "toml",
+			Usage: `select one of: toml or json`,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		span, _ := katautils.Trace(ctx, "config dump")
+		defer span.Finish()
+
+		format := context.String("format")
+		if format != "toml" && format != "json" {
+			return fmt.Errorf("invalid format option %q", format)
+		}
+
+		dump, err := katautils.DumpConfiguration(context.GlobalString(configFilePathOption))
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			data, err := json.MarshalIndent(dump, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(defaultOutputFile, string(data))
+			return err
+		}
+
+		return writeConfigDump(defaultOutputFile, dump)
+	},
+}
+
+// configDumpEntry is a value of the configuration in its table, an entry
+// without a name being an empty table.
+type configDumpEntry struct {
+	table string
+	name  string
+	value katautils.ConfigValue
+}
+
+// tomlKeyValue formats a value of the configuration as a TOML key/value
+// pair.
+func tomlKeyValue(name string, value interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{name: value}); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// writeConfigDump writes the merged configuration as TOML, each value
+// commented with the file setting it.
+func writeConfigDump(w io.Writer, dump katautils.ConfigDump) error {
+	var entries []configDumpEntry
+	for _, v := range dump.Values {
+		e := configDumpEntry{value: v}
+		if _, ok := v.Value.(map[string]interface{}); ok {
+			e.table = v.Key
+		} else if i := strings.LastIndex(v.Key, "."); i >= 0 {
+			e.table = v.Key[:i]
+			e.name = v.Key[i+1:]
+		} else {
+			e.name = v.Key
+		}
+		entries = append(entries, e)
+	}
+
+	// Each table once, with its values
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].table != entries[j].table {
+			return entries[i].table < entries[j].table
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	fmt.Fprintf(w, "# configuration file: %s\n", dump.File)
+	for _, dropIn := range dump.DropIns {
+		fmt.Fprintf(w, "# drop-in file: %s\n", dropIn)
+	}
+
+	table := ""
+	for _, e := range entries {
+		if e.table != table {
+			table = e.table
+			fmt.Fprintf(w, "\n[%s]", table)
+			if e.name == "" {
+				fmt.Fprintf(w, "  # %s", e.value.File)
+			}
+			fmt.Fprintln(w)
+		}
+
+		if e.name == "" {
+			continue
+		}
+
+		kv, err := tomlKeyValue(e.name, e.value.Value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s  # %s\n", kv, e.value.File)
+	}
+
+	return nil
+}
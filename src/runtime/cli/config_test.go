@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+)
+
+func TestWriteConfigDump(t *testing.T) {
+	assert := assert.New(t)
+
+	dump := katautils.ConfigDump{
+		File:    "/etc/kata/configuration.toml",
+		DropIns: []string{"/etc/kata/configuration.toml.d/10-debug.toml"},
+		Values: []katautils.ConfigValue{
+			{Key: "factory", Value: map[string]interface{}{}, File: "/etc/kata/configuration.toml.d/10-debug.toml"},
+			{Key: "hypervisor.qemu.kernel_params", Value: "quiet", File: "/etc/kata/configuration.toml"},
+			{Key: "hypervisor.qemu.path", Value: "/usr/bin/qemu", File: "/etc/kata/configuration.toml"},
+			{Key: "runtime.enable_debug", Value: true, File: "/etc/kata/configuration.toml.d/10-debug.toml"},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(writeConfigDump(&buf, dump))
+
+	assert.Equal(`# configuration file: /etc/kata/configuration.toml
+# drop-in file: /etc/kata/configuration.toml.d/10-debug.toml
+
+[factory]  # /etc/kata/configuration.toml.d/10-debug.toml
+
+[hypervisor.qemu]
+kernel_params = "quiet"  # /etc/kata/configuration.toml
+path = "/usr/bin/qemu"  # /etc/kata/configuration.toml
+
+[runtime]
+enable_debug = true  # /etc/kata/configuration.toml.d/10-debug.toml
+`, buf.String())
+
+	// The dump is valid TOML
+	var values map[string]interface{}
+	_, err := toml.Decode(buf.String(), &values)
+	assert.NoError(err)
+}
+
+func TestConfigDumpCLIFunction(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "config-dump")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath := filepath.Join(dir, "configuration.toml")
+	assert.NoError(ioutil.WriteFile(configPath, []byte("[runtime]\nenable_debug = false\n"), testFileMode))
+
+	dropInDir := configPath + ".d"
+	assert.NoError(os.MkdirAll(dropInDir, testDirMode))
+	dropIn := filepath.Join(dropInDir, "10-debug.toml")
+	assert.NoError(ioutil.WriteFile(dropIn, []byte("[runtime]\nenable_debug = true\n"), testFileMode))
+
+	output, err := ioutil.TempFile("", "config-dump")
+	assert.NoError(err)
+	defer os.Remove(output.Name())
+
+	savedOutputFile := defaultOutputFile
+	defaultOutputFile = output
+	defer func() {
+		defaultOutputFile = savedOutputFile
+	}()
+
+	fn, ok := dumpConfigCommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+
+	set := flag.NewFlagSet("", 0)
+	set.String(configFilePathOption, configPath, "")
+	set.String("format", "json", "")
+
+	assert.NoError(fn(createCLIContext(set)))
+
+	data, err := ioutil.ReadFile(output.Name())
+	assert.NoError(err)
+
+	var dump katautils.ConfigDump
+	assert.NoError(json.Unmarshal(data, &dump))
+	assert.Equal(configPath, dump.File)
+	assert.Equal([]string{dropIn}, dump.DropIns)
+	assert.Equal([]katautils.ConfigValue{
+		{Key: "runtime.enable_debug", Value: true, File: dropIn},
+	}, dump.Values)
+
+	set = flag.NewFlagSet("", 0)
+	set.String(configFilePathOption, configPath, "")
+	set.String("format", "toml", "")
+
+	assert.NoError(output.Truncate(0))
+	_, err = output.Seek(0, 0)
+	assert.NoError(err)
+
+	assert.NoError(fn(createCLIContext(set)))
+
+	data, err = ioutil.ReadFile(output.Name())
+	assert.NoError(err)
+	assert.True(strings.HasSuffix(string(data), "enable_debug = true  # "+dropIn+"\n"))
+
+	set = flag.NewFlagSet("", 0)
+	set.String(configFilePathOption, configPath, "")
+	set.String("format", "yaml", "")
+	assert.Error(fn(createCLIContext(set)))
+
+	set = flag.NewFlagSet("", 0)
+	set.String(configFilePathOption, filepath.Join(dir, "missing.toml"), "")
+	set.String("format", "toml", "")
+	assert.Error(fn(createCLIContext(set)))
+}
@@ -119,6 +119,10 @@ func create(ctx context.Context, containerID, bundlePath, console, pidFilePath s
 		return err
 	}
 
+	if err := oci.ApplyCDIDevices(bundlePath, &ociSpec); err != nil {
+		return err
+	}
+
 	containerType, err := oci.ContainerType(ociSpec)
 	if err != nil {
 		return err
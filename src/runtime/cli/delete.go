@@ -124,7 +124,7 @@ func delete(ctx context.Context, containerID string, force bool) error {
 	}
 
 	// Run post-stop OCI hooks.
-	if err := katautils.PostStopHooks(ctx, ociSpec, sandboxID, status.Annotations[vcAnnot.BundlePathKey]); err != nil {
+	if err := katautils.PostStopHooks(ctx, ociSpec, katautils.HookState(nil, sandboxID, status.Annotations[vcAnnot.BundlePathKey])); err != nil {
 		return err
 	}
 
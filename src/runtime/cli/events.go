@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/containerd/cgroups"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 
@@ -180,6 +181,26 @@ information is displayed once every 5 seconds.`,
 			return fmt.Errorf("container with id %s is not running", status.ID)
 		}
 
+		shim, err := newShimClient(sandboxID)
+		if err != nil {
+			return err
+		}
+		if shim != nil {
+			defer shim.Close()
+		}
+
+		containerStats := func() (*stats, error) {
+			if shim != nil {
+				return shimContainerStats(ctx, shim, containerID)
+			}
+
+			s, err := vci.StatsContainer(ctx, sandboxID, containerID)
+			if err != nil {
+				return nil, err
+			}
+			return convertVirtcontainerStats(&s), nil
+		}
+
 		var (
 			events = make(chan *event, 1024)
 			group  = &sync.WaitGroup{}
@@ -197,11 +218,11 @@ information is displayed once every 5 seconds.`,
 		}()
 
 		if context.Bool("stats") {
-			s, err := vci.StatsContainer(ctx, sandboxID, containerID)
+			s, err := containerStats()
 			if err != nil {
 				return err
 			}
-			events <- &event{Type: "stats", ID: status.ID, Data: convertVirtcontainerStats(&s)}
+			events <- &event{Type: "stats", ID: status.ID, Data: s}
 			close(events)
 			group.Wait()
 			return nil
@@ -209,12 +230,12 @@ information is displayed once every 5 seconds.`,
 
 		go func() {
 			for range time.Tick(context.Duration("interval")) {
-				s, err := vci.StatsContainer(ctx, sandboxID, containerID)
+				s, err := containerStats()
 				if err != nil {
 					logrus.Error(err)
 					continue
 				}
-				events <- &event{Type: "stats", ID: status.ID, Data: convertVirtcontainerStats(&s)}
+				events <- &event{Type: "stats", ID: status.ID, Data: s}
 			}
 		}()
 
@@ -293,3 +314,91 @@ func convertBlkioEntry(c []vc.BlkioStatEntry) []blkioEntry {
 	}
 	return out
 }
+
+// convertMetricsStats converts the cgroups metrics of a container, as
+// reported by the shim owning its sandbox.
+func convertMetricsStats(m *cgroups.Metrics) *stats {
+	var s stats
+
+	if m.Pids != nil {
+		s.Pids.Current = m.Pids.Current
+		s.Pids.Limit = m.Pids.Limit
+	}
+
+	if m.CPU != nil {
+		if u := m.CPU.Usage; u != nil {
+			s.CPU.Usage.Kernel = u.Kernel
+			s.CPU.Usage.User = u.User
+			s.CPU.Usage.Total = u.Total
+			s.CPU.Usage.Percpu = u.PerCPU
+		}
+		if t := m.CPU.Throttling; t != nil {
+			s.CPU.Throttling.Periods = t.Periods
+			s.CPU.Throttling.ThrottledPeriods = t.ThrottledPeriods
+			s.CPU.Throttling.ThrottledTime = t.ThrottledTime
+		}
+	}
+
+	if m.Memory != nil {
+		s.Memory.Cache = m.Memory.Cache
+		s.Memory.Kernel = convertMetricsMemoryEntry(m.Memory.Kernel)
+		s.Memory.KernelTCP = convertMetricsMemoryEntry(m.Memory.KernelTCP)
+		s.Memory.Swap = convertMetricsMemoryEntry(m.Memory.Swap)
+		s.Memory.Usage = convertMetricsMemoryEntry(m.Memory.Usage)
+		s.Memory.Raw = map[string]uint64{
+			"rss":                 m.Memory.RSS,
+			"mapped_file":         m.Memory.MappedFile,
+			"pgfault":             m.Memory.PgFault,
+			"pgmajfault":          m.Memory.PgMajFault,
+			"total_inactive_file": m.Memory.TotalInactiveFile,
+		}
+	}
+
+	if b := m.Blkio; b != nil {
+		s.Blkio.IoServiceBytesRecursive = convertMetricsBlkioEntry(b.IoServiceBytesRecursive)
+		s.Blkio.IoServicedRecursive = convertMetricsBlkioEntry(b.IoServicedRecursive)
+		s.Blkio.IoQueuedRecursive = convertMetricsBlkioEntry(b.IoQueuedRecursive)
+		s.Blkio.IoServiceTimeRecursive = convertMetricsBlkioEntry(b.IoServiceTimeRecursive)
+		s.Blkio.IoWaitTimeRecursive = convertMetricsBlkioEntry(b.IoWaitTimeRecursive)
+		s.Blkio.IoMergedRecursive = convertMetricsBlkioEntry(b.IoMergedRecursive)
+		s.Blkio.IoTimeRecursive = convertMetricsBlkioEntry(b.IoTimeRecursive)
+		s.Blkio.SectorsRecursive = convertMetricsBlkioEntry(b.SectorsRecursive)
+	}
+
+	s.Hugetlb = make(map[string]hugetlb)
+	for _, h := range m.Hugetlb {
+		s.Hugetlb[h.Pagesize] = hugetlb{
+			Usage:   h.Usage,
+			Max:     h.Max,
+			Failcnt: h.Failcnt,
+		}
+	}
+
+	return &s
+}
+
+func convertMetricsMemoryEntry(e *cgroups.MemoryEntry) memoryEntry {
+	if e == nil {
+		return memoryEntry{}
+	}
+
+	return memoryEntry{
+		Limit:   e.Limit,
+		Usage:   e.Usage,
+		Max:     e.Max,
+		Failcnt: e.Failcnt,
+	}
+}
+
+func convertMetricsBlkioEntry(c []*cgroups.BlkIOEntry) []blkioEntry {
+	var out []blkioEntry
+	for _, e := range c {
+		out = append(out, blkioEntry{
+			Major: e.Major,
+			Minor: e.Minor,
+			Op:    e.Op,
+			Value: e.Value,
+		})
+	}
+	return out
+}
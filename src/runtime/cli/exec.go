@@ -12,8 +12,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/containerd/console"
+	"github.com/containerd/containerd/cio"
+	// registers the OCI types the exec process spec is sent as
+	_ "github.com/containerd/containerd/runtime"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
@@ -233,6 +241,15 @@ func execute(ctx context.Context, context *cli.Context) error {
 			params.cID)
 	}
 
+	shim, err := newShimClient(sandboxID)
+	if err != nil {
+		return err
+	}
+	if shim != nil {
+		defer shim.Close()
+		return shimExec(ctx, shim, params)
+	}
+
 	envVars, err := oci.EnvVars(params.ociProcess.Env)
 	if err != nil {
 		return err
@@ -250,13 +267,23 @@ func execute(ctx context.Context, context *cli.Context) error {
 	}
 
 	cmd := types.Cmd{
-		Args:        params.ociProcess.Args,
-		Envs:        envVars,
-		WorkDir:     params.ociProcess.Cwd,
-		User:        user,
-		Interactive: params.ociProcess.Terminal,
-		Console:     consolePath,
-		Detach:      noNeedForOutput(params.detach, params.ociProcess.Terminal),
+		Args:            params.ociProcess.Args,
+		Envs:            envVars,
+		WorkDir:         params.ociProcess.Cwd,
+		User:            user,
+		Capabilities:    params.ociProcess.Capabilities,
+		Rlimits:         params.ociProcess.Rlimits,
+		ApparmorProfile: params.ociProcess.ApparmorProfile,
+		SelinuxLabel:    params.ociProcess.SelinuxLabel,
+		OOMScoreAdj:     params.ociProcess.OOMScoreAdj,
+		Interactive:     params.ociProcess.Terminal,
+		Console:         consolePath,
+		Detach:          noNeedForOutput(params.detach, params.ociProcess.Terminal),
+		NoNewPrivileges: params.ociProcess.NoNewPrivileges,
+	}
+
+	for _, gid := range params.ociProcess.User.AdditionalGids {
+		cmd.SupplementaryGroups = append(cmd.SupplementaryGroups, fmt.Sprintf("%d", gid))
 	}
 
 	_, _, process, err := vci.EnterContainer(ctx, sandboxID, params.cID, cmd)
@@ -289,3 +316,131 @@ func execute(ctx context.Context, context *cli.Context) error {
 	// Exit code has to be forwarded in this case.
 	return cli.NewExitError("", ps.Sys().(syscall.WaitStatus).ExitStatus())
 }
+
+// shimExec runs a process in a container through the shim owning its
+// sandbox, the stdio of the process being forwarded by the shim through
+// fifos.
+func shimExec(ctx context.Context, shim *shimClient, params execParams) (err error) {
+	if params.console != "" || params.consoleSock != "" {
+		return fmt.Errorf("console options are not supported for the containers of containerd-shim-kata-v2 sandboxes")
+	}
+
+	process := params.ociProcess
+	if process.User.Username != "" {
+		// The shim only runs processes as numeric users.
+		if process.User.UID, process.User.GID, err = parseNumericUser(process.User.Username); err != nil {
+			return err
+		}
+		process.User.Username = ""
+	}
+
+	spec, err := typeurl.MarshalAny(&process)
+	if err != nil {
+		return err
+	}
+
+	execID := fmt.Sprintf("%s-exec-%d", name, os.Getpid())
+
+	var con console.Console
+	creator := cio.NullIO
+	if !params.detach {
+		opts := []cio.Opt{cio.WithStdio}
+		if process.Terminal {
+			if con, err = console.ConsoleFromFile(os.Stdin); err != nil {
+				return err
+			}
+			defer con.Reset()
+			if err := con.SetRaw(); err != nil {
+				return err
+			}
+			opts = append(opts, cio.WithTerminal)
+		}
+		creator = cio.NewCreator(opts...)
+	}
+
+	processIO, err := creator(execID)
+	if err != nil {
+		return err
+	}
+	defer processIO.Close()
+
+	ioConfig := processIO.Config()
+	if _, err := shim.Exec(ctx, &taskAPI.ExecProcessRequest{
+		ID:       params.cID,
+		ExecID:   execID,
+		Terminal: process.Terminal,
+		Stdin:    ioConfig.Stdin,
+		Stdout:   ioConfig.Stdout,
+		Stderr:   ioConfig.Stderr,
+		Spec:     spec,
+	}); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			shim.Delete(ctx, &taskAPI.DeleteRequest{ID: params.cID, ExecID: execID})
+		}
+	}()
+
+	resp, err := shim.Start(ctx, &taskAPI.StartRequest{ID: params.cID, ExecID: execID})
+	if err != nil {
+		return err
+	}
+
+	// Creation of PID file has to be the last thing done in the exec
+	// because containerd considers the exec to have finished starting
+	// after this file is created.
+	if err := createPIDFile(ctx, params.pidFile, int(resp.Pid)); err != nil {
+		return err
+	}
+
+	if params.detach {
+		return nil
+	}
+
+	if con != nil {
+		if size, err := con.Size(); err == nil {
+			if _, err := shim.ResizePty(ctx, &taskAPI.ResizePtyRequest{
+				ID:     params.cID,
+				ExecID: execID,
+				Width:  uint32(size.Width),
+				Height: uint32(size.Height),
+			}); err != nil {
+				kataLog.WithError(err).Warn("failed to resize exec terminal")
+			}
+		}
+	}
+
+	wait, err := shim.Wait(ctx, &taskAPI.WaitRequest{ID: params.cID, ExecID: execID})
+	if err != nil {
+		return err
+	}
+	processIO.Wait()
+
+	if _, err := shim.Delete(ctx, &taskAPI.DeleteRequest{ID: params.cID, ExecID: execID}); err != nil {
+		return err
+	}
+
+	// Exit code has to be forwarded in this case.
+	return cli.NewExitError("", int(wait.ExitStatus))
+}
+
+// parseNumericUser parses a user of the <uid>[:<gid>] format.
+func parseNumericUser(user string) (uint32, uint32, error) {
+	ids := strings.SplitN(user, ":", 2)
+
+	uid, err := strconv.ParseUint(ids[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid user %q, only <uid>[:<gid>] is supported: %v", user, err)
+	}
+
+	var gid uint64
+	if len(ids) == 2 {
+		if gid, err = strconv.ParseUint(ids[1], 10, 32); err != nil {
+			return 0, 0, fmt.Errorf("invalid user %q, only <uid>[:<gid>] is supported: %v", user, err)
+		}
+	}
+
+	return uint32(uid), uint32(gid), nil
+}
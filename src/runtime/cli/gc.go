@@ -0,0 +1,207 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	namespacesapi "github.com/containerd/containerd/api/services/namespaces/v1"
+	tasksapi "github.com/containerd/containerd/api/services/tasks/v1"
+	"github.com/containerd/containerd/namespaces"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+const (
+	defaultContainerdAddress = "/run/containerd/containerd.sock"
+	containerdDialTimeout    = 5 * time.Second
+)
+
+var gcCLICommand = cli.Command{
+	Name:  "gc",
+	Usage: "remove the artifacts left behind by crashed sandboxes",
+	Description: `The gc command looks for the sandboxes whose shim or hypervisor died and
+   removes what they left on the host: storage directories, shared directories
+   and their mounts, network namespaces and devices, ACRN UUIDs and firecracker
+   jails. The sandboxes known to containerd, served by a running shim, or whose
+   hypervisor is still running are kept.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only report the artifacts that would be removed",
+		},
+		cli.StringFlag{
+			Name:  "containerd-address",
+			Value: defaultContainerdAddress,
+			Usage: "address of containerd, an empty address not to query it",
+		},
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		span, ctx := katautils.Trace(ctx, "gc")
+		defer span.Finish()
+
+		format := context.String("format")
+		if format != "table" && format != "json" {
+			return fmt.Errorf("invalid format option %q", format)
+		}
+
+		inUse, err := listInUse(ctx, context.String("containerd-address"))
+		if err != nil {
+			return err
+		}
+
+		artifacts, err := vci.GCSandboxes(ctx, vc.GCConfig{
+			InUse:  inUse,
+			DryRun: context.Bool("dry-run"),
+		})
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			return json.NewEncoder(defaultOutputFile).Encode(artifacts)
+		}
+
+		return writeGCReport(defaultOutputFile, artifacts)
+	},
+}
+
+// listContainerdTasks returns the IDs of the tasks of every namespace of
+// containerd.
+func listContainerdTasks(ctx context.Context, address string) ([]string, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, containerdDialTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, address,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to containerd at %s: %v", address, err)
+	}
+	defer conn.Close()
+
+	nsResp, err := namespacesapi.NewNamespacesClient(conn).List(ctx, &namespacesapi.ListNamespacesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := tasksapi.NewTasksClient(conn)
+
+	var ids []string
+	for _, ns := range nsResp.Namespaces {
+		resp, err := tasks.List(namespaces.WithNamespace(ctx, ns.Name), &tasksapi.ListTasksRequest{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range resp.Tasks {
+			ids = append(ids, t.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// listServedSandboxes returns the IDs of the sandboxes a running shim
+// serves.
+func listServedSandboxes(ctx context.Context) ([]string, error) {
+	pattern, err := katautils.ShimManagementSocketPath("*")
+	if err != nil {
+		return nil, err
+	}
+
+	sockets, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, socket := range sockets {
+		sandboxID := filepath.Base(filepath.Dir(socket))
+
+		shim, err := newShimClient(sandboxID)
+		if err != nil || shim == nil {
+			// The socket of a dead shim
+			continue
+		}
+
+		_, err = shim.Connect(ctx, &taskAPI.ConnectRequest{ID: sandboxID})
+		shim.Close()
+		if err == nil {
+			ids = append(ids, sandboxID)
+		}
+	}
+
+	return ids, nil
+}
+
+// listInUse returns the IDs of the sandboxes and containers known to be in
+// use by containerd or a shim.
+func listInUse(ctx context.Context, containerdAddress string) ([]string, error) {
+	inUse, err := listServedSandboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerdAddress == "" {
+		return inUse, nil
+	}
+
+	if _, err := os.Stat(containerdAddress); os.IsNotExist(err) {
+		kataLog.WithField("address", containerdAddress).Info("containerd not running, not listing its tasks")
+		return inUse, nil
+	}
+
+	tasks, err := listContainerdTasks(ctx, containerdAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(inUse, tasks...), nil
+}
+
+func writeGCReport(file *os.File, artifacts []vc.GCArtifact) error {
+	w := tabwriter.NewWriter(file, 12, 8, 1, ' ', 0)
+
+	fmt.Fprintln(w, "SANDBOX\tKIND\tPATH\tSTATUS")
+
+	for _, a := range artifacts {
+		status := "found"
+		if a.Removed {
+			status = "removed"
+		} else if a.Error != "" {
+			status = "error: " + a.Error
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.SandboxID, a.Kind, a.Path, status)
+	}
+
+	return w.Flush()
+}
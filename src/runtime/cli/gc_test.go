@@ -0,0 +1,173 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	namespacesapi "github.com/containerd/containerd/api/services/namespaces/v1"
+	tasksapi "github.com/containerd/containerd/api/services/tasks/v1"
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/store"
+)
+
+// fakeContainerd serves a task per namespace, named after it.
+type fakeContainerd struct {
+	namespacesapi.NamespacesServer
+}
+
+func (c *fakeContainerd) List(ctx context.Context, r *namespacesapi.ListNamespacesRequest) (*namespacesapi.ListNamespacesResponse, error) {
+	return &namespacesapi.ListNamespacesResponse{
+		Namespaces: []namespacesapi.Namespace{{Name: "default"}, {Name: "k8s.io"}},
+	}, nil
+}
+
+type fakeContainerdTasks struct {
+	tasksapi.TasksServer
+}
+
+func (c *fakeContainerdTasks) List(ctx context.Context, r *tasksapi.ListTasksRequest) (*tasksapi.ListTasksResponse, error) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tasksapi.ListTasksResponse{
+		Tasks: []*task.Process{{ID: ns + "-task"}},
+	}, nil
+}
+
+func startFakeContainerd(t *testing.T, address string) func() {
+	l, err := net.Listen("unix", address)
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	namespacesapi.RegisterNamespacesServer(server, &fakeContainerd{})
+	tasksapi.RegisterTasksServer(server, &fakeContainerdTasks{})
+	go server.Serve(l)
+
+	return server.Stop
+}
+
+func TestListInUse(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "gc")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedRunVMStoragePath := store.RunVMStoragePath
+	store.RunVMStoragePath = func() string {
+		return dir
+	}
+	defer func() {
+		store.RunVMStoragePath = savedRunVMStoragePath
+	}()
+
+	ctx := context.Background()
+	address := filepath.Join(dir, "containerd.sock")
+
+	// Nothing running
+	inUse, err := listInUse(ctx, address)
+	assert.NoError(err)
+	assert.Empty(inUse)
+
+	stopShim := startFakeShim(t, testSandboxID)
+	defer stopShim()
+
+	stopContainerd := startFakeContainerd(t, address)
+	defer stopContainerd()
+
+	inUse, err = listInUse(ctx, address)
+	assert.NoError(err)
+	assert.Equal([]string{testSandboxID, "default-task", "k8s.io-task"}, inUse)
+
+	inUse, err = listInUse(ctx, "")
+	assert.NoError(err)
+	assert.Equal([]string{testSandboxID}, inUse)
+}
+
+func TestGCCLIFunction(t *testing.T) {
+	assert := assert.New(t)
+
+	var gcConfig vc.GCConfig
+	testingImpl.GCSandboxesFunc = func(ctx context.Context, config vc.GCConfig) ([]vc.GCArtifact, error) {
+		gcConfig = config
+		return []vc.GCArtifact{
+			{SandboxID: "orphan", Kind: vc.GCVMStorage, Path: "/run/vc/vm/orphan", Removed: !config.DryRun},
+			{SandboxID: "orphan", Kind: vc.GCSharedDir, Path: "/run/kata-containers/shared/sandboxes/orphan", Error: "busy"},
+		}, nil
+	}
+	defer func() {
+		testingImpl.GCSandboxesFunc = nil
+	}()
+
+	fn, ok := gcCLICommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+
+	output, err := ioutil.TempFile("", "gc")
+	assert.NoError(err)
+	defer os.Remove(output.Name())
+
+	savedOutputFile := defaultOutputFile
+	defaultOutputFile = output
+	defer func() {
+		defaultOutputFile = savedOutputFile
+	}()
+
+	set := flag.NewFlagSet("", 0)
+	set.Bool("dry-run", true, "")
+	set.String("containerd-address", "", "")
+	set.String("format", "json", "")
+
+	assert.NoError(fn(createCLIContext(set)))
+	assert.True(gcConfig.DryRun)
+
+	data, err := ioutil.ReadFile(output.Name())
+	assert.NoError(err)
+
+	var artifacts []vc.GCArtifact
+	assert.NoError(json.Unmarshal(data, &artifacts))
+	assert.Len(artifacts, 2)
+	assert.False(artifacts[0].Removed)
+
+	set = flag.NewFlagSet("", 0)
+	set.String("containerd-address", "", "")
+	set.String("format", "table", "")
+
+	assert.NoError(output.Truncate(0))
+	_, err = output.Seek(0, 0)
+	assert.NoError(err)
+
+	assert.NoError(fn(createCLIContext(set)))
+	assert.False(gcConfig.DryRun)
+
+	data, err = ioutil.ReadFile(output.Name())
+	assert.NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(lines, 3)
+	assert.Contains(lines[1], "removed")
+	assert.Contains(lines[2], "error: busy")
+
+	set = flag.NewFlagSet("", 0)
+	set.String("format", "yaml", "")
+	assert.Error(fn(createCLIContext(set)))
+}
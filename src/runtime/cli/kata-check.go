@@ -314,9 +314,19 @@ var kataCheckCLICommand = cli.Command{
 			Name:  "strict, s",
 			Usage: "perform strict checking",
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "text",
+			Usage: "output format, text or json",
+		},
 	},
 
 	Action: func(context *cli.Context) error {
+		format := context.String("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("kata-check: invalid format %q", format)
+		}
+
 		verbose := context.Bool("verbose")
 		if verbose {
 			kataLog.Logger.SetLevel(logrus.InfoLevel)
@@ -346,6 +356,28 @@ var kataCheckCLICommand = cli.Command{
 			requiredKernelModules: archRequiredKernelModules,
 		}
 
+		if format == "json" {
+			configFile, ok := context.App.Metadata["configFile"].(string)
+			if !ok {
+				return errors.New("kata-check: cannot determine config file")
+			}
+
+			report, err := getCheckReport(configFile, runtimeConfig, details, context.Bool("strict"))
+			if err != nil {
+				return err
+			}
+
+			if err := writeJSONCheckReport(report, defaultOutputFile); err != nil {
+				return err
+			}
+
+			if !report.OK {
+				return fmt.Errorf("ERROR: %s", failMessage)
+			}
+
+			return nil
+		}
+
 		err = hostIsVMContainerCapable(details)
 
 		if err != nil {
@@ -0,0 +1,349 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+)
+
+// Status of a check of the kata-check report
+const (
+	checkPass = "pass"
+	checkFail = "fail"
+	checkWarn = "warn"
+	checkSkip = "skip"
+)
+
+// suffix of the file holding the expected SHA-512 digest of an asset, in
+// the sha512sum(1) format.
+const assetHashSuffix = ".sha512"
+
+// variables rather than consts to allow tests to modify them
+var (
+	procMemInfo    = "/proc/meminfo"
+	vhostNetDevice = "/dev/vhost-net"
+)
+
+// checkResult is the result of a single check. The ID of a check is stable
+// across releases so that tools can rely on it.
+type checkResult struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Details     string `json:"details,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// hypervisorReport gathers the checks of a configured hypervisor.
+type hypervisorReport struct {
+	Type    string        `json:"type"`
+	Path    string        `json:"path"`
+	Version string        `json:"version"`
+	Active  bool          `json:"active"`
+	Checks  []checkResult `json:"checks"`
+}
+
+// checkReport is the machine-readable output of kata-check. OK is only
+// false when a host check or a check of the active hypervisor failed.
+type checkReport struct {
+	OK          bool               `json:"ok"`
+	Host        []checkResult      `json:"host"`
+	Hypervisors []hypervisorReport `json:"hypervisors"`
+}
+
+func newCheckResult(id, description string, err error, remediation string) checkResult {
+	result := checkResult{
+		ID:          id,
+		Description: description,
+		Status:      checkPass,
+	}
+
+	if err != nil {
+		result.Status = checkFail
+		result.Details = err.Error()
+		result.Remediation = remediation
+	}
+
+	return result
+}
+
+func checksFailed(checks []checkResult) bool {
+	for _, check := range checks {
+		if check.Status == checkFail {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getHostChecks runs the checks kata-check runs in text mode.
+func getHostChecks(runtimeConfig oci.RuntimeConfig, details vmContainerCapableDetails, strict bool) []checkResult {
+	checks := []checkResult{
+		newCheckResult("host.capable", successMessageCapable,
+			hostIsVMContainerCapable(details),
+			"enable the virtualization extensions in the firmware and load the required kernel modules"),
+	}
+
+	if os.Geteuid() == 0 {
+		checks = append(checks, newCheckResult("host.create-vm", successMessageCreate,
+			archHostCanCreateVMContainer(runtimeConfig.HypervisorType),
+			"ensure no other hypervisor is running and "+kvmDevice+" is accessible"))
+	} else {
+		checks = append(checks, checkResult{
+			ID:          "host.create-vm",
+			Description: successMessageCreate,
+			Status:      checkSkip,
+			Details:     "requires root",
+		})
+	}
+
+	if strict {
+		checks = append(checks, newCheckResult("host.version-consistency", successMessageVersion,
+			checkVersionConsistencyInComponents(runtimeConfig),
+			"install the matching versions of the "+project+" components"))
+	}
+
+	return checks
+}
+
+// fileSHA512 returns the hex encoded SHA-512 digest of a file.
+func fileSHA512(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkAssetHash compares the digest of an asset with the one recorded in
+// the sha512sum(1) file next to it. The check is skipped when there is no
+// such file, reporting the digest so that one can be created.
+func checkAssetHash(id, name, path string) checkResult {
+	result := checkResult{
+		ID:          id,
+		Description: fmt.Sprintf("%s matches its expected digest", name),
+	}
+
+	digest, err := fileSHA512(path)
+	if err != nil {
+		result.Status = checkSkip
+		result.Details = err.Error()
+		return result
+	}
+
+	expected, err := katautils.GetFileContents(path + assetHashSuffix)
+	if err != nil {
+		result.Status = checkSkip
+		result.Details = fmt.Sprintf("no %s file, digest is %s", path+assetHashSuffix, digest)
+		return result
+	}
+
+	fields := strings.Fields(expected)
+	if len(fields) > 0 && fields[0] == digest {
+		result.Status = checkPass
+		return result
+	}
+
+	result.Status = checkFail
+	result.Details = fmt.Sprintf("digest is %s", digest)
+	result.Remediation = fmt.Sprintf("reinstall %s", path)
+
+	return result
+}
+
+// getFreeHugePagesKB returns the size in kB of the huge pages free on the
+// host.
+func getFreeHugePagesKB(memInfo string) (uint64, error) {
+	f, err := os.Open(memInfo)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var free, size uint64
+	var foundFree, foundSize bool
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "HugePages_Free:":
+			free, err = strconv.ParseUint(fields[1], 10, 64)
+			foundFree = true
+		case "Hugepagesize:":
+			size, err = strconv.ParseUint(fields[1], 10, 64)
+			foundSize = true
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	if !foundFree || !foundSize {
+		return 0, fmt.Errorf("no huge pages details in %s", memInfo)
+	}
+
+	return free * size, nil
+}
+
+func checkDevice(id, description, device, remediation string) checkResult {
+	_, err := os.Stat(device)
+	return newCheckResult(id, description, err, remediation)
+}
+
+// getHypervisorChecks probes the host for what a configured hypervisor
+// needs.
+func getHypervisorChecks(hypervisor katautils.ConfiguredHypervisor) hypervisorReport {
+	hType := string(hypervisor.Type)
+	config := hypervisor.Config
+
+	report := hypervisorReport{
+		Type:    hType,
+		Path:    config.HypervisorPath,
+		Version: unknown,
+	}
+
+	report.Checks = append(report.Checks, newCheckResult(hType+".config",
+		"hypervisor configuration is valid", hypervisor.Err,
+		fmt.Sprintf("fix the [hypervisor.%s] section of the configuration file", hType)))
+
+	if hypervisor.Err != nil {
+		// The remaining checks rely on a valid configuration
+		return report
+	}
+
+	report.Checks = append(report.Checks, checkDevice(hType+".binary",
+		"hypervisor binary exists", config.HypervisorPath,
+		"install "+config.HypervisorPath))
+
+	versionCheck := checkResult{
+		ID:          hType + ".version",
+		Description: "hypervisor version can be determined",
+		Status:      checkPass,
+	}
+	if version, err := getCommandVersion(config.HypervisorPath); err != nil {
+		versionCheck.Status = checkWarn
+		versionCheck.Details = err.Error()
+	} else {
+		report.Version = version
+	}
+	report.Checks = append(report.Checks, versionCheck)
+
+	assets := []struct {
+		name string
+		path string
+	}{
+		{"kernel", config.KernelPath},
+		{"image", config.ImagePath},
+		{"initrd", config.InitrdPath},
+		{"firmware", config.FirmwarePath},
+		{"jailer", config.JailerPath},
+	}
+
+	for _, asset := range assets {
+		if asset.path == "" {
+			continue
+		}
+
+		id := hType + "." + asset.name
+		report.Checks = append(report.Checks,
+			checkDevice(id, asset.name+" exists", asset.path, "install "+asset.path),
+			checkAssetHash(id+".hash", asset.name, asset.path))
+	}
+
+	if hypervisor.Type == vc.QemuHypervisor && !config.DisableVhostNet {
+		report.Checks = append(report.Checks, checkDevice(hType+".vhost-net",
+			"vhost-net device is available", vhostNetDevice,
+			"load the vhost_net module (modprobe vhost_net) or set disable_vhost_net"))
+	}
+
+	if config.UseVSock {
+		report.Checks = append(report.Checks, checkDevice(hType+".vsock",
+			"vhost-vsock device is available", utils.VHostVSockDevicePath,
+			"load the vhost_vsock module (modprobe vhost_vsock) or unset use_vsock"))
+	}
+
+	if config.HugePages {
+		free, err := getFreeHugePagesKB(procMemInfo)
+		if err == nil && free < uint64(config.MemorySize)*1024 {
+			err = fmt.Errorf("%d kB of huge pages free, %d MiB required", free, config.MemorySize)
+		}
+
+		report.Checks = append(report.Checks, newCheckResult(hType+".hugepages",
+			"enough huge pages are reserved for the guest memory", err,
+			"reserve more huge pages (sysctl vm.nr_hugepages) or unset enable_hugepages"))
+	}
+
+	return report
+}
+
+// getCheckReport builds the report of the host checks and of the checks of
+// every hypervisor of the configuration file.
+func getCheckReport(configFile string, runtimeConfig oci.RuntimeConfig, details vmContainerCapableDetails, strict bool) (checkReport, error) {
+	hypervisors, err := katautils.LoadHypervisorConfigs(configFile)
+	if err != nil {
+		return checkReport{}, err
+	}
+
+	report := checkReport{
+		Host:        getHostChecks(runtimeConfig, details, strict),
+		Hypervisors: []hypervisorReport{},
+	}
+
+	report.OK = !checksFailed(report.Host)
+
+	for _, hypervisor := range hypervisors {
+		hReport := getHypervisorChecks(hypervisor)
+		hReport.Active = hypervisor.Type == runtimeConfig.HypervisorType
+
+		if hReport.Active && checksFailed(hReport.Checks) {
+			report.OK = false
+		}
+
+		report.Hypervisors = append(report.Hypervisors, hReport)
+	}
+
+	return report, nil
+}
+
+func writeJSONCheckReport(report checkReport, file *os.File) error {
+	encoder := json.NewEncoder(file)
+
+	// Make it more human readable
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
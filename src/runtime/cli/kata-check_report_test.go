@@ -0,0 +1,206 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+)
+
+func findCheck(checks []checkResult, id string) checkResult {
+	for _, check := range checks {
+		if check.ID == id {
+			return check
+		}
+	}
+
+	return checkResult{}
+}
+
+func TestCheckAssetHash(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	asset := filepath.Join(dir, "kernel")
+
+	result := checkAssetHash("qemu.kernel.hash", "kernel", asset)
+	assert.Equal(checkSkip, result.Status)
+
+	assert.NoError(createFile(asset, "foo"))
+	digest, err := fileSHA512(asset)
+	assert.NoError(err)
+
+	// No digest recorded
+	result = checkAssetHash("qemu.kernel.hash", "kernel", asset)
+	assert.Equal("qemu.kernel.hash", result.ID)
+	assert.Equal(checkSkip, result.Status)
+	assert.Contains(result.Details, digest)
+
+	assert.NoError(createFile(asset+assetHashSuffix, digest+"  "+asset+"\n"))
+	result = checkAssetHash("qemu.kernel.hash", "kernel", asset)
+	assert.Equal(checkPass, result.Status)
+
+	assert.NoError(createFile(asset+assetHashSuffix, "bad  "+asset+"\n"))
+	result = checkAssetHash("qemu.kernel.hash", "kernel", asset)
+	assert.Equal(checkFail, result.Status)
+	assert.NotEmpty(result.Remediation)
+}
+
+func TestGetFreeHugePagesKB(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	memInfo := filepath.Join(dir, "meminfo")
+
+	_, err = getFreeHugePagesKB(memInfo)
+	assert.Error(err)
+
+	assert.NoError(createFile(memInfo, "MemTotal:       16304412 kB\n"))
+	_, err = getFreeHugePagesKB(memInfo)
+	assert.Error(err)
+
+	assert.NoError(createFile(memInfo, `MemTotal:       16304412 kB
+HugePages_Total:     512
+HugePages_Free:      256
+Hugepagesize:       2048 kB
+`))
+	free, err := getFreeHugePagesKB(memInfo)
+	assert.NoError(err)
+	assert.Equal(uint64(256*2048), free)
+
+	assert.NoError(createFile(memInfo, "HugePages_Free: foo\nHugepagesize: 2048 kB\n"))
+	_, err = getFreeHugePagesKB(memInfo)
+	assert.Error(err)
+}
+
+func TestGetHypervisorChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcMemInfo := procMemInfo
+	savedVhostNetDevice := vhostNetDevice
+	savedVSockDevicePath := utils.VHostVSockDevicePath
+	defer func() {
+		procMemInfo = savedProcMemInfo
+		vhostNetDevice = savedVhostNetDevice
+		utils.VHostVSockDevicePath = savedVSockDevicePath
+	}()
+
+	procMemInfo = filepath.Join(dir, "meminfo")
+	vhostNetDevice = filepath.Join(dir, "vhost-net")
+	utils.VHostVSockDevicePath = filepath.Join(dir, "vhost-vsock")
+
+	hypervisorPath := filepath.Join(dir, "hypervisor")
+	kernelPath := filepath.Join(dir, "kernel")
+
+	assert.NoError(makeVersionBinary(hypervisorPath, testHypervisorVersion))
+	assert.NoError(createFile(kernelPath, "foo"))
+	assert.NoError(createFile(vhostNetDevice, ""))
+	assert.NoError(createFile(procMemInfo, "HugePages_Free: 1\nHugepagesize: 2048 kB\n"))
+
+	report := getHypervisorChecks(katautils.ConfiguredHypervisor{
+		Type: vc.QemuHypervisor,
+		Config: vc.HypervisorConfig{
+			HypervisorPath: hypervisorPath,
+			KernelPath:     kernelPath,
+			ImagePath:      filepath.Join(dir, "image"),
+			MemorySize:     2048,
+			HugePages:      true,
+			UseVSock:       true,
+		},
+	})
+
+	assert.Equal("qemu", report.Type)
+	assert.Equal(hypervisorPath, report.Path)
+	assert.Equal(testHypervisorVersion, report.Version)
+
+	for id, status := range map[string]string{
+		"qemu.config":      checkPass,
+		"qemu.binary":      checkPass,
+		"qemu.version":     checkPass,
+		"qemu.kernel":      checkPass,
+		"qemu.kernel.hash": checkSkip,
+		"qemu.image":       checkFail,
+		"qemu.image.hash":  checkSkip,
+		"qemu.vhost-net":   checkPass,
+		"qemu.vsock":       checkFail,
+		"qemu.hugepages":   checkFail,
+	} {
+		assert.Equal(status, findCheck(report.Checks, id).Status, id)
+	}
+
+	assert.Empty(findCheck(report.Checks, "qemu.initrd").ID)
+	assert.NotEmpty(findCheck(report.Checks, "qemu.vsock").Remediation)
+
+	// An invalid configuration is not probed any further
+	report = getHypervisorChecks(katautils.ConfiguredHypervisor{
+		Type: vc.FirecrackerHypervisor,
+		Err:  os.ErrNotExist,
+	})
+	assert.Len(report.Checks, 1)
+	assert.Equal("firecracker.config", report.Checks[0].ID)
+	assert.Equal(checkFail, report.Checks[0].Status)
+	assert.Equal(unknown, report.Version)
+}
+
+func TestGetCheckReport(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configFile, config, err := makeRuntimeConfig(dir)
+	assert.NoError(err)
+
+	_, err = getCheckReport(filepath.Join(dir, "missing.toml"), config, vmContainerCapableDetails{}, false)
+	assert.Error(err)
+
+	cpuInfoFile := filepath.Join(dir, "cpuinfo")
+	assert.NoError(createFile(cpuInfoFile, ""))
+
+	report, err := getCheckReport(configFile, config, vmContainerCapableDetails{cpuInfoFile: cpuInfoFile}, false)
+	assert.NoError(err)
+
+	// The host cannot be probed from an empty cpuinfo
+	assert.False(report.OK)
+	assert.Equal(checkFail, findCheck(report.Host, "host.capable").Status)
+	assert.Empty(findCheck(report.Host, "host.version-consistency").ID)
+
+	assert.Len(report.Hypervisors, 1)
+	assert.Equal("qemu", report.Hypervisors[0].Type)
+	assert.True(report.Hypervisors[0].Active)
+
+	tmpfile, err := ioutil.TempFile(dir, "")
+	assert.NoError(err)
+	defer tmpfile.Close()
+
+	assert.NoError(writeJSONCheckReport(report, tmpfile))
+
+	var decoded checkReport
+	data, err := ioutil.ReadFile(tmpfile.Name())
+	assert.NoError(err)
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(report, decoded)
+}
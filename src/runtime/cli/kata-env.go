@@ -25,7 +25,7 @@ import (
 //
 // XXX: Increment for every change to the output format
 // (meaning any change to the EnvInfo type).
-const formatVersion = "1.0.24"
+const formatVersion = "1.0.26"
 
 // MetaInfo stores information on the format of the output itself
 type MetaInfo struct {
@@ -89,19 +89,22 @@ type RuntimeVersionInfo struct {
 
 // HypervisorInfo stores hypervisor details
 type HypervisorInfo struct {
-	MachineType          string
-	Version              string
-	Path                 string
-	BlockDeviceDriver    string
-	EntropySource        string
-	SharedFS             string
-	VirtioFSDaemon       string
-	Msize9p              uint32
-	MemorySlots          uint32
-	PCIeRootPort         uint32
-	HotplugVFIOOnRootBus bool
-	Debug                bool
-	UseVSock             bool
+	MachineType           string
+	Version               string
+	Path                  string
+	BlockDeviceDriver     string
+	EntropySource         string
+	EntropyMaxBytes       uint32
+	EntropyPeriod         uint32
+	EntropyReseedInterval uint32
+	SharedFS              string
+	VirtioFSDaemon        string
+	Msize9p               uint32
+	MemorySlots           uint32
+	PCIeRootPort          uint32
+	HotplugVFIOOnRootBus  bool
+	Debug                 bool
+	UseVSock              bool
 }
 
 // ProxyInfo stores proxy details
@@ -359,6 +362,13 @@ func getHypervisorInfo(config oci.RuntimeConfig) HypervisorInfo {
 		version = unknown
 	}
 
+	// Only report the rate limit actually applied to the guest entropy
+	var entropyMaxBytes, entropyPeriod uint32
+	if vc.RNGRateLimitSupported(config.HypervisorType) {
+		entropyMaxBytes = config.HypervisorConfig.EntropyMaxBytes
+		entropyPeriod = config.HypervisorConfig.EntropyPeriod
+	}
+
 	return HypervisorInfo{
 		Debug:             config.HypervisorConfig.Debug,
 		MachineType:       config.HypervisorConfig.HypervisorMachineType,
@@ -372,6 +382,10 @@ func getHypervisorInfo(config oci.RuntimeConfig) HypervisorInfo {
 		SharedFS:          config.HypervisorConfig.SharedFS,
 		VirtioFSDaemon:    config.HypervisorConfig.VirtioFSDaemon,
 
+		EntropyMaxBytes:       entropyMaxBytes,
+		EntropyPeriod:         entropyPeriod,
+		EntropyReseedInterval: config.HypervisorConfig.EntropyReseedInterval,
+
 		HotplugVFIOOnRootBus: config.HypervisorConfig.HotplugVFIOOnRootBus,
 		PCIeRootPort:         config.HypervisorConfig.PCIeRootPort,
 	}
@@ -465,7 +479,29 @@ func handleSettings(file *os.File, c *cli.Context) error {
 	return writeTOMLSettings(env, file)
 }
 
-func writeTOMLSettings(env EnvInfo, file *os.File) error {
+func handleSandboxesSettings(file *os.File, c *cli.Context) error {
+	if file == nil {
+		return errors.New("Invalid output file specified")
+	}
+
+	ctx, err := cliContextToContext(c)
+	if err != nil {
+		return err
+	}
+
+	sandboxes, err := getSandboxesInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		return writeJSONSettings(sandboxes, file)
+	}
+
+	return writeTOMLSettings(sandboxes, file)
+}
+
+func writeTOMLSettings(env interface{}, file *os.File) error {
 	encoder := toml.NewEncoder(file)
 
 	err := encoder.Encode(env)
@@ -476,7 +512,7 @@ func writeTOMLSettings(env EnvInfo, file *os.File) error {
 	return nil
 }
 
-func writeJSONSettings(env EnvInfo, file *os.File) error {
+func writeJSONSettings(env interface{}, file *os.File) error {
 	encoder := json.NewEncoder(file)
 
 	// Make it more human readable
@@ -498,6 +534,10 @@ var kataEnvCLICommand = cli.Command{
 			Name:  "json",
 			Usage: "Format output as JSON",
 		},
+		cli.BoolFlag{
+			Name:  "sandboxes",
+			Usage: "display the sandboxes of the host instead of the settings",
+		},
 	},
 	Action: func(context *cli.Context) error {
 		ctx, err := cliContextToContext(context)
@@ -508,6 +548,10 @@ var kataEnvCLICommand = cli.Command{
 		span, _ := katautils.Trace(ctx, "kata-env")
 		defer span.Finish()
 
+		if context.Bool("sandboxes") {
+			return handleSandboxesSettings(defaultOutputFile, context)
+		}
+
 		return handleSettings(defaultOutputFile, context)
 	},
 }
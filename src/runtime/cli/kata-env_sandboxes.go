@@ -0,0 +1,199 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// SandboxHypervisorInfo stores details of the hypervisor running a sandbox
+type SandboxHypervisorInfo struct {
+	Type    string
+	Path    string
+	Version string
+	Pid     int
+}
+
+// SandboxDeviceInfo stores details of a device attached to a sandbox
+type SandboxDeviceInfo struct {
+	ID       string
+	Type     string
+	HostPath string
+}
+
+// SandboxInfo stores details of a sandbox persisted on the host
+type SandboxInfo struct {
+	ID               string
+	State            string
+	Hypervisor       SandboxHypervisorInfo
+	Containers       int
+	HotpluggedMemory int
+	HotpluggedVCPUs  int
+	Devices          []SandboxDeviceInfo
+
+	// Only set for running sandboxes which stats could be read
+	Overhead *SandboxOverheadInfo `json:",omitempty" toml:",omitempty"`
+}
+
+// SandboxesInfo is the output of the env command in sandboxes mode.
+//
+// XXX: Any changes must be coupled with a change to formatVersion.
+type SandboxesInfo struct {
+	Meta      MetaInfo
+	Sandboxes []SandboxInfo
+}
+
+// getDeviceHostPath returns the host side of a device attached to a
+// sandbox.
+func getDeviceHostPath(d persistapi.DeviceState) string {
+	switch {
+	case d.BlockDrive != nil:
+		return d.BlockDrive.File
+	case d.VhostUserDev != nil:
+		return d.VhostUserDev.SocketPath
+	case d.CharDev != nil:
+		return d.CharDev.HostPath
+	case len(d.VFIODevs) > 0 && d.VFIODevs[0] != nil:
+		return d.VFIODevs[0].BDF
+	default:
+		return ""
+	}
+}
+
+func getSandboxInfo(id string, ss persistapi.SandboxState, cs map[string]persistapi.ContainerState) SandboxInfo {
+	hs := ss.HypervisorState
+
+	info := SandboxInfo{
+		ID:    id,
+		State: ss.State,
+		Hypervisor: SandboxHypervisorInfo{
+			Type: ss.Config.HypervisorType,
+			Path: ss.Config.HypervisorConfig.HypervisorPath,
+			Pid:  hs.Pid,
+		},
+		Containers:       len(cs),
+		HotpluggedMemory: hs.HotpluggedMemory,
+		HotpluggedVCPUs:  len(hs.HotpluggedVCPUs),
+		Devices:          []SandboxDeviceInfo{},
+	}
+
+	for _, d := range ss.Devices {
+		info.Devices = append(info.Devices, SandboxDeviceInfo{
+			ID:       d.ID,
+			Type:     d.Type,
+			HostPath: getDeviceHostPath(d),
+		})
+	}
+
+	return info
+}
+
+// listPersistedSandboxes returns the IDs of the sandboxes found in the run
+// storage path of the persist driver.
+func listPersistedSandboxes() ([]string, error) {
+	driver, err := persist.GetDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(driver.RunStoragePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, file := range files {
+		if file.IsDir() {
+			ids = append(ids, file.Name())
+		}
+	}
+
+	return ids, nil
+}
+
+// getSandboxesInfo describes the persisted sandboxes. The overhead of the
+// running ones is measured over a second, as kata-overhead does.
+func getSandboxesInfo(ctx context.Context) (SandboxesInfo, error) {
+	info := SandboxesInfo{
+		Meta:      getMetaInfo(),
+		Sandboxes: []SandboxInfo{},
+	}
+
+	ids, err := listPersistedSandboxes()
+	if err != nil {
+		return info, err
+	}
+
+	versions := make(map[string]string)
+	initial := make(map[int]overheadSample)
+
+	for _, id := range ids {
+		// A new driver for each sandbox as the fs one accumulates the
+		// containers it reads.
+		driver, err := persist.GetDriver()
+		if err != nil {
+			return info, err
+		}
+
+		ss, cs, err := driver.FromDisk(id)
+		if err != nil {
+			kataLog.WithError(err).WithField("sandbox", id).Warn("cannot read sandbox state")
+			continue
+		}
+
+		sandbox := getSandboxInfo(id, ss, cs)
+
+		path := sandbox.Hypervisor.Path
+		if _, ok := versions[path]; !ok {
+			versions[path] = unknown
+			if version, err := getCommandVersion(path); err == nil {
+				versions[path] = version
+			}
+		}
+		sandbox.Hypervisor.Version = versions[path]
+
+		if ss.State == string(types.StateRunning) {
+			sample, err := sampleSandboxOverhead(ctx, id)
+			if err == nil {
+				initial[len(info.Sandboxes)] = sample
+			} else {
+				kataLog.WithError(err).WithField("sandbox", id).Warn("cannot measure sandbox overhead")
+			}
+		}
+
+		info.Sandboxes = append(info.Sandboxes, sandbox)
+	}
+
+	if len(initial) == 0 {
+		return info, nil
+	}
+
+	// Wait for 1 second to calculate CPU usage
+	time.Sleep(time.Second * 1)
+
+	for i, sample := range initial {
+		final, err := sampleSandboxOverhead(ctx, info.Sandboxes[i].ID)
+		if err != nil {
+			kataLog.WithError(err).WithField("sandbox", info.Sandboxes[i].ID).Warn("cannot measure sandbox overhead")
+			continue
+		}
+
+		overhead := computeSandboxOverhead(sample, final)
+		info.Sandboxes[i].Overhead = &overhead
+	}
+
+	return info, nil
+}
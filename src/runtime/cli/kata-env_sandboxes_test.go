@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/fs"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+func TestEnvGetSandboxesInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	persist.EnableMockTesting()
+	defer fs.MockStorageDestroy()
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	hypervisorPath := filepath.Join(tmpdir, "hypervisor")
+	assert.NoError(makeVersionBinary(hypervisorPath, testHypervisorVersion))
+
+	driver, err := persist.GetDriver()
+	assert.NoError(err)
+
+	err = driver.ToDisk(persistapi.SandboxState{
+		SandboxContainer: testSandboxID,
+		State:            string(types.StateReady),
+		Devices: []persistapi.DeviceState{
+			{
+				ID:         "drive",
+				Type:       "block",
+				BlockDrive: &persistapi.BlockDrive{File: "/dev/sda"},
+			},
+			{
+				ID:   "generic",
+				Type: "generic",
+			},
+		},
+		HypervisorState: persistapi.HypervisorState{
+			Pid:              42,
+			HotpluggedMemory: 512,
+			HotpluggedVCPUs:  []persistapi.CPUDevice{{ID: "cpu-0"}, {ID: "cpu-1"}},
+		},
+		Config: persistapi.SandboxConfig{
+			HypervisorType: "qemu",
+			HypervisorConfig: persistapi.HypervisorConfig{
+				HypervisorPath: hypervisorPath,
+			},
+		},
+	}, map[string]persistapi.ContainerState{
+		testContainerID: {State: string(types.StateReady)},
+	})
+	assert.NoError(err)
+
+	ctx := createCLIContext(nil)
+
+	info, err := getSandboxesInfo(ctx.App.Metadata["context"].(context.Context))
+	assert.NoError(err)
+	assert.Equal(getExpectedMetaInfo(), info.Meta)
+
+	var sandbox *SandboxInfo
+	for i := range info.Sandboxes {
+		if info.Sandboxes[i].ID == testSandboxID {
+			sandbox = &info.Sandboxes[i]
+		}
+	}
+	assert.NotNil(sandbox)
+
+	assert.Equal(SandboxInfo{
+		ID:    testSandboxID,
+		State: string(types.StateReady),
+		Hypervisor: SandboxHypervisorInfo{
+			Type:    "qemu",
+			Path:    hypervisorPath,
+			Version: testHypervisorVersion,
+			Pid:     42,
+		},
+		Containers:       1,
+		HotpluggedMemory: 512,
+		HotpluggedVCPUs:  2,
+		Devices: []SandboxDeviceInfo{
+			{ID: "drive", Type: "block", HostPath: "/dev/sda"},
+			{ID: "generic", Type: "generic"},
+		},
+	}, *sandbox)
+
+	// Through the env command
+	set := flag.NewFlagSet("", 0)
+	set.Bool("json", true, "")
+	ctx = createCLIContext(set)
+
+	tmpfile, err := ioutil.TempFile(tmpdir, "")
+	assert.NoError(err)
+	defer tmpfile.Close()
+
+	assert.NoError(handleSandboxesSettings(tmpfile, ctx))
+
+	data, err := ioutil.ReadFile(tmpfile.Name())
+	assert.NoError(err)
+
+	var decoded SandboxesInfo
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(info, decoded)
+
+	tomlfile, err := ioutil.TempFile(tmpdir, "")
+	assert.NoError(err)
+	defer tomlfile.Close()
+
+	assert.NoError(handleSandboxesSettings(tomlfile, createCLIContext(flag.NewFlagSet("", 0))))
+
+	decoded = SandboxesInfo{}
+	_, err = toml.DecodeFile(tomlfile.Name(), &decoded)
+	assert.NoError(err)
+	assert.Equal(info, decoded)
+
+	assert.Error(handleSandboxesSettings(nil, ctx))
+}
+
+func TestEnvComputeSandboxOverhead(t *testing.T) {
+	assert := assert.New(t)
+
+	initial := overheadSample{
+		time:     0,
+		hostCPU:  1000,
+		guestCPU: 500,
+	}
+
+	final := overheadSample{
+		time:        1000,
+		hostCPU:     1500,
+		guestCPU:    700,
+		hostMemory:  4096,
+		guestMemory: 1024,
+		vcpus:       2,
+	}
+
+	assert.Equal(SandboxOverheadInfo{
+		CPUOverhead:         30,
+		CPUHost:             50,
+		CPUGuest:            20,
+		MemoryOverheadBytes: 3072,
+		MemoryHostBytes:     4096,
+		MemoryGuestBytes:    1024,
+		VCPUs:               2,
+	}, computeSandboxOverhead(initial, final))
+}
@@ -337,6 +337,10 @@ func getExpectedHypervisor(config oci.RuntimeConfig) HypervisorInfo {
 		SharedFS:          config.HypervisorConfig.SharedFS,
 		VirtioFSDaemon:    config.HypervisorConfig.VirtioFSDaemon,
 
+		EntropyMaxBytes:       config.HypervisorConfig.EntropyMaxBytes,
+		EntropyPeriod:         config.HypervisorConfig.EntropyPeriod,
+		EntropyReseedInterval: config.HypervisorConfig.EntropyReseedInterval,
+
 		HotplugVFIOOnRootBus: config.HypervisorConfig.HotplugVFIOOnRootBus,
 		PCIeRootPort:         config.HypervisorConfig.PCIeRootPort,
 	}
@@ -1225,7 +1229,7 @@ func TestEnvCLIFunctionFail(t *testing.T) {
 	_, err = getExpectedSettings(config, tmpdir, configFile)
 	assert.NoError(t, err)
 
-	ctx := createCLIContext(nil)
+	ctx := createCLIContext(flag.NewFlagSet("", 0))
 	ctx.App.Name = "foo"
 
 	ctx.App.Metadata["configFile"] = configFile
@@ -1265,3 +1269,31 @@ func TestGetHypervisorInfo(t *testing.T) {
 	info = getHypervisorInfo(config)
 	assert.Equal(info.Version, unknown)
 }
+
+func TestGetHypervisorInfoEntropy(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	_, config, err := makeRuntimeConfig(tmpdir)
+	assert.NoError(err)
+
+	config.HypervisorConfig.EntropyMaxBytes = 1024
+	config.HypervisorConfig.EntropyPeriod = 500
+	config.HypervisorConfig.EntropyReseedInterval = 60
+
+	config.HypervisorType = vc.QemuHypervisor
+	info := getHypervisorInfo(config)
+	assert.Equal(uint32(1024), info.EntropyMaxBytes)
+	assert.Equal(uint32(500), info.EntropyPeriod)
+	assert.Equal(uint32(60), info.EntropyReseedInterval)
+
+	// cloud-hypervisor does not rate limit the guest entropy
+	config.HypervisorType = vc.ClhHypervisor
+	info = getHypervisorInfo(config)
+	assert.Zero(info.EntropyMaxBytes)
+	assert.Zero(info.EntropyPeriod)
+	assert.Equal(uint32(60), info.EntropyReseedInterval)
+}
@@ -78,59 +78,99 @@ func overhead(ctx context.Context, containerID string) error {
 		return fmt.Errorf("container with id %s is not running", status.ID)
 	}
 
-	initTime := time.Now().UnixNano()
-
-	initialSandboxStats, initialContainerStats, err := vci.StatsSandbox(ctx, sandboxID)
+	initial, err := sampleSandboxOverhead(ctx, sandboxID)
 	if err != nil {
 		return err
 	}
 
-	hostInitCPU := initialSandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	guestInitCPU := uint64(0)
-	for _, cs := range initialContainerStats {
-		guestInitCPU += cs.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	}
-
 	// Wait for 1 second to calculate CPU usage
 	time.Sleep(time.Second * 1)
-	finishtTime := time.Now().UnixNano()
 
-	finishSandboxStats, finishContainersStats, err := vci.StatsSandbox(ctx, sandboxID)
+	final, err := sampleSandboxOverhead(ctx, sandboxID)
 	if err != nil {
 		return err
 	}
 
-	hostFinalCPU := finishSandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	guestFinalCPU := uint64(0)
-	for _, cs := range finishContainersStats {
-		guestFinalCPU += cs.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	}
-
-	var guestMemoryUsage uint64
-	for _, cs := range finishContainersStats {
-		guestMemoryUsage += cs.CgroupStats.MemoryStats.Usage.Usage
-	}
-
-	hostMemoryUsage := finishSandboxStats.CgroupStats.MemoryStats.Usage.Usage
-	deltaTime := finishtTime - initTime
-
-	cpuUsageGuest := float64(guestFinalCPU-guestInitCPU) / float64(deltaTime) * 100
-	cpuUsageHost := float64(hostFinalCPU-hostInitCPU) / float64(deltaTime) * 100
+	o := computeSandboxOverhead(initial, final)
 
 	fmt.Printf("Sandbox overhead for container: %s\n", containerID)
-	fmt.Printf("cpu_overhead=%f\n", cpuUsageHost-cpuUsageGuest)
-	fmt.Printf("memory_overhead_bytes=%d\n\n", hostMemoryUsage-guestMemoryUsage)
+	fmt.Printf("cpu_overhead=%f\n", o.CPUOverhead)
+	fmt.Printf("memory_overhead_bytes=%d\n\n", o.MemoryOverheadBytes)
 	fmt.Printf(" --CPU details--\n")
-	fmt.Printf("cpu_host=%f\n", cpuUsageHost)
-	fmt.Printf("\tcpu_host_init=%d\n", hostInitCPU)
-	fmt.Printf("\tcpu_host_final=%d\n", hostFinalCPU)
-	fmt.Printf("cpu_guest=%f\n", cpuUsageGuest)
-	fmt.Printf("\tcpu_guest_init=%d\n", guestInitCPU)
-	fmt.Printf("\tcpu_guest_final=%d\n", guestFinalCPU)
-	fmt.Printf("Number of available vCPUs=%d\n", finishSandboxStats.Cpus)
+	fmt.Printf("cpu_host=%f\n", o.CPUHost)
+	fmt.Printf("\tcpu_host_init=%d\n", initial.hostCPU)
+	fmt.Printf("\tcpu_host_final=%d\n", final.hostCPU)
+	fmt.Printf("cpu_guest=%f\n", o.CPUGuest)
+	fmt.Printf("\tcpu_guest_init=%d\n", initial.guestCPU)
+	fmt.Printf("\tcpu_guest_final=%d\n", final.guestCPU)
+	fmt.Printf("Number of available vCPUs=%d\n", o.VCPUs)
 	fmt.Printf(" --Memory details--\n")
-	fmt.Printf("memory_host_bytes=%d\n", hostMemoryUsage)
-	fmt.Printf("memory_guest_bytes=%d\n\n", guestMemoryUsage)
+	fmt.Printf("memory_host_bytes=%d\n", o.MemoryHostBytes)
+	fmt.Printf("memory_guest_bytes=%d\n\n", o.MemoryGuestBytes)
 
 	return nil
 }
+
+// overheadSample is a measure of the resource usage of a sandbox, on the
+// host and inside the guest.
+type overheadSample struct {
+	time        int64
+	hostCPU     uint64
+	guestCPU    uint64
+	hostMemory  uint64
+	guestMemory uint64
+	vcpus       uint32
+}
+
+// SandboxOverheadInfo stores the overhead of a sandbox: the resource usage
+// of the sandbox on the host cgroup minus the usage of its containers in the
+// guest.
+type SandboxOverheadInfo struct {
+	CPUOverhead         float64
+	CPUHost             float64
+	CPUGuest            float64
+	MemoryOverheadBytes uint64
+	MemoryHostBytes     uint64
+	MemoryGuestBytes    uint64
+	VCPUs               uint32
+}
+
+func sampleSandboxOverhead(ctx context.Context, sandboxID string) (overheadSample, error) {
+	sandboxStats, containerStats, err := vci.StatsSandbox(ctx, sandboxID)
+	if err != nil {
+		return overheadSample{}, err
+	}
+
+	sample := overheadSample{
+		time:       time.Now().UnixNano(),
+		hostCPU:    sandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage,
+		hostMemory: sandboxStats.CgroupStats.MemoryStats.Usage.Usage,
+		vcpus:      uint32(sandboxStats.Cpus),
+	}
+
+	for _, cs := range containerStats {
+		sample.guestCPU += cs.CgroupStats.CPUStats.CPUUsage.TotalUsage
+		sample.guestMemory += cs.CgroupStats.MemoryStats.Usage.Usage
+	}
+
+	return sample, nil
+}
+
+// computeSandboxOverhead computes the overhead of a sandbox between two
+// samples, the memory overhead being the one of the final sample.
+func computeSandboxOverhead(initial, final overheadSample) SandboxOverheadInfo {
+	deltaTime := final.time - initial.time
+
+	cpuUsageGuest := float64(final.guestCPU-initial.guestCPU) / float64(deltaTime) * 100
+	cpuUsageHost := float64(final.hostCPU-initial.hostCPU) / float64(deltaTime) * 100
+
+	return SandboxOverheadInfo{
+		CPUOverhead:         cpuUsageHost - cpuUsageGuest,
+		CPUHost:             cpuUsageHost,
+		CPUGuest:            cpuUsageGuest,
+		MemoryOverheadBytes: final.hostMemory - final.guestMemory,
+		MemoryHostBytes:     final.hostMemory,
+		MemoryGuestBytes:    final.guestMemory,
+		VCPUs:               final.vcpus,
+	}
+}
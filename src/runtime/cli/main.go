@@ -141,7 +141,9 @@ var runtimeCommands = []cli.Command{
 	kataEnvCLICommand,
 	kataNetworkCLICommand,
 	kataOverheadCLICommand,
+	gcCLICommand,
 	factoryCLICommand,
+	configCLICommand,
 }
 
 // runtimeBeforeSubcommands is the function to run before command-line
@@ -42,6 +42,13 @@ func getContainerInfo(ctx context.Context, containerID string) (vc.ContainerStat
 	if err != nil {
 		return vc.ContainerStatus{}, "", err
 	}
+	if sandboxID == "" {
+		// The containers created by containerd-shim-kata-v2 are only
+		// known by the shim owning their sandbox.
+		if sandboxID, err = findContainerSandbox(ctx, containerID); err != nil {
+			return vc.ContainerStatus{}, "", err
+		}
+	}
 	if sandboxID == "" {
 		// Not finding a container should not trigger an error as
 		// getContainerInfo is used for checking the existence and
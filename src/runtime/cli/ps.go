@@ -8,8 +8,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"text/tabwriter"
 
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
+	ptypes "github.com/gogo/protobuf/types"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
@@ -86,6 +92,15 @@ func ps(ctx context.Context, containerID, format string, args []string) error {
 		return fmt.Errorf("Container %s is not running", containerID)
 	}
 
+	shim, err := newShimClient(sandboxID)
+	if err != nil {
+		return err
+	}
+	if shim != nil {
+		defer shim.Close()
+		return shimPs(ctx, shim, containerID, format, args)
+	}
+
 	var options vc.ProcessListOptions
 
 	options.Args = args
@@ -104,3 +119,48 @@ func ps(ctx context.Context, containerID, format string, args []string) error {
 
 	return nil
 }
+
+// shimPs lists the processes of a container from the shim owning its
+// sandbox. The shim only knows the pid, state and command line of the
+// processes, the ps(1) options cannot be honoured.
+func shimPs(ctx context.Context, shim *shimClient, containerID, format string, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("ps options are not supported for the containers of containerd-shim-kata-v2 sandboxes")
+	}
+
+	resp, err := shim.Pids(ctx, &taskAPI.PidsRequest{ID: containerID})
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		pids := make([]uint32, 0, len(resp.Processes))
+		for _, p := range resp.Processes {
+			pids = append(pids, p.Pid)
+		}
+
+		return json.NewEncoder(os.Stdout).Encode(pids)
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 1, 8, 1, ' ', 0)
+		fmt.Fprintln(w, "PID\tSTAT\tCMD")
+		for _, p := range resp.Processes {
+			state, cmdline := "-", "-"
+			if p.Info != nil {
+				v, err := typeurl.UnmarshalAny(p.Info)
+				if err != nil {
+					return err
+				}
+				if info, ok := v.(*ptypes.Struct); ok {
+					state = info.Fields["state"].GetStringValue()
+					cmdline = info.Fields["cmdline"].GetStringValue()
+				}
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", p.Pid, state, cmdline)
+		}
+
+		return w.Flush()
+	default:
+		return fmt.Errorf("invalid format option %q", format)
+	}
+}
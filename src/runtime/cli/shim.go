@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/cgroups"
+	"github.com/containerd/containerd/api/types/task"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+)
+
+// shimClient is a client of the task API containerd-shim-kata-v2 serves on
+// the management socket of the sandbox it owns.
+type shimClient struct {
+	taskAPI.TaskService
+	client *ttrpc.Client
+}
+
+func (c *shimClient) Close() error {
+	return c.client.Close()
+}
+
+// newShimClient connects to the shim owning the sandbox sandboxID. A nil
+// client is returned when the sandbox is not owned by a shim, as for the
+// sandboxes created by the runtime commands.
+func newShimClient(sandboxID string) (*shimClient, error) {
+	socket, err := katautils.ShimManagementSocketPath(sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(socket); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	client := ttrpc.NewClient(conn)
+
+	return &shimClient{
+		TaskService: taskAPI.NewTaskClient(client),
+		client:      client,
+	}, nil
+}
+
+// findContainerSandbox returns the ID of the sandbox of a container the
+// runtime commands did not create, asking the shims owning the sandboxes.
+func findContainerSandbox(ctx context.Context, containerID string) (string, error) {
+	pattern, err := katautils.ShimManagementSocketPath("*")
+	if err != nil {
+		return "", err
+	}
+
+	sockets, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	for _, socket := range sockets {
+		sandboxID := filepath.Base(filepath.Dir(socket))
+
+		shim, err := newShimClient(sandboxID)
+		if err != nil || shim == nil {
+			// The shim of a sandbox being removed
+			continue
+		}
+
+		_, err = shim.State(ctx, &taskAPI.StateRequest{ID: containerID})
+		shim.Close()
+		if err == nil {
+			return sandboxID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// taskStatusToOCIState translates the status of a shim task into an OCI
+// state.
+func taskStatusToOCIState(status task.Status) string {
+	switch status {
+	case task.StatusCreated:
+		return oci.StateCreated
+	case task.StatusRunning:
+		return oci.StateRunning
+	case task.StatusStopped:
+		return oci.StateStopped
+	case task.StatusPaused, task.StatusPausing:
+		return oci.StatePaused
+	default:
+		return ""
+	}
+}
+
+// shimContainerStats returns the stats of a container from the shim owning
+// its sandbox.
+func shimContainerStats(ctx context.Context, shim *shimClient, containerID string) (*stats, error) {
+	resp, err := shim.Stats(ctx, &taskAPI.StatsRequest{ID: containerID})
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := typeurl.UnmarshalAny(resp.Stats)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, ok := v.(*cgroups.Metrics)
+	if !ok {
+		return nil, fmt.Errorf("unexpected container stats type %T", v)
+	}
+
+	return convertMetricsStats(metrics), nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/cgroups"
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/errdefs"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/store"
+)
+
+// fakeShim serves the task API of a shim owning testContainerID.
+type fakeShim struct {
+	taskAPI.TaskService
+}
+
+func (s *fakeShim) State(ctx context.Context, r *taskAPI.StateRequest) (*taskAPI.StateResponse, error) {
+	if r.ID != testContainerID {
+		return nil, errdefs.ToGRPC(errdefs.ErrNotFound)
+	}
+
+	return &taskAPI.StateResponse{ID: r.ID, Pid: 42, Status: task.StatusRunning}, nil
+}
+
+func (s *fakeShim) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (*taskAPI.ConnectResponse, error) {
+	return &taskAPI.ConnectResponse{ShimPid: 1, TaskPid: 1}, nil
+}
+
+func (s *fakeShim) Stats(ctx context.Context, r *taskAPI.StatsRequest) (*taskAPI.StatsResponse, error) {
+	stats, err := typeurl.MarshalAny(&cgroups.Metrics{
+		Pids: &cgroups.PidsStat{Current: 3, Limit: 10},
+		Memory: &cgroups.MemoryStat{
+			Cache: 1024,
+			Usage: &cgroups.MemoryEntry{Usage: 4096, Limit: 8192},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &taskAPI.StatsResponse{Stats: stats}, nil
+}
+
+func startFakeShim(t *testing.T, sandboxID string) func() {
+	socket, err := katautils.ShimManagementSocketPath(sandboxID)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(socket), testDirMode))
+
+	l, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	server, err := ttrpc.NewServer()
+	assert.NoError(t, err)
+	taskAPI.RegisterTaskService(server, &fakeShim{})
+	go server.Serve(context.Background(), l)
+
+	return func() {
+		server.Close()
+		os.Remove(socket)
+	}
+}
+
+func TestShimClient(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shim-management")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedRunVMStoragePath := store.RunVMStoragePath
+	store.RunVMStoragePath = func() string {
+		return dir
+	}
+	defer func() {
+		store.RunVMStoragePath = savedRunVMStoragePath
+	}()
+
+	ctx := context.Background()
+
+	// Not owned by a shim
+	shim, err := newShimClient(testSandboxID)
+	assert.NoError(err)
+	assert.Nil(shim)
+
+	sandboxID, err := findContainerSandbox(ctx, testContainerID)
+	assert.NoError(err)
+	assert.Empty(sandboxID)
+
+	stop := startFakeShim(t, testSandboxID)
+	defer stop()
+
+	sandboxID, err = findContainerSandbox(ctx, testContainerID)
+	assert.NoError(err)
+	assert.Equal(testSandboxID, sandboxID)
+
+	sandboxID, err = findContainerSandbox(ctx, "unknown")
+	assert.NoError(err)
+	assert.Empty(sandboxID)
+
+	shim, err = newShimClient(testSandboxID)
+	assert.NoError(err)
+	assert.NotNil(shim)
+	defer shim.Close()
+
+	s, err := shimContainerStats(ctx, shim, testContainerID)
+	assert.NoError(err)
+	assert.Equal(uint64(3), s.Pids.Current)
+	assert.Equal(uint64(10), s.Pids.Limit)
+	assert.Equal(uint64(1024), s.Memory.Cache)
+	assert.Equal(memoryEntry{Usage: 4096, Limit: 8192}, s.Memory.Usage)
+	assert.Equal(memoryEntry{}, s.Memory.Swap)
+}
+
+func TestTaskStatusToOCIState(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(oci.StateCreated, taskStatusToOCIState(task.StatusCreated))
+	assert.Equal(oci.StateRunning, taskStatusToOCIState(task.StatusRunning))
+	assert.Equal(oci.StateStopped, taskStatusToOCIState(task.StatusStopped))
+	assert.Equal(oci.StatePaused, taskStatusToOCIState(task.StatusPaused))
+	assert.Equal(oci.StatePaused, taskStatusToOCIState(task.StatusPausing))
+	assert.Empty(taskStatusToOCIState(task.StatusUnknown))
+}
+
+func TestParseNumericUser(t *testing.T) {
+	assert := assert.New(t)
+
+	uid, gid, err := parseNumericUser("1000")
+	assert.NoError(err)
+	assert.Equal(uint32(1000), uid)
+	assert.Equal(uint32(0), gid)
+
+	uid, gid, err = parseNumericUser("1000:100")
+	assert.NoError(err)
+	assert.Equal(uint32(1000), uid)
+	assert.Equal(uint32(100), gid)
+
+	_, _, err = parseNumericUser("root")
+	assert.Error(err)
+
+	_, _, err = parseNumericUser("1000:users")
+	assert.Error(err)
+}
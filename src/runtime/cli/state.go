@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/urfave/cli"
@@ -50,7 +51,7 @@ func state(ctx context.Context, containerID string) error {
 	setExternalLoggers(ctx, kataLog)
 
 	// Checks the MUST and MUST NOT from OCI runtime specification
-	status, _, err := getExistingContainerInfo(ctx, containerID)
+	status, sandboxID, err := getExistingContainerInfo(ctx, containerID)
 	if err != nil {
 		return err
 	}
@@ -58,6 +59,24 @@ func state(ctx context.Context, containerID string) error {
 	// Convert the status to the expected State structure
 	state := oci.StatusToOCIState(status)
 
+	// The shim owning the sandbox knows the status of the container
+	// process, and its pid.
+	shim, err := newShimClient(sandboxID)
+	if err != nil {
+		return err
+	}
+	if shim != nil {
+		defer shim.Close()
+
+		resp, err := shim.State(ctx, &taskAPI.StateRequest{ID: status.ID})
+		if err != nil {
+			return err
+		}
+
+		state.Status = taskStatusToOCIState(resp.Status)
+		state.Pid = int(resp.Pid)
+	}
+
 	stateJSON, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return err
@@ -11,6 +11,7 @@ import (
 	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/errdefs"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	ptypes "github.com/gogo/protobuf/types"
 	"github.com/opencontainers/runtime-spec/specs-go"
 
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
@@ -31,6 +32,12 @@ type container struct {
 	bundle   string
 	cType    vc.ContainerType
 	exit     uint32
+	// hookState is the state passed to the post-stop hooks, saved before
+	// the sandbox VM stops.
+	hookState *specs.State
+	// stats are the last statistics of the container, returned while the
+	// sandbox VM is paused.
+	stats    *ptypes.Any
 	status   task.Status
 	terminal bool
 	mounted  bool
@@ -12,11 +12,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	containerd_types "github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/mount"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
+	ptypes "github.com/gogo/protobuf/types"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -33,7 +35,13 @@ import (
 
 func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*container, error) {
 	rootFs := vc.RootFs{}
-	if len(r.Rootfs) == 1 {
+	layers, err := layerImages(r.Rootfs)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) > 0 {
+		rootFs.Layers = layers
+	} else if len(r.Rootfs) == 1 {
 		m := r.Rootfs[0]
 		rootFs.Source = m.Source
 		rootFs.Type = m.Type
@@ -65,6 +73,10 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 			return nil, err
 		}
 
+		if err = setupTracing(s.config); err != nil {
+			return nil, err
+		}
+
 		if rootFs.Mounted, err = checkAndMount(s, r); err != nil {
 			return nil, err
 		}
@@ -89,6 +101,10 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		}
 		s.sandbox = sandbox
 
+		if err := startManagementServer(s); err != nil {
+			logrus.WithError(err).Warn("failed to start shim management server")
+		}
+
 	case vc.PodContainer:
 		if s.sandbox == nil {
 			return nil, fmt.Errorf("BUG: Cannot start the container, since the sandbox hasn't been created")
@@ -132,6 +148,10 @@ func loadSpec(r *taskAPI.CreateTaskRequest) (*specs.Spec, string, error) {
 		return nil, "", err
 	}
 
+	if err := oci.ApplyCDIDevices(bundlePath, &ociSpec); err != nil {
+		return nil, "", err
+	}
+
 	return &ociSpec, bundlePath, nil
 }
 
@@ -139,15 +159,12 @@ func loadSpec(r *taskAPI.CreateTaskRequest) (*specs.Spec, string, error) {
 // 1. podsandbox annotation
 // 2. shimv2 create task option
 // 3. environment
-func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string]string) (*oci.RuntimeConfig, error) {
-	if s.config != nil {
-		return s.config, nil
-	}
+func runtimeConfigPath(anno map[string]string, options *ptypes.Any) (string, error) {
 	configPath := oci.GetSandboxConfigPath(anno)
-	if configPath == "" && r.Options != nil {
-		v, err := typeurl.UnmarshalAny(r.Options)
+	if configPath == "" && options != nil {
+		v, err := typeurl.UnmarshalAny(options)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		option, ok := v.(*crioption.Options)
 		// cri default runtime handler will pass a linux runc options,
@@ -162,7 +179,20 @@ func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string
 		configPath = os.Getenv("KATA_CONF_FILE")
 	}
 
-	_, runtimeConfig, err := katautils.LoadConfiguration(configPath, false, true)
+	return configPath, nil
+}
+
+func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string]string) (*oci.RuntimeConfig, error) {
+	if s.config != nil {
+		return s.config, nil
+	}
+
+	configPath, err := runtimeConfigPath(anno, r.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedPath, runtimeConfig, err := katautils.LoadConfiguration(configPath, false, true)
 	if err != nil {
 		return nil, err
 	}
@@ -170,12 +200,48 @@ func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string
 	// For the unit test, the config will be predefined
 	if s.config == nil {
 		s.config = &runtimeConfig
+		s.configPath = resolvedPath
 	}
 
 	return &runtimeConfig, nil
 }
 
+// layerImages returns the layer images the rootfs is made of, when its
+// mounts are all of the layer image type. The mounts are listed top-most
+// first, and the filesystem type of the images is given by the "fstype="
+// option, defaulting to ext4.
+func layerImages(mounts []*containerd_types.Mount) ([]vc.LayerImage, error) {
+	var layers []vc.LayerImage
+	for _, m := range mounts {
+		if m.Type != vc.LayerImageMountType {
+			continue
+		}
+
+		layer := vc.LayerImage{
+			Source: m.Source,
+			Fstype: "ext4",
+		}
+		for _, opt := range m.Options {
+			if strings.HasPrefix(opt, "fstype=") {
+				layer.Fstype = strings.TrimPrefix(opt, "fstype=")
+			}
+		}
+		layers = append(layers, layer)
+	}
+
+	if len(layers) > 0 && len(layers) != len(mounts) {
+		return nil, fmt.Errorf("rootfs mixes layer images with other mounts")
+	}
+
+	return layers, nil
+}
+
 func checkAndMount(s *service, r *taskAPI.CreateTaskRequest) (bool, error) {
+	// Layer images are overlaid inside the guest
+	if layers, _ := layerImages(r.Rootfs); len(layers) > 0 {
+		return false, nil
+	}
+
 	if len(r.Rootfs) == 1 {
 		m := r.Rootfs[0]
 
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	containerd_types "github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/namespaces"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	crioption "github.com/containerd/cri-containerd/pkg/api/runtimeoptions/v1"
@@ -267,6 +268,70 @@ func TestCreateContainerSuccess(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestCreateContainerWhenPodPaused(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &pausableSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+		paused:  true,
+	}
+
+	testingImpl.CreateContainerFunc = func(ctx context.Context, sandboxID string, containerConfig vc.ContainerConfig) (vc.VCSandbox, vc.VCContainer, error) {
+		if sandbox.paused {
+			return nil, nil, fmt.Errorf("sandbox %s is paused", sandboxID)
+		}
+		return sandbox, &vcmock.Container{}, nil
+	}
+
+	defer func() {
+		testingImpl.CreateContainerFunc = nil
+	}()
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	bundlePath := filepath.Join(tmpdir, "bundle")
+
+	err = makeOCIBundle(bundlePath)
+	assert.NoError(err)
+
+	ociConfigFile := filepath.Join(bundlePath, "config.json")
+	spec, err := compatoci.ParseConfigJSON(bundlePath)
+	assert.NoError(err)
+
+	spec.Annotations = make(map[string]string)
+	spec.Annotations[testContainerTypeAnnotation] = testContainerTypeContainer
+	spec.Annotations[testSandboxIDAnnotation] = testSandboxID
+
+	err = writeOCIConfigFile(spec, ociConfigFile)
+	assert.NoError(err)
+
+	s := &service{
+		id:         testContainerID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+		config:     &runtimeConfig,
+		ctx:        context.Background(),
+	}
+
+	req := &taskAPI.CreateTaskRequest{
+		ID:       testContainerID,
+		Bundle:   bundlePath,
+		Terminal: true,
+	}
+
+	// The VM of the pod is resumed for the agent to create the container
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+	_, err = s.Create(ctx, req)
+	assert.NoError(err)
+	assert.False(sandbox.paused)
+	assert.Equal(1, sandbox.resumes)
+}
+
 func TestCreateContainerFail(t *testing.T) {
 	assert := assert.New(t)
 
@@ -498,3 +563,29 @@ func TestCreateLoadRuntimeConfig(t *testing.T) {
 	_, err = loadRuntimeConfig(s, r, anno)
 	assert.NoError(err)
 }
+
+func TestLayerImages(t *testing.T) {
+	assert := assert.New(t)
+
+	layers, err := layerImages([]*containerd_types.Mount{
+		{Type: "overlay", Source: "overlay"},
+	})
+	assert.NoError(err)
+	assert.Empty(layers)
+
+	layers, err = layerImages([]*containerd_types.Mount{
+		{Type: vc.LayerImageMountType, Source: "/var/lib/layers/app.img", Options: []string{"fstype=erofs"}},
+		{Type: vc.LayerImageMountType, Source: "/var/lib/layers/base.img"},
+	})
+	assert.NoError(err)
+	assert.Equal([]vc.LayerImage{
+		{Source: "/var/lib/layers/app.img", Fstype: "erofs"},
+		{Source: "/var/lib/layers/base.img", Fstype: "ext4"},
+	}, layers)
+
+	_, err = layerImages([]*containerd_types.Mount{
+		{Type: vc.LayerImageMountType, Source: "/var/lib/layers/base.img"},
+		{Type: "bind", Source: "/var/lib/rootfs"},
+	})
+	assert.Error(err)
+}
@@ -34,8 +34,13 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 		}
 	}
 
-	// Run post-stop OCI hooks.
-	if err := katautils.PostStopHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle); err != nil {
+	// Run post-stop OCI hooks, with the state saved when the sandbox VM
+	// was stopped if it was.
+	if c.hookState == nil {
+		state := katautils.HookState(s.sandbox, s.sandbox.ID(), c.bundle)
+		c.hookState = &state
+	}
+	if err := katautils.PostStopHooks(ctx, *c.spec, *c.hookState); err != nil {
 		return err
 	}
 
@@ -50,3 +55,17 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 
 	return nil
 }
+
+// saveHookStates saves the state passed to the post-stop hooks of the
+// containers. It carries the hypervisor PID, and must then be built while
+// the sandbox VM runs.
+func saveHookStates(s *service) {
+	for _, c := range s.containers {
+		if c.hookState != nil {
+			continue
+		}
+
+		state := katautils.HookState(s.sandbox, s.sandbox.ID(), c.bundle)
+		c.hookState = &state
+	}
+}
@@ -44,6 +44,34 @@ func TestDeleteContainerSuccessAndFail(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestSaveHookStates(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID:            testSandboxID,
+		MockHypervisorPid: 1234,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	s.containers[testContainerID] = &container{id: testContainerID, bundle: "/test/bundle"}
+
+	saveHookStates(s)
+	state := s.containers[testContainerID].hookState
+	assert.NotNil(state)
+	assert.Equal(1234, state.Pid)
+	assert.Equal("/test/bundle", state.Bundle)
+
+	// The state saved while the VM ran is kept
+	sandbox.MockHypervisorPid = 0
+	saveHookStates(s)
+	assert.Equal(1234, s.containers[testContainerID].hookState.Pid)
+}
+
 func testConfigSetup(t *testing.T) (rootPath string, bundlePath string) {
 	assert := assert.New(t)
 
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"path/filepath"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	kataevents "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
+	"github.com/sirupsen/logrus"
+)
+
+// eventsFile is the name of the file the sandbox events are appended to,
+// in the sandbox run directory.
+const eventsFile = "events.jsonl"
+
+// eventSink forwards the sandbox events to containerd and appends them to
+// the sandbox events file.
+type eventSink struct {
+	s    *service
+	file *kataevents.FileSink
+}
+
+func newEventSink(s *service) *eventSink {
+	sink := &eventSink{s: s}
+
+	driver, err := persist.GetDriver()
+	if err != nil {
+		logrus.WithError(err).Warn("no sandbox directory, sandbox events are not saved")
+		return sink
+	}
+
+	sink.file = kataevents.NewFileSink(filepath.Join(driver.RunStoragePath(), s.id, eventsFile))
+
+	return sink
+}
+
+// Send implements kataevents.Sink. The sandbox events are sent from the
+// virtcontainers calls, possibly made with the service lock held: an event
+// is dropped rather than blocking them when the events channel is full.
+func (e *eventSink) Send(evt kataevents.Event) {
+	if e.file != nil {
+		e.file.Send(evt)
+	}
+
+	// for unit test, it will not initialize s.events
+	if e.s.events == nil {
+		return
+	}
+
+	select {
+	case e.s.events <- evt:
+	default:
+		logrus.WithField("topic", evt.Topic()).Warn("events channel full, sandbox event dropped")
+	}
+}
+
+// close closes the sandbox events file, once the sandbox is deleted.
+func (e *eventSink) close() {
+	if e == nil || e.file == nil {
+		return
+	}
+
+	if err := e.file.Close(); err != nil {
+		logrus.WithError(err).Warn("failed to close sandbox events file")
+	}
+}
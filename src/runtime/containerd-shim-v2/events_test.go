@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kataevents "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
+)
+
+func TestEventSinkSendFullChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:     testSandboxID,
+		events: make(chan interface{}, 1),
+	}
+	sink := &eventSink{s: s}
+
+	sink.Send(&kataevents.SandboxBooted{SandboxId: testSandboxID})
+
+	// The channel is full, the event is dropped rather than blocking
+	sink.Send(&kataevents.SandboxResized{SandboxId: testSandboxID})
+
+	assert.Len(s.events, 1)
+	assert.IsType(&kataevents.SandboxBooted{}, <-s.events)
+
+	// Nothing to close without events file, nor without sink
+	sink.close()
+	s.eventSink.close()
+}
@@ -33,6 +33,11 @@ type exec struct {
 	exitCh   chan uint32
 
 	exitTime time.Time
+
+	// timeout kills the exec at deadline, when the exec timeout is
+	// elapsed.
+	timeout  *time.Timer
+	deadline time.Time
 }
 
 type tty struct {
@@ -102,11 +107,20 @@ func newExec(c *container, stdin, stdout, stderr string, terminal bool, jspec *g
 		User:            fmt.Sprintf("%d", spec.User.UID),
 		PrimaryGroup:    fmt.Sprintf("%d", spec.User.GID),
 		WorkDir:         spec.Cwd,
+		Capabilities:    spec.Capabilities,
+		Rlimits:         spec.Rlimits,
+		ApparmorProfile: spec.ApparmorProfile,
+		SelinuxLabel:    spec.SelinuxLabel,
+		OOMScoreAdj:     spec.OOMScoreAdj,
 		Interactive:     terminal,
 		Detach:          !terminal,
 		NoNewPrivileges: spec.NoNewPrivileges,
 	}
 
+	for _, gid := range spec.User.AdditionalGids {
+		cmds.SupplementaryGroups = append(cmds.SupplementaryGroups, fmt.Sprintf("%d", gid))
+	}
+
 	exec := &exec{
 		container: c,
 		cmds:      cmds,
@@ -8,11 +8,18 @@ package containerdshim
 
 import (
 	"context"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/namespaces"
-
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 
 	"github.com/stretchr/testify/assert"
@@ -48,3 +55,132 @@ func TestExecNoSpecFail(t *testing.T) {
 	_, err = s.Exec(ctx, reqExec)
 	assert.Error(err)
 }
+
+func TestNewExecProcessSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	oomScoreAdj := 100
+	spec := &specs.Process{
+		Args: []string{"sh"},
+		Env:  []string{"PATH=/bin", "EMPTY"},
+		Cwd:  "/work",
+		User: specs.User{
+			UID:            1000,
+			GID:            1001,
+			AdditionalGids: []uint32{10, 20},
+		},
+		Capabilities: &specs.LinuxCapabilities{
+			Bounding: []string{"CAP_NET_RAW"},
+		},
+		Rlimits: []specs.POSIXRlimit{
+			{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 1024},
+		},
+		NoNewPrivileges: true,
+		ApparmorProfile: "kata-default",
+		SelinuxLabel:    "system_u:system_r:container_t:s0",
+		OOMScoreAdj:     &oomScoreAdj,
+	}
+
+	any, err := typeurl.MarshalAny(spec)
+	assert.NoError(err)
+
+	execs, err := newExec(nil, "", "", "", false, any)
+	assert.NoError(err)
+
+	cmd := execs.cmds
+	assert.Equal(spec.Args, cmd.Args)
+	assert.Equal("/work", cmd.WorkDir)
+	assert.Equal("1000", cmd.User)
+	assert.Equal("1001", cmd.PrimaryGroup)
+	assert.Equal([]string{"10", "20"}, cmd.SupplementaryGroups)
+	assert.Equal(spec.Capabilities, cmd.Capabilities)
+	assert.Equal(spec.Rlimits, cmd.Rlimits)
+	assert.True(cmd.NoNewPrivileges)
+	assert.Equal("kata-default", cmd.ApparmorProfile)
+	assert.Equal("system_u:system_r:container_t:s0", cmd.SelinuxLabel)
+	assert.Equal(&oomScoreAdj, cmd.OOMScoreAdj)
+	assert.True(cmd.Detach)
+}
+
+// signalSandbox records the signals sent to the processes of the sandbox.
+type signalSandbox struct {
+	*vcmock.Sandbox
+	signals chan syscall.Signal
+}
+
+func (s *signalSandbox) SignalProcess(containerID, processID string, signal syscall.Signal, all bool) error {
+	s.signals <- signal
+	return nil
+}
+
+func TestExecTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &signalSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+		signals: make(chan syscall.Signal, 1),
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+		config:     &oci.RuntimeConfig{},
+	}
+
+	var err error
+	s.containers[testContainerID], err = newContainer(s, &taskAPI.CreateTaskRequest{
+		ID: testContainerID,
+	}, vc.PodContainer, nil, false)
+	assert.NoError(err)
+	c := s.containers[testContainerID]
+
+	execs := &exec{
+		container: c,
+		id:        "exec-token",
+		status:    task.StatusRunning,
+	}
+
+	// No timeout configured
+	setExecTimeout(s, c, testContainerID, execs)
+	assert.Nil(execs.timeout)
+
+	// A running exec is killed once the timeout is elapsed
+	s.config.ExecTimeout = 10 * time.Millisecond
+	setExecTimeout(s, c, testContainerID, execs)
+	assert.NotNil(execs.timeout)
+
+	select {
+	case signal := <-sandbox.signals:
+		assert.Equal(syscall.SIGKILL, signal)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out exec not killed")
+	}
+
+	// An exec which has exited is not
+	execs.status = task.StatusStopped
+	setExecTimeout(s, c, testContainerID, execs)
+
+	select {
+	case <-sandbox.signals:
+		t.Fatal("exited exec killed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A recovered exec is killed at the deadline it was started with
+	s.config.ExecTimeout = time.Hour
+	execs = &exec{
+		container: c,
+		id:        "exec-token",
+		status:    task.StatusRunning,
+		deadline:  time.Now().Add(10 * time.Millisecond),
+	}
+	setExecTimeout(s, c, testContainerID, execs)
+
+	select {
+	case signal := <-sandbox.signals:
+		assert.Equal(syscall.SIGKILL, signal)
+	case <-time.After(5 * time.Second):
+		t.Fatal("recovered exec not killed at its deadline")
+	}
+}
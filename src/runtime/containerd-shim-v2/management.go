@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/errdefs"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/sirupsen/logrus"
+)
+
+// startManagementServer serves the task API of the shim on the management
+// socket of the sandbox, for the runtime commands to reach the containers
+// of the sandbox through the shim owning it. Only the calls the runtime
+// commands need are served, see managementService.
+func startManagementServer(s *service) error {
+	// for unit test, the server is not started unless asked to
+	if s.managementSocket == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.managementSocket), 0750); err != nil {
+		return err
+	}
+
+	// Remove the socket of a previous shim of the sandbox.
+	if err := os.Remove(s.managementSocket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	l, err := net.Listen("unix", s.managementSocket)
+	if err != nil {
+		return err
+	}
+
+	// Exec processes are run on behalf of the clients, only root may
+	// connect whatever the umask the shim inherited. The parent directory
+	// is not readable by the other users meanwhile.
+	if err := os.Chmod(s.managementSocket, 0600); err != nil {
+		l.Close()
+		return err
+	}
+
+	server, err := ttrpc.NewServer()
+	if err != nil {
+		l.Close()
+		return err
+	}
+	taskAPI.RegisterTaskService(server, &managementService{s})
+	s.managementServer = server
+
+	go func() {
+		defer l.Close()
+		if err := server.Serve(s.ctx, l); err != nil && err != ttrpc.ErrServerClosed {
+			logrus.WithError(err).Warn("shim management server failure")
+		}
+	}()
+
+	return nil
+}
+
+var _ taskAPI.TaskService = &managementService{}
+
+// managementService is the task API served on the management socket. The
+// lifecycle of the containers and of the shim is owned by containerd: the
+// runtime commands can only query the containers and run exec processes in
+// them.
+type managementService struct {
+	s *service
+}
+
+// task returns the task API serving the calls: the one of the shared shim
+// when it serves the sandbox, routing them to the service of the sandbox of
+// the container, or the one of the sandbox otherwise.
+func (m *managementService) task() taskAPI.TaskService {
+	if m.s.shared != nil {
+		return m.s.shared
+	}
+	return m.s
+}
+
+func errManagementDenied(call string) error {
+	return errdefs.ToGRPCf(errdefs.ErrNotImplemented, "%s is not served on the shim management socket", call)
+}
+
+func (m *managementService) State(ctx context.Context, r *taskAPI.StateRequest) (*taskAPI.StateResponse, error) {
+	return m.task().State(ctx, r)
+}
+
+func (m *managementService) Pids(ctx context.Context, r *taskAPI.PidsRequest) (*taskAPI.PidsResponse, error) {
+	return m.task().Pids(ctx, r)
+}
+
+func (m *managementService) Stats(ctx context.Context, r *taskAPI.StatsRequest) (*taskAPI.StatsResponse, error) {
+	return m.task().Stats(ctx, r)
+}
+
+func (m *managementService) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (*taskAPI.ConnectResponse, error) {
+	return m.task().Connect(ctx, r)
+}
+
+func (m *managementService) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (*ptypes.Empty, error) {
+	return m.task().Exec(ctx, r)
+}
+
+// The calls below are only served for the exec processes, the ones of the
+// containers themselves are containerd's.
+
+func (m *managementService) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.StartResponse, error) {
+	if r.ExecID == "" {
+		return nil, errManagementDenied("Start")
+	}
+	return m.task().Start(ctx, r)
+}
+
+func (m *managementService) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAPI.DeleteResponse, error) {
+	if r.ExecID == "" {
+		return nil, errManagementDenied("Delete")
+	}
+	return m.task().Delete(ctx, r)
+}
+
+func (m *managementService) Wait(ctx context.Context, r *taskAPI.WaitRequest) (*taskAPI.WaitResponse, error) {
+	if r.ExecID == "" {
+		return nil, errManagementDenied("Wait")
+	}
+	return m.task().Wait(ctx, r)
+}
+
+func (m *managementService) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (*ptypes.Empty, error) {
+	if r.ExecID == "" {
+		return nil, errManagementDenied("ResizePty")
+	}
+	return m.task().ResizePty(ctx, r)
+}
+
+func (m *managementService) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (*taskAPI.CreateTaskResponse, error) {
+	return nil, errManagementDenied("Create")
+}
+
+func (m *managementService) Pause(ctx context.Context, r *taskAPI.PauseRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Pause")
+}
+
+func (m *managementService) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Resume")
+}
+
+func (m *managementService) Checkpoint(ctx context.Context, r *taskAPI.CheckpointTaskRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Checkpoint")
+}
+
+func (m *managementService) Kill(ctx context.Context, r *taskAPI.KillRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Kill")
+}
+
+func (m *managementService) CloseIO(ctx context.Context, r *taskAPI.CloseIORequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("CloseIO")
+}
+
+func (m *managementService) Update(ctx context.Context, r *taskAPI.UpdateTaskRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Update")
+}
+
+func (m *managementService) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (*ptypes.Empty, error) {
+	return nil, errManagementDenied("Shutdown")
+}
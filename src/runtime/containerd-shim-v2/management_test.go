@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/errdefs"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
+	"github.com/stretchr/testify/assert"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+)
+
+func TestManagementServer(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shim-management")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &service{
+		id:         testSandboxID,
+		ctx:        ctx,
+		sandbox:    &vcmock.Sandbox{MockID: testSandboxID},
+		containers: make(map[string]*container),
+	}
+
+	// Nothing served unless asked to
+	assert.NoError(startManagementServer(s))
+
+	s.containers[testContainerID], err = newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testContainerID,
+		Bundle: testBundleDir,
+	}, vc.PodContainer, nil, false)
+	assert.NoError(err)
+
+	s.managementSocket = filepath.Join(dir, testSandboxID, "shim-management.sock")
+
+	// A stale socket of a previous shim is replaced
+	assert.NoError(os.MkdirAll(filepath.Dir(s.managementSocket), 0750))
+	assert.NoError(ioutil.WriteFile(s.managementSocket, nil, 0600))
+
+	assert.NoError(startManagementServer(s))
+
+	// Only root can connect
+	fi, err := os.Stat(s.managementSocket)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), fi.Mode().Perm())
+
+	conn, err := net.Dial("unix", s.managementSocket)
+	assert.NoError(err)
+
+	client := ttrpc.NewClient(conn)
+	defer client.Close()
+
+	resp, err := taskAPI.NewTaskClient(client).State(ctx, &taskAPI.StateRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(testContainerID, resp.ID)
+	assert.Equal(task.StatusCreated, resp.Status)
+
+	// The lifecycle of the containers and of the shim is not served
+	_, err = taskAPI.NewTaskClient(client).Shutdown(ctx, &taskAPI.ShutdownRequest{ID: testSandboxID})
+	assert.True(errdefs.IsNotImplemented(errdefs.FromGRPC(err)))
+
+	_, err = taskAPI.NewTaskClient(client).Kill(ctx, &taskAPI.KillRequest{ID: testContainerID, Signal: 9})
+	assert.True(errdefs.IsNotImplemented(errdefs.FromGRPC(err)))
+
+	_, err = taskAPI.NewTaskClient(client).Delete(ctx, &taskAPI.DeleteRequest{ID: testContainerID})
+	assert.True(errdefs.IsNotImplemented(errdefs.FromGRPC(err)))
+	assert.Contains(s.containers, testContainerID)
+}
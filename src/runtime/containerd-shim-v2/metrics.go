@@ -29,6 +29,12 @@ func marshalMetrics(s *service, containerID string) (*google_protobuf.Any, error
 	return data, nil
 }
 
+// marshalEmptyMetrics returns the metrics of a container no statistics
+// were read for.
+func marshalEmptyMetrics() (*google_protobuf.Any, error) {
+	return typeurl.MarshalAny(&cgroups.Metrics{})
+}
+
 func statsToMetrics(stats *vc.ContainerStats) *cgroups.Metrics {
 	metrics := &cgroups.Metrics{}
 
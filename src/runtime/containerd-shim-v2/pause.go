@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// podPaused returns whether the workload of the pod is all paused. The
+// sandbox container of a pod with other containers runs no workload and is
+// not paused by the container managers, it is not taken into account.
+func podPaused(s *service) bool {
+	paused := false
+
+	for _, c := range s.containers {
+		if c.cType.IsSandbox() && len(s.containers) > 1 {
+			continue
+		}
+
+		if c.status != task.StatusPaused {
+			return false
+		}
+		paused = true
+	}
+
+	return paused
+}
+
+// pauseSandbox pauses the VM of the sandbox once every container of the pod
+// is paused, so that the pod uses no CPU time on the host.
+func pauseSandbox(s *service) {
+	if s.sandbox == nil || !podPaused(s) {
+		return
+	}
+
+	if err := s.sandbox.Pause(); err != nil {
+		logrus.WithError(err).WithField("sandbox", s.sandbox.ID()).Warn("failed to pause sandbox")
+	}
+}
+
+// sandboxPaused returns whether the VM of the sandbox is paused, the agent
+// not answering any request then.
+func sandboxPaused(s *service) bool {
+	return s.sandbox != nil && s.sandbox.Status().State.State == types.StatePaused
+}
+
+// resumeSandbox resumes the VM of the sandbox when it is paused, the agent
+// serving the requests on the containers only while it runs.
+func resumeSandbox(s *service) error {
+	if !sandboxPaused(s) {
+		return nil
+	}
+
+	return s.sandbox.Resume()
+}
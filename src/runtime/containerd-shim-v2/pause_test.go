@@ -8,8 +8,10 @@ package containerdshim
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/namespaces"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 
@@ -201,3 +203,169 @@ func TestResumeContainerFail(t *testing.T) {
 	_, err := s.Resume(ctx, reqResume)
 	assert.Error(err)
 }
+
+// pausableSandbox records the pauses and resumes of the sandbox VM.
+type pausableSandbox struct {
+	*vcmock.Sandbox
+	paused  bool
+	pauses  int
+	resumes int
+	stats   int
+}
+
+func (s *pausableSandbox) StatsContainer(containerID string) (vc.ContainerStats, error) {
+	if s.paused {
+		return vc.ContainerStats{}, fmt.Errorf("sandbox %s is paused", s.MockID)
+	}
+
+	s.stats++
+	return vc.ContainerStats{}, nil
+}
+
+func (s *pausableSandbox) Pause() error {
+	s.paused = true
+	s.pauses++
+	return nil
+}
+
+func (s *pausableSandbox) Resume() error {
+	s.paused = false
+	s.resumes++
+	return nil
+}
+
+func (s *pausableSandbox) Status() vc.SandboxStatus {
+	state := types.StateRunning
+	if s.paused {
+		state = types.StatePaused
+	}
+
+	return vc.SandboxStatus{
+		ID:    s.MockID,
+		State: types.SandboxState{State: state},
+	}
+}
+
+func TestPauseSandboxWhenPodPaused(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &pausableSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	var err error
+	for id, cType := range map[string]vc.ContainerType{
+		testSandboxID:   vc.PodSandbox,
+		testContainerID: vc.PodContainer,
+		"43":            vc.PodContainer,
+	} {
+		s.containers[id], err = newContainer(s, &taskAPI.CreateTaskRequest{ID: id}, cType, nil, false)
+		assert.NoError(err)
+	}
+
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+
+	// The VM is paused once the last workload container is
+	_, err = s.Pause(ctx, &taskAPI.PauseRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.False(podPaused(s))
+	assert.Zero(sandbox.pauses)
+
+	_, err = s.Pause(ctx, &taskAPI.PauseRequest{ID: "43"})
+	assert.NoError(err)
+	assert.True(podPaused(s))
+	assert.Equal(1, sandbox.pauses)
+
+	// and resumed before one of them is
+	_, err = s.Resume(ctx, &taskAPI.ResumeRequest{ID: "43"})
+	assert.NoError(err)
+	assert.Equal(1, sandbox.resumes)
+	assert.False(sandbox.paused)
+
+	// A running VM is left as is
+	_, err = s.Resume(ctx, &taskAPI.ResumeRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(1, sandbox.resumes)
+
+	// The VM is resumed to signal a container
+	_, err = s.Pause(ctx, &taskAPI.PauseRequest{ID: testContainerID})
+	assert.NoError(err)
+	_, err = s.Pause(ctx, &taskAPI.PauseRequest{ID: "43"})
+	assert.NoError(err)
+	assert.True(sandbox.paused)
+
+	_, err = s.Kill(ctx, &taskAPI.KillRequest{ID: "43", Signal: 9})
+	assert.NoError(err)
+	assert.False(sandbox.paused)
+	assert.Equal(2, sandbox.resumes)
+}
+
+func TestStatsWhenPodPaused(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &pausableSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	c, err := newContainer(s, &taskAPI.CreateTaskRequest{ID: testContainerID}, vc.PodContainer, nil, false)
+	assert.NoError(err)
+	c.status = task.StatusRunning
+	s.containers[testContainerID] = c
+
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+
+	// Empty statistics are returned when none were read before the pause
+	sandbox.paused = true
+	resp, err := s.Stats(ctx, &taskAPI.StatsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.NotNil(resp.Stats)
+	assert.Zero(sandbox.stats)
+
+	sandbox.paused = false
+	resp, err = s.Stats(ctx, &taskAPI.StatsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(1, sandbox.stats)
+
+	// and the last ones otherwise, without reaching the agent
+	sandbox.paused = true
+	paused, err := s.Stats(ctx, &taskAPI.StatsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(resp.Stats, paused.Stats)
+	assert.Equal(1, sandbox.stats)
+
+	pids, err := s.Pids(ctx, &taskAPI.PidsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Empty(pids.Processes)
+	assert.Zero(sandbox.resumes)
+}
+
+func TestPodPausedSingleContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:         testSandboxID,
+		containers: make(map[string]*container),
+	}
+	assert.False(podPaused(s))
+
+	var err error
+	s.containers[testSandboxID], err = newContainer(s, &taskAPI.CreateTaskRequest{ID: testSandboxID}, vc.PodSandbox, nil, false)
+	assert.NoError(err)
+	assert.False(podPaused(s))
+
+	// The sandbox container is the workload of a single container pod
+	s.containers[testSandboxID].status = task.StatusPaused
+	assert.True(podPaused(s))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/typeurl"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/sirupsen/logrus"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+// guestPsArgs are the ps(1) arguments the agent lists the details of the
+// container processes with.
+var guestPsArgs = []string{"-ww", "-o", "pid,stat,args"}
+
+// guestExecIDsFormat is the process list format the agent reports the
+// exec IDs of the container processes with, keyed by guest pid.
+const guestExecIDsFormat = "exec_ids"
+
+// guestProcess is a process of a container, as seen from the guest.
+type guestProcess struct {
+	pid     uint32
+	state   string
+	cmdline string
+
+	// execID is the ID the agent started the process with.
+	execID string
+}
+
+// listGuestProcesses returns the processes of a running container. The
+// process IDs are the ones of the guest, they do not exist on the host.
+func listGuestProcesses(s *service, containerID string) ([]guestProcess, error) {
+	list, err := s.sandbox.ProcessListContainer(containerID, vc.ProcessListOptions{Format: guestExecIDsFormat})
+	if err != nil {
+		return nil, err
+	}
+
+	execIDs := make(map[uint32]string)
+	if len(list) > 0 {
+		if err := json.Unmarshal(list, &execIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	pids := make([]uint32, 0, len(execIDs))
+	for pid := range execIDs {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	// The details come from ps(1) in the guest, the process IDs are still
+	// useful without them when ps(1) is not available.
+	details := make(map[uint32]guestProcess)
+	table, err := s.sandbox.ProcessListContainer(containerID, vc.ProcessListOptions{
+		Format: "table",
+		Args:   guestPsArgs,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("container", containerID).Warn("failed to get guest processes details")
+	} else {
+		details = parseGuestPsTable(string(table))
+	}
+
+	processes := make([]guestProcess, 0, len(pids))
+	for _, pid := range pids {
+		p, ok := details[pid]
+		if !ok {
+			p = guestProcess{pid: pid}
+		}
+		p.execID = execIDs[pid]
+		processes = append(processes, p)
+	}
+
+	return processes, nil
+}
+
+// parseGuestPsTable parses the output of ps(1) run with guestPsArgs.
+func parseGuestPsTable(table string) map[uint32]guestProcess {
+	processes := make(map[uint32]guestProcess)
+
+	lines := strings.Split(table, "\n")
+	if len(lines) < 2 {
+		return processes
+	}
+
+	// lines[0] is the header
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		pid, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		processes[uint32(pid)] = guestProcess{
+			pid:     uint32(pid),
+			state:   fields[1],
+			cmdline: strings.Join(fields[2:], " "),
+		}
+	}
+
+	return processes
+}
+
+// processExecID returns the ID of the exec of c running p, or an empty
+// string when p is the container process. ok is false when p was not
+// started by the shim. The agent starts the container process with the
+// container ID, and the execs with their process tokens.
+func processExecID(c *container, p guestProcess) (execID string, ok bool) {
+	if p.execID == "" {
+		return "", false
+	}
+
+	if p.execID == c.id {
+		return "", true
+	}
+
+	for id, e := range c.execs {
+		if e.id == p.execID {
+			return id, true
+		}
+	}
+
+	return "", false
+}
+
+// processInfo returns the containerd process info of p, with the process
+// details of the guest.
+func processInfo(c *container, p guestProcess) (*task.ProcessInfo, error) {
+	info := &task.ProcessInfo{
+		Pid: p.pid,
+	}
+
+	fields := make(map[string]*ptypes.Value)
+	if p.cmdline != "" {
+		fields["cmdline"] = &ptypes.Value{Kind: &ptypes.Value_StringValue{StringValue: p.cmdline}}
+		fields["state"] = &ptypes.Value{Kind: &ptypes.Value_StringValue{StringValue: p.state}}
+	}
+	if execID, ok := processExecID(c, p); ok && execID != "" {
+		fields["exec_id"] = &ptypes.Value{Kind: &ptypes.Value_StringValue{StringValue: execID}}
+	}
+
+	if len(fields) == 0 {
+		return info, nil
+	}
+
+	any, err := typeurl.MarshalAny(&ptypes.Struct{Fields: fields})
+	if err != nil {
+		return nil, err
+	}
+	info.Info = any
+
+	return info, nil
+}
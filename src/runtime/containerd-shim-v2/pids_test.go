@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
+	ptypes "github.com/gogo/protobuf/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+const testGuestPsTable = `  PID STAT COMMAND
+    5 Ss   sleep infinity
+   12 S+   sh -c echo hello
+`
+
+// psSandbox lists the processes of testGuestPsTable.
+type psSandbox struct {
+	*vcmock.Sandbox
+}
+
+func (s *psSandbox) ProcessListContainer(containerID string, options vc.ProcessListOptions) (vc.ProcessList, error) {
+	if options.Format == guestExecIDsFormat {
+		return vc.ProcessList(`{"5":"` + containerID + `","12":"exec-token","20":"other-token"}`), nil
+	}
+	return vc.ProcessList(testGuestPsTable), nil
+}
+
+func newPidsTestService(t *testing.T) (*service, *container) {
+	s := &service{
+		id:         testSandboxID,
+		pid:        1000,
+		sandbox:    &psSandbox{&vcmock.Sandbox{MockID: testSandboxID}},
+		containers: make(map[string]*container),
+	}
+
+	c, err := newContainer(s, &taskAPI.CreateTaskRequest{ID: testContainerID}, vc.PodContainer, &specs.Spec{
+		Process: &specs.Process{Args: []string{"sleep", "infinity"}},
+	}, false)
+	assert.NoError(t, err)
+	c.status = task.StatusRunning
+	c.execs["exec"] = &exec{
+		container: c,
+		cmds:      &types.Cmd{Args: []string{"sh", "-c", "echo hello"}},
+		tty:       &tty{},
+		id:        "exec-token",
+		status:    task.StatusRunning,
+	}
+	// An exec running the same command as the container process
+	c.execs["same"] = &exec{
+		container: c,
+		cmds:      &types.Cmd{Args: []string{"sleep", "infinity"}},
+		tty:       &tty{},
+		id:        "same-token",
+		status:    task.StatusRunning,
+	}
+	s.containers[testContainerID] = c
+
+	return s, c
+}
+
+func TestParseGuestPsTable(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(parseGuestPsTable(""))
+	assert.Equal(map[uint32]guestProcess{
+		5:  {pid: 5, state: "Ss", cmdline: "sleep infinity"},
+		12: {pid: 12, state: "S+", cmdline: "sh -c echo hello"},
+	}, parseGuestPsTable(testGuestPsTable))
+}
+
+func TestServicePids(t *testing.T) {
+	assert := assert.New(t)
+
+	s, c := newPidsTestService(t)
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+
+	resp, err := s.Pids(ctx, &taskAPI.PidsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Len(resp.Processes, 3)
+
+	assert.Equal(uint32(5), resp.Processes[0].Pid)
+	v, err := typeurl.UnmarshalAny(resp.Processes[0].Info)
+	assert.NoError(err)
+	fields := v.(*ptypes.Struct).Fields
+	assert.Equal("sleep infinity", fields["cmdline"].GetStringValue())
+	assert.Equal("Ss", fields["state"].GetStringValue())
+	assert.NotContains(fields, "exec_id")
+
+	assert.Equal(uint32(12), resp.Processes[1].Pid)
+	v, err = typeurl.UnmarshalAny(resp.Processes[1].Info)
+	assert.NoError(err)
+	assert.Equal("exec", v.(*ptypes.Struct).Fields["exec_id"].GetStringValue())
+
+	// ps(1) did not list this process, the shim did not start it
+	assert.Equal(uint32(20), resp.Processes[2].Pid)
+	assert.Nil(resp.Processes[2].Info)
+
+	// Nothing to list once stopped
+	c.status = task.StatusStopped
+	resp, err = s.Pids(ctx, &taskAPI.PidsRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Empty(resp.Processes)
+}
+
+func TestServiceStatePid(t *testing.T) {
+	assert := assert.New(t)
+
+	s, _ := newPidsTestService(t)
+	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
+
+	// The guest pids are only listed by Pids, State returns the shim pid
+	resp, err := s.State(ctx, &taskAPI.StateRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(s.pid, resp.Pid)
+
+	resp, err = s.State(ctx, &taskAPI.StateRequest{ID: testContainerID, ExecID: "exec"})
+	assert.NoError(err)
+	assert.Equal(s.pid, resp.Pid)
+
+	// The task pid containerd keeps is the shim pid
+	connect, err := s.Connect(ctx, &taskAPI.ConnectRequest{ID: testContainerID})
+	assert.NoError(err)
+	assert.Equal(s.pid, connect.ShimPid)
+	assert.Equal(s.pid, connect.TaskPid)
+}
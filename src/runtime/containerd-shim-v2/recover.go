@@ -0,0 +1,306 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/api/types/task"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// shimStateFile is the file, in the bundle of the sandbox, the shim state
+// is saved to. A shim started again for the sandbox, after the previous one
+// died or was upgraded, reattaches to the sandbox from this state.
+const shimStateFile = "kata-shim-state.json"
+
+// loadConfigurationFunc loads the runtime configuration of a recovered
+// sandbox, it is replaced by the tests.
+var loadConfigurationFunc = katautils.LoadConfiguration
+
+type execState struct {
+	ID       string      `json:"id"`
+	Cmd      *types.Cmd  `json:"cmd"`
+	Stdin    string      `json:"stdin"`
+	Stdout   string      `json:"stdout"`
+	Stderr   string      `json:"stderr"`
+	Height   uint32      `json:"height"`
+	Width    uint32      `json:"width"`
+	Terminal bool        `json:"terminal"`
+	Status   task.Status `json:"status"`
+	ExitCode int32       `json:"exitCode"`
+	ExitTime time.Time   `json:"exitTime"`
+	Deadline time.Time   `json:"deadline"`
+}
+
+type containerState struct {
+	ID       string               `json:"id"`
+	Bundle   string               `json:"bundle"`
+	Type     vc.ContainerType     `json:"type"`
+	Stdin    string               `json:"stdin"`
+	Stdout   string               `json:"stdout"`
+	Stderr   string               `json:"stderr"`
+	Terminal bool                 `json:"terminal"`
+	Mounted  bool                 `json:"mounted"`
+	Status   task.Status          `json:"status"`
+	Exit     uint32               `json:"exit"`
+	ExitTime time.Time            `json:"exitTime"`
+	Execs    map[string]execState `json:"execs"`
+}
+
+type shimState struct {
+	ConfigPath string                    `json:"configPath"`
+	Containers map[string]containerState `json:"containers"`
+}
+
+// saveState saves the state of the containers and execs of the shim, it
+// must be called with s.mu held whenever the state changes.
+func (s *service) saveState() {
+	// for unit test, the state is not saved unless asked to
+	if s.statePath == "" {
+		return
+	}
+
+	// Nothing is left to reattach to.
+	if len(s.containers) == 0 {
+		if err := os.Remove(s.statePath); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).Warn("failed to remove shim state")
+		}
+		return
+	}
+
+	state := shimState{
+		ConfigPath: s.configPath,
+		Containers: make(map[string]containerState),
+	}
+
+	for id, c := range s.containers {
+		cs := containerState{
+			ID:       c.id,
+			Bundle:   c.bundle,
+			Type:     c.cType,
+			Stdin:    c.stdin,
+			Stdout:   c.stdout,
+			Stderr:   c.stderr,
+			Terminal: c.terminal,
+			Mounted:  c.mounted,
+			Status:   c.status,
+			Exit:     c.exit,
+			ExitTime: c.exitTime,
+			Execs:    make(map[string]execState),
+		}
+
+		for execID, e := range c.execs {
+			cs.Execs[execID] = execState{
+				ID:       e.id,
+				Cmd:      e.cmds,
+				Stdin:    e.tty.stdin,
+				Stdout:   e.tty.stdout,
+				Stderr:   e.tty.stderr,
+				Height:   e.tty.height,
+				Width:    e.tty.width,
+				Terminal: e.tty.terminal,
+				Status:   e.status,
+				ExitCode: e.exitCode,
+				ExitTime: e.exitTime,
+				Deadline: e.deadline,
+			}
+		}
+
+		state.Containers[id] = cs
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to marshal shim state")
+		return
+	}
+
+	// Write the state atomically, a shim dying while saving it must not
+	// leave a truncated state behind.
+	tmp := s.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		logrus.WithError(err).Warn("failed to save shim state")
+		return
+	}
+
+	if err := os.Rename(tmp, s.statePath); err != nil {
+		logrus.WithError(err).Warn("failed to save shim state")
+	}
+}
+
+// recover reattaches the shim to the sandbox a previous shim saved the
+// state of: it connects again to the sandbox agent and hypervisor, rebuilds
+// the containers and execs, and waits again for their processes, so that
+// they are still seen as running. The exec timeouts are armed again, and
+// the sandbox restarts its char device proxies and RNG reseeder once
+// monitored; the virtiofsd daemons outlive the shim and are found by their
+// saved pids.
+func (s *service) recover(ctx context.Context) error {
+	data, err := ioutil.ReadFile(s.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var state shimState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid shim state %s: %v", s.statePath, err)
+	}
+
+	logger := logrus.WithField("sandbox", s.id)
+	logger.Info("recovering sandbox")
+
+	sandbox, err := vci.FetchSandbox(s.ctx, s.id)
+	if err != nil {
+		// The sandbox is gone, the state is stale.
+		os.Remove(s.statePath)
+		return fmt.Errorf("could not fetch sandbox: %v", err)
+	}
+
+	if s.config == nil {
+		_, runtimeConfig, err := loadConfigurationFunc(state.ConfigPath, false, true)
+		if err != nil {
+			sandbox.Release()
+			return err
+		}
+		s.config = &runtimeConfig
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sandbox = sandbox
+	s.configPath = state.ConfigPath
+
+	if err := startManagementServer(s); err != nil {
+		logger.WithError(err).Warn("failed to start shim management server")
+	}
+
+	for id, cs := range state.Containers {
+		c, err := recoverContainer(ctx, s, cs)
+		if err != nil {
+			logger.WithError(err).WithField("container", id).Warn("failed to recover container")
+			continue
+		}
+		s.containers[id] = c
+	}
+
+	if sc, ok := s.containers[s.id]; ok && sc.status != task.StatusCreated && sc.status != task.StatusStopped {
+		if s.monitor, err = s.sandbox.Monitor(); err != nil {
+			return err
+		}
+		go watchSandbox(s)
+		go watchOOMEvents(s.ctx, s)
+	}
+
+	s.saveState()
+
+	return nil
+}
+
+// recoverContainer rebuilds a container of the shim, reattaching the I/O
+// and waiting again for its processes still running.
+func recoverContainer(ctx context.Context, s *service, cs containerState) (*container, error) {
+	spec, err := compatoci.ParseConfigJSON(cs.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &container{
+		s:        s,
+		spec:     &spec,
+		id:       cs.ID,
+		bundle:   cs.Bundle,
+		stdin:    cs.Stdin,
+		stdout:   cs.Stdout,
+		stderr:   cs.Stderr,
+		terminal: cs.Terminal,
+		cType:    cs.Type,
+		execs:    make(map[string]*exec),
+		status:   cs.Status,
+		exit:     cs.Exit,
+		exitTime: cs.ExitTime,
+		exitIOch: make(chan struct{}),
+		exitCh:   make(chan uint32, 1),
+		mounted:  cs.Mounted,
+	}
+
+	switch c.status {
+	case task.StatusStopped:
+		c.exitCh <- c.exit
+	case task.StatusRunning, task.StatusPaused, task.StatusPausing:
+		if err := startContainerIO(ctx, s, c); err != nil {
+			return nil, err
+		}
+		if status, err := s.getContainerStatus(c.id); err == nil {
+			c.status = status
+		}
+	}
+
+	for execID, es := range cs.Execs {
+		e := &exec{
+			container: c,
+			cmds:      es.Cmd,
+			tty: &tty{
+				stdin:    es.Stdin,
+				stdout:   es.Stdout,
+				stderr:   es.Stderr,
+				height:   es.Height,
+				width:    es.Width,
+				terminal: es.Terminal,
+			},
+			id:       es.ID,
+			exitCode: es.ExitCode,
+			status:   es.Status,
+			exitIOch: make(chan struct{}),
+			exitCh:   make(chan uint32, 1),
+			exitTime: es.ExitTime,
+			deadline: es.Deadline,
+		}
+		c.execs[execID] = e
+
+		switch e.status {
+		case task.StatusStopped:
+			e.exitCh <- uint32(e.exitCode)
+		case task.StatusRunning:
+			if err := startExecIO(ctx, s, c, execID, e); err != nil {
+				return nil, err
+			}
+			setExecTimeout(s, c, execID, e)
+		}
+	}
+
+	return c, nil
+}
+
+// shimStatePath returns the path of the shim state file of the sandbox, in
+// the bundle the shim is started for.
+func shimStatePath(opts cdshim.Opts) string {
+	bundle := opts.BundlePath
+	if bundle == "" {
+		var err error
+		if bundle, err = os.Getwd(); err != nil {
+			return ""
+		}
+	}
+
+	return filepath.Join(bundle, shimStateFile)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/stretchr/testify/assert"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+func newRecoverTestService(statePath string) *service {
+	return &service{
+		id:         testSandboxID,
+		ctx:        namespaces.WithNamespace(context.Background(), "UnitTest"),
+		containers: make(map[string]*container),
+		config:     &oci.RuntimeConfig{},
+		statePath:  statePath,
+	}
+}
+
+func TestServiceRecover(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shim-state")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	statePath := filepath.Join(dir, shimStateFile)
+
+	// Nothing to recover
+	s := newRecoverTestService(statePath)
+	assert.NoError(s.recover(s.ctx))
+	assert.Nil(s.sandbox)
+
+	s.configPath = "/etc/kata-containers/configuration.toml"
+
+	s.containers[testSandboxID], err = newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testSandboxID,
+		Bundle: testBundleDir,
+	}, vc.PodSandbox, nil, false)
+	assert.NoError(err)
+
+	c, err := newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testContainerID,
+		Bundle: testBundleDir,
+		Stdout: "/run/stdout",
+	}, vc.PodContainer, nil, true)
+	assert.NoError(err)
+	c.status = task.StatusStopped
+	c.exit = 3
+	deadline := time.Now().Add(time.Minute)
+	c.execs["exec"] = &exec{
+		container: c,
+		cmds:      &types.Cmd{Args: []string{"ls"}},
+		tty:       &tty{stdout: "/run/exec-stdout"},
+		id:        "exec-token",
+		exitCode:  2,
+		status:    task.StatusStopped,
+		deadline:  deadline,
+	}
+	s.containers[testContainerID] = c
+
+	s.saveState()
+	assert.FileExists(statePath)
+
+	sandbox := &vcmock.Sandbox{MockID: testSandboxID}
+	testingImpl.FetchSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		assert.Equal(testSandboxID, sandboxID)
+		return sandbox, nil
+	}
+	defer func() {
+		testingImpl.FetchSandboxFunc = nil
+	}()
+
+	s = newRecoverTestService(statePath)
+	assert.NoError(s.recover(s.ctx))
+	assert.Equal(sandbox, s.sandbox)
+	assert.Equal("/etc/kata-containers/configuration.toml", s.configPath)
+	assert.Len(s.containers, 2)
+
+	sc := s.containers[testSandboxID]
+	assert.True(sc.cType.IsSandbox())
+	assert.Equal(task.StatusCreated, sc.status)
+
+	c = s.containers[testContainerID]
+	assert.Equal("/run/stdout", c.stdout)
+	assert.True(c.mounted)
+	assert.Equal(task.StatusStopped, c.status)
+	assert.Equal(uint32(3), <-c.exitCh)
+
+	e, err := c.getExec("exec")
+	assert.NoError(err)
+	assert.Equal("exec-token", e.id)
+	assert.Equal([]string{"ls"}, e.cmds.Args)
+	assert.Equal("/run/exec-stdout", e.tty.stdout)
+	assert.True(deadline.Equal(e.deadline))
+	assert.Equal(uint32(2), <-e.exitCh)
+
+	// The state is removed once the containers are gone
+	s.containers = make(map[string]*container)
+	s.saveState()
+	_, err = os.Stat(statePath)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestServiceRecoverSandboxGone(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shim-state")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	statePath := filepath.Join(dir, shimStateFile)
+
+	s := newRecoverTestService(statePath)
+	s.containers[testSandboxID], err = newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testSandboxID,
+		Bundle: testBundleDir,
+	}, vc.PodSandbox, nil, false)
+	assert.NoError(err)
+	s.saveState()
+
+	testingImpl.FetchSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		return nil, errors.New("sandbox not found")
+	}
+	defer func() {
+		testingImpl.FetchSandboxFunc = nil
+	}()
+
+	s = newRecoverTestService(statePath)
+	assert.Error(s.recover(s.ctx))
+	assert.Nil(s.sandbox)
+
+	// The stale state is removed
+	_, err = os.Stat(statePath)
+	assert.True(os.IsNotExist(err))
+}
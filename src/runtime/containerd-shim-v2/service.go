@@ -7,6 +7,7 @@ package containerdshim
 
 import (
 	"context"
+	"flag"
 	"io/ioutil"
 	"os"
 	sysexec "os/exec"
@@ -22,6 +23,7 @@ import (
 	cdruntime "github.com/containerd/containerd/runtime"
 	cdshim "github.com/containerd/containerd/runtime/v2/shim"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
 	"github.com/containerd/typeurl"
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -32,6 +34,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	kataevents "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
@@ -72,6 +75,37 @@ func New(ctx context.Context, id string, publisher events.Publisher) (cdshim.Shi
 	vci.SetLogger(ctx, logger)
 	katautils.SetLogger(ctx, logger, logger.Logger.Level)
 
+	// The shared shim serves the sandboxes of the namespace, each one of
+	// them by a service of its own.
+	if flag.Arg(0) == "" && isSharedShimID(id) {
+		ss, err := newSharedService(ctx, id, publisher)
+		if err != nil {
+			return nil, err
+		}
+		return ss, nil
+	}
+
+	s := newService(ctx, id, shimStatePath(opts), publisher)
+
+	// Only the shim serving the sandbox reattaches to it, and serves the
+	// runtime commands, not the ones run to start or to clean it up.
+	if flag.Arg(0) == "" {
+		var err error
+		if s.managementSocket, err = katautils.ShimManagementSocketPath(id); err != nil {
+			logger.WithError(err).Warn("no shim management socket")
+		}
+
+		if err := s.recover(s.ctx); err != nil {
+			logger.WithError(err).Warn("failed to recover sandbox")
+		}
+	}
+
+	return s, nil
+}
+
+// newService returns the service of the sandbox id, saving its state to
+// statePath and publishing its events with publisher.
+func newService(ctx context.Context, id, statePath string, publisher events.Publisher) *service {
 	ctx, cancel := context.WithCancel(ctx)
 
 	s := &service{
@@ -81,14 +115,21 @@ func New(ctx context.Context, id string, publisher events.Publisher) (cdshim.Shi
 		containers: make(map[string]*container),
 		events:     make(chan interface{}, chSize),
 		ec:         make(chan exit, bufferSize),
+		done:       make(chan struct{}),
 		cancel:     cancel,
+		statePath:  statePath,
 	}
 
+	// The sandbox lifecycle events are sent to the sink carried by the
+	// context the sandbox is created with.
+	s.eventSink = newEventSink(s)
+	s.ctx = kataevents.WithSink(s.ctx, s.eventSink)
+
 	go s.processExits()
 
 	go s.forward(publisher)
 
-	return s, nil
+	return s
 }
 
 type exit struct {
@@ -104,17 +145,31 @@ type service struct {
 	mu          sync.Mutex
 	eventSendMu sync.Mutex
 
-	// pid Since this shimv2 cannot get the container processes pid from VM,
-	// thus for the returned values needed pid, just return this shim's
-	// pid directly.
+	// pid of this shimv2, returned where containerd needs the pid of a
+	// process on the host, the container processes only exist in the VM.
 	pid uint32
 
 	ctx        context.Context
 	sandbox    vc.VCSandbox
 	containers map[string]*container
 	config     *oci.RuntimeConfig
-	events     chan interface{}
-	monitor    chan error
+	configPath string
+	// statePath is the file the state of the shim is saved to, for a
+	// shim started again to reattach to the sandbox.
+	statePath string
+	// managementSocket is the socket the task API is served on for the
+	// runtime commands.
+	managementSocket string
+	managementServer *ttrpc.Server
+	events           chan interface{}
+	eventSink        *eventSink
+	monitor          chan error
+	// done is closed when the sandbox is removed from the shared shim, to
+	// stop processing its exits and forwarding its events.
+	done chan struct{}
+	// shared is the shared shim serving the sandbox, nil when the shim
+	// serves the sandbox alone.
+	shared *sharedService
 
 	cancel func()
 
@@ -166,7 +221,14 @@ func (s *service) StartShim(ctx context.Context, id, containerdBinary, container
 		return "", err
 	}
 
-	address, err := getAddress(ctx, bundlePath, id)
+	// The create task options are only given to the shims containerd
+	// starts with them.
+	options, err := readRuntimeOptions(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+
+	address, shimID, err := getAddress(ctx, bundlePath, id, options)
 	if err != nil {
 		return "", err
 	}
@@ -177,19 +239,31 @@ func (s *service) StartShim(ctx context.Context, id, containerdBinary, container
 		return address, nil
 	}
 
-	cmd, err := newCommand(ctx, containerdBinary, id, containerdAddress)
+	cmd, err := newCommand(ctx, containerdBinary, shimID, containerdAddress)
 	if err != nil {
 		return "", err
 	}
 
-	address, err = cdshim.SocketAddress(ctx, id)
+	address, err = cdshim.SocketAddress(ctx, shimID)
 	if err != nil {
 		return "", err
 	}
 
 	socket, err := cdshim.NewSocket(address)
 	if err != nil {
-		return "", err
+		if !isSharedShimID(shimID) {
+			return "", err
+		}
+
+		// The shared shim may have been started for another sandbox
+		// meanwhile.
+		if _, ok := sharedShimServes(ctx, shimID, ""); !ok {
+			return "", err
+		}
+		if err := cdshim.WriteAddress("address", address); err != nil {
+			return "", err
+		}
+		return address, nil
 	}
 	defer socket.Close()
 	f, err := socket.File()
@@ -221,31 +295,66 @@ func (s *service) StartShim(ctx context.Context, id, containerdBinary, container
 }
 
 func (s *service) forward(publisher events.Publisher) {
-	for e := range s.events {
-		ctx, cancel := context.WithTimeout(s.ctx, timeOut)
-		err := publisher.Publish(ctx, getTopic(e), e)
-		cancel()
-		if err != nil {
-			logrus.WithError(err).Error("post event")
+	for {
+		select {
+		case e := <-s.events:
+			s.publish(publisher, e)
+		case <-s.done:
+			// Publish the events sent before the service was stopped,
+			// the deletion of the sandbox task among them.
+			for {
+				select {
+				case e := <-s.events:
+					s.publish(publisher, e)
+				default:
+					s.cancel()
+					return
+				}
+			}
 		}
 	}
 }
 
+func (s *service) publish(publisher events.Publisher, e interface{}) {
+	ctx, cancel := context.WithTimeout(s.ctx, timeOut)
+	err := publisher.Publish(ctx, getTopic(e), e)
+	cancel()
+	if err != nil {
+		logrus.WithError(err).Error("post event")
+	}
+}
+
 func (s *service) send(evt interface{}) {
 	// for unit test, it will not initialize s.events
 	if s.events != nil {
-		s.events <- evt
+		select {
+		case s.events <- evt:
+		case <-s.done:
+		}
 	}
 }
 
 func (s *service) sendL(evt interface{}) {
 	s.eventSendMu.Lock()
-	if s.events != nil {
-		s.events <- evt
-	}
+	s.send(evt)
 	s.eventSendMu.Unlock()
 }
 
+// stop stops the service of a sandbox removed from the shared shim: its
+// management server, the processing of its exits and, once the events
+// already sent are published, the forwarding of its events.
+func (s *service) stop() {
+	if s.managementServer != nil {
+		s.managementServer.Close()
+	}
+
+	if s.managementSocket != "" {
+		os.Remove(s.managementSocket)
+	}
+
+	close(s.done)
+}
+
 func getTopic(e interface{}) string {
 	switch e.(type) {
 	case *eventstypes.TaskCreate:
@@ -268,6 +377,8 @@ func getTopic(e interface{}) string {
 		return cdruntime.TaskResumedEventTopic
 	case *eventstypes.TaskCheckpointed:
 		return cdruntime.TaskCheckpointedEventTopic
+	case kataevents.Event:
+		return e.(kataevents.Event).Topic()
 	default:
 		logrus.Warnf("no topic for type %#v", e)
 	}
@@ -339,6 +450,11 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 
 	var c *container
 
+	// The agent creates the container, the VM cannot be paused
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	c, err = create(ctx, s, r)
 	if err != nil {
 		return nil, err
@@ -347,6 +463,7 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 	c.status = task.StatusCreated
 
 	s.containers[r.ID] = c
+	s.saveState()
 
 	s.send(&eventstypes.TaskCreate{
 		ContainerID: r.ID,
@@ -381,10 +498,17 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (_ *taskAP
 		return nil, err
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	// hold the send lock so that the start events are sent before any exit events in the error case
 	s.eventSendMu.Lock()
 	defer s.eventSendMu.Unlock()
 
+	// the state is saved once the process is started, or failed to.
+	defer s.saveState()
+
 	//start a container
 	if r.ExecID == "" {
 		err = startContainer(ctx, s, c)
@@ -427,10 +551,15 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (_ *task
 		return nil, err
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	if r.ExecID == "" {
 		if err = deleteContainer(ctx, s, c); err != nil {
 			return nil, err
 		}
+		s.saveState()
 
 		s.send(&eventstypes.TaskDelete{
 			ContainerID: c.id,
@@ -452,6 +581,7 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (_ *task
 	}
 
 	delete(c.execs, r.ExecID)
+	s.saveState()
 
 	return &taskAPI.DeleteResponse{
 		ExitStatus: uint32(execs.exitCode),
@@ -484,6 +614,7 @@ func (s *service) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (_ *p
 	}
 
 	c.execs[r.ExecID] = execs
+	s.saveState()
 
 	s.send(&eventstypes.TaskExecAdded{
 		ContainerID: c.id,
@@ -507,6 +638,10 @@ func (s *service) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (_
 		return nil, err
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	processID := c.id
 	if r.ExecID != "" {
 		execs, err := c.getExec(r.ExecID)
@@ -597,6 +732,7 @@ func (s *service) Pause(ctx context.Context, r *taskAPI.PauseRequest) (_ *ptypes
 		s.send(&eventstypes.TaskPaused{
 			ContainerID: c.id,
 		})
+		pauseSandbox(s)
 		return empty, nil
 	}
 
@@ -623,6 +759,10 @@ func (s *service) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (_ *ptyp
 		return nil, err
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	err = s.sandbox.ResumeContainer(c.id)
 	if err == nil {
 		c.status = task.StatusRunning
@@ -671,6 +811,10 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 		}
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	processID := c.id
 	if r.ExecID != "" {
 		execs, err := c.getExec(r.ExecID)
@@ -692,8 +836,8 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 }
 
 // Pids returns all pids inside the container
-// Since for kata, it cannot get the process's pid from VM,
-// thus only return the Shim's pid directly.
+// The pids are the ones of the processes in the VM, they do not exist on
+// the host.
 func (s *service) Pids(ctx context.Context, r *taskAPI.PidsRequest) (_ *taskAPI.PidsResponse, err error) {
 	var processes []*task.ProcessInfo
 
@@ -701,10 +845,32 @@ func (s *service) Pids(ctx context.Context, r *taskAPI.PidsRequest) (_ *taskAPI.
 		err = toGRPC(err)
 	}()
 
-	pInfo := task.ProcessInfo{
-		Pid: s.pid,
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.getContainer(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The processes can only be listed while the container is running,
+	// and the VM too.
+	if c.status != task.StatusRunning || sandboxPaused(s) {
+		return &taskAPI.PidsResponse{}, nil
+	}
+
+	guestProcesses, err := listGuestProcesses(s, c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range guestProcesses {
+		pInfo, err := processInfo(c, p)
+		if err != nil {
+			return nil, err
+		}
+		processes = append(processes, pInfo)
 	}
-	processes = append(processes, &pInfo)
 
 	return &taskAPI.PidsResponse{
 		Processes: processes,
@@ -763,7 +929,8 @@ func (s *service) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (_ *ta
 
 	return &taskAPI.ConnectResponse{
 		ShimPid: s.pid,
-		//Since kata cannot get the container's pid in VM, thus only return the shim's pid.
+		// containerd keeps the task pid as the pid of the task on the
+		// host, the guest pid of the container process is not one.
 		TaskPid: s.pid,
 	}, nil
 }
@@ -773,6 +940,11 @@ func (s *service) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (_ *
 		err = toGRPC(err)
 	}()
 
+	// The shared shim exits once none of its sandboxes is left.
+	if s.shared != nil {
+		return s.shared.Shutdown(ctx, r)
+	}
+
 	s.mu.Lock()
 	if len(s.containers) != 0 {
 		s.mu.Unlock()
@@ -782,6 +954,12 @@ func (s *service) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (_ *
 
 	s.cancel()
 
+	if s.managementSocket != "" {
+		os.Remove(s.managementSocket)
+	}
+
+	katautils.StopTracing(s.ctx)
+
 	os.Exit(0)
 
 	// This will never be called, but this is only there to make sure the
@@ -802,10 +980,26 @@ func (s *service) Stats(ctx context.Context, r *taskAPI.StatsRequest) (_ *taskAP
 		return nil, err
 	}
 
+	// Nothing changes while the VM is paused, the last statistics are
+	// returned rather than resuming it.
+	if sandboxPaused(s) {
+		data := c.stats
+		if data == nil {
+			if data, err = marshalEmptyMetrics(); err != nil {
+				return nil, err
+			}
+		}
+
+		return &taskAPI.StatsResponse{
+			Stats: data,
+		}, nil
+	}
+
 	data, err := marshalMetrics(s, c.id)
 	if err != nil {
 		return nil, err
 	}
+	c.stats = data
 
 	return &taskAPI.StatsResponse{
 		Stats: data,
@@ -831,6 +1025,10 @@ func (s *service) Update(ctx context.Context, r *taskAPI.UpdateTaskRequest) (_ *
 		return nil, errdefs.ToGRPCf(errdefs.ErrInvalidArgument, "Invalid resources type for %s", s.id)
 	}
 
+	if err = resumeSandbox(s); err != nil {
+		return nil, err
+	}
+
 	err = s.sandbox.UpdateContainer(r.ID, *resources)
 	if err != nil {
 		return nil, errdefs.ToGRPC(err)
@@ -881,8 +1079,13 @@ func (s *service) Wait(ctx context.Context, r *taskAPI.WaitRequest) (_ *taskAPI.
 }
 
 func (s *service) processExits() {
-	for e := range s.ec {
-		s.checkProcesses(e)
+	for {
+		select {
+		case e := <-s.ec:
+			s.checkProcesses(e)
+		case <-s.done:
+			return
+		}
 	}
 }
 
@@ -0,0 +1,657 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/events"
+	"github.com/containerd/containerd/namespaces"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/ttrpc"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+)
+
+// sharedShimIDPrefix prefixes the IDs the shared shims are started with.
+// The sandboxes of a namespace using the same runtime configuration share
+// a shim, the factory, tracer and logger of the runtime being global to it.
+const sharedShimIDPrefix = "kata-shared-shim-"
+
+var _ cdshim.Shim = (cdshim.Shim)(&sharedService{})
+
+// variables rather than consts to allow tests to modify them
+var (
+	// sharedShimStateDir is the directory the shared shims of each
+	// namespace list the sandboxes they serve in, one directory per
+	// namespace.
+	sharedShimStateDir = "/run/kata-containers/shared-shim"
+
+	// sharedShimIdleTimeout is how long the shared shim keeps running
+	// without any sandbox, for the sandboxes being started to join it.
+	sharedShimIdleTimeout = time.Minute
+
+	sharedShimDialTimeout = 2 * time.Second
+)
+
+// sharedShimID returns the ID of the shim shared by the sandboxes using
+// the runtime configuration configPath.
+func sharedShimID(configPath string) string {
+	sum := sha256.Sum256([]byte(configPath))
+	return sharedShimIDPrefix + hex.EncodeToString(sum[:8])
+}
+
+func isSharedShimID(id string) bool {
+	return strings.HasPrefix(id, sharedShimIDPrefix)
+}
+
+// sharedShimEnabled returns the ID of the shim the sandbox shares, and
+// tells if its runtime configuration asks for the sandboxes to share a
+// shim. The configuration is found the way the sandbox creation finds it,
+// from the annotations, the create task options or the environment.
+func sharedShimEnabled(ociSpec specs.Spec, options *ptypes.Any) (string, bool) {
+	configPath, err := runtimeConfigPath(ociSpec.Annotations, options)
+	if err != nil {
+		logrus.WithError(err).Warn("invalid create task options, not sharing the shim")
+		return "", false
+	}
+
+	resolvedPath, runtimeConfig, err := katautils.LoadConfiguration(configPath, false, true)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to load the configuration, not sharing the shim")
+		return "", false
+	}
+
+	if !runtimeConfig.SharedShim {
+		return "", false
+	}
+
+	return sharedShimID(resolvedPath), true
+}
+
+// sharedShimStatePath returns the file the shared shim shimID of the
+// namespace of ctx lists its sandboxes in.
+func sharedShimStatePath(ctx context.Context, shimID string) (string, error) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(sharedShimStateDir, ns, shimID+".json"), nil
+}
+
+// sharedShimServing returns the address of the shared shim of the
+// namespace serving the sandbox sandboxID, and tells if there is one.
+func sharedShimServing(ctx context.Context, sandboxID string) (string, bool) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	states, err := filepath.Glob(filepath.Join(sharedShimStateDir, ns, sharedShimIDPrefix+"*.json"))
+	if err != nil {
+		return "", false
+	}
+
+	for _, state := range states {
+		shimID := strings.TrimSuffix(filepath.Base(state), ".json")
+		if address, ok := sharedShimServes(ctx, shimID, sandboxID); ok {
+			return address, true
+		}
+	}
+
+	return "", false
+}
+
+// sharedShimServes returns the address of the shared shim shimID of the
+// namespace and tells if it is running and, for a non empty sandboxID, if
+// it serves the sandbox.
+func sharedShimServes(ctx context.Context, shimID, sandboxID string) (string, bool) {
+	address, err := cdshim.SocketAddress(ctx, shimID)
+	if err != nil {
+		return "", false
+	}
+
+	conn, err := cdshim.AnonDialer(address, sharedShimDialTimeout)
+	if err != nil {
+		return "", false
+	}
+
+	client := ttrpc.NewClient(conn)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, sharedShimDialTimeout)
+	defer cancel()
+
+	_, err = taskAPI.NewTaskClient(client).Connect(ctx, &taskAPI.ConnectRequest{ID: sandboxID})
+
+	return address, err == nil
+}
+
+type sharedShimState struct {
+	// Sandboxes maps the IDs of the sandboxes to their bundle.
+	Sandboxes map[string]string `json:"sandboxes"`
+}
+
+// sharedService is the shim implementation of the shim shared by the
+// sandboxes of a namespace using the same runtime configuration. Each
+// sandbox is served by a service of its own, with its own locks, state and
+// events, the task API calls being routed to it by container ID. The lock
+// of the shared service is only held to look the sandboxes up.
+type sharedService struct {
+	mu sync.Mutex
+
+	pid       uint32
+	ctx       context.Context
+	publisher events.Publisher
+	cancel    func()
+
+	// statePath is the file the sandboxes of the shim are listed in, for
+	// a shared shim started again to reattach to them.
+	statePath string
+	// serveManagement tells if the sandboxes serve the runtime commands
+	// on their management socket.
+	serveManagement bool
+
+	// sandboxes maps the IDs of the sandboxes to their service.
+	sandboxes map[string]*service
+	// containers maps the IDs of the containers to the service of their
+	// sandbox.
+	containers map[string]*service
+
+	// lastActive is when a sandbox last joined the shim.
+	lastActive time.Time
+	idleTimer  *time.Timer
+}
+
+func newSharedService(ctx context.Context, id string, publisher events.Publisher) (*sharedService, error) {
+	statePath, err := sharedShimStatePath(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	ss := &sharedService{
+		pid:             uint32(os.Getpid()),
+		ctx:             ctx,
+		publisher:       publisher,
+		cancel:          cancel,
+		statePath:       statePath,
+		serveManagement: true,
+		sandboxes:       make(map[string]*service),
+		containers:      make(map[string]*service),
+		lastActive:      time.Now(),
+	}
+
+	if err := ss.recover(); err != nil {
+		logrus.WithError(err).Warn("failed to recover sandboxes")
+	}
+
+	return ss, nil
+}
+
+// newSandboxService returns the service of a sandbox of the shim, saving
+// its state in its bundle.
+func (ss *sharedService) newSandboxService(id, bundle string) *service {
+	s := newService(ss.ctx, id, filepath.Join(bundle, shimStateFile), ss.publisher)
+	s.shared = ss
+
+	if ss.serveManagement {
+		var err error
+		if s.managementSocket, err = katautils.ShimManagementSocketPath(id); err != nil {
+			logrus.WithError(err).WithField("sandbox", id).Warn("no shim management socket")
+		}
+	}
+
+	return s
+}
+
+// saveState lists the sandboxes of the shim, it must be called with ss.mu
+// held whenever they change.
+func (ss *sharedService) saveState() {
+	// for unit test, the state is not saved unless asked to
+	if ss.statePath == "" {
+		return
+	}
+
+	if len(ss.sandboxes) == 0 {
+		if err := os.Remove(ss.statePath); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).Warn("failed to remove shared shim state")
+		}
+		return
+	}
+
+	state := sharedShimState{
+		Sandboxes: make(map[string]string),
+	}
+	for id, s := range ss.sandboxes {
+		state.Sandboxes[id] = filepath.Dir(s.statePath)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to marshal shared shim state")
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ss.statePath), 0750); err != nil {
+		logrus.WithError(err).Warn("failed to save shared shim state")
+		return
+	}
+
+	tmp := ss.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		logrus.WithError(err).Warn("failed to save shared shim state")
+		return
+	}
+
+	if err := os.Rename(tmp, ss.statePath); err != nil {
+		logrus.WithError(err).Warn("failed to save shared shim state")
+	}
+}
+
+// recover reattaches the shim to the sandboxes a previous shared shim
+// served, each of them from the state saved in its bundle.
+func (ss *sharedService) recover() error {
+	data, err := ioutil.ReadFile(ss.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var state sharedShimState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid shared shim state %s: %v", ss.statePath, err)
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for id, bundle := range state.Sandboxes {
+		s := ss.newSandboxService(id, bundle)
+		if err := s.recover(s.ctx); err != nil {
+			logrus.WithError(err).WithField("sandbox", id).Warn("failed to recover sandbox")
+		}
+
+		s.mu.Lock()
+		var containers []string
+		for cid := range s.containers {
+			containers = append(containers, cid)
+		}
+		s.mu.Unlock()
+
+		// Nothing was left to reattach to
+		if len(containers) == 0 {
+			s.stop()
+			continue
+		}
+
+		ss.sandboxes[id] = s
+		for _, cid := range containers {
+			ss.containers[cid] = s
+		}
+	}
+
+	ss.saveState()
+
+	return nil
+}
+
+// getService returns the service of the sandbox of the container id.
+func (ss *sharedService) getService(id string) (*service, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	s := ss.containers[id]
+	if s == nil {
+		return nil, errdefs.ToGRPCf(errdefs.ErrNotFound, "container does not exist %s", id)
+	}
+
+	return s, nil
+}
+
+// addSandbox adds the service of a sandbox being created to the shim.
+func (ss *sharedService) addSandbox(id, bundle string) (*service, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.sandboxes[id] != nil {
+		return nil, errdefs.ToGRPCf(errdefs.ErrAlreadyExists, "sandbox %s", id)
+	}
+
+	s := ss.newSandboxService(id, bundle)
+	ss.sandboxes[id] = s
+	ss.lastActive = time.Now()
+
+	return s, nil
+}
+
+// removeSandbox removes the service of a sandbox left without containers
+// from the shim, and stops it.
+func (ss *sharedService) removeSandbox(s *service) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.sandboxes[s.id] != s {
+		return
+	}
+
+	delete(ss.sandboxes, s.id)
+	ss.saveState()
+
+	s.stop()
+}
+
+// exitIfIdle exits the shim when it served no sandbox for
+// sharedShimIdleTimeout, the sandboxes started in the meantime keeping it
+// running.
+func (ss *sharedService) exitIfIdle() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.idleTimer = nil
+
+	if len(ss.sandboxes) != 0 {
+		return
+	}
+
+	if idle := time.Since(ss.lastActive); idle < sharedShimIdleTimeout {
+		ss.idleTimer = time.AfterFunc(sharedShimIdleTimeout-idle, ss.exitIfIdle)
+		return
+	}
+
+	ss.cancel()
+
+	katautils.StopTracing(ss.ctx)
+
+	os.Exit(0)
+}
+
+// Cleanup is not served by the shared shim, the shim started to clean a
+// container up is not a shared one.
+func (ss *sharedService) Cleanup(ctx context.Context) (*taskAPI.DeleteResponse, error) {
+	return nil, errdefs.ToGRPCf(errdefs.ErrNotImplemented, "shared shim Cleanup")
+}
+
+// StartShim is not served by the shared shim, the shim started to start
+// a shim is not a shared one.
+func (ss *sharedService) StartShim(ctx context.Context, id, containerdBinary, containerdAddress string) (string, error) {
+	return "", errdefs.ToGRPCf(errdefs.ErrNotImplemented, "shared shim StartShim")
+}
+
+// Create a new sandbox in the shim, or a new container in one of its
+// sandboxes
+func (ss *sharedService) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *taskAPI.CreateTaskResponse, err error) {
+	defer func() {
+		err = toGRPC(err)
+	}()
+
+	bundlePath, err := validBundle(r.ID, r.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	ociSpec, err := compatoci.ParseConfigJSON(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	containerType, err := oci.ContainerType(ociSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *service
+
+	if containerType == vc.PodSandbox {
+		if s, err = ss.addSandbox(r.ID, r.Bundle); err != nil {
+			return nil, err
+		}
+	} else {
+		sandboxID, err := oci.SandboxID(ociSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		ss.mu.Lock()
+		s = ss.sandboxes[sandboxID]
+		ss.mu.Unlock()
+
+		if s == nil {
+			return nil, errdefs.ToGRPCf(errdefs.ErrNotFound, "sandbox does not exist %s", sandboxID)
+		}
+	}
+
+	resp, err := s.Create(ctx, r)
+	if err != nil {
+		if containerType == vc.PodSandbox {
+			ss.removeSandbox(s)
+		}
+		return nil, err
+	}
+
+	ss.mu.Lock()
+	ss.containers[r.ID] = s
+	ss.saveState()
+	ss.mu.Unlock()
+
+	return resp, nil
+}
+
+// Start a process
+func (ss *sharedService) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.StartResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Start(ctx, r)
+}
+
+// Delete the initial process and container, and the sandbox once it has
+// no container left
+func (ss *sharedService) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAPI.DeleteResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Delete(ctx, r)
+	if err != nil || r.ExecID != "" {
+		return resp, err
+	}
+
+	ss.mu.Lock()
+	delete(ss.containers, r.ID)
+	ss.mu.Unlock()
+
+	s.mu.Lock()
+	last := len(s.containers) == 0
+	s.mu.Unlock()
+
+	if last {
+		ss.removeSandbox(s)
+	}
+
+	return resp, nil
+}
+
+// Exec an additional process inside the container
+func (ss *sharedService) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Exec(ctx, r)
+}
+
+// ResizePty of a process
+func (ss *sharedService) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ResizePty(ctx, r)
+}
+
+// State returns runtime state information for a process
+func (ss *sharedService) State(ctx context.Context, r *taskAPI.StateRequest) (*taskAPI.StateResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.State(ctx, r)
+}
+
+// Pause the container
+func (ss *sharedService) Pause(ctx context.Context, r *taskAPI.PauseRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Pause(ctx, r)
+}
+
+// Resume the container
+func (ss *sharedService) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Resume(ctx, r)
+}
+
+// Kill a process with the provided signal
+func (ss *sharedService) Kill(ctx context.Context, r *taskAPI.KillRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Kill(ctx, r)
+}
+
+// Pids returns all pids inside the container
+func (ss *sharedService) Pids(ctx context.Context, r *taskAPI.PidsRequest) (*taskAPI.PidsResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Pids(ctx, r)
+}
+
+// CloseIO of a process
+func (ss *sharedService) CloseIO(ctx context.Context, r *taskAPI.CloseIORequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CloseIO(ctx, r)
+}
+
+// Checkpoint the container
+func (ss *sharedService) Checkpoint(ctx context.Context, r *taskAPI.CheckpointTaskRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Checkpoint(ctx, r)
+}
+
+// Connect returns shim information such as the shim's pid. Without an ID,
+// it tells the shim a sandbox is about to join it, an unknown ID is the one
+// of a sandbox the shim does not serve.
+func (ss *sharedService) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (*taskAPI.ConnectResponse, error) {
+	if r.ID != "" {
+		s, err := ss.getService(r.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.Connect(ctx, r)
+	}
+
+	ss.mu.Lock()
+	ss.lastActive = time.Now()
+	ss.mu.Unlock()
+
+	return &taskAPI.ConnectResponse{
+		ShimPid: ss.pid,
+		TaskPid: ss.pid,
+	}, nil
+}
+
+// Shutdown the shim once it has no sandbox left, and none joined it for
+// sharedShimIdleTimeout
+func (ss *sharedService) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (*ptypes.Empty, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if len(ss.sandboxes) == 0 && ss.idleTimer == nil {
+		ss.idleTimer = time.AfterFunc(sharedShimIdleTimeout, ss.exitIfIdle)
+	}
+
+	return empty, nil
+}
+
+// Stats returns the statistics of the container
+func (ss *sharedService) Stats(ctx context.Context, r *taskAPI.StatsRequest) (*taskAPI.StatsResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Stats(ctx, r)
+}
+
+// Update a running container
+func (ss *sharedService) Update(ctx context.Context, r *taskAPI.UpdateTaskRequest) (*ptypes.Empty, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Update(ctx, r)
+}
+
+// Wait for a process to exit
+func (ss *sharedService) Wait(ctx context.Context, r *taskAPI.WaitRequest) (*taskAPI.WaitResponse, error) {
+	s, err := ss.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Wait(ctx, r)
+}
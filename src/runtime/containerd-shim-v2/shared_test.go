@@ -0,0 +1,419 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/events"
+	"github.com/containerd/containerd/namespaces"
+	cdruntime "github.com/containerd/containerd/runtime"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	crioption "github.com/containerd/cri-containerd/pkg/api/runtimeoptions/v1"
+	"github.com/containerd/ttrpc"
+	"github.com/containerd/typeurl"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+)
+
+type fakePublisher struct {
+	topics chan string
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, topic string, event events.Event) error {
+	p.topics <- topic
+	return nil
+}
+
+func newTestSharedService(statePath string) *sharedService {
+	return &sharedService{
+		ctx:        namespaces.WithNamespace(context.Background(), "UnitTest"),
+		publisher:  &fakePublisher{topics: make(chan string, chSize)},
+		cancel:     func() {},
+		statePath:  statePath,
+		sandboxes:  make(map[string]*service),
+		containers: make(map[string]*service),
+	}
+}
+
+// addTestSandbox adds a sandbox and one of its containers to the shared
+// shim.
+func addTestSandbox(t *testing.T, ss *sharedService, dir, sandboxID, containerID string) *service {
+	assert := assert.New(t)
+
+	bundle := filepath.Join(dir, sandboxID)
+	assert.NoError(os.MkdirAll(bundle, testDirMode))
+
+	s, err := ss.addSandbox(sandboxID, bundle)
+	assert.NoError(err)
+	s.sandbox = &vcmock.Sandbox{MockID: sandboxID}
+	s.config = &oci.RuntimeConfig{}
+
+	for id, cType := range map[string]vc.ContainerType{
+		sandboxID:   vc.PodSandbox,
+		containerID: vc.PodContainer,
+	} {
+		s.containers[id], err = newContainer(s, &taskAPI.CreateTaskRequest{
+			ID:     id,
+			Bundle: bundle,
+		}, cType, nil, false)
+		assert.NoError(err)
+		ss.containers[id] = s
+	}
+	ss.saveState()
+
+	return s
+}
+
+func readSharedShimState(t *testing.T, statePath string) sharedShimState {
+	data, err := ioutil.ReadFile(statePath)
+	assert.NoError(t, err)
+
+	var state sharedShimState
+	assert.NoError(t, json.Unmarshal(data, &state))
+
+	return state
+}
+
+func TestSharedServiceRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shared-shim")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedIdleTimeout := sharedShimIdleTimeout
+	sharedShimIdleTimeout = time.Hour
+	defer func() {
+		sharedShimIdleTimeout = savedIdleTimeout
+	}()
+
+	statePath := filepath.Join(dir, "UnitTest.json")
+	ss := newTestSharedService(statePath)
+	ctx := context.Background()
+
+	sa := addTestSandbox(t, ss, dir, "sandbox-a", "container-a")
+	sb := addTestSandbox(t, ss, dir, "sandbox-b", "container-b")
+
+	_, err = ss.addSandbox("sandbox-a", dir)
+	assert.True(errdefs.IsAlreadyExists(errdefs.FromGRPC(err)))
+
+	state := readSharedShimState(t, statePath)
+	assert.Equal(map[string]string{
+		"sandbox-a": filepath.Join(dir, "sandbox-a"),
+		"sandbox-b": filepath.Join(dir, "sandbox-b"),
+	}, state.Sandboxes)
+
+	resp, err := ss.State(ctx, &taskAPI.StateRequest{ID: "container-b"})
+	assert.NoError(err)
+	assert.Equal("container-b", resp.ID)
+	assert.Equal(filepath.Join(dir, "sandbox-b"), resp.Bundle)
+
+	_, err = ss.State(ctx, &taskAPI.StateRequest{ID: "unknown"})
+	assert.True(errdefs.IsNotFound(errdefs.FromGRPC(err)))
+
+	// A sandbox holding its lock does not block the others
+	sa.mu.Lock()
+	_, err = ss.State(ctx, &taskAPI.StateRequest{ID: "sandbox-b"})
+	sa.mu.Unlock()
+	assert.NoError(err)
+
+	_, err = ss.Connect(ctx, &taskAPI.ConnectRequest{})
+	assert.NoError(err)
+	_, err = ss.Connect(ctx, &taskAPI.ConnectRequest{ID: "sandbox-a"})
+	assert.NoError(err)
+	_, err = ss.Connect(ctx, &taskAPI.ConnectRequest{ID: "unknown"})
+	assert.Error(err)
+
+	// The sandbox is kept until its last container is deleted
+	_, err = ss.Delete(ctx, &taskAPI.DeleteRequest{ID: "sandbox-a"})
+	assert.NoError(err)
+	assert.Contains(ss.sandboxes, "sandbox-a")
+
+	_, err = ss.Delete(ctx, &taskAPI.DeleteRequest{ID: "container-a"})
+	assert.NoError(err)
+	assert.NotContains(ss.sandboxes, "sandbox-a")
+	assert.NotContains(ss.containers, "container-a")
+
+	// The deletion events are published before the sandbox service stops
+	publisher := ss.publisher.(*fakePublisher)
+	for i := 0; i < 2; i++ {
+		assert.Equal(cdruntime.TaskDeleteEventTopic, <-publisher.topics)
+	}
+	select {
+	case <-sa.ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("sandbox service not stopped")
+	}
+
+	state = readSharedShimState(t, statePath)
+	assert.Equal(map[string]string{
+		"sandbox-b": filepath.Join(dir, "sandbox-b"),
+	}, state.Sandboxes)
+
+	// The shim keeps running while it serves a sandbox
+	_, err = ss.Shutdown(ctx, &taskAPI.ShutdownRequest{ID: "sandbox-a"})
+	assert.NoError(err)
+	assert.Nil(ss.idleTimer)
+
+	// The sandboxes leave the shutdown to the shared shim
+	_, err = sb.Shutdown(ctx, &taskAPI.ShutdownRequest{ID: "sandbox-b"})
+	assert.NoError(err)
+	assert.Nil(ss.idleTimer)
+
+	for _, id := range []string{"container-b", "sandbox-b"} {
+		_, err = ss.Delete(ctx, &taskAPI.DeleteRequest{ID: id})
+		assert.NoError(err)
+	}
+	assert.Empty(ss.sandboxes)
+
+	_, err = os.Stat(statePath)
+	assert.True(os.IsNotExist(err))
+
+	_, err = ss.Shutdown(ctx, &taskAPI.ShutdownRequest{ID: "sandbox-b"})
+	assert.NoError(err)
+	assert.NotNil(ss.idleTimer)
+	ss.idleTimer.Stop()
+}
+
+func TestSharedServiceRecover(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "shared-shim")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath, err := createAllRuntimeConfigFiles(dir, "qemu")
+	require.NoError(err)
+
+	// A sandbox with a shim state, and one without any
+	bundle := filepath.Join(dir, testSandboxID)
+	require.NoError(os.MkdirAll(bundle, testDirMode))
+
+	s := newRecoverTestService(filepath.Join(bundle, shimStateFile))
+	s.configPath = configPath
+	s.containers[testSandboxID], err = newContainer(s, &taskAPI.CreateTaskRequest{
+		ID:     testSandboxID,
+		Bundle: testBundleDir,
+	}, vc.PodSandbox, nil, false)
+	require.NoError(err)
+	s.saveState()
+
+	statePath := filepath.Join(dir, "UnitTest.json")
+	ss := newTestSharedService(statePath)
+	ss.sandboxes["gone"] = &service{statePath: filepath.Join(dir, "gone", shimStateFile)}
+	ss.sandboxes[testSandboxID] = s
+	ss.saveState()
+
+	testingImpl.FetchSandboxFunc = func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+		return &vcmock.Sandbox{MockID: sandboxID}, nil
+	}
+	defer func() {
+		testingImpl.FetchSandboxFunc = nil
+	}()
+
+	// Loading the configuration sets the logging up, syslog included,
+	// which depends on the host.
+	var loadedPath string
+	loadConfigurationFunc = func(configPath string, ignoreLogging, builtIn bool) (string, oci.RuntimeConfig, error) {
+		loadedPath = configPath
+		return configPath, oci.RuntimeConfig{}, nil
+	}
+	defer func() {
+		loadConfigurationFunc = katautils.LoadConfiguration
+	}()
+
+	ss = newTestSharedService(statePath)
+	require.NoError(ss.recover())
+
+	require.Len(ss.sandboxes, 1)
+	require.Contains(ss.sandboxes, testSandboxID)
+	assert.Equal(ss.sandboxes[testSandboxID], ss.containers[testSandboxID])
+	assert.Equal(configPath, ss.sandboxes[testSandboxID].configPath)
+	assert.Equal(configPath, loadedPath)
+
+	state := readSharedShimState(t, statePath)
+	assert.Equal(map[string]string{testSandboxID: bundle}, state.Sandboxes)
+}
+
+func TestSharedShimID(t *testing.T) {
+	assert := assert.New(t)
+
+	id := sharedShimID("/etc/kata-containers/configuration.toml")
+	assert.True(isSharedShimID(id))
+	assert.Equal(id, sharedShimID("/etc/kata-containers/configuration.toml"))
+	assert.NotEqual(id, sharedShimID("/etc/kata-containers/configuration-fc.toml"))
+
+	assert.False(isSharedShimID(testSandboxID))
+}
+
+func TestSharedShimAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shared-shim")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedStateDir := sharedShimStateDir
+	sharedShimStateDir = dir
+	defer func() {
+		sharedShimStateDir = savedStateDir
+	}()
+
+	ctx := namespaces.WithNamespace(context.Background(), "shared-shim-test")
+	shimID := sharedShimID("/etc/kata-containers/configuration.toml")
+
+	_, ok := sharedShimServes(ctx, shimID, "")
+	assert.False(ok)
+
+	address, err := cdshim.SocketAddress(ctx, shimID)
+	assert.NoError(err)
+
+	l, err := cdshim.NewSocket(address)
+	assert.NoError(err)
+
+	statePath, err := sharedShimStatePath(ctx, shimID)
+	assert.NoError(err)
+	assert.Equal(filepath.Join(dir, "shared-shim-test", shimID+".json"), statePath)
+
+	ss := newTestSharedService(statePath)
+	ss.sandboxes[testSandboxID] = &service{
+		id:         testSandboxID,
+		statePath:  filepath.Join(dir, testSandboxID, shimStateFile),
+		containers: make(map[string]*container),
+	}
+	ss.containers[testSandboxID] = ss.sandboxes[testSandboxID]
+	ss.saveState()
+
+	server, err := ttrpc.NewServer()
+	assert.NoError(err)
+	defer server.Close()
+	taskAPI.RegisterTaskService(server, ss)
+	go server.Serve(ctx, l)
+
+	served, ok := sharedShimServes(ctx, shimID, "")
+	assert.True(ok)
+	assert.Equal(address, served)
+
+	_, ok = sharedShimServes(ctx, shimID, testSandboxID)
+	assert.True(ok)
+
+	_, ok = sharedShimServes(ctx, shimID, "unknown")
+	assert.False(ok)
+
+	// The shared shims of the namespace are looked up for the sandbox
+	served, ok = sharedShimServing(ctx, testSandboxID)
+	assert.True(ok)
+	assert.Equal(address, served)
+
+	_, ok = sharedShimServing(ctx, "unknown")
+	assert.False(ok)
+
+	// The containers of a sandbox of the shared shim are served by it
+	rootPath, bundlePath := testConfigSetup(t)
+	defer os.RemoveAll(rootPath)
+
+	spec, err := compatoci.ParseConfigJSON(bundlePath)
+	assert.NoError(err)
+	spec.Annotations = map[string]string{
+		testContainerTypeAnnotation: testContainerTypeContainer,
+		testSandboxIDAnnotation:     testSandboxID,
+	}
+	assert.NoError(writeOCIConfigFile(spec, filepath.Join(bundlePath, specConf)))
+
+	served, startID, err := getAddress(ctx, bundlePath, testContainerID, nil)
+	assert.NoError(err)
+	assert.Equal(address, served)
+	assert.Empty(startID)
+}
+
+func TestSharedShimEnabled(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shared-shim")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath, err := createAllRuntimeConfigFiles(dir, "qemu")
+	assert.NoError(err)
+
+	spec := specs.Spec{
+		Annotations: map[string]string{
+			vcAnnotations.SandboxConfigPathKey: configPath,
+		},
+	}
+	_, ok := sharedShimEnabled(spec, nil)
+	assert.False(ok)
+
+	data, err := ioutil.ReadFile(configPath)
+	assert.NoError(err)
+	data = []byte(strings.Replace(string(data), "[runtime]", "[runtime]\nshared_shim = true", 1))
+	assert.NoError(ioutil.WriteFile(configPath, data, testFileMode))
+
+	shimID, ok := sharedShimEnabled(spec, nil)
+	assert.True(ok)
+	assert.Equal(sharedShimID(configPath), shimID)
+
+	// The configuration of the create task options is used too
+	options, err := typeurl.MarshalAny(&crioption.Options{ConfigPath: configPath})
+	assert.NoError(err)
+	shimID, ok = sharedShimEnabled(specs.Spec{}, options)
+	assert.True(ok)
+	assert.Equal(sharedShimID(configPath), shimID)
+
+	spec.Annotations[vcAnnotations.SandboxConfigPathKey] = filepath.Join(dir, "missing.toml")
+	_, ok = sharedShimEnabled(spec, options)
+	assert.False(ok)
+}
+
+func TestReadRuntimeOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := ioutil.TempFile("", "runtime-options")
+	assert.NoError(err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// containerd did not give any
+	options, err := readRuntimeOptions(f)
+	assert.NoError(err)
+	assert.Nil(options)
+
+	any, err := typeurl.MarshalAny(&crioption.Options{ConfigPath: "/etc/kata-containers/configuration.toml"})
+	assert.NoError(err)
+	data, err := any.Marshal()
+	assert.NoError(err)
+	_, err = f.Write(data)
+	assert.NoError(err)
+	_, err = f.Seek(0, 0)
+	assert.NoError(err)
+
+	options, err = readRuntimeOptions(f)
+	assert.NoError(err)
+	assert.Equal(any.TypeUrl, options.TypeUrl)
+
+	configPath, err := runtimeConfigPath(nil, options)
+	assert.NoError(err)
+	assert.Equal("/etc/kata-containers/configuration.toml", configPath)
+}
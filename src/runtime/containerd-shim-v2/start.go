@@ -8,9 +8,12 @@ package containerdshim
 import (
 	"context"
 	"fmt"
+	"syscall"
+	"time"
 
 	"github.com/containerd/containerd/api/types/task"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/sirupsen/logrus"
 )
 
 func startContainer(ctx context.Context, s *service, c *container) error {
@@ -49,7 +52,7 @@ func startContainer(ctx context.Context, s *service, c *container) error {
 
 	// Run post-start OCI hooks.
 	err := katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
-		return katautils.PostStartHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle)
+		return katautils.PostStartHooks(ctx, *c.spec, s.sandbox, s.sandbox.ID(), c.bundle)
 	})
 	if err != nil {
 		return err
@@ -57,6 +60,12 @@ func startContainer(ctx context.Context, s *service, c *container) error {
 
 	c.status = task.StatusRunning
 
+	return startContainerIO(ctx, s, c)
+}
+
+// startContainerIO copies the I/O of the container process and waits for
+// it to exit.
+func startContainerIO(ctx context.Context, s *service, c *container) error {
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, c.id)
 	if err != nil {
 		return err
@@ -81,6 +90,11 @@ func startContainer(ctx context.Context, s *service, c *container) error {
 }
 
 func startExec(ctx context.Context, s *service, containerID, execID string) (*exec, error) {
+	span, ctx := trace(ctx, "startExec")
+	span.SetTag("container", containerID)
+	span.SetTag("exec", execID)
+	defer span.Finish()
+
 	//start an exec
 	c, err := s.getContainer(containerID)
 	if err != nil {
@@ -92,7 +106,9 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (*ex
 		return nil, err
 	}
 
+	enterSpan, _ := trace(ctx, "enterContainer")
 	_, proc, err := s.sandbox.EnterContainer(containerID, *execs.cmds)
+	enterSpan.Finish()
 	if err != nil {
 		err := fmt.Errorf("cannot enter container %s, with err %s", containerID, err)
 		return nil, err
@@ -101,19 +117,35 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (*ex
 
 	execs.status = task.StatusRunning
 	if execs.tty.height != 0 && execs.tty.width != 0 {
+		winsizeSpan, _ := trace(ctx, "winsizeProcess")
 		err = s.sandbox.WinsizeProcess(c.id, execs.id, execs.tty.height, execs.tty.width)
+		winsizeSpan.Finish()
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if err = startExecIO(ctx, s, c, execID, execs); err != nil {
+		return nil, err
+	}
+
+	setExecTimeout(s, c, execID, execs)
+
+	return execs, nil
+}
+
+// startExecIO copies the I/O of the exec process and waits for it to exit.
+func startExecIO(ctx context.Context, s *service, c *container, execID string, execs *exec) error {
+	span, ctx := trace(ctx, "startExecIO")
+	defer span.Finish()
+
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, execs.id)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	tty, err := newTtyIO(ctx, execs.tty.stdin, execs.tty.stdout, execs.tty.stderr, execs.tty.terminal)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	execs.ttyio = tty
 
@@ -121,5 +153,37 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (*ex
 
 	go wait(s, c, execID)
 
-	return execs, nil
+	return nil
+}
+
+// setExecTimeout kills the exec process once the exec timeout of the
+// configuration is elapsed, unless it has exited by then. An exec recovered
+// by a restarted shim keeps the deadline it was started with.
+func setExecTimeout(s *service, c *container, execID string, execs *exec) {
+	if execs.deadline.IsZero() {
+		if s.config == nil || s.config.ExecTimeout == 0 {
+			return
+		}
+		execs.deadline = time.Now().Add(s.config.ExecTimeout)
+	}
+
+	execs.timeout = time.AfterFunc(time.Until(execs.deadline), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if execs.status == task.StatusStopped {
+			return
+		}
+
+		logger := logrus.WithFields(logrus.Fields{
+			"container": c.id,
+			"exec":      execID,
+			"deadline":  execs.deadline,
+		})
+		logger.Warn("exec timed out, killing it")
+
+		if err := s.sandbox.SignalProcess(c.id, execs.id, syscall.SIGKILL, false); err != nil {
+			logger.WithError(err).Warn("failed to kill timed out exec")
+		}
+	})
 }
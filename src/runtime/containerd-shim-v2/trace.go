@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+
+	opentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+)
+
+// shimTracerName is the service name of the spans of the shim.
+const shimTracerName = "kata-shim-v2"
+
+func trace(parent context.Context, name string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(parent, name)
+
+	span.SetTag("source", "runtime")
+	span.SetTag("component", "containerd-shim-v2")
+
+	return span, ctx
+}
+
+// setupTracing registers the tracer of the shim when the configuration
+// enables tracing, once for all the sandboxes the shim serves.
+func setupTracing(config *oci.RuntimeConfig) error {
+	if !config.Trace || opentracing.IsGlobalTracerRegistered() {
+		return nil
+	}
+
+	_, err := katautils.CreateTracer(shimTracerName)
+	return err
+}
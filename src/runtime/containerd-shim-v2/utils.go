@@ -9,12 +9,14 @@ package containerdshim
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/containerd/containerd/mount"
 	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	ptypes "github.com/gogo/protobuf/types"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
@@ -23,12 +25,15 @@ import (
 )
 
 func cReap(s *service, status int, id, execid string, exitat time.Time) {
-	s.ec <- exit{
+	select {
+	case s.ec <- exit{
 		timestamp: exitat,
 		pid:       s.pid,
 		status:    status,
 		id:        id,
 		execid:    execid,
+	}:
+	case <-s.done:
 	}
 }
 
@@ -79,37 +84,78 @@ func validBundle(containerID, bundlePath string) (string, error) {
 	return resolved, nil
 }
 
-func getAddress(ctx context.Context, bundlePath, id string) (string, error) {
+// readRuntimeOptions reads the create task options containerd writes to the
+// standard input of the shim started to start a shim, when it does. Nothing
+// is read from a terminal.
+func readRuntimeOptions(r *os.File) (*ptypes.Any, error) {
+	fi, err := r.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice != 0 {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var options ptypes.Any
+	if err := options.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("invalid runtime options: %v", err)
+	}
+
+	return &options, nil
+}
+
+// getAddress returns the address of the shim already serving the sandbox of
+// the container id, or the ID of the shim to start for it when there is
+// none.
+func getAddress(ctx context.Context, bundlePath, id string, options *ptypes.Any) (string, string, error) {
 	var err error
 
 	// Checks the MUST and MUST NOT from OCI runtime specification
 	if bundlePath, err = validBundle(id, bundlePath); err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	ociSpec, err := compatoci.ParseConfigJSON(bundlePath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	containerType, err := oci.ContainerType(ociSpec)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	if containerType == vc.PodContainer {
 		sandboxID, err := oci.SandboxID(ociSpec)
 		if err != nil {
-			return "", err
+			return "", "", err
+		}
+
+		// The sandbox may have been created in a shared shim
+		if address, ok := sharedShimServing(ctx, sandboxID); ok {
+			return address, "", nil
 		}
+
 		address, err := cdshim.SocketAddress(ctx, sandboxID)
 		if err != nil {
-			return "", err
+			return "", "", err
+		}
+		return address, "", nil
+	}
+
+	if shimID, ok := sharedShimEnabled(ociSpec, options); ok {
+		if address, ok := sharedShimServes(ctx, shimID, ""); ok {
+			return address, "", nil
 		}
-		return address, nil
+		return "", shimID, nil
 	}
 
-	return "", nil
+	return "", id, nil
 }
 
 func noNeedForOutput(detach bool, tty bool) bool {
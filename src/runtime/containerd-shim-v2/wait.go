@@ -60,6 +60,7 @@ func wait(s *service, c *container, execID string) (int32, error) {
 			if s.monitor != nil {
 				s.monitor <- nil
 			}
+			saveHookStates(s)
 			if err = s.sandbox.Stop(true); err != nil {
 				logrus.WithField("sandbox", s.sandbox.ID()).Error("failed to stop sandbox")
 			}
@@ -67,6 +68,7 @@ func wait(s *service, c *container, execID string) (int32, error) {
 			if err = s.sandbox.Delete(); err != nil {
 				logrus.WithField("sandbox", s.sandbox.ID()).Error("failed to delete sandbox")
 			}
+			s.eventSink.close()
 		} else {
 			if _, err = s.sandbox.StopContainer(c.id, false); err != nil {
 				logrus.WithError(err).WithField("container", c.id).Warn("stop container failed")
@@ -79,12 +81,16 @@ func wait(s *service, c *container, execID string) (int32, error) {
 		c.exitCh <- uint32(ret)
 
 	} else {
+		if execs.timeout != nil {
+			execs.timeout.Stop()
+		}
 		execs.status = task.StatusStopped
 		execs.exitCode = ret
 		execs.exitTime = timeStamp
 
 		execs.exitCh <- uint32(ret)
 	}
+	s.saveState()
 	s.mu.Unlock()
 
 	go cReap(s, int(ret), c.id, execID, timeStamp)
@@ -106,6 +112,7 @@ func watchSandbox(s *service) {
 	defer s.mu.Unlock()
 	// sandbox malfunctioning, cleanup as much as we can
 	logrus.WithError(err).Warn("sandbox stopped unexpectedly")
+	saveHookStates(s)
 	err = s.sandbox.Stop(true)
 	if err != nil {
 		logrus.WithError(err).Warn("stop sandbox failed")
@@ -114,6 +121,7 @@ func watchSandbox(s *service) {
 	if err != nil {
 		logrus.WithError(err).Warn("delete sandbox failed")
 	}
+	s.eventSink.close()
 
 	for _, c := range s.containers {
 		if !c.mounted {
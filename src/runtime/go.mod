@@ -33,7 +33,7 @@ require (
 	github.com/mdlayher/vsock v0.0.0-20191108225356-d9c65923cb8f
 	github.com/mitchellh/mapstructure v1.1.2
 	github.com/opencontainers/runc v1.0.0-rc9.0.20200102164712-2b52db75279c
-	github.com/opencontainers/runtime-spec v1.0.2-0.20190408193819-a1b50f621a48
+	github.com/opencontainers/runtime-spec v1.0.2
 	github.com/opencontainers/selinux v1.4.0
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/pkg/errors v0.8.1
@@ -53,10 +53,13 @@ require (
 	golang.org/x/oauth2 v0.0.0-20191122200657-5d9234df094c
 	golang.org/x/sys v0.0.0-20200420163511-1957bb5e6d1f
 	google.golang.org/grpc v1.19.0
+	gopkg.in/yaml.v2 v2.2.2
 	gotest.tools v2.2.0+incompatible // indirect
 )
 
 replace (
+	// govmm changes not merged upstream yet, see pkg/govmm/README.md
+	github.com/intel/govmm => ./pkg/govmm
 	github.com/uber-go/atomic => go.uber.org/atomic v1.5.1
 	gotest.tools/v3 => gotest.tools v2.2.0+incompatible
 )
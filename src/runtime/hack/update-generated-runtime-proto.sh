@@ -12,3 +12,8 @@ protoc \
 Mgoogle/protobuf/empty.proto=github.com/gogo/protobuf/types,\
 plugins=grpc:protocols/cache \
 	protocols/cache/cache.proto
+
+protoc \
+	-I=$GOPATH/src \
+	--gogottrpc_out=$GOPATH/src \
+	$GOPATH/src/github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/events/events.proto
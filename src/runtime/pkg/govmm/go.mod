@@ -0,0 +1,3 @@
+module github.com/intel/govmm
+
+go 1.14
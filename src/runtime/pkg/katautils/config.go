@@ -7,11 +7,15 @@
 package katautils
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	goruntime "runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	govmmQemu "github.com/intel/govmm/qemu"
@@ -98,6 +102,9 @@ type hypervisor struct {
 	MachineType             string   `toml:"machine_type"`
 	BlockDeviceDriver       string   `toml:"block_device_driver"`
 	EntropySource           string   `toml:"entropy_source"`
+	EntropyMaxBytes         uint32   `toml:"entropy_max_bytes"`
+	EntropyPeriod           uint32   `toml:"entropy_period"`
+	EntropyReseedInterval   uint32   `toml:"entropy_reseed_interval"`
 	SharedFS                string   `toml:"shared_fs"`
 	VirtioFSDaemon          string   `toml:"virtio_fs_daemon"`
 	VirtioFSCache           string   `toml:"virtio_fs_cache"`
@@ -108,6 +115,7 @@ type hypervisor struct {
 	BlockDeviceCacheNoflush bool     `toml:"block_device_cache_noflush"`
 	EnableVhostUserStore    bool     `toml:"enable_vhost_user_store"`
 	VhostUserStorePath      string   `toml:"vhost_user_store_path"`
+	PassthroughCharDevices  []string `toml:"passthrough_char_devices"`
 	NumVCPUs                int32    `toml:"default_vcpus"`
 	DefaultMaxVCPUs         uint32   `toml:"default_maxvcpus"`
 	MemorySize              uint32   `toml:"default_memory"`
@@ -133,6 +141,8 @@ type hypervisor struct {
 	GuestHookPath           string   `toml:"guest_hook_path"`
 	RxRateLimiterMaxRate    uint64   `toml:"rx_rate_limiter_max_rate"`
 	TxRateLimiterMaxRate    uint64   `toml:"tx_rate_limiter_max_rate"`
+	CPUPlacementPolicy      string   `toml:"cpu_placement_policy"`
+	HousekeepingCPUs        string   `toml:"housekeeping_cpus"`
 }
 
 type proxy struct {
@@ -141,13 +151,16 @@ type proxy struct {
 }
 
 type runtime struct {
-	Debug               bool     `toml:"enable_debug"`
-	Tracing             bool     `toml:"enable_tracing"`
-	DisableNewNetNs     bool     `toml:"disable_new_netns"`
-	DisableGuestSeccomp bool     `toml:"disable_guest_seccomp"`
-	SandboxCgroupOnly   bool     `toml:"sandbox_cgroup_only"`
-	Experimental        []string `toml:"experimental"`
-	InterNetworkModel   string   `toml:"internetworking_model"`
+	Debug                bool     `toml:"enable_debug"`
+	Tracing              bool     `toml:"enable_tracing"`
+	DisableNewNetNs      bool     `toml:"disable_new_netns"`
+	DisableGuestSeccomp  bool     `toml:"disable_guest_seccomp"`
+	SandboxCgroupOnly    bool     `toml:"sandbox_cgroup_only"`
+	SharedShim           bool     `toml:"shared_shim"`
+	ExecTimeout          uint32   `toml:"exec_timeout"`
+	Experimental         []string `toml:"experimental"`
+	InterNetworkModel    string   `toml:"internetworking_model"`
+	GuestSysctlAllowlist []string `toml:"guest_sysctl_allowlist"`
 }
 
 type shim struct {
@@ -157,11 +170,15 @@ type shim struct {
 }
 
 type agent struct {
-	Debug         bool     `toml:"enable_debug"`
-	Tracing       bool     `toml:"enable_tracing"`
-	TraceMode     string   `toml:"trace_mode"`
-	TraceType     string   `toml:"trace_type"`
-	KernelModules []string `toml:"kernel_modules"`
+	Debug                       bool     `toml:"enable_debug"`
+	Tracing                     bool     `toml:"enable_tracing"`
+	TraceMode                   string   `toml:"trace_mode"`
+	TraceType                   string   `toml:"trace_type"`
+	KernelModules               []string `toml:"kernel_modules"`
+	HealthCheckInterval         uint32   `toml:"health_check_interval"`
+	HealthCheckTimeout          uint32   `toml:"health_check_timeout"`
+	HealthCheckFailureThreshold uint32   `toml:"health_check_failure_threshold"`
+	HealthCheckRecovery         string   `toml:"health_check_recovery"`
 }
 
 type netmon struct {
@@ -429,6 +446,20 @@ func (h hypervisor) vhostUserStorePath() string {
 	return h.VhostUserStorePath
 }
 
+func (h hypervisor) passthroughCharDevices() ([]string, error) {
+	for _, pattern := range h.PassthroughCharDevices {
+		if !filepath.IsAbs(pattern) {
+			return nil, fmt.Errorf("Invalid passthrough char device pattern %q: not an absolute path", pattern)
+		}
+
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("Invalid passthrough char device pattern %q: %v", pattern, err)
+		}
+	}
+
+	return h.PassthroughCharDevices, nil
+}
+
 func (h hypervisor) getInitrdAndImage() (initrd string, image string, err error) {
 	initrd, errInitrd := h.initrd()
 
@@ -512,6 +543,21 @@ func (a agent) kernelModules() []string {
 	return a.KernelModules
 }
 
+func (a agent) healthCheck() (vc.AgentHealthCheckConfig, error) {
+	healthCheck := vc.AgentHealthCheckConfig{
+		Interval:         time.Duration(a.HealthCheckInterval) * time.Second,
+		Timeout:          time.Duration(a.HealthCheckTimeout) * time.Second,
+		FailureThreshold: a.HealthCheckFailureThreshold,
+		Recovery:         vc.AgentRecoveryPolicy(a.HealthCheckRecovery),
+	}
+
+	if err := healthCheck.Valid(); err != nil {
+		return vc.AgentHealthCheckConfig{}, err
+	}
+
+	return healthCheck, nil
+}
+
 func (n netmon) enable() bool {
 	return n.Enable
 }
@@ -561,6 +607,11 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	charDevices, err := h.passthroughCharDevices()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	if !utils.SupportsVsocks() {
 		return vc.HypervisorConfig{}, errors.New("No vsock support, firecracker cannot be used")
 	}
@@ -576,25 +627,29 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 	}
 
 	return vc.HypervisorConfig{
-		HypervisorPath:        hypervisor,
-		JailerPath:            jailer,
-		KernelPath:            kernel,
-		InitrdPath:            initrd,
-		ImagePath:             image,
-		FirmwarePath:          firmware,
-		KernelParams:          vc.DeserializeParams(strings.Fields(kernelParams)),
-		NumVCPUs:              h.defaultVCPUs(),
-		DefaultMaxVCPUs:       h.defaultMaxVCPUs(),
-		MemorySize:            h.defaultMemSz(),
-		MemSlots:              h.defaultMemSlots(),
-		EntropySource:         h.GetEntropySource(),
-		DefaultBridges:        h.defaultBridges(),
-		DisableBlockDeviceUse: h.DisableBlockDeviceUse,
-		HugePages:             h.HugePages,
-		Mlock:                 !h.Swap,
-		Debug:                 h.Debug,
-		DisableNestingChecks:  h.DisableNestingChecks,
-		BlockDeviceDriver:     blockDriver,
+		HypervisorPath:         hypervisor,
+		JailerPath:             jailer,
+		KernelPath:             kernel,
+		InitrdPath:             initrd,
+		ImagePath:              image,
+		FirmwarePath:           firmware,
+		KernelParams:           vc.DeserializeParams(strings.Fields(kernelParams)),
+		NumVCPUs:               h.defaultVCPUs(),
+		DefaultMaxVCPUs:        h.defaultMaxVCPUs(),
+		MemorySize:             h.defaultMemSz(),
+		MemSlots:               h.defaultMemSlots(),
+		EntropySource:          h.GetEntropySource(),
+		EntropyMaxBytes:        h.EntropyMaxBytes,
+		EntropyPeriod:          h.EntropyPeriod,
+		EntropyReseedInterval:  h.EntropyReseedInterval,
+		DefaultBridges:         h.defaultBridges(),
+		DisableBlockDeviceUse:  h.DisableBlockDeviceUse,
+		HugePages:              h.HugePages,
+		Mlock:                  !h.Swap,
+		Debug:                  h.Debug,
+		DisableNestingChecks:   h.DisableNestingChecks,
+		BlockDeviceDriver:      blockDriver,
+		PassthroughCharDevices: charDevices,
 		EnableIOThreads:       h.EnableIOThreads,
 		DisableVhostNet:       true, // vhost-net backend is not supported in Firecracker
 		UseVSock:              true,
@@ -653,6 +708,11 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	charDevices, err := h.passthroughCharDevices()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	sharedFS, err := h.sharedFS()
 	if err != nil {
 		return vc.HypervisorConfig{}, err
@@ -700,6 +760,9 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		MemOffset:               h.defaultMemOffset(),
 		VirtioMem:               h.VirtioMem,
 		EntropySource:           h.GetEntropySource(),
+		EntropyMaxBytes:         h.EntropyMaxBytes,
+		EntropyPeriod:           h.EntropyPeriod,
+		EntropyReseedInterval:   h.EntropyReseedInterval,
 		DefaultBridges:          h.defaultBridges(),
 		DisableBlockDeviceUse:   h.DisableBlockDeviceUse,
 		SharedFS:                sharedFS,
@@ -715,6 +778,7 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		Debug:                   h.Debug,
 		DisableNestingChecks:    h.DisableNestingChecks,
 		BlockDeviceDriver:       blockDriver,
+		PassthroughCharDevices: charDevices,
 		BlockDeviceCacheSet:     h.BlockDeviceCacheSet,
 		BlockDeviceCacheDirect:  h.BlockDeviceCacheDirect,
 		BlockDeviceCacheNoflush: h.BlockDeviceCacheNoflush,
@@ -730,6 +794,8 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		GuestHookPath:           h.guestHookPath(),
 		RxRateLimiterMaxRate:    rxRateLimiterMaxRate,
 		TxRateLimiterMaxRate:    txRateLimiterMaxRate,
+		CPUPlacementPolicy:      vc.CPUPlacementPolicy(h.CPUPlacementPolicy),
+		HousekeepingCPUs:        h.HousekeepingCPUs,
 	}, nil
 }
 
@@ -834,6 +900,16 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	charDevices, err := h.passthroughCharDevices()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
+	if h.EntropyMaxBytes > 0 {
+		return vc.HypervisorConfig{},
+			errors.New("cloud-hypervisor does not support rate limiting the entropy source, entropy_max_bytes must not be set")
+	}
+
 	sharedFS := config.VirtioFS
 
 	if h.VirtioFSDaemon == "" {
@@ -857,6 +933,7 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		MemOffset:               h.defaultMemOffset(),
 		VirtioMem:               h.VirtioMem,
 		EntropySource:           h.GetEntropySource(),
+		EntropyReseedInterval:   h.EntropyReseedInterval,
 		DefaultBridges:          h.defaultBridges(),
 		DisableBlockDeviceUse:   h.DisableBlockDeviceUse,
 		SharedFS:                sharedFS,
@@ -870,6 +947,7 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		Debug:                   h.Debug,
 		DisableNestingChecks:    h.DisableNestingChecks,
 		BlockDeviceDriver:       blockDriver,
+		PassthroughCharDevices: charDevices,
 		BlockDeviceCacheSet:     h.BlockDeviceCacheSet,
 		BlockDeviceCacheDirect:  h.BlockDeviceCacheDirect,
 		BlockDeviceCacheNoflush: h.BlockDeviceCacheNoflush,
@@ -880,6 +958,8 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		DisableVhostNet:         true,
 		UseVSock:                true,
 		VirtioFSExtraArgs:       h.VirtioFSExtraArgs,
+		CPUPlacementPolicy:      vc.CPUPlacementPolicy(h.CPUPlacementPolicy),
+		HousekeepingCPUs:        h.HousekeepingCPUs,
 	}, nil
 }
 
@@ -911,24 +991,34 @@ func newShimConfig(s shim) (vc.ShimConfig, error) {
 	}, nil
 }
 
+func newHypervisorConfig(tableType string, h hypervisor) (vc.HypervisorType, vc.HypervisorConfig, error) {
+	var hConfig vc.HypervisorConfig
+	var err error
+
+	switch tableType {
+	case firecrackerHypervisorTableType:
+		hConfig, err = newFirecrackerHypervisorConfig(h)
+		return vc.FirecrackerHypervisor, hConfig, err
+	case qemuHypervisorTableType:
+		hConfig, err = newQemuHypervisorConfig(h)
+		return vc.QemuHypervisor, hConfig, err
+	case acrnHypervisorTableType:
+		hConfig, err = newAcrnHypervisorConfig(h)
+		return vc.AcrnHypervisor, hConfig, err
+	case clhHypervisorTableType:
+		hConfig, err = newClhHypervisorConfig(h)
+		return vc.ClhHypervisor, hConfig, err
+	}
+
+	// unknown hypervisor type
+	return "", hConfig, nil
+}
+
 func updateRuntimeConfigHypervisor(configPath string, tomlConf tomlConfig, config *oci.RuntimeConfig) error {
 	for k, hypervisor := range tomlConf.Hypervisor {
-		var err error
-		var hConfig vc.HypervisorConfig
-
-		switch k {
-		case firecrackerHypervisorTableType:
-			config.HypervisorType = vc.FirecrackerHypervisor
-			hConfig, err = newFirecrackerHypervisorConfig(hypervisor)
-		case qemuHypervisorTableType:
-			config.HypervisorType = vc.QemuHypervisor
-			hConfig, err = newQemuHypervisorConfig(hypervisor)
-		case acrnHypervisorTableType:
-			config.HypervisorType = vc.AcrnHypervisor
-			hConfig, err = newAcrnHypervisorConfig(hypervisor)
-		case clhHypervisorTableType:
-			config.HypervisorType = vc.ClhHypervisor
-			hConfig, err = newClhHypervisorConfig(hypervisor)
+		hType, hConfig, err := newHypervisorConfig(k, hypervisor)
+		if hType != "" {
+			config.HypervisorType = hType
 		}
 
 		if err != nil {
@@ -941,6 +1031,45 @@ func updateRuntimeConfigHypervisor(configPath string, tomlConf tomlConfig, confi
 	return nil
 }
 
+// ConfiguredHypervisor is a hypervisor a configuration file has a section
+// for.
+type ConfiguredHypervisor struct {
+	Type   vc.HypervisorType
+	Config vc.HypervisorConfig
+	// Err is the error the section of the hypervisor is invalid with, a
+	// guest asset it refers to not existing for example.
+	Err error
+}
+
+// LoadHypervisorConfigs returns every hypervisor the configuration file
+// has a section for, sorted by type, even the ones with an invalid section.
+func LoadHypervisorConfigs(configPath string) ([]ConfiguredHypervisor, error) {
+	tomlConf, _, err := decodeConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var hypervisors []ConfiguredHypervisor
+	for k, h := range tomlConf.Hypervisor {
+		hType, hConfig, err := newHypervisorConfig(k, h)
+		if hType == "" {
+			continue
+		}
+
+		hypervisors = append(hypervisors, ConfiguredHypervisor{
+			Type:   hType,
+			Config: hConfig,
+			Err:    err,
+		})
+	}
+
+	sort.Slice(hypervisors, func(i, j int) bool {
+		return hypervisors[i].Type < hypervisors[j].Type
+	})
+
+	return hypervisors, nil
+}
+
 func updateRuntimeConfigProxy(configPath string, tomlConf tomlConfig, config *oci.RuntimeConfig, builtIn bool) error {
 	if builtIn {
 		config.ProxyType = vc.KataBuiltInProxyType
@@ -986,6 +1115,7 @@ func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oc
 			UseVSock:      config.HypervisorConfig.UseVSock,
 			Debug:         agentConfig.Debug,
 			KernelModules: agentConfig.KernelModules,
+			HealthCheck:   agentConfig.HealthCheck,
 		}
 
 		return nil
@@ -994,6 +1124,11 @@ func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oc
 	for k, agent := range tomlConf.Agent {
 		switch k {
 		case kataAgentTableType:
+			healthCheck, err := agent.healthCheck()
+			if err != nil {
+				return fmt.Errorf("%v: %v", configPath, err)
+			}
+
 			config.AgentType = vc.KataContainersAgent
 			config.AgentConfig = vc.KataAgentConfig{
 				UseVSock:      config.HypervisorConfig.UseVSock,
@@ -1002,6 +1137,7 @@ func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oc
 				TraceMode:     agent.traceMode(),
 				TraceType:     agent.traceType(),
 				KernelModules: agent.kernelModules(),
+				HealthCheck:   healthCheck,
 			}
 		default:
 			return fmt.Errorf("%s agent type is not supported", k)
@@ -1264,6 +1400,9 @@ func LoadConfiguration(configPath string, ignoreLogging, builtIn bool) (resolved
 
 	config.SandboxCgroupOnly = tomlConf.Runtime.SandboxCgroupOnly
 	config.DisableNewNetNs = tomlConf.Runtime.DisableNewNetNs
+	config.SharedShim = tomlConf.Runtime.SharedShim
+	config.ExecTimeout = time.Duration(tomlConf.Runtime.ExecTimeout) * time.Second
+	config.GuestSysctlAllowlist = tomlConf.Runtime.GuestSysctlAllowlist
 	for _, f := range tomlConf.Runtime.Experimental {
 		feature := exp.Get(f)
 		if feature == nil {
@@ -1280,27 +1419,37 @@ func LoadConfiguration(configPath string, ignoreLogging, builtIn bool) (resolved
 }
 
 func decodeConfig(configPath string) (tomlConfig, string, error) {
-	var (
-		resolved string
-		tomlConf tomlConfig
-		err      error
-	)
+	var tomlConf tomlConfig
 
-	if configPath == "" {
-		resolved, err = getDefaultConfigFile()
-	} else {
-		resolved, err = ResolvePath(configPath)
+	resolved, err := resolveConfigFile(configPath)
+	if err != nil {
+		return tomlConf, "", err
 	}
 
+	configData, err := ioutil.ReadFile(resolved)
 	if err != nil {
-		return tomlConf, "", fmt.Errorf("Cannot find usable config file (%v)", err)
+		return tomlConf, resolved, err
 	}
 
-	configData, err := ioutil.ReadFile(resolved)
+	dropIns, err := configDropIns(configPath)
 	if err != nil {
 		return tomlConf, resolved, err
 	}
 
+	if len(dropIns) != 0 {
+		// Decode the configuration merged with its drop-in files
+		values, _, err := mergeConfigFiles(append([]string{resolved}, dropIns...))
+		if err != nil {
+			return tomlConf, resolved, err
+		}
+
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(values); err != nil {
+			return tomlConf, resolved, err
+		}
+		configData = buf.Bytes()
+	}
+
 	_, err = toml.Decode(string(configData), &tomlConf)
 	if err != nil {
 		return tomlConf, resolved, err
@@ -1309,6 +1458,27 @@ func decodeConfig(configPath string) (tomlConfig, string, error) {
 	return tomlConf, resolved, nil
 }
 
+// resolveConfigFile returns the resolved path of the configuration file
+// configPath, or of the one found in the default locations when empty.
+func resolveConfigFile(configPath string) (string, error) {
+	var (
+		resolved string
+		err      error
+	)
+
+	if configPath == "" {
+		resolved, err = getDefaultConfigFile()
+	} else {
+		resolved, err = ResolvePath(configPath)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Cannot find usable config file (%v)", err)
+	}
+
+	return resolved, nil
+}
+
 // checkConfig checks the validity of the specified config.
 func checkConfig(config oci.RuntimeConfig) error {
 	if err := checkNetNsConfig(config); err != nil {
@@ -0,0 +1,207 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katautils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configDropInDirSuffix suffixes the name of a configuration file to name
+// the directory, next to it, holding the files merged into it:
+// configuration.toml.d for configuration.toml.
+const configDropInDirSuffix = ".d"
+
+// ConfigValue is a value of the configuration, a table when it is a map,
+// and the file setting it.
+type ConfigValue struct {
+	Key   string
+	Value interface{}
+	File  string
+}
+
+// ConfigDump is the configuration merged from a configuration file and its
+// drop-in files.
+type ConfigDump struct {
+	File    string
+	DropIns []string
+	Values  []ConfigValue
+}
+
+// configDropInDirs returns the drop-in directories of the configuration
+// file configPath, or of the one found in the default locations when empty,
+// in the order their files are merged in. The directory is the one next to
+// the file as configured, not as resolved. A default configuration file
+// found out of /etc, the one of the package, also takes the drop-in files
+// of the configuration file of /etc.
+func configDropInDirs(configPath string) []string {
+	if configPath != "" {
+		return []string{configPath + configDropInDirSuffix}
+	}
+
+	var dirs []string
+	for _, file := range GetDefaultConfigFilePaths() {
+		if _, err := ResolvePath(file); err == nil {
+			dirs = append(dirs, file+configDropInDirSuffix)
+			configPath = file
+			break
+		}
+	}
+
+	if configPath != defaultSysConfRuntimeConfiguration && defaultSysConfRuntimeConfiguration != "" {
+		dirs = append(dirs, defaultSysConfRuntimeConfiguration+configDropInDirSuffix)
+	}
+
+	return dirs
+}
+
+// configDropIns returns the drop-in files of the configuration file
+// configPath, in the order they are merged in: directory by directory, and
+// in lexical order within each of them.
+func configDropIns(configPath string) ([]string, error) {
+	var dropIns []string
+
+	for _, dir := range configDropInDirs(configPath) {
+		files, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(files)
+
+		for _, file := range files {
+			if fi, err := os.Stat(file); err == nil && fi.Mode().IsRegular() {
+				dropIns = append(dropIns, file)
+			}
+		}
+	}
+
+	return dropIns, nil
+}
+
+// mergeConfigValues merges the values src of file into dst, the tables
+// key by key and the other values, arrays included, as a whole. The file
+// setting each value is recorded in sources, by dotted key.
+func mergeConfigValues(dst, src map[string]interface{}, prefix, file string, sources map[string]string) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		table, isTable := v.(map[string]interface{})
+		dstTable, dstIsTable := dst[k].(map[string]interface{})
+		if isTable && dstIsTable {
+			mergeConfigValues(dstTable, table, key, file, sources)
+			continue
+		}
+
+		// Whatever the value replaces is gone
+		for sk := range sources {
+			if strings.HasPrefix(sk, key+".") {
+				delete(sources, sk)
+			}
+		}
+		sources[key] = file
+
+		if !isTable {
+			dst[k] = v
+			continue
+		}
+
+		dstTable = make(map[string]interface{})
+		dst[k] = dstTable
+
+		mergeConfigValues(dstTable, table, key, file, sources)
+	}
+}
+
+// mergeConfigFiles returns the values of the configuration files merged in
+// order, and the file setting each of them by dotted key.
+func mergeConfigFiles(files []string) (map[string]interface{}, map[string]string, error) {
+	values := make(map[string]interface{})
+	sources := make(map[string]string)
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var fileValues map[string]interface{}
+		if _, err := toml.Decode(string(data), &fileValues); err != nil {
+			return nil, nil, fmt.Errorf("invalid configuration file %s: %v", file, err)
+		}
+
+		mergeConfigValues(values, fileValues, "", file, sources)
+	}
+
+	return values, sources, nil
+}
+
+// flattenConfigValues lists the values, the empty tables included, by
+// dotted key.
+func flattenConfigValues(values map[string]interface{}, prefix string, sources map[string]string) []ConfigValue {
+	var flat []ConfigValue
+
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if table, ok := v.(map[string]interface{}); ok && len(table) != 0 {
+			flat = append(flat, flattenConfigValues(table, key, sources)...)
+			continue
+		}
+
+		flat = append(flat, ConfigValue{
+			Key:   key,
+			Value: v,
+			File:  sources[key],
+		})
+	}
+
+	return flat
+}
+
+// DumpConfiguration returns the values of the configuration file
+// configPath, or of the one found in the default locations when empty,
+// merged with its drop-in files, sorted by key.
+func DumpConfiguration(configPath string) (ConfigDump, error) {
+	resolved, err := resolveConfigFile(configPath)
+	if err != nil {
+		return ConfigDump{}, err
+	}
+
+	dropIns, err := configDropIns(configPath)
+	if err != nil {
+		return ConfigDump{}, err
+	}
+
+	values, sources, err := mergeConfigFiles(append([]string{resolved}, dropIns...))
+	if err != nil {
+		return ConfigDump{}, err
+	}
+
+	dump := ConfigDump{
+		File:    resolved,
+		DropIns: dropIns,
+		Values:  flattenConfigValues(values, "", sources),
+	}
+
+	sort.Slice(dump.Values, func(i, j int) bool {
+		return dump.Values[i].Key < dump.Values[j].Key
+	})
+
+	return dump, nil
+}
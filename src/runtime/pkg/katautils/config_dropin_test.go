@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katautils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBaseConfig = `
+[hypervisor.qemu]
+path = "/usr/bin/qemu"
+kernel_params = "quiet"
+default_vcpus = 1
+
+[runtime]
+enable_debug = false
+`
+
+func createTestDropIns(t *testing.T, dir string, dropIns map[string]string) string {
+	assert := assert.New(t)
+
+	configPath := filepath.Join(dir, "configuration.toml")
+	assert.NoError(createConfig(configPath, testBaseConfig))
+
+	dropInDir := configPath + configDropInDirSuffix
+	assert.NoError(os.MkdirAll(dropInDir, testDirMode))
+
+	for name, data := range dropIns {
+		assert.NoError(createConfig(filepath.Join(dropInDir, name), data))
+	}
+
+	return configPath
+}
+
+func TestConfigDropIns(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "config-dropin-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath := createTestDropIns(t, dir, map[string]string{
+		"20-debug.toml": "[runtime]\nenable_debug = true\n",
+		"10-vcpus.toml": "[hypervisor.qemu]\ndefault_vcpus = 2\n",
+		"30-vcpus.toml": "[hypervisor.qemu]\ndefault_vcpus = 4\n",
+		"ignored.conf":  "[runtime]\nenable_debug = false\n",
+	})
+	assert.NoError(os.MkdirAll(filepath.Join(configPath+configDropInDirSuffix, "dir.toml"), testDirMode))
+
+	dropIns, err := configDropIns(configPath)
+	assert.NoError(err)
+
+	dropInDir := configPath + configDropInDirSuffix
+	assert.Equal([]string{
+		filepath.Join(dropInDir, "10-vcpus.toml"),
+		filepath.Join(dropInDir, "20-debug.toml"),
+		filepath.Join(dropInDir, "30-vcpus.toml"),
+	}, dropIns)
+
+	// Drop-in files are applied in lexical order
+	tomlConf, resolved, err := decodeConfig(configPath)
+	assert.NoError(err)
+	assert.Equal(configPath, resolved)
+	assert.True(tomlConf.Runtime.Debug)
+	assert.Equal(int32(4), tomlConf.Hypervisor["qemu"].NumVCPUs)
+	assert.Equal("/usr/bin/qemu", tomlConf.Hypervisor["qemu"].Path)
+	assert.Equal("quiet", tomlConf.Hypervisor["qemu"].KernelParams)
+}
+
+func TestConfigDropInsDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "config-dropin-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedConf := defaultRuntimeConfiguration
+	savedSysConf := defaultSysConfRuntimeConfiguration
+	defer func() {
+		defaultRuntimeConfiguration = savedConf
+		defaultSysConfRuntimeConfiguration = savedSysConf
+	}()
+
+	for _, d := range []string{"usr", "etc"} {
+		assert.NoError(os.MkdirAll(filepath.Join(dir, d), testDirMode))
+	}
+
+	// The configuration file of the package, and drop-in files for it in
+	// both its directory and the one of /etc
+	defaultRuntimeConfiguration = createTestDropIns(t, filepath.Join(dir, "usr"), map[string]string{
+		"20-debug.toml": "[runtime]\nenable_debug = true\n",
+	})
+	defaultSysConfRuntimeConfiguration = filepath.Join(dir, "etc", "configuration.toml")
+	sysConfDropInDir := defaultSysConfRuntimeConfiguration + configDropInDirSuffix
+	assert.NoError(os.MkdirAll(sysConfDropInDir, testDirMode))
+	assert.NoError(createConfig(filepath.Join(sysConfDropInDir, "10-vcpus.toml"), "[hypervisor.qemu]\ndefault_vcpus = 2\n"))
+
+	dropIns, err := configDropIns("")
+	assert.NoError(err)
+	assert.Equal([]string{
+		filepath.Join(defaultRuntimeConfiguration+configDropInDirSuffix, "20-debug.toml"),
+		filepath.Join(sysConfDropInDir, "10-vcpus.toml"),
+	}, dropIns)
+
+	// The drop-in files of /etc are the only ones of its configuration
+	assert.NoError(createConfig(defaultSysConfRuntimeConfiguration, testBaseConfig))
+
+	dropIns, err = configDropIns("")
+	assert.NoError(err)
+	assert.Equal([]string{filepath.Join(sysConfDropInDir, "10-vcpus.toml")}, dropIns)
+}
+
+func TestConfigDropInsInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "config-dropin-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath := createTestDropIns(t, dir, map[string]string{
+		"10-invalid.toml": "[runtime\n",
+	})
+
+	_, _, err = decodeConfig(configPath)
+	assert.Error(err)
+	assert.Contains(err.Error(), "10-invalid.toml")
+}
+
+func TestMergeConfigValues(t *testing.T) {
+	assert := assert.New(t)
+
+	values := map[string]interface{}{}
+	sources := map[string]string{}
+
+	mergeConfigValues(values, map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": int64(1),
+			"c": map[string]interface{}{"d": "x"},
+		},
+		"e": []interface{}{"y"},
+	}, "", "first", sources)
+
+	mergeConfigValues(values, map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": int64(2),
+			"c": "replaced",
+		},
+		"e": []interface{}{"z"},
+	}, "", "second", sources)
+
+	assert.Equal(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": int64(2),
+			"c": "replaced",
+		},
+		"e": []interface{}{"z"},
+	}, values)
+
+	assert.Equal(map[string]string{
+		"a":   "first",
+		"a.b": "second",
+		"a.c": "second",
+		"e":   "second",
+	}, sources)
+}
+
+func TestDumpConfiguration(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "config-dropin-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	configPath := createTestDropIns(t, dir, map[string]string{
+		"10-debug.toml": "[runtime]\nenable_debug = true\n[factory]\n",
+	})
+	dropIn := filepath.Join(configPath+configDropInDirSuffix, "10-debug.toml")
+
+	dump, err := DumpConfiguration(configPath)
+	assert.NoError(err)
+
+	assert.Equal(configPath, dump.File)
+	assert.Equal([]string{dropIn}, dump.DropIns)
+	assert.Equal([]ConfigValue{
+		{Key: "factory", Value: map[string]interface{}{}, File: dropIn},
+		{Key: "hypervisor.qemu.default_vcpus", Value: int64(1), File: configPath},
+		{Key: "hypervisor.qemu.kernel_params", Value: "quiet", File: configPath},
+		{Key: "hypervisor.qemu.path", Value: "/usr/bin/qemu", File: configPath},
+		{Key: "runtime.enable_debug", Value: true, File: dropIn},
+	}, dump.Values)
+
+	_, err = DumpConfiguration(filepath.Join(dir, "missing.toml"))
+	assert.Error(err)
+}
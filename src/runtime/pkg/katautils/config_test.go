@@ -18,6 +18,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
@@ -1071,6 +1072,10 @@ func TestNewClhHypervisorConfig(t *testing.T) {
 		t.Errorf("Expected VirtioFSCache %v, got %v", true, config.VirtioFSCache)
 	}
 
+	// cloud-hypervisor cannot rate limit the entropy source
+	hypervisor.EntropyMaxBytes = 1024
+	_, err = newClhHypervisorConfig(hypervisor)
+	assert.Error(err)
 }
 
 func TestNewShimConfig(t *testing.T) {
@@ -1328,6 +1333,30 @@ func TestHypervisorDefaultsVhostUserStorePath(t *testing.T) {
 	assert.Equal(vhostUserStorePath, testVhostUserStorePath, "custom vhost-user store path wrong")
 }
 
+func TestHypervisorPassthroughCharDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	h := hypervisor{}
+	charDevices, err := h.passthroughCharDevices()
+	assert.NoError(err)
+	assert.Empty(charDevices)
+
+	h = hypervisor{
+		PassthroughCharDevices: []string{"/dev/ttyUSB*", "/dev/ttyS0"},
+	}
+	charDevices, err = h.passthroughCharDevices()
+	assert.NoError(err)
+	assert.Equal([]string{"/dev/ttyUSB*", "/dev/ttyS0"}, charDevices)
+
+	for _, pattern := range []string{"ttyUSB*", "/dev/tty[", ""} {
+		h = hypervisor{
+			PassthroughCharDevices: []string{pattern},
+		}
+		_, err = h.passthroughCharDevices()
+		assert.Error(err, pattern)
+	}
+}
+
 func TestProxyDefaults(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1431,6 +1460,32 @@ func TestAgentDefaults(t *testing.T) {
 	assert.Equal(a.traceType(), a.TraceType)
 }
 
+func TestAgentHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	a := agent{}
+	healthCheck, err := a.healthCheck()
+	assert.NoError(err)
+	assert.Equal(vc.AgentHealthCheckConfig{}, healthCheck)
+
+	a.HealthCheckInterval = 2
+	a.HealthCheckTimeout = 10
+	a.HealthCheckFailureThreshold = 3
+	a.HealthCheckRecovery = "reconnect"
+	healthCheck, err = a.healthCheck()
+	assert.NoError(err)
+	assert.Equal(vc.AgentHealthCheckConfig{
+		Interval:         2 * time.Second,
+		Timeout:          10 * time.Second,
+		FailureThreshold: 3,
+		Recovery:         vc.AgentRecoveryReconnect,
+	}, healthCheck)
+
+	a.HealthCheckRecovery = "restart"
+	_, err = a.healthCheck()
+	assert.Error(err)
+}
+
 func TestGetDefaultConfigFilePaths(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1950,3 +2005,50 @@ func TestCheckNetNsConfigShimTrace(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadHypervisorConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "hypervisor-configs-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	hypervisorPath := path.Join(dir, "hypervisor")
+	kernelPath := path.Join(dir, "kernel")
+	imagePath := path.Join(dir, "image")
+
+	for _, file := range []string{hypervisorPath, kernelPath, imagePath} {
+		assert.NoError(createEmptyFile(file))
+	}
+
+	configPath := path.Join(dir, "runtime.toml")
+	err = createConfig(configPath, `
+	[hypervisor.qemu]
+	path = "`+hypervisorPath+`"
+	kernel = "`+kernelPath+`"
+	image = "`+imagePath+`"
+
+	[hypervisor.firecracker]
+	path = "`+hypervisorPath+`"
+	kernel = "`+path.Join(dir, "missing")+`"
+	image = "`+imagePath+`"
+
+	[hypervisor.unknown]
+`)
+	assert.NoError(err)
+
+	hypervisors, err := LoadHypervisorConfigs(configPath)
+	assert.NoError(err)
+	assert.Len(hypervisors, 2)
+
+	assert.Equal(vc.FirecrackerHypervisor, hypervisors[0].Type)
+	assert.Error(hypervisors[0].Err)
+
+	assert.Equal(vc.QemuHypervisor, hypervisors[1].Type)
+	assert.NoError(hypervisors[1].Err)
+	assert.Equal(kernelPath, hypervisors[1].Config.KernelPath)
+	assert.Equal(imagePath, hypervisors[1].Config.ImagePath)
+
+	_, err = LoadHypervisorConfigs(path.Join(dir, "missing.toml"))
+	assert.Error(err)
+}
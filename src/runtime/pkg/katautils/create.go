@@ -150,9 +150,9 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 		}
 	}()
 
-	// Run pre-start OCI hooks.
+	// Run pre-start OCI hooks, before the sandbox VM exists.
 	err = EnterNetNS(sandboxConfig.NetworkConfig.NetNSPath, func() error {
-		return PreStartHooks(ctx, ociSpec, containerID, bundlePath)
+		return PreStartHooks(ctx, ociSpec, nil, containerID, bundlePath)
 	})
 	if err != nil {
 		return nil, vc.Process{}, err
@@ -167,6 +167,21 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 	kataUtilsLogger = kataUtilsLogger.WithField("sandbox", sid)
 	span.SetTag("sandbox", sid)
 
+	// Run createRuntime OCI hooks, now that the VM is running.
+	err = EnterNetNS(sandbox.GetNetNs(), func() error {
+		return CreateRuntimeHooks(ctx, ociSpec, sandbox, containerID, bundlePath)
+	})
+	if err != nil {
+		// The VM is running, do not leak it.
+		if stopErr := sandbox.Stop(true); stopErr != nil {
+			kataUtilsLogger.WithError(stopErr).Warn("failed to stop sandbox after createRuntime hook failure")
+		}
+		if delErr := sandbox.Delete(); delErr != nil {
+			kataUtilsLogger.WithError(delErr).Warn("failed to delete sandbox after createRuntime hook failure")
+		}
+		return nil, vc.Process{}, err
+	}
+
 	containers := sandbox.GetAllContainers()
 	if len(containers) != 1 {
 		return nil, vc.Process{}, fmt.Errorf("BUG: Container list from sandbox is wrong, expecting only one container, found %d containers", len(containers))
@@ -261,13 +276,38 @@ func CreateContainer(ctx context.Context, vci vc.VC, sandbox vc.VCSandbox, ociSp
 		}
 	}
 
-	// Run pre-start OCI hooks.
+	// Run pre-start and createRuntime OCI hooks.
 	err = EnterNetNS(sandbox.GetNetNs(), func() error {
-		return PreStartHooks(ctx, ociSpec, containerID, bundlePath)
+		if err := PreStartHooks(ctx, ociSpec, sandbox, containerID, bundlePath); err != nil {
+			return err
+		}
+
+		return CreateRuntimeHooks(ctx, ociSpec, sandbox, containerID, bundlePath)
 	})
 	if err != nil {
+		removeContainer(ctx, sandbox, containerID, builtIn)
 		return vc.Process{}, err
 	}
 
 	return c.Process(), nil
 }
+
+// removeContainer stops and deletes a container whose creation failed
+// after it was created in the sandbox VM.
+func removeContainer(ctx context.Context, sandbox vc.VCSandbox, containerID string, builtIn bool) {
+	logger := kataUtilsLogger.WithField("container", containerID)
+
+	if _, err := sandbox.StopContainer(containerID, true); err != nil {
+		logger.WithError(err).Warn("failed to stop container")
+	}
+
+	if _, err := sandbox.DeleteContainer(containerID); err != nil {
+		logger.WithError(err).Warn("failed to delete container")
+	}
+
+	if !builtIn {
+		if err := DelContainerIDMapping(ctx, containerID); err != nil {
+			logger.WithError(err).Warn("failed to delete container ID mapping")
+		}
+	}
+}
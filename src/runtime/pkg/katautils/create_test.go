@@ -513,3 +513,54 @@ func TestCreateContainer(t *testing.T) {
 		os.RemoveAll(path)
 	}
 }
+
+type removedSandbox struct {
+	*vcmock.Sandbox
+	stopped []string
+	deleted []string
+}
+
+func (s *removedSandbox) StopContainer(contID string, force bool) (vc.VCContainer, error) {
+	s.stopped = append(s.stopped, contID)
+	return &vcmock.Container{}, nil
+}
+
+func (s *removedSandbox) DeleteContainer(contID string) (vc.VCContainer, error) {
+	s.deleted = append(s.deleted, contID)
+	return &vcmock.Container{}, nil
+}
+
+func TestCreateContainerCreateRuntimeHookFail(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	bundlePath := filepath.Join(tmpdir, "bundle")
+
+	err = makeOCIBundle(bundlePath)
+	assert.NoError(err)
+
+	spec, err := compatoci.ParseConfigJSON(bundlePath)
+	assert.NoError(err)
+
+	spec.Annotations = make(map[string]string)
+	spec.Annotations[testContainerTypeAnnotation] = testContainerTypeContainer
+	spec.Annotations[testSandboxIDAnnotation] = testSandboxID
+
+	// The hook binary does not exist
+	spec.Hooks = &specs.Hooks{
+		CreateRuntime: []specs.Hook{{Path: filepath.Join(tmpdir, "missing-hook")}},
+	}
+
+	sandbox := &removedSandbox{Sandbox: &vcmock.Sandbox{}}
+	rootFs := vc.RootFs{Mounted: true}
+
+	_, err = CreateContainer(context.Background(), testingImpl, sandbox, spec, rootFs, testContainerID, bundlePath, testConsole, true, true)
+	assert.Error(err)
+
+	// The container created in the VM is removed
+	assert.Equal([]string{testContainerID}, sandbox.stopped)
+	assert.Equal([]string{testContainerID}, sandbox.deleted)
+}
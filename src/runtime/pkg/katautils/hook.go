@@ -16,6 +16,7 @@ import (
 	"syscall"
 	"time"
 
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/sirupsen/logrus"
@@ -26,7 +27,32 @@ func hookLogger() *logrus.Entry {
 	return kataUtilsLogger.WithField("subsystem", "hook")
 }
 
-func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error {
+// HookState returns the state passed to the hooks. When the sandbox VM is
+// known, the state carries the hypervisor PID instead of the runtime one,
+// together with kata specific annotations describing the VM.
+func HookState(sandbox vc.VCSandbox, cid, bundlePath string) specs.State {
+	state := specs.State{
+		Pid:    syscall.Gettid(),
+		Bundle: bundlePath,
+		ID:     cid,
+	}
+
+	if sandbox == nil {
+		return state
+	}
+
+	if pid, err := sandbox.GetHypervisorPid(); err == nil {
+		state.Pid = pid
+	} else {
+		hookLogger().WithError(err).Warn("could not get hypervisor PID")
+	}
+
+	state.Annotations = sandbox.GetHookAnnotations()
+
+	return state
+}
+
+func runHook(ctx context.Context, hook specs.Hook, state specs.State) error {
 	span, _ := Trace(ctx, "hook")
 	defer span.Finish()
 
@@ -36,12 +62,6 @@ func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error
 		log.String("hook-name", hook.Path),
 		log.String("hook-args", strings.Join(hook.Args, " ")))
 
-	state := specs.State{
-		Pid:    syscall.Gettid(),
-		Bundle: bundlePath,
-		ID:     cid,
-	}
-
 	stateJSON, err := json.Marshal(state)
 	if err != nil {
 		return err
@@ -89,14 +109,14 @@ func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error
 	return nil
 }
 
-func runHooks(ctx context.Context, hooks []specs.Hook, cid, bundlePath, hookType string) error {
+func runHooks(ctx context.Context, hooks []specs.Hook, state specs.State, hookType string) error {
 	span, _ := Trace(ctx, "hooks")
 	defer span.Finish()
 
 	span.SetTag("subsystem", hookType)
 
 	for _, hook := range hooks {
-		if err := runHook(ctx, hook, cid, bundlePath); err != nil {
+		if err := runHook(ctx, hook, state); err != nil {
 			hookLogger().WithFields(logrus.Fields{
 				"hook-type": hookType,
 				"error":     err,
@@ -109,32 +129,45 @@ func runHooks(ctx context.Context, hooks []specs.Hook, cid, bundlePath, hookType
 	return nil
 }
 
-// PreStartHooks run the hooks before start container
-func PreStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+// PreStartHooks run the hooks before start container. The sandbox is nil
+// when they run before the sandbox VM is created.
+func PreStartHooks(ctx context.Context, spec specs.Spec, sandbox vc.VCSandbox, cid, bundlePath string) error {
+	// If no hook available, nothing needs to be done.
+	if spec.Hooks == nil {
+		return nil
+	}
+
+	return runHooks(ctx, spec.Hooks.Prestart, HookState(sandbox, cid, bundlePath), "pre-start")
+}
+
+// CreateRuntimeHooks run the hooks once the container has been created in the sandbox VM
+func CreateRuntimeHooks(ctx context.Context, spec specs.Spec, sandbox vc.VCSandbox, cid, bundlePath string) error {
 	// If no hook available, nothing needs to be done.
 	if spec.Hooks == nil {
 		return nil
 	}
 
-	return runHooks(ctx, spec.Hooks.Prestart, cid, bundlePath, "pre-start")
+	return runHooks(ctx, spec.Hooks.CreateRuntime, HookState(sandbox, cid, bundlePath), "create-runtime")
 }
 
 // PostStartHooks run the hooks just after start container
-func PostStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+func PostStartHooks(ctx context.Context, spec specs.Spec, sandbox vc.VCSandbox, cid, bundlePath string) error {
 	// If no hook available, nothing needs to be done.
 	if spec.Hooks == nil {
 		return nil
 	}
 
-	return runHooks(ctx, spec.Hooks.Poststart, cid, bundlePath, "post-start")
+	return runHooks(ctx, spec.Hooks.Poststart, HookState(sandbox, cid, bundlePath), "post-start")
 }
 
-// PostStopHooks run the hooks after stop container
-func PostStopHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+// PostStopHooks run the hooks after stop container. The sandbox VM may be
+// gone at that point, the state has to be built by HookState() before it is
+// stopped.
+func PostStopHooks(ctx context.Context, spec specs.Spec, state specs.State) error {
 	// If no hook available, nothing needs to be done.
 	if spec.Hooks == nil {
 		return nil
 	}
 
-	return runHooks(ctx, spec.Hooks.Poststop, cid, bundlePath, "post-stop")
+	return runHooks(ctx, spec.Hooks.Poststop, state, "post-stop")
 }
@@ -9,10 +9,13 @@ package katautils
 import (
 	"context"
 	"os"
+	"syscall"
 	"testing"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	. "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/mock"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 )
@@ -62,26 +65,27 @@ func TestRunHook(t *testing.T) {
 	assert := assert.New(t)
 
 	ctx := context.Background()
+	state := HookState(nil, testSandboxID, testBundlePath)
 
 	// Run with timeout 0
 	hook := createHook(0)
-	err := runHook(ctx, hook, testSandboxID, testBundlePath)
+	err := runHook(ctx, hook, state)
 	assert.NoError(err)
 
 	// Run with timeout 1
 	hook = createHook(1)
-	err = runHook(ctx, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, hook, state)
 	assert.NoError(err)
 
 	// Run timeout failure
 	hook = createHook(1)
 	hook.Args = append(hook.Args, "2")
-	err = runHook(ctx, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, hook, state)
 	assert.Error(err)
 
 	// Failure due to wrong hook
 	hook = createWrongHook()
-	err = runHook(ctx, hook, testSandboxID, testBundlePath)
+	err = runHook(ctx, hook, state)
 	assert.Error(err)
 }
 
@@ -93,17 +97,21 @@ func TestPreStartHooks(t *testing.T) {
 	assert := assert.New(t)
 
 	ctx := context.Background()
+	sandbox := &vcmock.Sandbox{
+		MockID:            testSandboxID,
+		MockHypervisorPid: os.Getpid(),
+	}
 
 	// Hooks field is nil
 	spec := specs.Spec{}
-	err := PreStartHooks(ctx, spec, "", "")
+	err := PreStartHooks(ctx, spec, nil, "", "")
 	assert.NoError(err)
 
 	// Hooks list is empty
 	spec = specs.Spec{
 		Hooks: &specs.Hooks{},
 	}
-	err = PreStartHooks(ctx, spec, "", "")
+	err = PreStartHooks(ctx, spec, nil, "", "")
 	assert.NoError(err)
 
 	// Run with timeout 0
@@ -113,7 +121,11 @@ func TestPreStartHooks(t *testing.T) {
 			Prestart: []specs.Hook{hook},
 		},
 	}
-	err = PreStartHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PreStartHooks(ctx, spec, nil, testSandboxID, testBundlePath)
+	assert.NoError(err)
+
+	// Run once the sandbox VM exists
+	err = PreStartHooks(ctx, spec, sandbox, testSandboxID, testBundlePath)
 	assert.NoError(err)
 
 	// Failure due to wrong hook
@@ -123,7 +135,7 @@ func TestPreStartHooks(t *testing.T) {
 			Prestart: []specs.Hook{hook},
 		},
 	}
-	err = PreStartHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PreStartHooks(ctx, spec, sandbox, testSandboxID, testBundlePath)
 	assert.Error(err)
 }
 
@@ -138,14 +150,14 @@ func TestPostStartHooks(t *testing.T) {
 
 	// Hooks field is nil
 	spec := specs.Spec{}
-	err := PostStartHooks(ctx, spec, "", "")
+	err := PostStartHooks(ctx, spec, nil, "", "")
 	assert.NoError(err)
 
 	// Hooks list is empty
 	spec = specs.Spec{
 		Hooks: &specs.Hooks{},
 	}
-	err = PostStartHooks(ctx, spec, "", "")
+	err = PostStartHooks(ctx, spec, nil, "", "")
 	assert.NoError(err)
 
 	// Run with timeout 0
@@ -155,7 +167,7 @@ func TestPostStartHooks(t *testing.T) {
 			Poststart: []specs.Hook{hook},
 		},
 	}
-	err = PostStartHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PostStartHooks(ctx, spec, nil, testSandboxID, testBundlePath)
 	assert.NoError(err)
 
 	// Failure due to wrong hook
@@ -165,7 +177,7 @@ func TestPostStartHooks(t *testing.T) {
 			Poststart: []specs.Hook{hook},
 		},
 	}
-	err = PostStartHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PostStartHooks(ctx, spec, nil, testSandboxID, testBundlePath)
 	assert.Error(err)
 }
 
@@ -180,14 +192,14 @@ func TestPostStopHooks(t *testing.T) {
 
 	// Hooks field is nil
 	spec := specs.Spec{}
-	err := PostStopHooks(ctx, spec, "", "")
+	err := PostStopHooks(ctx, spec, HookState(nil, "", ""))
 	assert.NoError(err)
 
 	// Hooks list is empty
 	spec = specs.Spec{
 		Hooks: &specs.Hooks{},
 	}
-	err = PostStopHooks(ctx, spec, "", "")
+	err = PostStopHooks(ctx, spec, HookState(nil, "", ""))
 	assert.NoError(err)
 
 	// Run with timeout 0
@@ -197,7 +209,7 @@ func TestPostStopHooks(t *testing.T) {
 			Poststop: []specs.Hook{hook},
 		},
 	}
-	err = PostStopHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PostStopHooks(ctx, spec, HookState(nil, testSandboxID, testBundlePath))
 	assert.NoError(err)
 
 	// Failure due to wrong hook
@@ -207,6 +219,79 @@ func TestPostStopHooks(t *testing.T) {
 			Poststop: []specs.Hook{hook},
 		},
 	}
-	err = PostStopHooks(ctx, spec, testSandboxID, testBundlePath)
+	err = PostStopHooks(ctx, spec, HookState(nil, testSandboxID, testBundlePath))
 	assert.Error(err)
 }
+
+func TestCreateRuntimeHooks(t *testing.T) {
+	if tc.NotValid(ktu.NeedRoot()) {
+		t.Skip(ktu.TestDisabledNeedRoot)
+	}
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	sandbox := &vcmock.Sandbox{
+		MockID:            testSandboxID,
+		MockHypervisorPid: os.Getpid(),
+	}
+
+	// Hooks field is nil
+	spec := specs.Spec{}
+	err := CreateRuntimeHooks(ctx, spec, sandbox, "", "")
+	assert.NoError(err)
+
+	// Hooks list is empty
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{},
+	}
+	err = CreateRuntimeHooks(ctx, spec, sandbox, "", "")
+	assert.NoError(err)
+
+	// Run with timeout 0
+	hook := createHook(0)
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{
+			CreateRuntime: []specs.Hook{hook},
+		},
+	}
+	err = CreateRuntimeHooks(ctx, spec, sandbox, testSandboxID, testBundlePath)
+	assert.NoError(err)
+
+	// Failure due to wrong hook
+	hook = createWrongHook()
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{
+			CreateRuntime: []specs.Hook{hook},
+		},
+	}
+	err = CreateRuntimeHooks(ctx, spec, sandbox, testSandboxID, testBundlePath)
+	assert.Error(err)
+}
+
+func TestHookState(t *testing.T) {
+	assert := assert.New(t)
+
+	state := HookState(nil, testContainerID, testBundlePath)
+	assert.Equal(syscall.Gettid(), state.Pid)
+	assert.Equal(testContainerID, state.ID)
+	assert.Equal(testBundlePath, state.Bundle)
+	assert.Empty(state.Annotations)
+
+	hookAnnotations := map[string]string{
+		vcAnnotations.HookNetNSPathKey: "/var/run/netns/test",
+		vcAnnotations.HookGuestCIDKey:  "3",
+		vcAnnotations.HookSharedDirKey: "/run/kata-containers/shared/sandboxes/test/shared",
+	}
+	sandbox := &vcmock.Sandbox{
+		MockID:              testSandboxID,
+		MockHypervisorPid:   1234,
+		MockHookAnnotations: hookAnnotations,
+	}
+
+	state = HookState(sandbox, testContainerID, testBundlePath)
+	assert.Equal(1234, state.Pid)
+	assert.Equal(testContainerID, state.ID)
+	assert.Equal(testBundlePath, state.Bundle)
+	assert.Equal(hookAnnotations, state.Annotations)
+}
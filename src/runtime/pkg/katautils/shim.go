@@ -0,0 +1,21 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katautils
+
+import (
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/store"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+)
+
+// shimManagementSocket is the socket, in the VM directory of a sandbox, the
+// shim owning the sandbox serves its task API on for the runtime commands.
+const shimManagementSocket = "shim-management.sock"
+
+// ShimManagementSocketPath returns the path of the management socket of the
+// shim owning the sandbox sandboxID.
+func ShimManagementSocketPath(sandboxID string) (string, error) {
+	return utils.BuildSocketPath(store.RunVMStoragePath(), sandboxID, shimManagementSocket)
+}
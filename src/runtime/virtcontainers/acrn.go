@@ -550,8 +550,8 @@ func (a *Acrn) updateBlockDevice(drive *config.BlockDrive) error {
 
 	slot := AcrnBlkdDevSlot[drive.Index]
 
-	//Explicitly set PCIAddr to NULL, so that VirtPath can be used
-	drive.PCIAddr = ""
+	//Explicitly set PCIPath to NULL, so that VirtPath can be used
+	drive.PCIPath = types.PciPath{}
 
 	args := []string{"blkrescan", a.acrnConfig.Name, fmt.Sprintf("%d,%s", slot, drive.File)}
 
@@ -817,3 +817,13 @@ func (a *Acrn) loadInfo() error {
 func (a *Acrn) isRateLimiterBuiltin() bool {
 	return false
 }
+
+func (a *Acrn) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	return fmt.Errorf("acrn does not support block drive I/O throttling")
+}
+
+// setMemoryHostNodes does nothing, acrn does not bind the VM memory to host
+// nodes.
+func (a *Acrn) setMemoryHostNodes(nodes []int) error {
+	return nil
+}
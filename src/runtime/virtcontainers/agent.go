@@ -132,6 +132,10 @@ type agent interface {
 	// disconnect will disconnect the connection to the agent
 	disconnect() error
 
+	// resetCheck drops the connection the agent liveness is checked on,
+	// leaving the one of the other requests alone
+	resetCheck() error
+
 	// start the proxy
 	startProxy(sandbox *Sandbox) error
 
@@ -536,7 +536,7 @@ func TestStatusSandboxSuccessfulStateReady(t *testing.T) {
 		State: types.SandboxState{
 			State:          types.StateReady,
 			BlockIndexMap:  make(map[int]struct{}),
-			PersistVersion: 2,
+			PersistVersion: 3,
 		},
 		Hypervisor:       MockHypervisor,
 		HypervisorConfig: hypervisorConfig,
@@ -596,7 +596,7 @@ func TestStatusSandboxSuccessfulStateRunning(t *testing.T) {
 		State: types.SandboxState{
 			State:          types.StateRunning,
 			BlockIndexMap:  make(map[int]struct{}),
-			PersistVersion: 2,
+			PersistVersion: 3,
 		},
 		Hypervisor:       MockHypervisor,
 		HypervisorConfig: hypervisorConfig,
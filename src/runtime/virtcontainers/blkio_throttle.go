@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// blockIODevice identifies a host block device by its major and minor
+// numbers.
+type blockIODevice struct {
+	major int64
+	minor int64
+}
+
+// blockIOThrottles returns the I/O limits blkio sets on each host block
+// device.
+func blockIOThrottles(blkio *specs.LinuxBlockIO) map[blockIODevice]config.BlockIOThrottle {
+	throttles := make(map[blockIODevice]config.BlockIOThrottle)
+	if blkio == nil {
+		return throttles
+	}
+
+	set := func(devices []specs.LinuxThrottleDevice, rate func(*config.BlockIOThrottle) *uint64) {
+		for _, d := range devices {
+			dev := blockIODevice{d.Major, d.Minor}
+			t := throttles[dev]
+			*rate(&t) = d.Rate
+			throttles[dev] = t
+		}
+	}
+
+	set(blkio.ThrottleReadBpsDevice, func(t *config.BlockIOThrottle) *uint64 { return &t.ReadBps })
+	set(blkio.ThrottleWriteBpsDevice, func(t *config.BlockIOThrottle) *uint64 { return &t.WriteBps })
+	set(blkio.ThrottleReadIOPSDevice, func(t *config.BlockIOThrottle) *uint64 { return &t.ReadIOPS })
+	set(blkio.ThrottleWriteIOPSDevice, func(t *config.BlockIOThrottle) *uint64 { return &t.WriteIOPS })
+
+	return throttles
+}
+
+// throttleBlockDevices applies the blkio limits to the block devices
+// hotplugged for the container. The device numbers the limits refer to
+// do not exist in the guest, the limits are set on the drives instead. The
+// limits set by previous on devices blkio does not mention anymore are
+// removed.
+func (c *Container) throttleBlockDevices(blkio, previous *specs.LinuxBlockIO) error {
+	throttles := blockIOThrottles(blkio)
+	for dev := range blockIOThrottles(previous) {
+		if _, ok := throttles[dev]; !ok {
+			throttles[dev] = config.BlockIOThrottle{}
+		}
+	}
+
+	if len(throttles) == 0 {
+		return nil
+	}
+
+	ids := []string{c.state.BlockDeviceID}
+	for _, dev := range c.devices {
+		ids = append(ids, dev.ID)
+	}
+
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		device := c.sandbox.devManager.GetDeviceByID(id)
+		if device == nil || device.DeviceType() != config.DeviceBlock {
+			continue
+		}
+
+		major, minor := device.GetMajorMinor()
+		throttle, ok := throttles[blockIODevice{major, minor}]
+		if !ok {
+			continue
+		}
+
+		if err := c.sandbox.throttleBlockDevice(device, throttle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// throttleBlockDevice limits the I/O rate of a hotplugged block device, on
+// its drive when the hypervisor can throttle the drives, or on its host
+// backing device through the sandbox cgroup otherwise.
+func (s *Sandbox) throttleBlockDevice(device api.Device, throttle config.BlockIOThrottle) error {
+	drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+	if !ok || drive == nil {
+		return fmt.Errorf("malformed block drive of device %s", device.DeviceID())
+	}
+
+	logger := s.Logger().WithField("device", device.GetHostPath()).WithField("throttle", throttle)
+
+	caps := s.hypervisor.capabilities()
+	if caps.IsBlockIOThrottleSupported() {
+		logger.Info("Throttling block drive")
+		return s.hypervisor.setBlockIOThrottle(drive, throttle)
+	}
+
+	// The hypervisor is only in a blkio cgroup of its own when the
+	// sandbox is constrained by the sandbox cgroup only.
+	if !s.config.SandboxCgroupOnly || s.cgroupMgr == nil {
+		logger.Warn("Block drive I/O throttling not supported")
+		return nil
+	}
+
+	logger.Info("Throttling block drive backing device")
+	if err := s.cgroupMgr.SetBlkioThrottle(device.GetHostPath(), throttle.ReadBps,
+		throttle.WriteBps, throttle.ReadIOPS, throttle.WriteIOPS); err != nil {
+		logger.WithError(err).Warn("Could not throttle block drive backing device")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// throttleHypervisor records the I/O limits set on the drives.
+type throttleHypervisor struct {
+	mockHypervisor
+	throttles map[string]config.BlockIOThrottle
+}
+
+func (h *throttleHypervisor) capabilities() types.Capabilities {
+	var caps types.Capabilities
+	caps.SetBlockIOThrottleSupport()
+	return caps
+}
+
+func (h *throttleHypervisor) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	h.throttles[drive.ID] = throttle
+	return nil
+}
+
+func throttleDevice(major, minor int64, rate uint64) specs.LinuxThrottleDevice {
+	d := specs.LinuxThrottleDevice{Rate: rate}
+	d.Major = major
+	d.Minor = minor
+	return d
+}
+
+func TestBlockIOThrottles(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(blockIOThrottles(nil))
+
+	throttles := blockIOThrottles(&specs.LinuxBlockIO{
+		ThrottleReadBpsDevice: []specs.LinuxThrottleDevice{
+			throttleDevice(8, 0, 1024),
+		},
+		ThrottleWriteIOPSDevice: []specs.LinuxThrottleDevice{
+			throttleDevice(8, 0, 100),
+			throttleDevice(8, 16, 200),
+		},
+	})
+
+	assert.Equal(map[blockIODevice]config.BlockIOThrottle{
+		{8, 0}:  {ReadBps: 1024, WriteIOPS: 100},
+		{8, 16}: {WriteIOPS: 200},
+	}, throttles)
+}
+
+func TestContainerThrottleBlockDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	newDevice := func(id string, major, minor int64) *drivers.BlockDevice {
+		d := drivers.NewBlockDevice(&config.DeviceInfo{ID: id, Major: major, Minor: minor})
+		d.BlockDrive = &config.BlockDrive{ID: "drive-" + id}
+		return d
+	}
+
+	devices := []api.Device{newDevice("sda", 8, 0), newDevice("sdb", 8, 16)}
+	h := &throttleHypervisor{throttles: make(map[string]config.BlockIOThrottle)}
+
+	c := &Container{
+		id: "100",
+		sandbox: &Sandbox{
+			ctx:        context.Background(),
+			config:     &SandboxConfig{},
+			hypervisor: h,
+			devManager: manager.NewDeviceManager(manager.VirtioBlock, false, "", nil, devices),
+		},
+		config:  &ContainerConfig{},
+		devices: []ContainerDevice{{ID: "sda"}, {ID: "sdb"}},
+	}
+
+	previous := &specs.LinuxBlockIO{
+		ThrottleWriteBpsDevice: []specs.LinuxThrottleDevice{
+			throttleDevice(8, 16, 4096),
+		},
+	}
+	blkio := &specs.LinuxBlockIO{
+		ThrottleReadBpsDevice: []specs.LinuxThrottleDevice{
+			throttleDevice(8, 0, 1024),
+		},
+	}
+
+	err := c.throttleBlockDevices(blkio, previous)
+	assert.NoError(err)
+	assert.Equal(map[string]config.BlockIOThrottle{
+		"drive-sda": {ReadBps: 1024},
+		"drive-sdb": {},
+	}, h.throttles)
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/client"
+	"github.com/mdlayher/vsock"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// charDevPortNamePrefix prefixes the names of the virtio-serial ports
+	// the host character devices are proxied to.
+	charDevPortNamePrefix = "kata.chardev."
+
+	// charDevVsockPortBase is the first guest vsock port used to proxy
+	// the host character devices.
+	charDevVsockPortBase = 2048
+
+	charDevDialTimeout     = 5 * time.Second
+	charDevMinRetryBackoff = 100 * time.Millisecond
+	charDevMaxRetryBackoff = 2 * time.Second
+)
+
+// charDeviceDialer connects to the guest end of a passed through
+// character device.
+type charDeviceDialer func() (net.Conn, error)
+
+// charDeviceProxy copies the data between a host character device and the
+// connection to its guest end. The guest end is dialed again whenever the
+// connection is closed, until the proxy itself is closed.
+type charDeviceProxy struct {
+	dev  *os.File
+	dial charDeviceDialer
+
+	// stopR is readable once the proxy is closed, and wakes up the read
+	// loop polling the host device.
+	stopR *os.File
+	stopW *os.File
+
+	sync.Mutex
+	conn net.Conn
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCharDeviceProxy(hostPath string, dial charDeviceDialer) (*charDeviceProxy, error) {
+	dev, err := os.OpenFile(hostPath, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stopR, stopW, err := os.Pipe()
+	if err != nil {
+		dev.Close()
+		return nil, err
+	}
+
+	p := &charDeviceProxy{
+		dev:   dev,
+		dial:  dial,
+		stopR: stopR,
+		stopW: stopW,
+		done:  make(chan struct{}),
+	}
+
+	p.wg.Add(2)
+	go p.hostToGuest()
+	go p.guestToHost()
+
+	return p, nil
+}
+
+func (p *charDeviceProxy) logger() *logrus.Entry {
+	return virtLog.WithFields(logrus.Fields{
+		"subsystem": "char-device-proxy",
+		"device":    p.dev.Name(),
+	})
+}
+
+func (p *charDeviceProxy) closed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitHostReadable blocks until the host device can be read, and returns
+// false when the proxy is closed first.
+func (p *charDeviceProxy) waitHostReadable() (bool, error) {
+	fds := []unix.PollFd{
+		{Fd: int32(p.dev.Fd()), Events: unix.POLLIN},
+		{Fd: int32(p.stopR.Fd()), Events: unix.POLLIN},
+	}
+
+	for {
+		if _, err := unix.Poll(fds, -1); err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return false, err
+		}
+
+		if fds[1].Revents != 0 {
+			return false, nil
+		}
+		if fds[0].Revents != 0 {
+			return true, nil
+		}
+	}
+}
+
+// hostToGuest forwards the data read from the host device to the current
+// connection. The data read while the guest end is not connected is lost.
+func (p *charDeviceProxy) hostToGuest() {
+	defer p.wg.Done()
+
+	buf := make([]byte, 4096)
+	for {
+		readable, err := p.waitHostReadable()
+		if err != nil {
+			p.logger().WithError(err).Warn("failed to poll host device")
+			return
+		}
+		if !readable {
+			return
+		}
+
+		n, err := p.dev.Read(buf)
+		if err != nil {
+			if !p.closed() {
+				p.logger().WithError(err).Warn("failed to read host device")
+			}
+			return
+		}
+
+		p.Lock()
+		conn := p.conn
+		p.Unlock()
+
+		if conn == nil {
+			continue
+		}
+
+		if _, err := conn.Write(buf[:n]); err != nil {
+			p.logger().WithError(err).Debug("failed to write to guest")
+		}
+	}
+}
+
+// guestToHost connects to the guest end and forwards the data read from
+// the connection to the host device.
+func (p *charDeviceProxy) guestToHost() {
+	defer p.wg.Done()
+
+	backoff := charDevMinRetryBackoff
+	for !p.closed() {
+		conn, err := p.dial()
+		if err != nil {
+			select {
+			case <-p.done:
+				return
+			case <-time.After(backoff):
+			}
+
+			if backoff *= 2; backoff > charDevMaxRetryBackoff {
+				backoff = charDevMaxRetryBackoff
+			}
+			continue
+		}
+		backoff = charDevMinRetryBackoff
+
+		p.Lock()
+		if p.closed() {
+			p.Unlock()
+			conn.Close()
+			return
+		}
+		p.conn = conn
+		p.Unlock()
+
+		if _, err := io.Copy(p.dev, conn); err != nil && !p.closed() {
+			p.logger().WithError(err).Debug("guest connection failed")
+		}
+
+		p.Lock()
+		p.conn = nil
+		p.Unlock()
+		conn.Close()
+	}
+}
+
+func (p *charDeviceProxy) close() {
+	p.Lock()
+	close(p.done)
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.Unlock()
+
+	// Closing the write end wakes up the read loop, the host device is
+	// only closed once nothing uses it anymore.
+	p.stopW.Close()
+	p.wg.Wait()
+
+	p.stopR.Close()
+	p.dev.Close()
+}
+
+// charDeviceVsockDialer returns a dialer connecting to port on the guest,
+// through the vsock device the agent is reached with.
+func charDeviceVsockDialer(agentURL string, port uint32) (charDeviceDialer, error) {
+	u, err := url.Parse(agentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case client.VSockSocketScheme:
+		cid, err := strconv.ParseUint(u.Hostname(), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vsock agent URL %s: %v", agentURL, err)
+		}
+
+		return func() (net.Conn, error) {
+			return vsock.Dial(uint32(cid), port)
+		}, nil
+	case client.HybridVSockScheme:
+		i := strings.LastIndex(u.Path, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid hybrid vsock agent URL %s", agentURL)
+		}
+		sock := fmt.Sprintf("%s:%s:%d", client.HybridVSockScheme, u.Path[:i], port)
+
+		return func() (net.Conn, error) {
+			return client.HybridVSockDialer(sock, charDevDialTimeout)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cannot proxy char devices over agent URL %s", agentURL)
+}
+
+// allocCharDeviceVsockPort returns the lowest guest vsock port not used by
+// another char device of the sandbox.
+func (s *Sandbox) allocCharDeviceVsockPort() uint32 {
+	used := make(map[uint32]bool)
+	for _, d := range s.devManager.GetAllDevices() {
+		if dev, ok := d.GetDeviceInfo().(*config.CharDev); ok && dev != nil &&
+			dev.Transport == config.CharDevVsock {
+			used[dev.VsockPort] = true
+		}
+	}
+
+	port := uint32(charDevVsockPortBase)
+	for used[port] {
+		port++
+	}
+
+	return port
+}
+
+// charDeviceDialer returns the dialer of the guest end the host character
+// device was plugged as.
+func (s *Sandbox) charDeviceDialer(dev *config.CharDev) (charDeviceDialer, error) {
+	switch dev.Transport {
+	case config.CharDevVirtioSerial:
+		socketPath := dev.SocketPath
+		return func() (net.Conn, error) {
+			return net.DialTimeout("unix", socketPath, charDevDialTimeout)
+		}, nil
+	case config.CharDevVsock:
+		agentURL, err := s.agent.getAgentURL()
+		if err != nil {
+			return nil, err
+		}
+		return charDeviceVsockDialer(agentURL, dev.VsockPort)
+	}
+
+	return nil, fmt.Errorf("unknown transport %q of char device %s", dev.Transport, dev.ID)
+}
+
+// startCharDeviceProxy starts proxying a host character device plugged to
+// the guest.
+func (s *Sandbox) startCharDeviceProxy(dev *config.CharDev) error {
+	dial, err := s.charDeviceDialer(dev)
+	if err != nil {
+		return err
+	}
+
+	proxy, err := newCharDeviceProxy(dev.HostPath, dial)
+	if err != nil {
+		return err
+	}
+
+	if s.charDevProxies == nil {
+		s.charDevProxies = make(map[string]*charDeviceProxy)
+	}
+	s.charDevProxies[dev.ID] = proxy
+
+	return nil
+}
+
+// startCharDeviceProxies starts proxying the attached host character
+// devices which are not proxied, like those of a sandbox fetched again by
+// a restarted shim.
+func (s *Sandbox) startCharDeviceProxies() {
+	if s.devManager == nil {
+		return
+	}
+
+	for _, d := range s.devManager.GetAllDevices() {
+		device, ok := d.(*drivers.CharDevice)
+		if !ok || device.CharDev == nil || !s.devManager.IsDeviceAttached(d.DeviceID()) {
+			continue
+		}
+
+		dev := device.CharDev
+		if _, ok := s.charDevProxies[dev.ID]; ok {
+			continue
+		}
+
+		if err := s.startCharDeviceProxy(dev); err != nil {
+			s.Logger().WithError(err).WithField("device", dev.HostPath).Warn("Could not proxy char device")
+		}
+	}
+}
+
+// attachCharDevice plugs the guest end of a host character device, as a
+// virtio-serial port when the hypervisor can hotplug them or as a vsock
+// port listened on by the agent otherwise, and starts proxying it.
+func (s *Sandbox) attachCharDevice(device *drivers.CharDevice) (err error) {
+	dev := device.CharDev
+
+	caps := s.hypervisor.capabilities()
+	if caps.IsCharDeviceHotplugSupported() {
+		if _, err = s.hypervisor.hotplugAddDevice(dev, charDev); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err != nil {
+				s.hypervisor.hotplugRemoveDevice(dev, charDev)
+			}
+		}()
+	} else {
+		dev.VsockPort = s.allocCharDeviceVsockPort()
+		dev.Transport = config.CharDevVsock
+	}
+
+	return s.startCharDeviceProxy(dev)
+}
+
+// detachCharDevice stops proxying a host character device and unplugs its
+// guest end.
+func (s *Sandbox) detachCharDevice(device *drivers.CharDevice) error {
+	dev := device.CharDev
+	if dev == nil {
+		return nil
+	}
+
+	if proxy, ok := s.charDevProxies[dev.ID]; ok {
+		proxy.close()
+		delete(s.charDevProxies, dev.ID)
+	}
+
+	if dev.Transport != config.CharDevVirtioSerial {
+		return nil
+	}
+
+	_, err := s.hypervisor.hotplugRemoveDevice(dev, charDev)
+	return err
+}
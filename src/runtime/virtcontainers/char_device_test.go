@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestCharDeviceVsockDialer(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := charDeviceVsockDialer("vsock://3:1024", 2048)
+	assert.NoError(err)
+
+	_, err = charDeviceVsockDialer("hvsock:///run/vc/firecracker/kata.hvsock:1024", 2048)
+	assert.NoError(err)
+
+	for _, u := range []string{
+		"unix:///run/vc/sbs/kata.sock",
+		"vsock://foo:1024",
+		"hvsock:///run/vc/firecracker/kata.hvsock",
+	} {
+		_, err = charDeviceVsockDialer(u, 2048)
+		assert.Error(err, u)
+	}
+}
+
+func TestCharDeviceProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "chardev")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A FIFO opened read-write stands for the host device: whatever the
+	// guest writes to it is read back and sent to the guest.
+	fifo := filepath.Join(dir, "fifo")
+	assert.NoError(unix.Mkfifo(fifo, 0600))
+
+	guest, host := net.Pipe()
+	dialed := make(chan struct{})
+	dial := func() (net.Conn, error) {
+		select {
+		case <-dialed:
+			return nil, io.EOF
+		default:
+			close(dialed)
+			return host, nil
+		}
+	}
+
+	p, err := newCharDeviceProxy(fifo, dial)
+	assert.NoError(err)
+
+	<-dialed
+	_, err = guest.Write([]byte("ping"))
+	assert.NoError(err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(guest, buf)
+	assert.NoError(err)
+	assert.Equal("ping", string(buf))
+
+	p.close()
+
+	_, err = guest.Read(buf)
+	assert.Error(err)
+
+	_, err = newCharDeviceProxy(filepath.Join(dir, "missing"), dial)
+	assert.Error(err)
+}
+
+func TestSandboxStartCharDeviceProxies(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "chardev")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fifo := filepath.Join(dir, "fifo")
+	assert.NoError(unix.Mkfifo(fifo, 0600))
+
+	// The socket stands for the guest end QEMU listens on.
+	socketPath := filepath.Join(dir, "char.sock")
+	l, err := net.Listen("unix", socketPath)
+	assert.NoError(err)
+	defer l.Close()
+
+	device := drivers.NewCharDevice(&config.DeviceInfo{ID: "tty0", HostPath: fifo})
+	assert.NoError(device.Attach(&api.MockDeviceReceiver{}))
+	device.CharDev.Transport = config.CharDevVirtioSerial
+	device.CharDev.SocketPath = socketPath
+
+	s := &Sandbox{
+		ctx:        context.Background(),
+		config:     &SandboxConfig{},
+		devManager: manager.NewDeviceManager(manager.VirtioBlock, false, "", nil, []api.Device{device}),
+	}
+
+	// Like a sandbox fetched again, the attached device is not proxied
+	// until the proxies are started.
+	s.startCharDeviceProxies()
+	assert.Len(s.charDevProxies, 1)
+
+	conn, err := l.Accept()
+	assert.NoError(err)
+	defer conn.Close()
+
+	proxy := s.charDevProxies[device.CharDev.ID]
+	s.startCharDeviceProxies()
+	assert.Equal(proxy, s.charDevProxies[device.CharDev.ID])
+
+	proxy.close()
+}
@@ -47,6 +47,7 @@ const (
 const (
 	clhStateCreated = "Created"
 	clhStateRunning = "Running"
+	clhStatePaused  = "Paused"
 )
 
 const (
@@ -89,6 +90,10 @@ type clhClient interface {
 	VmAddDevicePut(ctx context.Context, vmAddDevice chclient.VmAddDevice) (*http.Response, error)
 	// Add a new disk device to the VM
 	VmAddDiskPut(ctx context.Context, diskConfig chclient.DiskConfig) (*http.Response, error)
+	// Pause the VM
+	PauseVM(ctx context.Context) (*http.Response, error)
+	// Resume the paused VM
+	ResumeVM(ctx context.Context) (*http.Response, error)
 }
 
 type CloudHypervisorVersion struct {
@@ -264,6 +269,9 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 	clh.vmconfig.Rng = chclient.RngConfig{
 		Src: clh.config.EntropySource,
 	}
+	if clh.config.EntropyMaxBytes > 0 {
+		return fmt.Errorf("cloud-hypervisor does not support rate limiting the entropy source")
+	}
 
 	// set the initial root/boot disk of hypervisor
 	imagePath, err := clh.config.ImageAssetPath()
@@ -412,8 +420,8 @@ func (clh *cloudHypervisor) hotplugBlockDevice(drive *config.BlockDrive) error {
 		return openAPIClientError(err)
 	}
 
-	//Explicitly set PCIAddr to NULL, so that VirtPath can be used
-	drive.PCIAddr = ""
+	//Explicitly set PCIPath to NULL, so that VirtPath can be used
+	drive.PCIPath = types.PciPath{}
 
 	if drive.Pmem {
 		err = fmt.Errorf("pmem device hotplug not supported")
@@ -444,9 +452,14 @@ func (clh *cloudHypervisor) hotPlugVFIODevice(device config.VFIODev) error {
 
 	_, err = cl.VmAddDevicePut(ctx, chclient.VmAddDevice{Path: device.SysfsDev})
 	if err != nil {
-		err = fmt.Errorf("Failed to hotplug device %+v %s", device, openAPIClientError(err))
+		return fmt.Errorf("Failed to hotplug device %+v %s", device, openAPIClientError(err))
 	}
-	return err
+
+	// The add-device API does not report the PCI address given to the
+	// device, so its guest PCI path is left unknown.
+	clh.Logger().WithField("device", device.SysfsDev).Warn("Guest PCI path of the VFIO device is unknown")
+
+	return nil
 }
 
 func (clh *cloudHypervisor) hotplugAddDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
@@ -587,6 +600,14 @@ func (clh *cloudHypervisor) cleanup() error {
 
 func (clh *cloudHypervisor) pauseSandbox() error {
 	clh.Logger().WithField("function", "pauseSandbox").Info("Pause Sandbox")
+
+	ctx, cancel := context.WithTimeout(context.Background(), clhAPITimeout*time.Second)
+	defer cancel()
+
+	if _, err := clh.client().PauseVM(ctx); err != nil {
+		return openAPIClientError(err)
+	}
+
 	return nil
 }
 
@@ -597,6 +618,14 @@ func (clh *cloudHypervisor) saveSandbox() error {
 
 func (clh *cloudHypervisor) resumeSandbox() error {
 	clh.Logger().WithField("function", "resumeSandbox").Info("Resume Sandbox")
+
+	ctx, cancel := context.WithTimeout(context.Background(), clhAPITimeout*time.Second)
+	defer cancel()
+
+	if _, err := clh.client().ResumeVM(ctx); err != nil {
+		return openAPIClientError(err)
+	}
+
 	return nil
 }
 
@@ -689,6 +718,7 @@ func (clh *cloudHypervisor) capabilities() types.Capabilities {
 	var caps types.Capabilities
 	caps.SetFsSharingSupport()
 	caps.SetBlockDeviceHotplugSupport()
+	caps.SetVMPauseSupport()
 	return caps
 }
 
@@ -1271,3 +1301,13 @@ func (clh *cloudHypervisor) vmInfo() (chclient.VmInfo, error) {
 func (clh *cloudHypervisor) isRateLimiterBuiltin() bool {
 	return false
 }
+
+func (clh *cloudHypervisor) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	return fmt.Errorf("cloud-hypervisor does not support block drive I/O throttling")
+}
+
+// setMemoryHostNodes does nothing, cloud-hypervisor does not bind the VM
+// memory to host nodes.
+func (clh *cloudHypervisor) setMemoryHostNodes(nodes []int) error {
+	return nil
+}
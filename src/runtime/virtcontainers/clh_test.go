@@ -89,6 +89,16 @@ func (c *clhClientMock) BootVM(ctx context.Context) (*http.Response, error) {
 	return nil, nil
 }
 
+func (c *clhClientMock) PauseVM(ctx context.Context) (*http.Response, error) {
+	c.vmInfo.State = clhStatePaused
+	return nil, nil
+}
+
+func (c *clhClientMock) ResumeVM(ctx context.Context) (*http.Response, error) {
+	c.vmInfo.State = clhStateRunning
+	return nil, nil
+}
+
 //nolint:golint
 func (c *clhClientMock) VmResizePut(ctx context.Context, vmResize chclient.VmResize) (*http.Response, error) {
 	return nil, nil
@@ -190,6 +200,19 @@ func TestCloudHypervisorBootVM(t *testing.T) {
 	}
 }
 
+func TestCloudHypervisorPauseResumeSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	mockClient := &clhClientMock{}
+	clh := &cloudHypervisor{APIClient: mockClient}
+
+	assert.NoError(clh.pauseSandbox())
+	assert.Equal(clhStatePaused, mockClient.vmInfo.State)
+
+	assert.NoError(clh.resumeSandbox())
+	assert.Equal(clhStateRunning, mockClient.vmInfo.State)
+}
+
 func TestCloudHypervisorCleanupVM(t *testing.T) {
 	assert := assert.New(t)
 	store, err := persist.GetDriver()
@@ -13,6 +13,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -252,6 +253,10 @@ type ContainerConfig struct {
 	// Resources container resources
 	Resources specs.LinuxResources
 
+	// GuestSysctls are the non-namespaced sysctls of the container, which
+	// can only be applied to the guest kernel shared by the sandbox.
+	GuestSysctls map[string]string
+
 	// Raw OCI specification, it won't be saved to disk.
 	CustomSpec *specs.Spec `json:"-"`
 }
@@ -297,6 +302,52 @@ type ContainerDevice struct {
 	GID uint32
 }
 
+// LayerImageMountType is the type of the rootfs mounts which are read-only
+// layer images, to be overlaid inside the guest rather than on the host.
+const LayerImageMountType = "kata.layer-image"
+
+const (
+	// VirtioFSShareMountOption is the option of the bind mounts to be
+	// shared with the guest through their own virtio-fs device, rather
+	// than through the sandbox shared directory.
+	VirtioFSShareMountOption = "kata.virtiofs"
+
+	// VirtioFSCacheMountOption prefixes the option setting the cache mode
+	// of the virtio-fs daemon of a bind mount shared through its own
+	// virtio-fs device.
+	VirtioFSCacheMountOption = "kata.virtiofs.cache="
+)
+
+// virtioFSShareOptions tells if the mount options ask for the mount to be
+// shared through its own virtio-fs device, and with which cache mode. The
+// other options are returned as is.
+func virtioFSShareOptions(options []string) (share bool, cache string, rest []string) {
+	for _, o := range options {
+		switch {
+		case o == VirtioFSShareMountOption:
+			share = true
+		case strings.HasPrefix(o, VirtioFSCacheMountOption):
+			share = true
+			cache = strings.TrimPrefix(o, VirtioFSCacheMountOption)
+		default:
+			rest = append(rest, o)
+		}
+	}
+
+	return share, cache, rest
+}
+
+// LayerImage describes a read-only container image layer, packaged as a
+// filesystem image with the PFN signature. Layer images are hotplugged as
+// pmem devices and mapped by the guest with DAX, so that sandboxes using
+// the same layers share their page cache on the host.
+type LayerImage struct {
+	// Source is the path of the layer image on the host
+	Source string
+	// Fstype is the type of the layer image filesystem, e.g. ext4 or erofs
+	Fstype string
+}
+
 // RootFs describes the container's rootfs.
 type RootFs struct {
 	// Source specifies the BlockDevice path
@@ -309,6 +360,9 @@ type RootFs struct {
 	Options []string
 	// Mounted specifies whether the rootfs has be mounted or not
 	Mounted bool
+	// Layers are the layer images the rootfs is made of, top-most first.
+	// They are overlaid inside the guest, under a writable directory.
+	Layers []LayerImage
 }
 
 // Container is composed of a set of containers and a runtime environment.
@@ -334,6 +388,11 @@ type Container struct {
 
 	devices []ContainerDevice
 
+	// attachedDevices are the IDs of the devices attached while the
+	// container is being created, in attachment order. They are detached
+	// in reverse order if the creation fails.
+	attachedDevices []string
+
 	systemMountsInfo SystemMountsInfo
 
 	ctx context.Context
@@ -499,14 +558,6 @@ func (c *Container) shareFiles(m Mount, idx int, hostSharedDir, guestSharedDir s
 func (c *Container) mountSharedDirMounts(hostSharedDir, guestSharedDir string) (sharedDirMounts map[string]Mount, ignoredMounts map[string]Mount, err error) {
 	sharedDirMounts = make(map[string]Mount)
 	ignoredMounts = make(map[string]Mount)
-	var devicesToDetach []string
-	defer func() {
-		if err != nil {
-			for _, id := range devicesToDetach {
-				c.sandbox.devManager.DetachDevice(id, c.sandbox)
-			}
-		}
-	}()
 	for idx, m := range c.mounts {
 		// Skip mounting certain system paths from the source on the host side
 		// into the container as it does not make sense to do so.
@@ -529,11 +580,11 @@ func (c *Container) mountSharedDirMounts(hostSharedDir, guestSharedDir string) (
 		// Check if mount is a block device file. If it is, the block device will be attached to the host
 		// instead of passing this as a shared mount.
 		if len(m.BlockDeviceID) > 0 {
-			// Attach this block device, all other devices passed in the config have been attached at this point
-			if err = c.sandbox.devManager.AttachDevice(m.BlockDeviceID, c.sandbox); err != nil {
+			// Attach this block device, all other devices passed in the config have been attached at this point.
+			// It is detached along with the other container devices if the container creation fails.
+			if err = c.attachDevice(m.BlockDeviceID); err != nil {
 				return nil, nil, err
 			}
-			devicesToDetach = append(devicesToDetach, m.BlockDeviceID)
 			continue
 		}
 
@@ -642,6 +693,46 @@ func filterDevices(c *Container, devices []ContainerDevice) (ret []ContainerDevi
 	return
 }
 
+// createVirtioFSShareDevices creates the devices sharing the bind mounts
+// asking for it through their own virtio-fs device. The mounts fall back to
+// the sandbox shared directory when the devices cannot be hotplugged.
+func (c *Container) createVirtioFSShareDevices() error {
+	for i, m := range c.mounts {
+		if len(m.BlockDeviceID) > 0 || m.Type != "bind" {
+			continue
+		}
+
+		share, cache, _ := virtioFSShareOptions(m.Options)
+		if !share {
+			continue
+		}
+
+		if !c.sandbox.virtioFSShareHotplugSupported() {
+			c.Logger().WithField("mount-source", m.Source).
+				Warn("virtio-fs share hotplug not supported, using the shared directory")
+			continue
+		}
+
+		if cache == "" {
+			cache = c.sandbox.config.HypervisorConfig.VirtioFSCache
+		}
+
+		di, err := config.VirtioFSShareDeviceInfo(m.Source, m.Destination, cache)
+		if err != nil {
+			return err
+		}
+
+		d, err := c.sandbox.devManager.NewDevice(*di)
+		if err != nil {
+			return err
+		}
+
+		c.mounts[i].BlockDeviceID = d.DeviceID()
+	}
+
+	return nil
+}
+
 func (c *Container) createBlockDevices() error {
 	if !c.checkBlockDeviceSupport() {
 		c.Logger().Warn("Block device not supported")
@@ -759,6 +850,9 @@ func newContainer(sandbox *Sandbox, contConfig *ContainerConfig) (*Container, er
 	}
 
 	if err := c.createDevices(contConfig); err != nil {
+		if err := c.removeDevices(); err != nil {
+			c.Logger().WithError(err).Error("rollback failed removeDevices()")
+		}
 		return nil, err
 	}
 
@@ -794,6 +888,12 @@ func (c *Container) createMounts() error {
 		}
 	}
 
+	// Create the virtio-fs shares before the block devices, their sources
+	// must not be checked for pmem devices.
+	if err := c.createVirtioFSShareDevices(); err != nil {
+		return err
+	}
+
 	// Create block devices for newly created container
 	if err := c.createBlockDevices(); err != nil {
 		return err
@@ -821,6 +921,9 @@ func (c *Container) createDevices(contConfig *ContainerConfig) error {
 	for _, info := range contConfig.DeviceInfos {
 		dev, err := c.sandbox.devManager.NewDevice(info)
 		if err != nil {
+			for _, d := range storedDevices {
+				c.sandbox.devManager.RemoveDevice(d.ID)
+			}
 			return err
 		}
 
@@ -841,12 +944,18 @@ func (c *Container) createDevices(contConfig *ContainerConfig) error {
 // - Unplug CPU and memory resources from the VM.
 // - Unplug devices from the VM.
 func (c *Container) rollbackFailingContainerCreation() {
-	if err := c.detachDevices(); err != nil {
-		c.Logger().WithError(err).Error("rollback failed detachDevices()")
+	if err := c.detachAttachedDevices(); err != nil {
+		c.Logger().WithError(err).Error("rollback failed detachAttachedDevices()")
+	}
+	if err := c.removeDevices(); err != nil {
+		c.Logger().WithError(err).Error("rollback failed removeDevices()")
 	}
 	if err := c.removeDrive(); err != nil {
 		c.Logger().WithError(err).Error("rollback failed removeDrive()")
 	}
+	if err := c.removeLayers(); err != nil {
+		c.Logger().WithError(err).Error("rollback failed removeLayers()")
+	}
 	if err := c.unmountHostMounts(); err != nil {
 		c.Logger().WithError(err).Error("rollback failed unmountHostMounts()")
 	}
@@ -873,14 +982,20 @@ func (c *Container) checkBlockDeviceSupport() bool {
 func (c *Container) create() (err error) {
 	// In case the container creation fails, the following takes care
 	// of rolling back all the actions previously performed.
+	c.attachedDevices = nil
 	defer func() {
 		if err != nil {
 			c.Logger().WithError(err).Error("container create failed")
 			c.rollbackFailingContainerCreation()
 		}
+		c.attachedDevices = nil
 	}()
 
-	if c.checkBlockDeviceSupport() {
+	if len(c.rootFs.Layers) > 0 {
+		if err = c.hotplugLayers(); err != nil {
+			return
+		}
+	} else if c.checkBlockDeviceSupport() {
 		if err = c.hotplugDrive(); err != nil {
 			return
 		}
@@ -941,6 +1056,10 @@ func (c *Container) create() (err error) {
 		}
 	}
 
+	if err = c.throttleBlockDevices(c.config.Resources.BlockIO, nil); err != nil {
+		return
+	}
+
 	if !rootless.IsRootless() && !c.sandbox.config.SandboxCgroupOnly {
 		if err = c.cgroupsCreate(); err != nil {
 			return
@@ -1132,6 +1251,10 @@ func (c *Container) stop(force bool) error {
 		return err
 	}
 
+	if err := c.removeLayers(); err != nil && !force {
+		return err
+	}
+
 	shareDir := filepath.Join(kataHostSharedDir(), c.sandbox.id, c.id)
 	if err := syscall.Rmdir(shareDir); err != nil {
 		c.Logger().WithError(err).WithField("share-dir", shareDir).Warn("Could not remove container share dir")
@@ -1263,6 +1386,13 @@ func (c *Container) update(resources specs.LinuxResources) error {
 		return err
 	}
 
+	if blkio := resources.BlockIO; blkio != nil {
+		if err := c.throttleBlockDevices(blkio, c.config.Resources.BlockIO); err != nil {
+			return err
+		}
+		c.config.Resources.BlockIO = blkio
+	}
+
 	if !c.sandbox.config.SandboxCgroupOnly {
 		if err := c.cgroupsUpdate(resources); err != nil {
 			return err
@@ -1388,12 +1518,13 @@ func (c *Container) plugDevice(devicePath string) error {
 			return fmt.Errorf("device manager failed to create rootfs device for %q: %v", devicePath, err)
 		}
 
-		c.state.BlockDeviceID = b.DeviceID()
-
 		// attach rootfs device
-		if err := c.sandbox.devManager.AttachDevice(b.DeviceID(), c.sandbox); err != nil {
+		if err := c.attachDevice(b.DeviceID()); err != nil {
+			c.sandbox.devManager.RemoveDevice(b.DeviceID())
 			return err
 		}
+
+		c.state.BlockDeviceID = b.DeviceID()
 	}
 	return nil
 }
@@ -1429,22 +1560,139 @@ func (c *Container) removeDrive() (err error) {
 	return nil
 }
 
-func (c *Container) attachDevices(devices []ContainerDevice) error {
-	// there's no need to do rollback when error happens,
-	// because if attachDevices fails, container creation will fail too,
-	// and rollbackFailingContainerCreation could do all the rollbacks
+// hotplugLayers hotplugs the layer images the container rootfs is made of
+// as pmem devices. The devices are shared with the other containers of the
+// sandbox using the same layers.
+func (c *Container) hotplugLayers() error {
+	hypervisorCaps := c.sandbox.hypervisor.capabilities()
+	if !hypervisorCaps.IsNvdimmHotplugSupported() {
+		return fmt.Errorf("layer images need NVDIMM devices hotplug, not supported by the hypervisor")
+	}
+
+	for _, layer := range c.rootFs.Layers {
+		di, err := config.LayerImageDeviceInfo(layer.Source, layer.Fstype)
+		if err != nil {
+			return err
+		}
+
+		b, err := c.sandbox.devManager.NewDevice(*di)
+		if err != nil {
+			return fmt.Errorf("device manager failed to create layer device for %q: %v", layer.Source, err)
+		}
+
+		// The layer device is plugged once for the sandbox, the
+		// containers using it only hold a device manager reference.
+		if !c.sandbox.devManager.IsDeviceAttached(b.DeviceID()) {
+			if err := c.sandbox.devManager.AttachDevice(b.DeviceID(), c.sandbox); err != nil {
+				c.sandbox.devManager.RemoveDevice(b.DeviceID())
+				return err
+			}
+		}
+
+		c.state.LayerDeviceIDs = append(c.state.LayerDeviceIDs, b.DeviceID())
+	}
+
+	return nil
+}
+
+// removeLayers releases the layer devices of the container rootfs. NVDIMM
+// devices cannot be unplugged: a layer device stays plugged, to be used by
+// the next containers made of the layer, and goes away with the VM.
+func (c *Container) removeLayers() error {
+	for i := len(c.state.LayerDeviceIDs) - 1; i >= 0; i-- {
+		devID := c.state.LayerDeviceIDs[i]
+
+		// Removing the last reference of an attached device fails,
+		// the device is kept for the sandbox.
+		err := c.sandbox.devManager.RemoveDevice(devID)
+		if err != nil && err != manager.ErrDeviceNotExist && err != manager.ErrRemoveAttachedDevice {
+			return err
+		}
+
+		c.state.LayerDeviceIDs = c.state.LayerDeviceIDs[:i]
+	}
 
+	return nil
+}
+
+// attachDevice attaches a device and records it, so that it is detached if
+// the container creation fails.
+func (c *Container) attachDevice(id string) error {
+	if err := c.sandbox.devManager.AttachDevice(id, c.sandbox); err != nil {
+		return err
+	}
+
+	c.attachedDevices = append(c.attachedDevices, id)
+	return nil
+}
+
+func (c *Container) attachDevices(devices []ContainerDevice) error {
 	// since devices with large bar space require delayed attachment,
 	// the devices need to be split into two lists, normalAttachedDevs and delayAttachedDevs.
 	// so c.device is not used here. See issue https://github.com/kata-containers/kata-containers/src/runtime/issues/2460.
+	var ids []string
 	for _, dev := range devices {
-		if err := c.sandbox.devManager.AttachDevice(dev.ID, c.sandbox); err != nil {
-			return err
-		}
+		ids = append(ids, dev.ID)
 	}
+
+	// The devices are attached all together or not at all, the ones
+	// attached by earlier steps are detached by
+	// rollbackFailingContainerCreation.
+	if err := c.sandbox.devManager.AttachDevices(ids, c.sandbox); err != nil {
+		return err
+	}
+
+	c.attachedDevices = append(c.attachedDevices, ids...)
 	return nil
 }
 
+// detachAttachedDevices detaches the devices attached since the container
+// creation started, in reverse order. It goes on when a device fails to
+// detach, and returns the first error.
+func (c *Container) detachAttachedDevices() (err error) {
+	for i := len(c.attachedDevices) - 1; i >= 0; i-- {
+		id := c.attachedDevices[i]
+
+		detachErr := c.sandbox.devManager.DetachDevice(id, c.sandbox)
+		if detachErr != nil && detachErr != manager.ErrDeviceNotAttached {
+			c.Logger().WithField("device-id", id).WithError(detachErr).Error("detach device failed")
+			if err == nil {
+				err = detachErr
+			}
+		}
+	}
+
+	c.attachedDevices = nil
+	return err
+}
+
+// removeDevices drops the references the container holds on its devices
+// and on the block devices backing its mounts, the devices being removed
+// from the device manager once no other container uses them.
+func (c *Container) removeDevices() (err error) {
+	ids := []string{}
+	for _, dev := range c.devices {
+		ids = append(ids, dev.ID)
+	}
+	for _, m := range c.mounts {
+		if m.BlockDeviceID != "" {
+			ids = append(ids, m.BlockDeviceID)
+		}
+	}
+
+	for _, id := range ids {
+		removeErr := c.sandbox.devManager.RemoveDevice(id)
+		if removeErr != nil && removeErr != manager.ErrDeviceNotExist {
+			c.Logger().WithField("device-id", id).WithError(removeErr).Error("remove device failed")
+			if err == nil {
+				err = removeErr
+			}
+		}
+	}
+
+	return err
+}
+
 func (c *Container) detachDevices() error {
 	for _, dev := range c.devices {
 		err := c.sandbox.devManager.DetachDevice(dev.ID, c.sandbox)
@@ -7,6 +7,7 @@ package virtcontainers
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -87,7 +88,7 @@ func TestContainerRemoveDrive(t *testing.T) {
 	sandbox := &Sandbox{
 		ctx:        context.Background(),
 		id:         "sandbox",
-		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil),
+		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil, nil),
 		config:     &SandboxConfig{},
 	}
 
@@ -125,6 +126,103 @@ func TestContainerRemoveDrive(t *testing.T) {
 	assert.Nil(t, err, "remove drive should succeed")
 }
 
+func TestContainerRemoveLayers(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &Sandbox{
+		ctx:        context.Background(),
+		id:         "sandbox",
+		devManager: manager.NewDeviceManager(manager.VirtioBlock, false, "", nil, nil),
+		config:     &SandboxConfig{},
+	}
+
+	layerInfo := config.DeviceInfo{
+		HostPath: "/layers/base.img",
+		DevType:  "b",
+		Pmem:     true,
+		ReadOnly: true,
+	}
+
+	// Two containers use the layer, plugged once.
+	device, err := sandbox.devManager.NewDevice(layerInfo)
+	assert.NoError(err)
+	assert.NoError(sandbox.devManager.AttachDevice(device.DeviceID(), &api.MockDeviceReceiver{}))
+	_, err = sandbox.devManager.NewDevice(layerInfo)
+	assert.NoError(err)
+
+	for _, id := range []string{"c1", "c2"} {
+		c := Container{
+			sandbox: sandbox,
+			id:      id,
+		}
+		c.state.LayerDeviceIDs = []string{device.DeviceID()}
+
+		assert.NoError(c.removeLayers())
+		assert.Empty(c.state.LayerDeviceIDs)
+	}
+
+	// The NVDIMM device is not unplugged, and is used again by the next
+	// container made of the layer.
+	assert.True(sandbox.devManager.IsDeviceAttached(device.DeviceID()))
+	again, err := sandbox.devManager.NewDevice(layerInfo)
+	assert.NoError(err)
+	assert.Equal(device.DeviceID(), again.DeviceID())
+}
+
+func TestContainerAttachDevicesRollback(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &Sandbox{
+		ctx:        context.Background(),
+		id:         "sandbox",
+		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil, nil),
+		config:     &SandboxConfig{},
+	}
+
+	container := Container{
+		sandbox: sandbox,
+		id:      "testContainer",
+	}
+
+	var devices []ContainerDevice
+	for _, minor := range []int64{3, 5} {
+		path := fmt.Sprintf("/dev/test%d", minor)
+		device, err := sandbox.devManager.NewDevice(config.DeviceInfo{
+			HostPath:      path,
+			ContainerPath: path,
+			DevType:       "c",
+			Major:         1,
+			Minor:         minor,
+		})
+		assert.NoError(err)
+		devices = append(devices, ContainerDevice{ID: device.DeviceID(), ContainerPath: path})
+	}
+
+	// A failing device leaves none of the devices attached.
+	err := container.attachDevices([]ContainerDevice{devices[0], {ID: "unknown"}})
+	assert.Error(err)
+	assert.False(sandbox.devManager.IsDeviceAttached(devices[0].ID))
+	assert.Empty(container.attachedDevices)
+
+	err = container.attachDevices(devices)
+	assert.NoError(err)
+	assert.Equal([]string{devices[0].ID, devices[1].ID}, container.attachedDevices)
+
+	err = container.detachAttachedDevices()
+	assert.NoError(err)
+	assert.Empty(container.attachedDevices)
+	for _, dev := range devices {
+		assert.False(sandbox.devManager.IsDeviceAttached(dev.ID))
+	}
+
+	container.devices = devices
+	err = container.removeDevices()
+	assert.NoError(err)
+	for _, dev := range devices {
+		assert.Nil(sandbox.devManager.GetDeviceByID(dev.ID))
+	}
+}
+
 func TestUnmountHostMountsRemoveBindHostPath(t *testing.T) {
 	if tc.NotValid(ktu.NeedRoot()) {
 		t.Skip(testDisabledAsNonRoot)
@@ -306,7 +404,7 @@ func TestContainerAddDriveDir(t *testing.T) {
 	sandbox := &Sandbox{
 		ctx:        context.Background(),
 		id:         testSandboxID,
-		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil),
+		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil, nil),
 		hypervisor: &mockHypervisor{},
 		agent:      &noopAgent{},
 		config: &SandboxConfig{
@@ -347,6 +445,25 @@ func TestContainerAddDriveDir(t *testing.T) {
 	assert.NotEmpty(container.state.Fstype)
 }
 
+func TestVirtioFSShareOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	share, cache, rest := virtioFSShareOptions([]string{"rbind", "ro"})
+	assert.False(share)
+	assert.Empty(cache)
+	assert.Equal([]string{"rbind", "ro"}, rest)
+
+	share, cache, rest = virtioFSShareOptions([]string{"rbind", VirtioFSShareMountOption})
+	assert.True(share)
+	assert.Empty(cache)
+	assert.Equal([]string{"rbind"}, rest)
+
+	share, cache, rest = virtioFSShareOptions([]string{VirtioFSCacheMountOption + "always", "ro"})
+	assert.True(share)
+	assert.Equal("always", cache)
+	assert.Equal([]string{"ro"}, rest)
+}
+
 func TestContainerRootfsPath(t *testing.T) {
 
 	testRawFile, loopDev, fakeRootfs, err := testSetupFakeRootfs(t)
@@ -0,0 +1,464 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// CPUPlacementPolicy describes how the hypervisor threads are placed on
+// the host CPUs and NUMA nodes.
+type CPUPlacementPolicy string
+
+const (
+	// NoCPUPlacement leaves the hypervisor threads to the host scheduler.
+	NoCPUPlacement CPUPlacementPolicy = "none"
+
+	// NUMACPUPlacement pins vCPU N to the Nth CPU of the sandbox cpuset,
+	// keeps the other hypervisor threads on the housekeeping CPUs and binds
+	// the VM memory to the NUMA nodes of the sandbox cpuset.
+	NUMACPUPlacement CPUPlacementPolicy = "numa"
+)
+
+var (
+	sysDevicesSystemPath = "/sys/devices/system"
+	procPath             = "/proc"
+	cpusetCgroupPath     = "/sys/fs/cgroup/cpuset"
+
+	schedSetAffinityFunc = unix.SchedSetaffinity
+	migratePagesFunc     = migratePages
+)
+
+func (p CPUPlacementPolicy) valid() bool {
+	switch p {
+	case "", NoCPUPlacement, NUMACPUPlacement:
+		return true
+	}
+
+	return false
+}
+
+// parseCPUList parses a list of CPUs or NUMA nodes in the kernel list
+// format (e.g. "0-3,8,10-11"), as found in cpuset.cpus or sysfs.
+// The result is sorted and without duplicates.
+func parseCPUList(list string) ([]int, error) {
+	set := make(map[int]bool)
+
+	for _, r := range strings.Split(strings.TrimSpace(list), ",") {
+		if r == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CPU list %q: %v", list, err)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("Invalid CPU list %q: %v", list, err)
+			}
+		}
+
+		if first < 0 || last < first {
+			return nil, fmt.Errorf("Invalid CPU range %q in %q", r, list)
+		}
+
+		for i := first; i <= last; i++ {
+			set[i] = true
+		}
+	}
+
+	var cpus []int
+	for cpu := range set {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+
+	return cpus, nil
+}
+
+func readCPUList(path string) ([]int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCPUList(string(data))
+}
+
+// hostOnlineCPUs returns the CPUs currently online on the host.
+func hostOnlineCPUs() ([]int, error) {
+	return readCPUList(filepath.Join(sysDevicesSystemPath, "cpu", "online"))
+}
+
+// hostOnlineNodes returns the NUMA nodes currently online on the host.
+func hostOnlineNodes() ([]int, error) {
+	return readCPUList(filepath.Join(sysDevicesSystemPath, "node", "online"))
+}
+
+// cpusNUMANodes returns the host NUMA nodes the given CPUs belong to.
+func cpusNUMANodes(cpus []int) ([]int, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(sysDevicesSystemPath, "node", "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int]bool)
+	for _, cpu := range cpus {
+		wanted[cpu] = true
+	}
+
+	var nodes []int
+	for _, dir := range nodeDirs {
+		node, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		nodeCPUs, err := readCPUList(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cpu := range nodeCPUs {
+			if wanted[cpu] {
+				nodes = append(nodes, node)
+				break
+			}
+		}
+	}
+	sort.Ints(nodes)
+
+	return nodes, nil
+}
+
+// housekeepingCPUs returns the CPUs where the hypervisor threads other than
+// the vCPUs run: the configured ones, or the online CPUs not used by the
+// sandbox.
+func housekeepingCPUs(configured string, sandboxCPUs []int) ([]int, error) {
+	if configured != "" {
+		return parseCPUList(configured)
+	}
+
+	online, err := hostOnlineCPUs()
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[int]bool)
+	for _, cpu := range sandboxCPUs {
+		used[cpu] = true
+	}
+
+	var cpus []int
+	for _, cpu := range online {
+		if !used[cpu] {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
+
+// processThreads returns the IDs of all the threads of a process.
+func processThreads(pid int) ([]int, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(procPath, strconv.Itoa(pid), "task"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tids []int
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		tids = append(tids, tid)
+	}
+
+	return tids, nil
+}
+
+// threadCpuset returns the CPUs of the cpuset cgroup of a thread, which
+// its affinity has to be a subset of, or nil when it cannot be found.
+func threadCpuset(tid int) []int {
+	data, err := ioutil.ReadFile(filepath.Join(procPath, strconv.Itoa(tid), "cpuset"))
+	if err != nil {
+		return nil
+	}
+
+	dir := filepath.Join(cpusetCgroupPath, strings.TrimSpace(string(data)))
+	for _, file := range []string{"cpuset.effective_cpus", "cpuset.cpus"} {
+		if cpus, err := readCPUList(filepath.Join(dir, file)); err == nil && len(cpus) > 0 {
+			return cpus
+		}
+	}
+
+	return nil
+}
+
+// setThreadAffinity sets the affinity of a thread to the given CPUs which
+// are in its cpuset, or to the whole cpuset if none is.
+func setThreadAffinity(tid int, cpus []int) error {
+	if cpuset := threadCpuset(tid); cpuset != nil {
+		allowed := make(map[int]bool)
+		for _, cpu := range cpuset {
+			allowed[cpu] = true
+		}
+
+		var inCpuset []int
+		for _, cpu := range cpus {
+			if allowed[cpu] {
+				inCpuset = append(inCpuset, cpu)
+			}
+		}
+
+		if len(inCpuset) == 0 {
+			virtLog.WithFields(logrus.Fields{
+				"tid":    tid,
+				"cpus":   cpus,
+				"cpuset": cpuset,
+			}).Debug("CPUs out of the thread cpuset, using the whole cpuset")
+			inCpuset = cpuset
+		}
+
+		cpus = inCpuset
+	}
+
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+
+	if err := schedSetAffinityFunc(tid, &set); err != nil {
+		return fmt.Errorf("Could not set CPU affinity of thread %d to %v: %v", tid, cpus, err)
+	}
+
+	return nil
+}
+
+// sandboxCPUs returns the union of the cpusets of the given containers.
+func sandboxCPUs(containers []ContainerConfig) ([]int, error) {
+	var cpusets []string
+
+	for _, c := range containers {
+		if c.Resources.CPU != nil && c.Resources.CPU.Cpus != "" {
+			cpusets = append(cpusets, c.Resources.CPU.Cpus)
+		}
+	}
+
+	return parseCPUList(strings.Join(cpusets, ","))
+}
+
+// memoryHostNodes returns the host NUMA nodes the VM memory is bound to
+// with the NUMA CPU placement policy, based on the cpusets of the
+// containers of the sandbox. The hypervisor binds its memory backends to
+// those nodes.
+func memoryHostNodes(containers []ContainerConfig) ([]int, error) {
+	cpus, err := sandboxCPUs(containers)
+	if err != nil || len(cpus) == 0 {
+		return nil, err
+	}
+
+	return cpusNUMANodes(cpus)
+}
+
+// nodeMask returns the bitmask of the given NUMA nodes, as taken by the
+// memory policy system calls.
+func nodeMask(nodes []int) []uint64 {
+	var mask []uint64
+
+	for _, node := range nodes {
+		for len(mask) <= node/64 {
+			mask = append(mask, 0)
+		}
+		mask[node/64] |= 1 << uint(node%64)
+	}
+
+	return mask
+}
+
+// migratePages is the migrate_pages(2) system call, moving the pages of a
+// process from the from nodes to the to nodes.
+func migratePages(pid int, from, to []uint64) error {
+	for len(from) < len(to) {
+		from = append(from, 0)
+	}
+	for len(to) < len(from) {
+		to = append(to, 0)
+	}
+
+	// The kernel reads maxnode - 1 bits of each mask.
+	maxNode := 64*len(from) + 1
+
+	_, _, errno := unix.Syscall6(unix.SYS_MIGRATE_PAGES, uintptr(pid), uintptr(maxNode),
+		uintptr(unsafe.Pointer(&from[0])), uintptr(unsafe.Pointer(&to[0])), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// migrateProcessMemory moves the memory a process allocated on the host
+// NUMA nodes other than the given ones to those.
+func migrateProcessMemory(pid int, nodes []int) error {
+	online, err := hostOnlineNodes()
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[int]bool)
+	for _, node := range nodes {
+		wanted[node] = true
+	}
+
+	var others []int
+	for _, node := range online {
+		if !wanted[node] {
+			others = append(others, node)
+		}
+	}
+
+	if len(others) == 0 || len(nodes) == 0 {
+		return nil
+	}
+
+	if err := migratePagesFunc(pid, nodeMask(others), nodeMask(nodes)); err != nil {
+		return fmt.Errorf("Could not move the memory of process %d to host nodes %v: %v", pid, nodes, err)
+	}
+
+	return nil
+}
+
+// updateMemoryHostNodes binds the VM memory to the NUMA nodes of the
+// cpusets of the containers of the sandbox, which change as the containers
+// join it: a pod sandbox is usually created with a container without any
+// cpuset, like the Kubernetes pause container, so that its memory is not
+// bound at all until the workload containers are created.
+func (s *Sandbox) updateMemoryHostNodes() error {
+	if s.config.HypervisorConfig.CPUPlacementPolicy != NUMACPUPlacement {
+		return nil
+	}
+
+	nodes, err := memoryHostNodes(s.config.Containers)
+	if err != nil {
+		return err
+	}
+
+	// The memory stays where it is when no container has a cpuset anymore.
+	if len(nodes) == 0 || reflect.DeepEqual(nodes, s.config.HypervisorConfig.memoryHostNodes) {
+		return nil
+	}
+
+	if err := s.hypervisor.setMemoryHostNodes(nodes); err != nil {
+		return err
+	}
+	s.config.HypervisorConfig.memoryHostNodes = nodes
+
+	s.Logger().WithField("host-nodes", nodes).Debug("VM memory bound to host nodes")
+
+	return nil
+}
+
+// placeHypervisorThreads applies the NUMA CPU placement policy: vCPU N is
+// pinned to the Nth CPU of the sandbox cpuset and all the other hypervisor
+// threads (VMM main thread, IO threads...) to the housekeeping CPUs. It has
+// to be called once the threads are in their cgroups, the affinities being
+// restricted to the cpusets of those.
+func (s *Sandbox) placeHypervisorThreads() error {
+	if s.config.HypervisorConfig.CPUPlacementPolicy != NUMACPUPlacement {
+		return nil
+	}
+
+	var configs []ContainerConfig
+	for _, c := range s.containers {
+		configs = append(configs, *c.config)
+	}
+
+	cpus, err := sandboxCPUs(configs)
+	if err != nil {
+		return err
+	}
+
+	if len(cpus) == 0 {
+		// Nothing to pin to without a cpuset.
+		return nil
+	}
+
+	// vCPUs can be hotplugged when a container joins the sandbox, so the
+	// thread IDs have to be queried every time.
+	tids, err := s.hypervisor.getThreadIDs()
+	if err != nil {
+		return fmt.Errorf("failed to get thread ids from hypervisor: %v", err)
+	}
+
+	vcpuThreads := make(map[int]bool)
+	for vcpu, tid := range tids.vcpus {
+		vcpuThreads[tid] = true
+
+		// Extra vCPUs share the whole sandbox cpuset.
+		pinned := cpus
+		if vcpu < len(cpus) {
+			pinned = cpus[vcpu : vcpu+1]
+		}
+
+		if err := setThreadAffinity(tid, pinned); err != nil {
+			return err
+		}
+	}
+
+	housekeeping, err := housekeepingCPUs(s.config.HypervisorConfig.HousekeepingCPUs, cpus)
+	if err != nil {
+		return err
+	}
+
+	if len(housekeeping) == 0 {
+		s.Logger().Warn("No housekeeping CPU left, hypervisor threads are not placed")
+		return nil
+	}
+
+	for _, pid := range s.hypervisor.getPids() {
+		if pid <= 0 {
+			continue
+		}
+
+		threads, err := processThreads(pid)
+		if err != nil {
+			return err
+		}
+
+		for _, tid := range threads {
+			if vcpuThreads[tid] {
+				continue
+			}
+
+			if err := setThreadAffinity(tid, housekeeping); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.Logger().WithFields(logrus.Fields{
+		"sandbox-cpus":      cpus,
+		"housekeeping-cpus": housekeeping,
+	}).Debug("Hypervisor threads placed")
+
+	return nil
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+// fakeTopology creates a host with 2 NUMA nodes of 4 CPUs each under a
+// fake sysfs, and returns a function restoring the real one.
+func fakeTopology(t *testing.T) func() {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "sys")
+	assert.NoError(err)
+
+	files := map[string]string{
+		"cpu/online":         "0-7\n",
+		"node/online":        "0-1\n",
+		"node/node0/cpulist": "0-3\n",
+		"node/node1/cpulist": "4-7\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(path), DirMode))
+		assert.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	savedPath := sysDevicesSystemPath
+	sysDevicesSystemPath = dir
+
+	return func() {
+		sysDevicesSystemPath = savedPath
+		os.RemoveAll(dir)
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	assert := assert.New(t)
+
+	for list, expected := range map[string][]int{
+		"":              nil,
+		"3":             {3},
+		"0-3\n":         {0, 1, 2, 3},
+		"8,0-1,1,10-11": {0, 1, 8, 10, 11},
+	} {
+		cpus, err := parseCPUList(list)
+		assert.NoError(err, list)
+		assert.Equal(expected, cpus, list)
+	}
+
+	for _, list := range []string{"a", "1-b", "3-1", "-1"} {
+		_, err := parseCPUList(list)
+		assert.Error(err, list)
+	}
+}
+
+func TestCPUsNUMANodes(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	nodes, err := cpusNUMANodes([]int{1, 2})
+	assert.NoError(err)
+	assert.Equal([]int{0}, nodes)
+
+	nodes, err = cpusNUMANodes([]int{3, 4})
+	assert.NoError(err)
+	assert.Equal([]int{0, 1}, nodes)
+
+	nodes, err = cpusNUMANodes([]int{12})
+	assert.NoError(err)
+	assert.Empty(nodes)
+}
+
+func TestHousekeepingCPUs(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	cpus, err := housekeepingCPUs("", []int{2, 3, 4, 5, 6, 7})
+	assert.NoError(err)
+	assert.Equal([]int{0, 1}, cpus)
+
+	cpus, err = housekeepingCPUs("7", []int{2, 3})
+	assert.NoError(err)
+	assert.Equal([]int{7}, cpus)
+}
+
+func TestMemoryHostNodes(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	nodes, err := memoryHostNodes([]ContainerConfig{{}})
+	assert.NoError(err)
+	assert.Empty(nodes)
+
+	nodes, err = memoryHostNodes([]ContainerConfig{
+		{Resources: specs.LinuxResources{CPU: &specs.LinuxCPU{Cpus: "5-6"}}},
+	})
+	assert.NoError(err)
+	assert.Equal([]int{1}, nodes)
+}
+
+func TestNodeMask(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(nodeMask(nil))
+	assert.Equal([]uint64{0x9}, nodeMask([]int{0, 3}))
+	assert.Equal([]uint64{0x1, 0x2}, nodeMask([]int{0, 65}))
+}
+
+func TestMigrateProcessMemory(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	var from, to []uint64
+	savedFunc := migratePagesFunc
+	migratePagesFunc = func(pid int, fromMask, toMask []uint64) error {
+		from, to = fromMask, toMask
+		return nil
+	}
+	defer func() {
+		migratePagesFunc = savedFunc
+	}()
+
+	// The pages are moved from the other online nodes
+	assert.NoError(migrateProcessMemory(1234, []int{1}))
+	assert.Equal([]uint64{0x1}, from)
+	assert.Equal([]uint64{0x2}, to)
+
+	// and left where they are when bound to all of them
+	from, to = nil, nil
+	assert.NoError(migrateProcessMemory(1234, []int{0, 1}))
+	assert.Nil(from)
+	assert.Nil(to)
+}
+
+// nodesHypervisor records the host nodes the VM memory is bound to.
+type nodesHypervisor struct {
+	mockHypervisor
+	nodes []int
+}
+
+func (h *nodesHypervisor) setMemoryHostNodes(nodes []int) error {
+	h.nodes = nodes
+	return nil
+}
+
+func TestSandboxUpdateMemoryHostNodes(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	h := &nodesHypervisor{}
+	s := &Sandbox{
+		hypervisor: h,
+		config: &SandboxConfig{
+			// Like the pause container of a pod
+			Containers: []ContainerConfig{{ID: "pause"}},
+		},
+	}
+
+	s.config.HypervisorConfig.CPUPlacementPolicy = NUMACPUPlacement
+	assert.NoError(s.updateMemoryHostNodes())
+	assert.Nil(h.nodes)
+	assert.Empty(s.config.HypervisorConfig.memoryHostNodes)
+
+	// The memory of a sandbox created without any cpuset gets bound to the
+	// nodes of the containers joining it
+	s.config.Containers = append(s.config.Containers, ContainerConfig{
+		ID:        "foo",
+		Resources: specs.LinuxResources{CPU: &specs.LinuxCPU{Cpus: "5-6"}},
+	})
+	assert.NoError(s.updateMemoryHostNodes())
+	assert.Equal([]int{1}, h.nodes)
+	assert.Equal([]int{1}, s.config.HypervisorConfig.memoryHostNodes)
+
+	s.config.Containers = append(s.config.Containers, ContainerConfig{
+		ID:        "bar",
+		Resources: specs.LinuxResources{CPU: &specs.LinuxCPU{Cpus: "2"}},
+	})
+	assert.NoError(s.updateMemoryHostNodes())
+	assert.Equal([]int{0, 1}, h.nodes)
+	assert.Equal([]int{0, 1}, s.config.HypervisorConfig.memoryHostNodes)
+
+	// Without placement policy it is left as is
+	h.nodes = nil
+	s.config.HypervisorConfig.CPUPlacementPolicy = NoCPUPlacement
+	s.config.Containers = s.config.Containers[:2]
+	assert.NoError(s.updateMemoryHostNodes())
+	assert.Nil(h.nodes)
+}
+
+func TestSetThreadAffinityCpuset(t *testing.T) {
+	assert := assert.New(t)
+
+	tid := os.Getpid()
+
+	dir, err := ioutil.TempDir("", "cpuset")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"proc/" + strconv.Itoa(tid) + "/cpuset": "/kata\n",
+		"cgroup/kata/cpuset.effective_cpus":     "4-5\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(os.MkdirAll(filepath.Dir(path), DirMode))
+		assert.NoError(ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	savedProcPath, savedCpusetPath := procPath, cpusetCgroupPath
+	procPath, cpusetCgroupPath = filepath.Join(dir, "proc"), filepath.Join(dir, "cgroup")
+	defer func() {
+		procPath, cpusetCgroupPath = savedProcPath, savedCpusetPath
+	}()
+
+	var affinity []int
+	savedFunc := schedSetAffinityFunc
+	schedSetAffinityFunc = func(tid int, set *unix.CPUSet) error {
+		affinity = nil
+		for cpu := 0; cpu < 8; cpu++ {
+			if set.IsSet(cpu) {
+				affinity = append(affinity, cpu)
+			}
+		}
+		return nil
+	}
+	defer func() {
+		schedSetAffinityFunc = savedFunc
+	}()
+
+	// The affinity is restricted to the cpuset of the thread
+	assert.NoError(setThreadAffinity(tid, []int{3, 4}))
+	assert.Equal([]int{4}, affinity)
+
+	// The thread is left on its whole cpuset when none of the CPUs is in it
+	assert.NoError(setThreadAffinity(tid, []int{0, 1}))
+	assert.Equal([]int{4, 5}, affinity)
+
+	// Without a cpuset, the CPUs are used as is
+	assert.NoError(setThreadAffinity(tid+1, []int{0, 1}))
+	assert.Equal([]int{0, 1}, affinity)
+}
+
+func TestPlaceHypervisorThreads(t *testing.T) {
+	assert := assert.New(t)
+	defer fakeTopology(t)()
+
+	// The mock hypervisor reports the test process as its only vCPU
+	// thread, and another process as the VMM.
+	vcpuTid := os.Getpid()
+	vmmPid := vcpuTid + 1
+
+	dir, err := ioutil.TempDir("", "proc")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	for _, tid := range []int{vmmPid, vcpuTid, vmmPid + 1} {
+		assert.NoError(os.MkdirAll(filepath.Join(dir, strconv.Itoa(vmmPid), "task", strconv.Itoa(tid)), DirMode))
+	}
+
+	savedProcPath := procPath
+	procPath = dir
+	defer func() {
+		procPath = savedProcPath
+	}()
+
+	affinities := make(map[int][]int)
+	savedFunc := schedSetAffinityFunc
+	schedSetAffinityFunc = func(tid int, set *unix.CPUSet) error {
+		var cpus []int
+		for cpu := 0; cpu < 8; cpu++ {
+			if set.IsSet(cpu) {
+				cpus = append(cpus, cpu)
+			}
+		}
+		affinities[tid] = cpus
+		return nil
+	}
+	defer func() {
+		schedSetAffinityFunc = savedFunc
+	}()
+
+	s := &Sandbox{
+		hypervisor: &mockHypervisor{mockPid: vmmPid},
+		config:     &SandboxConfig{},
+		containers: map[string]*Container{
+			"foo": {
+				config: &ContainerConfig{
+					Resources: specs.LinuxResources{CPU: &specs.LinuxCPU{Cpus: "4-5"}},
+				},
+			},
+		},
+	}
+
+	// No placement policy
+	assert.NoError(s.placeHypervisorThreads())
+	assert.Empty(affinities)
+
+	s.config.HypervisorConfig.CPUPlacementPolicy = NUMACPUPlacement
+	assert.NoError(s.placeHypervisorThreads())
+	assert.Equal(map[int][]int{
+		vcpuTid:    {4},
+		vmmPid:     {0, 1, 2, 3, 6, 7},
+		vmmPid + 1: {0, 1, 2, 3, 6, 7},
+	}, affinities)
+
+	s.config.HypervisorConfig.HousekeepingCPUs = "0"
+	assert.NoError(s.placeHypervisorThreads())
+	assert.Equal([]int{0}, affinities[vmmPid])
+}
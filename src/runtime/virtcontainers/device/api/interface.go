@@ -88,6 +88,10 @@ type DeviceManager interface {
 	NewDevice(config.DeviceInfo) (Device, error)
 	RemoveDevice(string) error
 	AttachDevice(string, DeviceReceiver) error
+	// AttachDevices attaches all the devices or none of them: when one
+	// of them fails, the ones already attached are detached in reverse
+	// order.
+	AttachDevices([]string, DeviceReceiver) error
 	DetachDevice(string, DeviceReceiver) error
 	IsDeviceAttached(string) bool
 	GetDeviceByID(string) Device
@@ -10,25 +10,67 @@ import (
 )
 
 // MockDeviceReceiver is a fake DeviceReceiver API implementation only used for test
-type MockDeviceReceiver struct{}
+type MockDeviceReceiver struct {
+	// HotplugAddDeviceErr, when set, is called before hotplugging a
+	// device and the error it returns fails the hotplug. It is used to
+	// inject faults.
+	HotplugAddDeviceErr func(Device, config.DeviceType) error
+
+	// Hotplugged lists the IDs of the hotplugged devices, in the order
+	// they were hotplugged.
+	Hotplugged []string
+
+	// Unplugged lists the IDs of the unplugged devices, in the order they
+	// were unplugged.
+	Unplugged []string
+
+	// BlockIndexes are the sandbox block indexes in use.
+	BlockIndexes map[int]bool
+}
 
 // HotplugAddDevice adds a new device
-func (mockDC *MockDeviceReceiver) HotplugAddDevice(Device, config.DeviceType) error {
+func (mockDC *MockDeviceReceiver) HotplugAddDevice(device Device, devType config.DeviceType) error {
+	if mockDC.HotplugAddDeviceErr != nil {
+		if err := mockDC.HotplugAddDeviceErr(device, devType); err != nil {
+			return err
+		}
+	}
+
+	mockDC.Hotplugged = append(mockDC.Hotplugged, device.DeviceID())
 	return nil
 }
 
 // HotplugRemoveDevice removes a device
-func (mockDC *MockDeviceReceiver) HotplugRemoveDevice(Device, config.DeviceType) error {
+func (mockDC *MockDeviceReceiver) HotplugRemoveDevice(device Device, devType config.DeviceType) error {
+	for i, id := range mockDC.Hotplugged {
+		if id == device.DeviceID() {
+			mockDC.Hotplugged = append(mockDC.Hotplugged[:i], mockDC.Hotplugged[i+1:]...)
+			break
+		}
+	}
+
+	mockDC.Unplugged = append(mockDC.Unplugged, device.DeviceID())
 	return nil
 }
 
 // GetAndSetSandboxBlockIndex is used for get and set virtio-blk indexes
 func (mockDC *MockDeviceReceiver) GetAndSetSandboxBlockIndex() (int, error) {
-	return 0, nil
+	if mockDC.BlockIndexes == nil {
+		mockDC.BlockIndexes = make(map[int]bool)
+	}
+
+	index := 0
+	for mockDC.BlockIndexes[index] {
+		index++
+	}
+	mockDC.BlockIndexes[index] = true
+
+	return index, nil
 }
 
 // DecrementSandboxBlockIndex decreases virtio-blk index by one
-func (mockDC *MockDeviceReceiver) UnsetSandboxBlockIndex(int) error {
+func (mockDC *MockDeviceReceiver) UnsetSandboxBlockIndex(index int) error {
+	delete(mockDC.BlockIndexes, index)
 	return nil
 }
 
@@ -16,6 +16,8 @@ import (
 
 	"github.com/go-ini/ini"
 	"golang.org/x/sys/unix"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
 
 // DeviceType indicates device type
@@ -31,6 +33,9 @@ const (
 	// DeviceGeneric is a generic device type
 	DeviceGeneric DeviceType = "generic"
 
+	// DeviceChar is the passthrough character device type
+	DeviceChar DeviceType = "char"
+
 	//VhostUserSCSI - SCSI based vhost-user type
 	VhostUserSCSI = "vhost-user-scsi-pci"
 
@@ -117,6 +122,10 @@ type DeviceInfo struct {
 	// for a nvdimm device in the guest.
 	Pmem bool
 
+	// ReadOnly maps the pmem backing file read-only, the guest cannot
+	// write to it.
+	ReadOnly bool
+
 	// FileMode permission bits for the device.
 	FileMode os.FileMode
 
@@ -152,8 +161,8 @@ type BlockDrive struct {
 	// MmioAddr is used to identify the slot at which the drive is attached (order?).
 	MmioAddr string
 
-	// PCIAddr is the PCI address used to identify the slot at which the drive is attached.
-	PCIAddr string
+	// PCIPath is the PCI path used to identify the slot at which the drive is attached.
+	PCIPath types.PciPath
 
 	// SCSI Address of the block device, in case the device is attached using SCSI driver
 	// SCSI address is in the format SCSI-Id:LUN
@@ -179,6 +188,15 @@ type BlockDrive struct {
 	Pmem bool
 }
 
+// BlockIOThrottle holds the I/O limits of a block drive. The limits are in
+// bytes and operations per second, zero meaning no limit.
+type BlockIOThrottle struct {
+	ReadBps   uint64
+	WriteBps  uint64
+	ReadIOPS  uint64
+	WriteIOPS uint64
+}
+
 // VFIODeviceType indicates VFIO device type
 type VFIODeviceType uint32
 
@@ -221,6 +239,47 @@ type VFIODev struct {
 
 	// Bus of VFIO PCIe device
 	Bus string
+
+	// GuestPciPath is the PCI path of the device in the guest. It is set
+	// by the hypervisor when the device is hotplugged, and left nil if the
+	// hypervisor cannot tell where the device is plugged.
+	GuestPciPath types.PciPath
+}
+
+// CharDevTransport is the transport a host character device is passed
+// through to the guest with.
+type CharDevTransport string
+
+const (
+	// CharDevVirtioSerial passes the character device through a
+	// virtio-serial port.
+	CharDevVirtioSerial CharDevTransport = "virtio-serial"
+
+	// CharDevVsock passes the character device through a vsock stream,
+	// the agent exposing its guest end as a pseudo terminal.
+	CharDevVsock CharDevTransport = "vsock"
+)
+
+// CharDev represents a host character device passed through to the guest
+type CharDev struct {
+	// ID is used to identify this device in the hypervisor options.
+	ID string
+
+	// HostPath is the path of the character device on the host.
+	HostPath string
+
+	// Transport is the transport the device is passed through with.
+	Transport CharDevTransport
+
+	// PortName is the name of the virtio-serial port in the guest.
+	PortName string
+
+	// SocketPath is the host socket the hypervisor exposes the
+	// virtio-serial port on.
+	SocketPath string
+
+	// VsockPort is the vsock port the agent listens on for the device.
+	VsockPort uint32
 }
 
 // RNGDev represents a random number generator device
@@ -229,6 +288,11 @@ type RNGDev struct {
 	ID string
 	// Filename is the file to use as entropy source.
 	Filename string
+	// MaxBytes is the number of bytes the guest can read per Period,
+	// 0 meaning no limit.
+	MaxBytes uint32
+	// Period is the rate limiting period in milliseconds.
+	Period uint32
 }
 
 // VhostUserDeviceAttrs represents data shared by most vhost-user devices
@@ -244,10 +308,19 @@ type VhostUserDeviceAttrs struct {
 	Tag       string
 	CacheSize uint32
 	Cache     string
+	DaemonPid int
 
-	// PCIAddr is the PCI address used to identify the slot at which the drive is attached.
+	// PCIPath is the PCI path used to identify the slot at which the drive is attached.
 	// It is only meaningful for vhost user block devices
-	PCIAddr string
+	PCIPath types.PciPath
+
+	// ReadOnly tells if the backend of a vhost user block device only
+	// serves reads
+	ReadOnly bool
+
+	// Queues is the number of request queues of a vhost user block
+	// device, 0 for the hypervisor default
+	Queues uint32
 
 	// Block index of the device if assigned
 	Index int
@@ -268,10 +341,21 @@ func GetHostPath(devInfo DeviceInfo, vhostUserStoreEnabled bool, vhostUserStoreP
 		return "", fmt.Errorf("Empty path provided for device")
 	}
 
-	// Filter out vhost-user storage devices by device Major numbers.
-	if vhostUserStoreEnabled && devInfo.DevType == "b" &&
-		(devInfo.Major == VhostUserSCSIMajor || devInfo.Major == VhostUserBlkMajor) {
-		return getVhostUserHostPath(devInfo, vhostUserStorePath)
+	if vhostUserStoreEnabled && devInfo.DevType == "b" {
+		// Volumes mapped to a vhost-user backend by its descriptor
+		// are served by the backend socket.
+		backend, err := FindVhostUserBackend(vhostUserStorePath, devInfo.Major, devInfo.Minor)
+		if err != nil {
+			return "", err
+		}
+		if backend != nil {
+			return backend.SocketPath, nil
+		}
+
+		// Filter out vhost-user storage devices by device Major numbers.
+		if devInfo.Major == VhostUserSCSIMajor || devInfo.Major == VhostUserBlkMajor {
+			return getVhostUserHostPath(devInfo, vhostUserStorePath)
+		}
 	}
 
 	ueventPath := filepath.Join(getSysDevPath(devInfo), "uevent")
@@ -338,7 +422,7 @@ func getSysDevPathImpl(devInfo DeviceInfo) string {
 // "<vhostUserStorePath>/block/devices/"
 func getVhostUserHostPath(devInfo DeviceInfo, vhostUserStorePath string) (string, error) {
 	vhostUserDevNodePath := filepath.Join(vhostUserStorePath, "/block/devices/")
-	vhostUserSockPath := filepath.Join(vhostUserStorePath, "/block/sockets/")
+	vhostUserSockPath := vhostUserSocketDir(vhostUserStorePath)
 
 	sockFileName, err := getVhostUserDevName(vhostUserDevNodePath,
 		uint32(devInfo.Major), uint32(devInfo.Minor))
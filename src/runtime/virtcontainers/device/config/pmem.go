@@ -87,6 +87,25 @@ func PmemDeviceInfo(source, destination string) (*DeviceInfo, error) {
 	return device, nil
 }
 
+// LayerImageDeviceInfo returns a DeviceInfo to hotplug the read-only layer
+// image source, holding a filesystem of type fstype, as pmem device.
+// The image must have the PFN signature for the guest to map it with DAX.
+func LayerImageDeviceInfo(source, fstype string) (*DeviceInfo, error) {
+	if !hasPFNSignature(source) {
+		return nil, fmt.Errorf("layer image %v has not PFN signature", source)
+	}
+
+	return &DeviceInfo{
+		HostPath: source,
+		DevType:  "b",
+		Pmem:     true,
+		ReadOnly: true,
+		DriverOptions: map[string]string{
+			"fstype": fstype,
+		},
+	}, nil
+}
+
 // returns true if the file/device path has the PFN signature
 // required to use it as PMEM device and enable DAX.
 // See [1] to know more about the PFN signature.
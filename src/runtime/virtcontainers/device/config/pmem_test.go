@@ -47,3 +47,24 @@ func TestHasPFNSignature(t *testing.T) {
 	b = hasPFNSignature(pfnFile)
 	assert.True(b)
 }
+
+func TestLayerImageDeviceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "layer")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	noPFNFile := filepath.Join(dir, "nopfn")
+	assert.NoError(ioutil.WriteFile(noPFNFile, []byte("layer"), 0644))
+	_, err = LayerImageDeviceInfo(noPFNFile, "ext4")
+	assert.Error(err)
+
+	pfnFile := createPFNFile(assert, dir)
+	di, err := LayerImageDeviceInfo(pfnFile, "erofs")
+	assert.NoError(err)
+	assert.Equal(pfnFile, di.HostPath)
+	assert.True(di.Pmem)
+	assert.True(di.ReadOnly)
+	assert.Equal("erofs", di.DriverOptions["fstype"])
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package config
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// vhostUserBackendDescriptorExt is the extension of the descriptor
+	// stored next to the socket of a vhost-user backend.
+	vhostUserBackendDescriptorExt = ".json"
+
+	// vhostUserMaxQueues is the highest number of virtqueues of a
+	// virtio device.
+	vhostUserMaxQueues = 1024
+
+	// VhostUserDialTimeout is how long the liveness check of a vhost-user
+	// backend waits for its socket to accept a connection.
+	VhostUserDialTimeout = 2 * time.Second
+
+	// vhost-user protocol messages, features and flags used to read the
+	// configuration of a block backend.
+	vhostUserGetFeatures         = 1
+	vhostUserGetProtocolFeatures = 15
+	vhostUserSetProtocolFeatures = 16
+	vhostUserGetConfig           = 24
+
+	vhostUserFProtocolFeatures  = 1 << 30
+	vhostUserProtocolFConfig    = 1 << 9
+	vhostUserVersion            = 0x1
+	vhostUserReplyMask          = 0x4
+	vhostUserHeaderSize         = 12
+	vhostUserConfigHeaderSize   = 12
+	virtioBlkSectorSize         = 512
+	virtioBlkConfigCapacitySize = 8
+)
+
+// VhostUserBackend describes a vhost-user block backend, like a SPDK
+// vhost target, serving a volume. It is read from the JSON descriptor
+// "<socket>.json" stored next to the backend socket, under directory
+// "<vhostUserStorePath>/block/sockets/".
+//
+// A descriptor maps the backend to the volumes whose major and minor
+// numbers it holds, so that the volumes do not need a device node in
+// the vhost-user store.
+type VhostUserBackend struct {
+	// SocketPath is the path of the backend socket.
+	SocketPath string `json:"-"`
+
+	// Major, minor numbers of the volumes served by the backend.
+	Major int64 `json:"major"`
+	Minor int64 `json:"minor"`
+
+	// Capacity is the size of the volume in bytes.
+	Capacity uint64 `json:"capacity"`
+
+	// Queues is the number of request queues served by the backend.
+	Queues uint32 `json:"queues,omitempty"`
+
+	// ReadOnly tells if the backend only serves reads.
+	ReadOnly bool `json:"readonly,omitempty"`
+}
+
+func vhostUserSocketDir(vhostUserStorePath string) string {
+	return filepath.Join(vhostUserStorePath, "/block/sockets/")
+}
+
+// ReadVhostUserBackend reads and validates the descriptor of the vhost-user
+// backend listening on socketPath. It returns nil if the backend has no
+// descriptor.
+func ReadVhostUserBackend(socketPath string) (*VhostUserBackend, error) {
+	data, err := ioutil.ReadFile(socketPath + vhostUserBackendDescriptorExt)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &VhostUserBackend{}
+	if err := json.Unmarshal(data, backend); err != nil {
+		return nil, fmt.Errorf("invalid descriptor of vhost-user backend %s: %v", socketPath, err)
+	}
+	backend.SocketPath = socketPath
+
+	if err := backend.validate(); err != nil {
+		return nil, fmt.Errorf("invalid descriptor of vhost-user backend %s: %v", socketPath, err)
+	}
+
+	return backend, nil
+}
+
+func (b *VhostUserBackend) validate() error {
+	if b.Major <= 0 || b.Minor < 0 {
+		return fmt.Errorf("invalid device number %d:%d", b.Major, b.Minor)
+	}
+
+	if b.Capacity == 0 {
+		return fmt.Errorf("missing capacity")
+	}
+
+	if b.Queues > vhostUserMaxQueues {
+		return fmt.Errorf("%d queues requested, at most %d supported", b.Queues, vhostUserMaxQueues)
+	}
+
+	fi, err := os.Stat(b.SocketPath)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a socket", b.SocketPath)
+	}
+
+	return nil
+}
+
+// FindVhostUserBackend looks in the vhost-user store for the backend serving
+// the volume of major and minor numbers. It returns nil if no descriptor
+// maps a backend to the volume. Invalid descriptors are logged and skipped.
+func FindVhostUserBackend(vhostUserStorePath string, major, minor int64) (*VhostUserBackend, error) {
+	files, err := ioutil.ReadDir(vhostUserSocketDir(vhostUserStorePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), vhostUserBackendDescriptorExt) {
+			continue
+		}
+
+		socketPath := filepath.Join(vhostUserSocketDir(vhostUserStorePath),
+			strings.TrimSuffix(file.Name(), vhostUserBackendDescriptorExt))
+		backend, err := ReadVhostUserBackend(socketPath)
+		if err != nil {
+			pmemLog.WithError(err).Warn("Skipping vhost-user backend")
+			continue
+		}
+
+		if backend != nil && backend.Major == major && backend.Minor == minor {
+			return backend, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// IsVhostUserBackendSocket checks if path is the socket of a vhost-user
+// backend described in the vhost-user store.
+func IsVhostUserBackendSocket(vhostUserStorePath, path string) bool {
+	if filepath.Dir(path) != filepath.Clean(vhostUserSocketDir(vhostUserStorePath)) {
+		return false
+	}
+
+	_, err := os.Stat(path + vhostUserBackendDescriptorExt)
+	return err == nil
+}
+
+// CheckVhostUserBackendCapacityFunc is function pointer used to mock
+// CheckVhostUserBackendCapacity in tests.
+var CheckVhostUserBackendCapacityFunc = CheckVhostUserBackendCapacity
+
+// CheckVhostUserBackendCapacity checks that the capacity the backend serves,
+// read from its virtio-blk configuration, is the capacity of its descriptor.
+func CheckVhostUserBackendCapacity(b *VhostUserBackend) error {
+	conn, err := net.DialTimeout("unix", b.SocketPath, VhostUserDialTimeout)
+	if err != nil {
+		return fmt.Errorf("vhost-user backend %s is not reachable: %v", b.SocketPath, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(VhostUserDialTimeout)); err != nil {
+		return err
+	}
+
+	sectors, err := vhostUserBlkCapacity(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read the capacity of vhost-user backend %s: %v", b.SocketPath, err)
+	}
+
+	if capacity := sectors * virtioBlkSectorSize; capacity != b.Capacity {
+		return fmt.Errorf("vhost-user backend %s serves %d bytes, its descriptor %d", b.SocketPath, capacity, b.Capacity)
+	}
+
+	return nil
+}
+
+// vhostUserBlkCapacity returns the capacity in sectors of the virtio-blk
+// device served on conn. Like QEMU, it requires the backend to support
+// the configuration space messages.
+func vhostUserBlkCapacity(conn io.ReadWriter) (uint64, error) {
+	features, err := vhostUserGetU64(conn, vhostUserGetFeatures)
+	if err != nil {
+		return 0, err
+	}
+	if features&vhostUserFProtocolFeatures == 0 {
+		return 0, fmt.Errorf("backend does not support protocol features")
+	}
+
+	protocolFeatures, err := vhostUserGetU64(conn, vhostUserGetProtocolFeatures)
+	if err != nil {
+		return 0, err
+	}
+	if protocolFeatures&vhostUserProtocolFConfig == 0 {
+		return 0, fmt.Errorf("backend does not support the configuration space")
+	}
+
+	payload := make([]byte, 8)
+	binary.LittleEndian.PutUint64(payload, vhostUserProtocolFConfig)
+	if err := vhostUserSend(conn, vhostUserSetProtocolFeatures, payload); err != nil {
+		return 0, err
+	}
+
+	// The configuration starts with the capacity: offset 0, size 8, no
+	// flags, then the configuration bytes the backend fills in.
+	payload = make([]byte, vhostUserConfigHeaderSize+virtioBlkConfigCapacitySize)
+	binary.LittleEndian.PutUint32(payload[4:], virtioBlkConfigCapacitySize)
+	if err := vhostUserSend(conn, vhostUserGetConfig, payload); err != nil {
+		return 0, err
+	}
+
+	reply, err := vhostUserReceive(conn, vhostUserGetConfig)
+	if err != nil {
+		return 0, err
+	}
+	if len(reply) < len(payload) {
+		return 0, fmt.Errorf("short configuration reply of %d bytes", len(reply))
+	}
+
+	return binary.LittleEndian.Uint64(reply[vhostUserConfigHeaderSize:]), nil
+}
+
+func vhostUserGetU64(conn io.ReadWriter, request uint32) (uint64, error) {
+	if err := vhostUserSend(conn, request, nil); err != nil {
+		return 0, err
+	}
+
+	reply, err := vhostUserReceive(conn, request)
+	if err != nil {
+		return 0, err
+	}
+	if len(reply) != 8 {
+		return 0, fmt.Errorf("invalid reply of %d bytes to request %d", len(reply), request)
+	}
+
+	return binary.LittleEndian.Uint64(reply), nil
+}
+
+func vhostUserSend(w io.Writer, request uint32, payload []byte) error {
+	msg := make([]byte, vhostUserHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(msg[0:], request)
+	binary.LittleEndian.PutUint32(msg[4:], vhostUserVersion)
+	binary.LittleEndian.PutUint32(msg[8:], uint32(len(payload)))
+	copy(msg[vhostUserHeaderSize:], payload)
+
+	_, err := w.Write(msg)
+	return err
+}
+
+func vhostUserReceive(r io.Reader, request uint32) ([]byte, error) {
+	header := make([]byte, vhostUserHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	if got := binary.LittleEndian.Uint32(header[0:]); got != request {
+		return nil, fmt.Errorf("reply to request %d received for request %d", got, request)
+	}
+	if binary.LittleEndian.Uint32(header[4:])&vhostUserReplyMask == 0 {
+		return nil, fmt.Errorf("message received for request %d is not a reply", request)
+	}
+
+	payload := make([]byte, binary.LittleEndian.Uint32(header[8:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// CheckVhostUserBackend checks that a vhost-user backend is alive, by
+// connecting to its socket.
+func CheckVhostUserBackend(socketPath string) error {
+	conn, err := net.DialTimeout("unix", socketPath, VhostUserDialTimeout)
+	if err != nil {
+		return fmt.Errorf("vhost-user backend %s is not reachable: %v", socketPath, err)
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package config
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadVhostUserBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "vhostblk0")
+	l, err := net.Listen("unix", socketPath)
+	assert.NoError(err)
+	defer l.Close()
+
+	// No descriptor
+	backend, err := ReadVhostUserBackend(socketPath)
+	assert.NoError(err)
+	assert.Nil(backend)
+
+	assert.NoError(ioutil.WriteFile(socketPath+".json",
+		[]byte(`{"major": 241, "minor": 1, "capacity": 1073741824, "queues": 4, "readonly": true}`), 0644))
+
+	backend, err = ReadVhostUserBackend(socketPath)
+	assert.NoError(err)
+	assert.Equal(&VhostUserBackend{
+		SocketPath: socketPath,
+		Major:      241,
+		Minor:      1,
+		Capacity:   1073741824,
+		Queues:     4,
+		ReadOnly:   true,
+	}, backend)
+
+	for _, descriptor := range []string{
+		`{"major": 241`,
+		`{"minor": 1, "capacity": 1024}`,
+		`{"major": 241, "minor": 1}`,
+		`{"major": 241, "minor": 1, "capacity": 1024, "queues": 2048}`,
+	} {
+		assert.NoError(ioutil.WriteFile(socketPath+".json", []byte(descriptor), 0644))
+		_, err = ReadVhostUserBackend(socketPath)
+		assert.Error(err, descriptor)
+	}
+
+	// The descriptor must be next to a socket
+	filePath := filepath.Join(dir, "file")
+	assert.NoError(ioutil.WriteFile(filePath, nil, 0644))
+	assert.NoError(ioutil.WriteFile(filePath+".json",
+		[]byte(`{"major": 241, "minor": 1, "capacity": 1024}`), 0644))
+	_, err = ReadVhostUserBackend(filePath)
+	assert.Error(err)
+}
+
+func TestFindVhostUserBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	storePath, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(storePath)
+
+	// No socket directory
+	backend, err := FindVhostUserBackend(storePath, 8, 16)
+	assert.NoError(err)
+	assert.Nil(backend)
+
+	socketDir := vhostUserSocketDir(storePath)
+	assert.NoError(os.MkdirAll(socketDir, 0750))
+
+	for name, descriptor := range map[string]string{
+		"vhostblk0": `{"major": 8, "minor": 16, "capacity": 1024}`,
+		"vhostblk1": `{"major": 8, "minor": 32, "capacity": 1024}`,
+		"invalid":   `{"major": 8, "minor": 48}`,
+	} {
+		socketPath := filepath.Join(socketDir, name)
+		l, err := net.Listen("unix", socketPath)
+		assert.NoError(err)
+		defer l.Close()
+
+		assert.NoError(ioutil.WriteFile(socketPath+".json", []byte(descriptor), 0644))
+	}
+
+	backend, err = FindVhostUserBackend(storePath, 8, 32)
+	assert.NoError(err)
+	assert.NotNil(backend)
+	assert.Equal(filepath.Join(socketDir, "vhostblk1"), backend.SocketPath)
+	assert.True(IsVhostUserBackendSocket(storePath, backend.SocketPath))
+
+	path, err := GetHostPath(DeviceInfo{ContainerPath: "/dev/vdb", DevType: "b", Major: 8, Minor: 32}, true, storePath)
+	assert.NoError(err)
+	assert.Equal(backend.SocketPath, path)
+
+	for _, minor := range []int64{48, 64} {
+		backend, err = FindVhostUserBackend(storePath, 8, minor)
+		assert.NoError(err)
+		assert.Nil(backend)
+	}
+
+	assert.False(IsVhostUserBackendSocket(storePath, filepath.Join(socketDir, "missing")))
+	assert.False(IsVhostUserBackendSocket(storePath, "/dev/sdb"))
+}
+
+func TestCheckVhostUserBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A local socket server stands for the vhost-user backend.
+	socketPath := filepath.Join(dir, "vhostblk0")
+	l, err := net.Listen("unix", socketPath)
+	assert.NoError(err)
+
+	accepted := make(chan struct{})
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			conn.Close()
+		}
+		close(accepted)
+	}()
+
+	assert.NoError(CheckVhostUserBackend(socketPath))
+	<-accepted
+
+	// A backend which went away leaves its socket behind.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	assert.NoError(l.Close())
+	_, err = os.Stat(socketPath)
+	assert.NoError(err)
+	assert.Error(CheckVhostUserBackend(socketPath))
+
+	assert.Error(CheckVhostUserBackend(filepath.Join(dir, "missing")))
+}
+
+// serveVhostUserBlk answers the vhost-user messages reading the capacity
+// of a virtio-blk backend, on the first connection accepted on l.
+func serveVhostUserBlk(l net.Listener, protocolFeatures, sectors uint64) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reply := func(request uint32, payload []byte) {
+		msg := make([]byte, vhostUserHeaderSize+len(payload))
+		binary.LittleEndian.PutUint32(msg[0:], request)
+		binary.LittleEndian.PutUint32(msg[4:], vhostUserVersion|vhostUserReplyMask)
+		binary.LittleEndian.PutUint32(msg[8:], uint32(len(payload)))
+		copy(msg[vhostUserHeaderSize:], payload)
+		conn.Write(msg)
+	}
+
+	u64 := func(v uint64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, v)
+		return b
+	}
+
+	for {
+		header := make([]byte, vhostUserHeaderSize)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		request := binary.LittleEndian.Uint32(header[0:])
+		payload := make([]byte, binary.LittleEndian.Uint32(header[8:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return
+		}
+
+		switch request {
+		case vhostUserGetFeatures:
+			reply(request, u64(vhostUserFProtocolFeatures))
+		case vhostUserGetProtocolFeatures:
+			reply(request, u64(protocolFeatures))
+		case vhostUserGetConfig:
+			copy(payload[vhostUserConfigHeaderSize:], u64(sectors))
+			reply(request, payload)
+		}
+	}
+}
+
+func TestCheckVhostUserBackendCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "vhostblk0")
+	backend := &VhostUserBackend{
+		SocketPath: socketPath,
+		Capacity:   1048576,
+	}
+
+	for _, d := range []struct {
+		protocolFeatures uint64
+		sectors          uint64
+		valid            bool
+	}{
+		{vhostUserProtocolFConfig, 2048, true},
+		{vhostUserProtocolFConfig, 4096, false},
+		{0, 2048, false},
+	} {
+		l, err := net.Listen("unix", socketPath)
+		assert.NoError(err)
+
+		done := make(chan struct{})
+		go func() {
+			serveVhostUserBlk(l, d.protocolFeatures, d.sectors)
+			close(done)
+		}()
+
+		err = CheckVhostUserBackendCapacity(backend)
+		if d.valid {
+			assert.NoError(err)
+		} else {
+			assert.Error(err)
+		}
+
+		<-done
+		assert.NoError(l.Close())
+	}
+
+	assert.Error(CheckVhostUserBackendCapacity(backend))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// VirtioFSShareDevType is the type of the devices sharing a host
+	// directory with the guest through their own virtio-fs daemon.
+	VirtioFSShareDevType = "d"
+
+	// VirtioFSCacheOption is the driver option holding the cache mode of
+	// the virtio-fs daemon of a shared directory.
+	VirtioFSCacheOption = "virtio-fs-cache"
+)
+
+// VirtioFSShareDeviceInfo returns the information of the device sharing the
+// host directory source, mounted at destination in the container, with the
+// guest through its own virtio-fs daemon using the cache mode cache.
+func VirtioFSShareDeviceInfo(source, destination, cache string) (*DeviceInfo, error) {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("virtio-fs share source %s is not a directory", source)
+	}
+
+	return &DeviceInfo{
+		HostPath:      source,
+		ContainerPath: destination,
+		DevType:       VirtioFSShareDevType,
+		DriverOptions: map[string]string{
+			VirtioFSCacheOption: cache,
+		},
+	}, nil
+}
+
+// IsVirtioFSShare tells if the device shares a host directory with the
+// guest through its own virtio-fs daemon.
+func IsVirtioFSShare(devInfo DeviceInfo) bool {
+	return devInfo.DevType == VirtioFSShareDevType
+}
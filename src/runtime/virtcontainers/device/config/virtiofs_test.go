@@ -0,0 +1,40 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtioFSShareDeviceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "virtiofs")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	_, err = VirtioFSShareDeviceInfo(filepath.Join(dir, "missing"), "/data", "auto")
+	assert.Error(err)
+
+	file := filepath.Join(dir, "file")
+	assert.NoError(ioutil.WriteFile(file, []byte("foo"), 0600))
+	_, err = VirtioFSShareDeviceInfo(file, "/data", "auto")
+	assert.Error(err)
+
+	di, err := VirtioFSShareDeviceInfo(dir, "/data", "auto")
+	assert.NoError(err)
+	assert.Equal(dir, di.HostPath)
+	assert.Equal("/data", di.ContainerPath)
+	assert.Equal("auto", di.DriverOptions[VirtioFSCacheOption])
+	assert.True(IsVirtioFSShare(*di))
+
+	assert.False(IsVirtioFSShare(DeviceInfo{DevType: "b"}))
+}
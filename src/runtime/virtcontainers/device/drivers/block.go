@@ -48,24 +48,26 @@ func (device *BlockDevice) Attach(devReceiver api.DeviceReceiver) (err error) {
 	// for the block device in the case where the block device is used as container
 	// rootfs and the predicted block device name needs to be provided to the agent.
 	index, err := devReceiver.GetAndSetSandboxBlockIndex()
+	if err != nil {
+		device.bumpAttachCount(false)
+		return err
+	}
 
 	defer func() {
 		if err != nil {
 			devReceiver.UnsetSandboxBlockIndex(index)
+			device.BlockDrive = nil
 			device.bumpAttachCount(false)
 		}
 	}()
 
-	if err != nil {
-		return err
-	}
-
 	drive := &config.BlockDrive{
-		File:   device.DeviceInfo.HostPath,
-		Format: "raw",
-		ID:     utils.MakeNameID("drive", device.DeviceInfo.ID, maxDevIDSize),
-		Index:  index,
-		Pmem:   device.DeviceInfo.Pmem,
+		File:     device.DeviceInfo.HostPath,
+		Format:   "raw",
+		ID:       utils.MakeNameID("drive", device.DeviceInfo.ID, maxDevIDSize),
+		Index:    index,
+		Pmem:     device.DeviceInfo.Pmem,
+		ReadOnly: device.DeviceInfo.ReadOnly,
 	}
 
 	if fs, ok := device.DeviceInfo.DriverOptions["fstype"]; ok {
@@ -169,7 +171,7 @@ func (device *BlockDevice) Save() persistapi.DeviceState {
 			ID:       drive.ID,
 			Index:    drive.Index,
 			MmioAddr: drive.MmioAddr,
-			PCIAddr:  drive.PCIAddr,
+			PCIPath:  drive.PCIPath.String(),
 			SCSIAddr: drive.SCSIAddr,
 			NvdimmID: drive.NvdimmID,
 			VirtPath: drive.VirtPath,
@@ -195,7 +197,7 @@ func (device *BlockDevice) Load(ds persistapi.DeviceState) {
 		ID:       bd.ID,
 		Index:    bd.Index,
 		MmioAddr: bd.MmioAddr,
-		PCIAddr:  bd.PCIAddr,
+		PCIPath:  loadPciPath(bd.PCIPath),
 		SCSIAddr: bd.SCSIAddr,
 		NvdimmID: bd.NvdimmID,
 		VirtPath: bd.VirtPath,
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package drivers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockDeviceAttachFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	device := NewBlockDevice(&config.DeviceInfo{
+		ID:            "block",
+		HostPath:      "/dev/vdb",
+		ContainerPath: "/dev/vdb",
+		DevType:       "b",
+		DriverOptions: map[string]string{"block-driver": "virtio-blk"},
+	})
+
+	devReceiver := &api.MockDeviceReceiver{
+		HotplugAddDeviceErr: func(api.Device, config.DeviceType) error {
+			return fmt.Errorf("hotplug failure")
+		},
+	}
+
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+	assert.Nil(device.BlockDrive)
+	assert.Empty(devReceiver.BlockIndexes)
+
+	devReceiver.HotplugAddDeviceErr = nil
+	assert.NoError(device.Attach(devReceiver))
+	assert.Equal(uint(1), device.GetAttachCount())
+	assert.Equal(0, device.BlockDrive.Index)
+	assert.Len(devReceiver.BlockIndexes, 1)
+
+	assert.NoError(device.Detach(devReceiver))
+	assert.Empty(devReceiver.BlockIndexes)
+}
+
+func TestVhostUserBlkDeviceAttachFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A local socket server stands for the vhost-user backend.
+	socketPath := filepath.Join(dir, "vhostblk0")
+	l, err := net.Listen("unix", socketPath)
+	assert.NoError(err)
+
+	assert.NoError(ioutil.WriteFile(socketPath+".json",
+		[]byte(`{"major": 8, "minor": 16, "capacity": 1048576, "queues": 4, "readonly": true}`), 0644))
+
+	device := NewVhostUserBlkDevice(&config.DeviceInfo{
+		ID:            "vhost-user-blk",
+		HostPath:      socketPath,
+		ContainerPath: "/dev/vdb",
+		DevType:       "b",
+		DriverOptions: map[string]string{"block-driver": "virtio-blk"},
+	})
+
+	devReceiver := &api.MockDeviceReceiver{
+		HotplugAddDeviceErr: func(api.Device, config.DeviceType) error {
+			return fmt.Errorf("hotplug failure")
+		},
+	}
+
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+	assert.Nil(device.VhostUserDeviceAttrs)
+	assert.Empty(devReceiver.BlockIndexes)
+
+	savedFunc := config.CheckVhostUserBackendCapacityFunc
+	defer func() {
+		config.CheckVhostUserBackendCapacityFunc = savedFunc
+	}()
+
+	// The backend does not serve the capacity of its descriptor.
+	config.CheckVhostUserBackendCapacityFunc = func(*config.VhostUserBackend) error {
+		return fmt.Errorf("capacity mismatch")
+	}
+
+	devReceiver.HotplugAddDeviceErr = nil
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+
+	config.CheckVhostUserBackendCapacityFunc = func(*config.VhostUserBackend) error {
+		return nil
+	}
+
+	assert.NoError(device.Attach(devReceiver))
+	assert.True(device.VhostUserDeviceAttrs.ReadOnly)
+	assert.Equal(uint32(4), device.VhostUserDeviceAttrs.Queues)
+	assert.NoError(device.Detach(devReceiver))
+
+	// A backend which is not listening is not attached.
+	assert.NoError(l.Close())
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+	assert.Empty(devReceiver.Hotplugged)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package drivers
+
+import (
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+)
+
+// CharDevice is a host character device passed through to the guest, either
+// by a virtio-serial port or by a vsock stream.
+type CharDevice struct {
+	*GenericDevice
+	CharDev *config.CharDev
+}
+
+// NewCharDevice creates a new passthrough character device based on DeviceInfo
+func NewCharDevice(devInfo *config.DeviceInfo) *CharDevice {
+	return &CharDevice{
+		GenericDevice: &GenericDevice{
+			ID:         devInfo.ID,
+			DeviceInfo: devInfo,
+		},
+	}
+}
+
+// Attach is standard interface of api.Device, it's used to add device to some
+// DeviceReceiver
+func (device *CharDevice) Attach(devReceiver api.DeviceReceiver) (err error) {
+	skip, err := device.bumpAttachCount(true)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			device.bumpAttachCount(false)
+		}
+	}()
+
+	// The transport is picked by the receiver, depending on what the
+	// hypervisor supports.
+	device.CharDev = &config.CharDev{
+		ID:       utils.MakeNameID("char", device.DeviceInfo.ID, maxDevIDSize),
+		HostPath: device.DeviceInfo.HostPath,
+	}
+
+	deviceLogger().WithField("device", device.DeviceInfo.HostPath).Info("Attaching char device")
+
+	return devReceiver.HotplugAddDevice(device, config.DeviceChar)
+}
+
+// Detach is standard interface of api.Device, it's used to remove device from some
+// DeviceReceiver
+func (device *CharDevice) Detach(devReceiver api.DeviceReceiver) (err error) {
+	skip, err := device.bumpAttachCount(false)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			device.bumpAttachCount(true)
+		}
+	}()
+
+	deviceLogger().WithField("device", device.DeviceInfo.HostPath).Info("Unplugging char device")
+
+	if err = devReceiver.HotplugRemoveDevice(device, config.DeviceChar); err != nil {
+		deviceLogger().WithError(err).Error("Failed to unplug char device")
+		return err
+	}
+
+	return nil
+}
+
+// DeviceType is standard interface of api.Device, it returns device type
+func (device *CharDevice) DeviceType() config.DeviceType {
+	return config.DeviceChar
+}
+
+// GetDeviceInfo returns device information used for creating
+func (device *CharDevice) GetDeviceInfo() interface{} {
+	return device.CharDev
+}
+
+// Save converts Device to DeviceState
+func (device *CharDevice) Save() persistapi.DeviceState {
+	ds := device.GenericDevice.Save()
+	ds.Type = string(device.DeviceType())
+
+	dev := device.CharDev
+	if dev != nil {
+		ds.CharDev = &persistapi.CharDev{
+			ID:         dev.ID,
+			HostPath:   dev.HostPath,
+			Transport:  string(dev.Transport),
+			PortName:   dev.PortName,
+			SocketPath: dev.SocketPath,
+			VsockPort:  dev.VsockPort,
+		}
+	}
+	return ds
+}
+
+// Load loads DeviceState and converts it to specific device
+func (device *CharDevice) Load(ds persistapi.DeviceState) {
+	device.GenericDevice = &GenericDevice{}
+	device.GenericDevice.Load(ds)
+
+	dev := ds.CharDev
+	if dev == nil {
+		return
+	}
+
+	device.CharDev = &config.CharDev{
+		ID:         dev.ID,
+		HostPath:   dev.HostPath,
+		Transport:  config.CharDevTransport(dev.Transport),
+		PortName:   dev.PortName,
+		SocketPath: dev.SocketPath,
+		VsockPort:  dev.VsockPort,
+	}
+}
+
+// It should implement GetAttachCount() and DeviceID() as api.Device implementation
+// here it shares function from *GenericDevice so we don't need duplicate codes
@@ -14,6 +14,7 @@ import (
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/sirupsen/logrus"
 )
 
@@ -43,6 +44,17 @@ func deviceLogger() *logrus.Entry {
 	return api.DeviceLogger()
 }
 
+// loadPciPath parses a persisted PCI path. A malformed path is dropped, the
+// device being then located as if the hypervisor could not tell its path.
+func loadPciPath(path string) types.PciPath {
+	pciPath, err := types.PciPathFromString(path)
+	if err != nil {
+		deviceLogger().WithError(err).Warn("failed to load the PCI path of the device")
+	}
+
+	return pciPath
+}
+
 /*
 Identify PCIe device by /sys/bus/pci/slots/xx/max_bus_speed, sample content "8.0 GT/s PCIe"
 The /sys/bus/pci/slots/xx/address contains bdf, sample content "0000:04:00"
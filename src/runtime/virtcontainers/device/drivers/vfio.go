@@ -76,6 +76,16 @@ func (device *VFIODevice) Attach(devReceiver api.DeviceReceiver) (retErr error)
 		return err
 	}
 
+	// The group devices are only kept if the whole group is attached.
+	defer func() {
+		if retErr != nil {
+			for _, vfio := range device.VfioDevs {
+				delete(AllPCIeDevs, vfio.BDF)
+			}
+			device.VfioDevs = nil
+		}
+	}()
+
 	// Pass all devices in iommu group
 	for i, deviceFile := range deviceFiles {
 		//Get bdf of device eg 0000:00:1c.0
@@ -134,6 +144,9 @@ func (device *VFIODevice) Detach(devReceiver api.DeviceReceiver) (retErr error)
 		return err
 	}
 
+	// The group devices are listed again on the next attach.
+	device.VfioDevs = nil
+
 	deviceLogger().WithFields(logrus.Fields{
 		"device-group": device.DeviceInfo.HostPath,
 		"device-type":  "vfio-passthrough",
@@ -160,10 +173,11 @@ func (device *VFIODevice) Save() persistapi.DeviceState {
 	for _, dev := range devs {
 		if dev != nil {
 			ds.VFIODevs = append(ds.VFIODevs, &persistapi.VFIODev{
-				ID:       dev.ID,
-				Type:     uint32(dev.Type),
-				BDF:      dev.BDF,
-				SysfsDev: dev.SysfsDev,
+				ID:           dev.ID,
+				Type:         uint32(dev.Type),
+				BDF:          dev.BDF,
+				SysfsDev:     dev.SysfsDev,
+				GuestPciPath: dev.GuestPciPath.String(),
 			})
 		}
 	}
@@ -177,10 +191,11 @@ func (device *VFIODevice) Load(ds persistapi.DeviceState) {
 
 	for _, dev := range ds.VFIODevs {
 		device.VfioDevs = append(device.VfioDevs, &config.VFIODev{
-			ID:       dev.ID,
-			Type:     config.VFIODeviceType(dev.Type),
-			BDF:      dev.BDF,
-			SysfsDev: dev.SysfsDev,
+			ID:           dev.ID,
+			Type:         config.VFIODeviceType(dev.Type),
+			BDF:          dev.BDF,
+			SysfsDev:     dev.SysfsDev,
+			GuestPciPath: loadPciPath(dev.GuestPciPath),
 		})
 	}
 }
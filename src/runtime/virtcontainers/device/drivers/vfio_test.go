@@ -7,9 +7,15 @@
 package drivers
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +53,81 @@ func TestGetVFIODetails(t *testing.T) {
 		}
 	}
 }
+
+func TestVFIODeviceSaveLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := types.PciPathFromString("02/03")
+	assert.NoError(err)
+
+	device := &VFIODevice{
+		GenericDevice: &GenericDevice{ID: "vfio"},
+		VfioDevs: []*config.VFIODev{
+			{ID: "vfio-0", BDF: "02:10.0", GuestPciPath: path},
+			{ID: "vfio-1", BDF: "02:10.1"},
+		},
+	}
+
+	ds := device.Save()
+	assert.Equal("02/03", ds.VFIODevs[0].GuestPciPath)
+	assert.Equal("", ds.VFIODevs[1].GuestPciPath)
+
+	loaded := &VFIODevice{}
+	loaded.Load(ds)
+	assert.Equal(device.VfioDevs, loaded.VfioDevs)
+
+	// A malformed path is dropped.
+	ds.VFIODevs[0].GuestPciPath = "02/xx"
+	loaded = &VFIODevice{}
+	loaded.Load(ds)
+	assert.True(loaded.VfioDevs[0].GuestPciPath.IsNil())
+}
+
+func TestVFIODeviceAttachFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	savedSysIOMMUPath := config.SysIOMMUPath
+	config.SysIOMMUPath = tmpDir
+	defer func() {
+		config.SysIOMMUPath = savedSysIOMMUPath
+	}()
+
+	devicesPath := filepath.Join(tmpDir, "2", "devices")
+	assert.NoError(os.MkdirAll(devicesPath, 0750))
+	for _, bdf := range []string{"0000:00:1c.0", "0000:00:1c.1"} {
+		_, err = os.Create(filepath.Join(devicesPath, bdf))
+		assert.NoError(err)
+	}
+
+	device := NewVFIODevice(&config.DeviceInfo{
+		ID:            "vfio",
+		HostPath:      "/dev/vfio/2",
+		ContainerPath: "/dev/vfio/2",
+		DevType:       "c",
+	})
+
+	devReceiver := &api.MockDeviceReceiver{
+		HotplugAddDeviceErr: func(api.Device, config.DeviceType) error {
+			return fmt.Errorf("hotplug failure")
+		},
+	}
+
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+	assert.Empty(device.VfioDevs)
+
+	// The group devices are not listed twice when the group is attached
+	// again.
+	devReceiver.HotplugAddDeviceErr = nil
+	for i := 0; i < 2; i++ {
+		assert.NoError(device.Attach(devReceiver))
+		assert.Len(device.VfioDevs, 2)
+
+		assert.NoError(device.Detach(devReceiver))
+		assert.Empty(device.VfioDevs)
+	}
+}
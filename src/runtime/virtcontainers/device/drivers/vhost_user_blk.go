@@ -55,6 +55,10 @@ func (device *VhostUserBlkDevice) Attach(devReceiver api.DeviceReceiver) (err er
 	updateBlockIndex := isVirtioBlkBlockDriver(device.DeviceInfo.DriverOptions)
 	if updateBlockIndex {
 		index, err = devReceiver.GetAndSetSandboxBlockIndex()
+		if err != nil {
+			device.bumpAttachCount(false)
+			return err
+		}
 	}
 
 	defer func() {
@@ -62,14 +66,22 @@ func (device *VhostUserBlkDevice) Attach(devReceiver api.DeviceReceiver) (err er
 			if updateBlockIndex {
 				devReceiver.UnsetSandboxBlockIndex(index)
 			}
+			device.VhostUserDeviceAttrs = nil
 			device.bumpAttachCount(false)
 		}
 	}()
 
+	backend, err := config.ReadVhostUserBackend(device.DeviceInfo.HostPath)
 	if err != nil {
 		return err
 	}
 
+	// Do not plug a device whose backend is gone, the guest would hang
+	// waiting for it.
+	if err = config.CheckVhostUserBackend(device.DeviceInfo.HostPath); err != nil {
+		return err
+	}
+
 	vAttrs := &config.VhostUserDeviceAttrs{
 		DevID:      utils.MakeNameID("blk", device.DeviceInfo.ID, maxDevIDSize),
 		SocketPath: device.DeviceInfo.HostPath,
@@ -77,12 +89,29 @@ func (device *VhostUserBlkDevice) Attach(devReceiver api.DeviceReceiver) (err er
 		Index:      index,
 	}
 
-	deviceLogger().WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"device":     device.DeviceInfo.HostPath,
 		"SocketPath": vAttrs.SocketPath,
 		"Type":       config.VhostUserBlk,
 		"Index":      index,
-	}).Info("Attaching device")
+	}
+
+	if backend != nil {
+		// The guest reads the capacity from the backend, make sure
+		// it serves the volume of the descriptor.
+		if err = config.CheckVhostUserBackendCapacityFunc(backend); err != nil {
+			return err
+		}
+
+		vAttrs.ReadOnly = backend.ReadOnly
+		vAttrs.Queues = backend.Queues
+
+		fields["Capacity"] = backend.Capacity
+		fields["Queues"] = backend.Queues
+		fields["ReadOnly"] = backend.ReadOnly
+	}
+
+	deviceLogger().WithFields(fields).Info("Attaching device")
 
 	device.VhostUserDeviceAttrs = vAttrs
 	if err = devReceiver.HotplugAddDevice(device, config.VhostUserBlk); err != nil {
@@ -164,8 +193,9 @@ func (device *VhostUserBlkDevice) Save() persistapi.DeviceState {
 			DevID:      vAttr.DevID,
 			SocketPath: vAttr.SocketPath,
 			Type:       string(vAttr.Type),
-			PCIAddr:    vAttr.PCIAddr,
+			PCIPath:    vAttr.PCIPath.String(),
 			Index:      vAttr.Index,
+			ReadOnly:   vAttr.ReadOnly,
 		}
 	}
 	return ds
@@ -185,8 +215,9 @@ func (device *VhostUserBlkDevice) Load(ds persistapi.DeviceState) {
 		DevID:      dev.DevID,
 		SocketPath: dev.SocketPath,
 		Type:       config.DeviceType(dev.Type),
-		PCIAddr:    dev.PCIAddr,
+		PCIPath:    loadPciPath(dev.PCIPath),
 		Index:      dev.Index,
+		ReadOnly:   dev.ReadOnly,
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 )
 
@@ -19,6 +20,24 @@ type VhostUserFSDevice struct {
 	config.VhostUserDeviceAttrs
 }
 
+// NewVhostUserFSDevice creates a new virtio-fs vhost-user device sharing the
+// host directory described by DeviceInfo.
+func NewVhostUserFSDevice(devInfo *config.DeviceInfo) *VhostUserFSDevice {
+	return &VhostUserFSDevice{
+		GenericDevice: &GenericDevice{
+			ID:         devInfo.ID,
+			DeviceInfo: devInfo,
+		},
+	}
+}
+
+// isShare tells if the device shares a host directory through its own
+// virtio-fs daemon, and is then hotplugged, rather than being the sandbox
+// shared directory appended to the VM before it boots.
+func (device *VhostUserFSDevice) isShare() bool {
+	return device.DeviceInfo != nil && config.IsVirtioFSShare(*device.DeviceInfo)
+}
+
 // Device interface
 
 func (device *VhostUserFSDevice) Attach(devReceiver api.DeviceReceiver) (err error) {
@@ -36,6 +55,19 @@ func (device *VhostUserFSDevice) Attach(devReceiver api.DeviceReceiver) (err err
 		}
 	}()
 
+	device.Type = device.DeviceType()
+
+	if device.isShare() {
+		// The ID is used as the tag the guest mounts the share with.
+		device.DevID = utils.MakeNameID("fs", device.DeviceInfo.ID, maxDevIDSize)
+		device.Tag = device.DevID
+		device.Cache = device.DeviceInfo.DriverOptions[config.VirtioFSCacheOption]
+
+		deviceLogger().WithField("device", device.DeviceInfo.HostPath).Info("Attaching virtio-fs share")
+
+		return devReceiver.HotplugAddDevice(device, config.VhostUserFS)
+	}
+
 	// generate a unique ID to be used for hypervisor commandline fields
 	randBytes, err := utils.GenerateRandomBytes(8)
 	if err != nil {
@@ -44,14 +76,28 @@ func (device *VhostUserFSDevice) Attach(devReceiver api.DeviceReceiver) (err err
 	id := hex.EncodeToString(randBytes)
 
 	device.DevID = id
-	device.Type = device.DeviceType()
 
 	return devReceiver.AppendDevice(device)
 }
 
 func (device *VhostUserFSDevice) Detach(devReceiver api.DeviceReceiver) error {
-	_, err := device.bumpAttachCount(false)
-	return err
+	skip, err := device.bumpAttachCount(false)
+	if err != nil {
+		return err
+	}
+	if skip || !device.isShare() {
+		return nil
+	}
+
+	deviceLogger().WithField("device", device.DeviceInfo.HostPath).Info("Unplugging virtio-fs share")
+
+	if err := devReceiver.HotplugRemoveDevice(device, config.VhostUserFS); err != nil {
+		deviceLogger().WithError(err).Error("Failed to unplug virtio-fs share")
+		device.bumpAttachCount(true)
+		return err
+	}
+
+	return nil
 }
 
 func (device *VhostUserFSDevice) DeviceType() config.DeviceType {
@@ -63,3 +109,40 @@ func (device *VhostUserFSDevice) GetDeviceInfo() interface{} {
 	device.Type = device.DeviceType()
 	return &device.VhostUserDeviceAttrs
 }
+
+// Save converts Device to DeviceState
+func (device *VhostUserFSDevice) Save() persistapi.DeviceState {
+	ds := device.GenericDevice.Save()
+	ds.Type = string(device.DeviceType())
+	ds.VhostUserDev = &persistapi.VhostUserDeviceAttrs{
+		DevID:      device.DevID,
+		SocketPath: device.SocketPath,
+		Type:       string(device.Type),
+		PCIPath:    device.PCIPath.String(),
+		Tag:        device.Tag,
+		Cache:      device.Cache,
+		DaemonPid:  device.DaemonPid,
+	}
+	return ds
+}
+
+// Load loads DeviceState and converts it to specific device
+func (device *VhostUserFSDevice) Load(ds persistapi.DeviceState) {
+	device.GenericDevice = &GenericDevice{}
+	device.GenericDevice.Load(ds)
+
+	dev := ds.VhostUserDev
+	if dev == nil {
+		return
+	}
+
+	device.VhostUserDeviceAttrs = config.VhostUserDeviceAttrs{
+		DevID:      dev.DevID,
+		SocketPath: dev.SocketPath,
+		Type:       config.DeviceType(dev.Type),
+		PCIPath:    loadPciPath(dev.PCIPath),
+		Tag:        dev.Tag,
+		Cache:      dev.Cache,
+		DaemonPid:  dev.DaemonPid,
+	}
+}
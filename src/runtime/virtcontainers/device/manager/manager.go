@@ -51,6 +51,10 @@ type deviceManager struct {
 	vhostUserStoreEnabled bool
 	vhostUserStorePath    string
 
+	// charDevicePaths are the glob patterns of the host character
+	// devices passed through to the guest
+	charDevicePaths []string
+
 	devices map[string]api.Device
 	sync.RWMutex
 }
@@ -60,10 +64,11 @@ func deviceLogger() *logrus.Entry {
 }
 
 // NewDeviceManager creates a deviceManager object behaved as api.DeviceManager
-func NewDeviceManager(blockDriver string, vhostUserStoreEnabled bool, vhostUserStorePath string, devices []api.Device) api.DeviceManager {
+func NewDeviceManager(blockDriver string, vhostUserStoreEnabled bool, vhostUserStorePath string, charDevicePaths []string, devices []api.Device) api.DeviceManager {
 	dm := &deviceManager{
 		vhostUserStoreEnabled: vhostUserStoreEnabled,
 		vhostUserStorePath:    vhostUserStorePath,
+		charDevicePaths:       charDevicePaths,
 		devices:               make(map[string]api.Device),
 	}
 	if blockDriver == VirtioMmio {
@@ -96,11 +101,50 @@ func (dm *deviceManager) findDeviceByMajorMinor(major, minor int64) api.Device {
 	return nil
 }
 
+// findPmemDeviceByPath finds the pmem device backed by the file hostPath.
+func (dm *deviceManager) findPmemDeviceByPath(hostPath string) api.Device {
+	for _, dev := range dm.devices {
+		b, ok := dev.(*drivers.BlockDevice)
+		if !ok {
+			continue
+		}
+
+		// The drive of an attached device is all that is left of it
+		// once restored from the persisted state.
+		if b.BlockDrive != nil {
+			if b.BlockDrive.Pmem && b.BlockDrive.File == hostPath {
+				return dev
+			}
+		} else if b.DeviceInfo.Pmem && b.DeviceInfo.HostPath == hostPath {
+			return dev
+		}
+	}
+	return nil
+}
+
+// findVirtioFSShareDevice finds the device sharing the same host directory
+// as devInfo, with the same cache mode.
+func (dm *deviceManager) findVirtioFSShareDevice(devInfo config.DeviceInfo) api.Device {
+	cache := devInfo.DriverOptions[config.VirtioFSCacheOption]
+	for _, dev := range dm.devices {
+		fs, ok := dev.(*drivers.VhostUserFSDevice)
+		if !ok || fs.DeviceInfo == nil || !config.IsVirtioFSShare(*fs.DeviceInfo) {
+			continue
+		}
+
+		if fs.DeviceInfo.HostPath == devInfo.HostPath &&
+			fs.DeviceInfo.DriverOptions[config.VirtioFSCacheOption] == cache {
+			return dev
+		}
+	}
+	return nil
+}
+
 // createDevice creates one device based on DeviceInfo
 func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device, err error) {
-	// pmem device may points to block devices or raw files,
-	// do not change its HostPath.
-	if !devInfo.Pmem {
+	// pmem device may points to block devices or raw files, and
+	// virtio-fs shares to directories, do not change their HostPath.
+	if !devInfo.Pmem && !config.IsVirtioFSShare(devInfo) {
 		path, err := config.GetHostPathFunc(devInfo, dm.vhostUserStoreEnabled, dm.vhostUserStorePath)
 		if err != nil {
 			return nil, err
@@ -114,7 +158,17 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 		}
 	}()
 
-	if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
+	// pmem devices, like the layer images shared by the containers, are
+	// backed by files rather than by device nodes.
+	if devInfo.Pmem {
+		if existingDev := dm.findPmemDeviceByPath(devInfo.HostPath); existingDev != nil {
+			return existingDev, nil
+		}
+	} else if config.IsVirtioFSShare(devInfo) {
+		if existingDev := dm.findVirtioFSShareDevice(devInfo); existingDev != nil {
+			return existingDev, nil
+		}
+	} else if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
 		return existingDev, nil
 	}
 
@@ -123,9 +177,11 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 	if devInfo.ID, err = dm.newDeviceID(); err != nil {
 		return nil, err
 	}
-	if isVFIO(devInfo.HostPath) {
+	if config.IsVirtioFSShare(devInfo) {
+		return drivers.NewVhostUserFSDevice(&devInfo), nil
+	} else if isVFIO(devInfo.HostPath) {
 		return drivers.NewVFIODevice(&devInfo), nil
-	} else if isVhostUserBlk(devInfo) {
+	} else if isVhostUserBlk(devInfo) || dm.isVhostUserBackend(devInfo) {
 		if devInfo.DriverOptions == nil {
 			devInfo.DriverOptions = make(map[string]string)
 		}
@@ -137,12 +193,21 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 		}
 		devInfo.DriverOptions["block-driver"] = dm.blockDriver
 		return drivers.NewBlockDevice(&devInfo), nil
+	} else if isPassthroughChar(devInfo, dm.charDevicePaths) {
+		return drivers.NewCharDevice(&devInfo), nil
 	} else {
 		deviceLogger().WithField("device", devInfo.HostPath).Info("Device has not been passed to the container")
 		return drivers.NewGenericDevice(&devInfo), nil
 	}
 }
 
+// isVhostUserBackend checks if the device is a volume mapped to a vhost-user
+// backend by its descriptor in the vhost-user store.
+func (dm *deviceManager) isVhostUserBackend(devInfo config.DeviceInfo) bool {
+	return dm.vhostUserStoreEnabled && devInfo.DevType == "b" &&
+		config.IsVhostUserBackendSocket(dm.vhostUserStorePath, devInfo.HostPath)
+}
+
 // NewDevice creates a device based on specified DeviceInfo
 func (dm *deviceManager) NewDevice(devInfo config.DeviceInfo) (api.Device, error) {
 	dm.Lock()
@@ -204,6 +269,40 @@ func (dm *deviceManager) AttachDevice(id string, dr api.DeviceReceiver) error {
 	return nil
 }
 
+func (dm *deviceManager) AttachDevices(ids []string, dr api.DeviceReceiver) (err error) {
+	dm.Lock()
+	defer dm.Unlock()
+
+	var attached []api.Device
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for i := len(attached) - 1; i >= 0; i-- {
+			d := attached[i]
+			if detachErr := d.Detach(dr); detachErr != nil {
+				deviceLogger().WithError(detachErr).WithField("device", d.DeviceID()).
+					Error("Failed to detach device during rollback")
+			}
+		}
+	}()
+
+	for _, id := range ids {
+		d, ok := dm.devices[id]
+		if !ok {
+			return ErrDeviceNotExist
+		}
+
+		if err = d.Attach(dr); err != nil {
+			return err
+		}
+		attached = append(attached, d)
+	}
+
+	return nil
+}
+
 func (dm *deviceManager) DetachDevice(id string, dr api.DeviceReceiver) error {
 	dm.Lock()
 	defer dm.Unlock()
@@ -272,6 +371,10 @@ func (dm *deviceManager) LoadDevices(devStates []persistapi.DeviceState) {
 			dev = &drivers.VhostUserBlkDevice{}
 		case config.VhostUserNet:
 			dev = &drivers.VhostUserNetDevice{}
+		case config.VhostUserFS:
+			dev = &drivers.VhostUserFSDevice{}
+		case config.DeviceChar:
+			dev = &drivers.CharDevice{}
 		default:
 			deviceLogger().WithField("device-type", ds.Type).Warning("unrecognized device type is detected")
 			// continue the for loop
@@ -9,6 +9,7 @@ package manager
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -170,6 +171,34 @@ func TestAttachGenericDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAttachCharDevice(t *testing.T) {
+	dm := &deviceManager{
+		blockDriver:     VirtioBlock,
+		devices:         make(map[string]api.Device),
+		charDevicePaths: []string{"/dev/ttyUSB*"},
+	}
+	path := "/dev/ttyUSB0"
+	deviceInfo := config.DeviceInfo{
+		HostPath:      path,
+		ContainerPath: path,
+		DevType:       "c",
+	}
+
+	device, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	charDevice, ok := device.(*drivers.CharDevice)
+	assert.True(t, ok)
+
+	devReceiver := &api.MockDeviceReceiver{}
+	err = device.Attach(devReceiver)
+	assert.Nil(t, err)
+	assert.Equal(t, path, charDevice.CharDev.HostPath)
+	assert.NotEmpty(t, charDevice.CharDev.ID)
+
+	err = device.Detach(devReceiver)
+	assert.Nil(t, err)
+}
+
 func TestAttachBlockDevice(t *testing.T) {
 	dm := &deviceManager{
 		blockDriver: VirtioBlock,
@@ -205,6 +234,151 @@ func TestAttachBlockDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestNewPmemDevice(t *testing.T) {
+	assert := assert.New(t)
+	dm := &deviceManager{
+		blockDriver: VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+
+	layerInfo := func(path string) config.DeviceInfo {
+		return config.DeviceInfo{
+			HostPath: path,
+			DevType:  "b",
+			Pmem:     true,
+		}
+	}
+
+	// pmem devices backed by the same file are shared.
+	device, err := dm.NewDevice(layerInfo("/var/lib/layers/base.img"))
+	assert.NoError(err)
+	_, ok := device.(*drivers.BlockDevice)
+	assert.True(ok)
+
+	shared, err := dm.NewDevice(layerInfo("/var/lib/layers/base.img"))
+	assert.NoError(err)
+	assert.Equal(device.DeviceID(), shared.DeviceID())
+
+	other, err := dm.NewDevice(layerInfo("/var/lib/layers/app.img"))
+	assert.NoError(err)
+	assert.NotEqual(device.DeviceID(), other.DeviceID())
+
+	// Once attached, the device is found by its drive.
+	devReceiver := &api.MockDeviceReceiver{}
+	assert.NoError(dm.AttachDevice(device.DeviceID(), devReceiver))
+	device.(*drivers.BlockDevice).DeviceInfo = &config.DeviceInfo{}
+
+	shared, err = dm.NewDevice(layerInfo("/var/lib/layers/base.img"))
+	assert.NoError(err)
+	assert.Equal(device.DeviceID(), shared.DeviceID())
+
+	assert.NoError(dm.RemoveDevice(other.DeviceID()))
+	assert.Nil(dm.GetDeviceByID(other.DeviceID()))
+}
+
+func TestNewVirtioFSShareDevice(t *testing.T) {
+	assert := assert.New(t)
+	dm := &deviceManager{
+		blockDriver: VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+
+	shareInfo := func(path, cache string) config.DeviceInfo {
+		return config.DeviceInfo{
+			HostPath:      path,
+			ContainerPath: "/data",
+			DevType:       config.VirtioFSShareDevType,
+			DriverOptions: map[string]string{config.VirtioFSCacheOption: cache},
+		}
+	}
+
+	// shares of the same directory with the same cache mode are shared.
+	device, err := dm.NewDevice(shareInfo("/srv/data", "auto"))
+	assert.NoError(err)
+	_, ok := device.(*drivers.VhostUserFSDevice)
+	assert.True(ok)
+
+	shared, err := dm.NewDevice(shareInfo("/srv/data", "auto"))
+	assert.NoError(err)
+	assert.Equal(device.DeviceID(), shared.DeviceID())
+
+	other, err := dm.NewDevice(shareInfo("/srv/data", "none"))
+	assert.NoError(err)
+	assert.NotEqual(device.DeviceID(), other.DeviceID())
+
+	// the share is hotplugged, and named after its ID
+	devReceiver := &api.MockDeviceReceiver{}
+	assert.NoError(dm.AttachDevice(device.DeviceID(), devReceiver))
+	attrs, ok := device.GetDeviceInfo().(*config.VhostUserDeviceAttrs)
+	assert.True(ok)
+	assert.Equal(config.VhostUserFS, string(attrs.Type))
+	assert.Equal("auto", attrs.Cache)
+	assert.NotEmpty(attrs.Tag)
+	assert.Equal(attrs.DevID, attrs.Tag)
+
+	assert.NoError(dm.DetachDevice(device.DeviceID(), devReceiver))
+	assert.NoError(dm.RemoveDevice(other.DeviceID()))
+	assert.Nil(dm.GetDeviceByID(other.DeviceID()))
+}
+
+func TestAttachDevicesRollback(t *testing.T) {
+	assert := assert.New(t)
+	dm := &deviceManager{
+		blockDriver: VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+
+	var ids []string
+	for i := 1; i <= 3; i++ {
+		path := fmt.Sprintf("/dev/vd%c", 'a'+i)
+		device, err := dm.NewDevice(config.DeviceInfo{
+			HostPath:      path,
+			ContainerPath: path,
+			DevType:       "b",
+			Major:         252,
+			Minor:         int64(i),
+		})
+		assert.NoError(err)
+		ids = append(ids, device.DeviceID())
+	}
+
+	// The third device fails to hotplug, the first two are unplugged
+	// in reverse order and their block indexes are released.
+	hotplugErr := fmt.Errorf("hotplug failure")
+	devReceiver := &api.MockDeviceReceiver{
+		HotplugAddDeviceErr: func(device api.Device, devType config.DeviceType) error {
+			if device.DeviceID() == ids[2] {
+				return hotplugErr
+			}
+			return nil
+		},
+	}
+
+	err := dm.AttachDevices(ids, devReceiver)
+	assert.Equal(hotplugErr, err)
+	assert.Empty(devReceiver.Hotplugged)
+	assert.Equal([]string{ids[1], ids[0]}, devReceiver.Unplugged)
+	assert.Empty(devReceiver.BlockIndexes)
+	for _, id := range ids {
+		assert.False(dm.IsDeviceAttached(id))
+	}
+
+	// Unknown devices fail the attachment as well.
+	devReceiver = &api.MockDeviceReceiver{}
+	err = dm.AttachDevices([]string{ids[0], "unknown"}, devReceiver)
+	assert.Equal(ErrDeviceNotExist, err)
+	assert.Empty(devReceiver.Hotplugged)
+	assert.False(dm.IsDeviceAttached(ids[0]))
+
+	err = dm.AttachDevices(ids, devReceiver)
+	assert.NoError(err)
+	assert.Equal(ids, devReceiver.Hotplugged)
+	assert.Len(devReceiver.BlockIndexes, 3)
+	for _, id := range ids {
+		assert.True(dm.IsDeviceAttached(id))
+	}
+}
+
 func TestAttachVhostUserBlkDevice(t *testing.T) {
 	rootEnabled := true
 	tc := ktu.NewTestConstraint(false)
@@ -231,8 +405,9 @@ func TestAttachVhostUserBlkDevice(t *testing.T) {
 	assert.Nil(t, err)
 	err = os.MkdirAll(vhostUserSockPath, dirMode)
 	assert.Nil(t, err)
-	_, err = os.Create(deviceSockPath)
+	l, err := net.Listen("unix", deviceSockPath)
 	assert.Nil(t, err)
+	defer l.Close()
 
 	// mknod requires root privilege, call mock function for non-root to
 	// get VhostUserBlk device type.
@@ -282,8 +457,83 @@ func TestAttachVhostUserBlkDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAttachVhostUserBackendDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	dm := &deviceManager{
+		blockDriver:           VirtioBlock,
+		devices:               make(map[string]api.Device),
+		vhostUserStoreEnabled: true,
+		vhostUserStorePath:    tmpDir,
+	}
+
+	// The volume is mapped to the backend by the descriptor, whatever its
+	// major number and without a device node in the store.
+	vhostUserSockPath := filepath.Join(tmpDir, "/block/sockets/")
+	assert.NoError(os.MkdirAll(vhostUserSockPath, dirMode))
+
+	deviceSockPath := filepath.Join(vhostUserSockPath, "spdk0")
+	l, err := net.Listen("unix", deviceSockPath)
+	assert.NoError(err)
+	defer l.Close()
+
+	assert.NoError(ioutil.WriteFile(deviceSockPath+".json",
+		[]byte(`{"major": 8, "minor": 32, "capacity": 4096, "queues": 2}`), fileMode0640))
+
+	deviceInfo := config.DeviceInfo{
+		ContainerPath: "/dev/vdb",
+		DevType:       "b",
+		Major:         8,
+		Minor:         32,
+	}
+
+	device, err := dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	vDevice, ok := device.(*drivers.VhostUserBlkDevice)
+	assert.True(ok)
+	assert.Equal(deviceSockPath, vDevice.DeviceInfo.HostPath)
+
+	savedFunc := config.CheckVhostUserBackendCapacityFunc
+	defer func() {
+		config.CheckVhostUserBackendCapacityFunc = savedFunc
+	}()
+	config.CheckVhostUserBackendCapacityFunc = func(*config.VhostUserBackend) error {
+		return nil
+	}
+
+	devReceiver := &api.MockDeviceReceiver{}
+	assert.NoError(device.Attach(devReceiver))
+	assert.False(vDevice.VhostUserDeviceAttrs.ReadOnly)
+	assert.Equal(uint32(2), vDevice.VhostUserDeviceAttrs.Queues)
+	assert.NoError(device.Detach(devReceiver))
+
+	// A backend added to the store is found without restarting anything.
+	otherSockPath := filepath.Join(vhostUserSockPath, "spdk1")
+	l1, err := net.Listen("unix", otherSockPath)
+	assert.NoError(err)
+	defer l1.Close()
+
+	assert.NoError(ioutil.WriteFile(otherSockPath+".json",
+		[]byte(`{"major": 8, "minor": 48, "capacity": 4096}`), fileMode0640))
+
+	deviceInfo.Minor = 48
+	device, err = dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	_, ok = device.(*drivers.VhostUserBlkDevice)
+	assert.True(ok)
+
+	// A backend which stopped listening is not attached.
+	assert.NoError(l1.Close())
+	assert.Error(device.Attach(devReceiver))
+	assert.Equal(uint(0), device.GetAttachCount())
+}
+
 func TestAttachDetachDevice(t *testing.T) {
-	dm := NewDeviceManager(VirtioSCSI, false, "", nil)
+	dm := NewDeviceManager(VirtioSCSI, false, "", nil, nil)
 
 	path := "/dev/hda"
 	deviceInfo := config.DeviceInfo{
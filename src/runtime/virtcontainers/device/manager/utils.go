@@ -42,6 +42,22 @@ func isBlock(devInfo config.DeviceInfo) bool {
 	return devInfo.DevType == "b"
 }
 
+// isPassthroughChar checks if the device is a character device whose host
+// path matches one of the passthrough patterns.
+func isPassthroughChar(devInfo config.DeviceInfo, patterns []string) bool {
+	if devInfo.DevType != "c" {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if match, _ := filepath.Match(pattern, devInfo.HostPath); match {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsVFIOLargeBarSpaceDevice checks if the device is a large bar space device.
 func IsVFIOLargeBarSpaceDevice(hostPath string) (bool, error) {
 	if !isVFIO(hostPath) {
@@ -56,6 +56,31 @@ func TestIsBlock(t *testing.T) {
 	}
 }
 
+func TestIsPassthroughChar(t *testing.T) {
+	type testData struct {
+		hostPath string
+		devType  string
+		expected bool
+	}
+
+	patterns := []string{"/dev/ttyUSB*", "/dev/ttyS0"}
+
+	data := []testData{
+		{"/dev/ttyUSB0", "c", true},
+		{"/dev/ttyS0", "c", true},
+		{"/dev/ttyS1", "c", false},
+		{"/dev/ttyUSB0", "b", false},
+		{"/dev/null", "c", false},
+	}
+
+	for _, d := range data {
+		isPassthroughChar := isPassthroughChar(config.DeviceInfo{HostPath: d.hostPath, DevType: d.devType}, patterns)
+		assert.Equal(t, d.expected, isPassthroughChar)
+	}
+
+	assert.False(t, isPassthroughChar(config.DeviceInfo{HostPath: "/dev/ttyS0", DevType: "c"}, nil))
+}
+
 func TestIsVhostUserBlk(t *testing.T) {
 	type testData struct {
 		major    int64
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
 
 // Endpoint represents a physical or virtual network interface.
@@ -17,11 +18,11 @@ type Endpoint interface {
 	Name() string
 	HardwareAddr() string
 	Type() EndpointType
-	PciAddr() string
+	PciPath() types.PciPath
 	NetworkPair() *NetworkInterfacePair
 
 	SetProperties(NetworkInfo)
-	SetPciAddr(string)
+	SetPciPath(types.PciPath)
 	Attach(hypervisor) error
 	Detach(netNsCreated bool, netNsPath string) error
 	HotAttach(h hypervisor) error
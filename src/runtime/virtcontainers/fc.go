@@ -83,6 +83,9 @@ const (
 // Specify the minimum version of firecracker supported
 var fcMinSupportedVersion = semver.MustParse("0.21.1")
 
+// Specify the minimum version of firecracker providing an entropy device
+var fcEntropyMinVersion = semver.MustParse("1.4.0")
+
 var fcKernelParams = append(commonVirtioblkKernelRootParams, []Param{
 	// The boot source is the first partition of the first block device added
 	{"pci", "off"},
@@ -635,6 +638,39 @@ func (fc *firecracker) fcSetLogger() error {
 	return err
 }
 
+// fcSetEntropy adds an entropy device to the VM, when firecracker provides
+// one. The device gets its entropy from firecracker itself, the entropy
+// source is not configurable.
+func (fc *firecracker) fcSetEntropy() {
+	version := fc.info.Version
+	if version == "" {
+		var err error
+		if version, err = fc.getVersionNumber(); err != nil {
+			fc.Logger().WithError(err).Warn("Not adding entropy device")
+			return
+		}
+	}
+
+	if v, err := semver.Make(version); err != nil || v.LT(fcEntropyMinVersion) {
+		fc.Logger().WithField("version", version).Warnf("Not adding entropy device, firecracker %v or newer is needed", fcEntropyMinVersion)
+		return
+	}
+
+	entropy := &models.EntropyDevice{}
+	if fc.config.EntropyMaxBytes > 0 {
+		refillTime := uint64(fc.config.EntropyPeriod)
+		size := uint64(fc.config.EntropyMaxBytes)
+		entropy.RateLimiter = &models.RateLimiter{
+			Bandwidth: &models.TokenBucket{
+				RefillTime: &refillTime,
+				Size:       &size,
+			},
+		}
+	}
+
+	fc.fcConfig.Entropy = entropy
+}
+
 func (fc *firecracker) fcListenToFifo(fifoName string) (string, error) {
 	fcFifoPath := filepath.Join(fc.vmPath, fifoName)
 	fcFifo, err := fifo.OpenFifo(context.Background(), fcFifoPath, syscall.O_CREAT|syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
@@ -738,6 +774,8 @@ func (fc *firecracker) fcInitConfiguration() error {
 		return err
 	}
 
+	fc.fcSetEntropy()
+
 	fc.state.set(cfReady)
 	for _, d := range fc.pendingDevices {
 		if err := fc.addDevice(d.dev, d.devType); err != nil {
@@ -984,8 +1022,10 @@ func (fc *firecracker) fcAddBlockDrive(drive config.BlockDrive) error {
 	return nil
 }
 
-// Firecracker supports replacing the host drive used once the VM has booted up
-func (fc *firecracker) fcUpdateBlockDrive(path, id string) error {
+// Firecracker supports replacing the host drive used once the VM has booted up,
+// and its rate limiter. The drive is kept when path is empty, and the rate
+// limiter when nil.
+func (fc *firecracker) fcUpdateBlockDrive(path, id string, rateLimiter *models.RateLimiter) error {
 	span, _ := fc.trace("fcUpdateBlockDrive")
 	defer span.Finish()
 
@@ -995,8 +1035,9 @@ func (fc *firecracker) fcUpdateBlockDrive(path, id string) error {
 	driveParams.SetDriveID(id)
 
 	driveFc := &models.PartialDrive{
-		DriveID:    &id,
-		PathOnHost: &path, //This is the only property that can be modified
+		DriveID:     &id,
+		PathOnHost:  path,
+		RateLimiter: rateLimiter,
 	}
 
 	driveParams.SetBody(driveFc)
@@ -1049,6 +1090,7 @@ func (fc *firecracker) addDevice(devInfo interface{}, devType deviceType) error
 func (fc *firecracker) hotplugBlockDevice(drive config.BlockDrive, op operation) (interface{}, error) {
 	var path string
 	var err error
+	var rateLimiter *models.RateLimiter
 	driveID := fcDriveIndexToID(drive.Index)
 
 	if op == addDevice {
@@ -1064,9 +1106,11 @@ func (fc *firecracker) hotplugBlockDevice(drive config.BlockDrive, op operation)
 		// use previous raw file created at createDiskPool, that way
 		// the resource is released by firecracker and it can be destroyed in the host
 		path = filepath.Join(fc.jailerRoot, driveID)
+		// the next drive using the slot starts without I/O limits.
+		rateLimiter = &models.RateLimiter{}
 	}
 
-	return nil, fc.fcUpdateBlockDrive(path, driveID)
+	return nil, fc.fcUpdateBlockDrive(path, driveID, rateLimiter)
 }
 
 // hotplugAddDevice supported in Firecracker VMM
@@ -1117,6 +1161,7 @@ func (fc *firecracker) capabilities() types.Capabilities {
 	defer span.Finish()
 	var caps types.Capabilities
 	caps.SetBlockDeviceHotplugSupport()
+	caps.SetBlockIOThrottleSupport()
 
 	return caps
 }
@@ -1258,6 +1303,46 @@ func (fc *firecracker) isRateLimiterBuiltin() bool {
 	return true
 }
 
+// fcDriveTokenBucket returns the token bucket limiting a drive to the lowest
+// of the read and write rates, firecracker does not limit them separately.
+func fcDriveTokenBucket(read, write uint64) *models.TokenBucket {
+	rate := read
+	if rate == 0 || (write != 0 && write < rate) {
+		rate = write
+	}
+
+	if rate == 0 {
+		return nil
+	}
+
+	refillTime := uint64(1000)
+	return &models.TokenBucket{
+		RefillTime: &refillTime,
+		Size:       &rate,
+	}
+}
+
+// setBlockIOThrottle updates the rate limiter of the drive slot the drive
+// was hotplugged to.
+func (fc *firecracker) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	span, _ := fc.trace("setBlockIOThrottle")
+	defer span.Finish()
+
+	rateLimiter := &models.RateLimiter{
+		Bandwidth: fcDriveTokenBucket(throttle.ReadBps, throttle.WriteBps),
+		Ops:       fcDriveTokenBucket(throttle.ReadIOPS, throttle.WriteIOPS),
+	}
+
+	// The drive keeps its backing file, only its rate limiter is patched.
+	return fc.fcUpdateBlockDrive("", fcDriveIndexToID(drive.Index), rateLimiter)
+}
+
+// setMemoryHostNodes does nothing, firecracker does not bind the VM memory
+// to host nodes.
+func (fc *firecracker) setMemoryHostNodes(nodes []int) error {
+	return nil
+}
+
 // In firecracker, it accepts the size of rate limiter in scaling factors of 2^10(1024)
 // But in kata-defined rate limiter, for better Human-readability, we prefer scaling factors of 10^3(1000).
 // func revertByte reverts num from scaling factors of 1000 to 1024, e.g. 10000000(10MB) to 10485760.
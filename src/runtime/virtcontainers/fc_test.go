@@ -56,3 +56,41 @@ func TestRevertBytes(t *testing.T) {
 	num := revertBytes(testNum)
 	assert.Equal(expectedNum, num)
 }
+
+func TestFCSetEntropy(t *testing.T) {
+	assert := assert.New(t)
+
+	fc := firecracker{fcConfig: &types.FcConfig{}}
+	fc.info.Version = "0.21.1"
+	fc.fcSetEntropy()
+	assert.Nil(fc.fcConfig.Entropy)
+
+	fc.info.Version = "1.4.0"
+	fc.fcSetEntropy()
+	assert.NotNil(fc.fcConfig.Entropy)
+	assert.Nil(fc.fcConfig.Entropy.RateLimiter)
+
+	fc.config.EntropyMaxBytes = 1024
+	fc.config.EntropyPeriod = 500
+	fc.fcSetEntropy()
+	assert.NotNil(fc.fcConfig.Entropy.RateLimiter)
+	assert.Equal(uint64(1024), *fc.fcConfig.Entropy.RateLimiter.Bandwidth.Size)
+	assert.Equal(uint64(500), *fc.fcConfig.Entropy.RateLimiter.Bandwidth.RefillTime)
+}
+
+func TestFCDriveTokenBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(fcDriveTokenBucket(0, 0))
+
+	for _, rates := range [][3]uint64{
+		{1024, 0, 1024},
+		{0, 2048, 2048},
+		{4096, 2048, 2048},
+	} {
+		b := fcDriveTokenBucket(rates[0], rates[1])
+		assert.NotNil(b)
+		assert.Equal(rates[2], *b.Size)
+		assert.Equal(uint64(1000), *b.RefillTime)
+	}
+}
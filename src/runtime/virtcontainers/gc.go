@@ -0,0 +1,439 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+)
+
+// Kinds of the sandbox artifacts the garbage collector removes
+const (
+	GCSandboxStorage = "sandbox-storage"
+	GCVMStorage      = "vm-storage"
+	GCSharedDir      = "shared-dir"
+	GCNetwork        = "network"
+	GCAcrnUUID       = "acrn-uuid"
+	GCJail           = "jail"
+)
+
+// GCConfig configures the collection of the artifacts leaked by the
+// sandboxes whose shim or hypervisor died.
+type GCConfig struct {
+	// InUse lists the IDs of the sandboxes and containers known to be in
+	// use, from the tasks of containerd for instance.
+	InUse []string
+
+	// DryRun only reports the artifacts, leaving them in place.
+	DryRun bool
+}
+
+// GCArtifact is an artifact of an orphaned sandbox.
+type GCArtifact struct {
+	SandboxID string
+	Kind      string
+	Path      string
+	Removed   bool
+	Error     string
+}
+
+// variables rather than consts to allow tests to modify them
+var (
+	// gcGracePeriod protects the sandboxes being created, whose hypervisor
+	// is not running yet, from being collected.
+	gcGracePeriod = time.Minute
+
+	procMountInfo = "/proc/self/mountinfo"
+
+	firecrackerJailDir = func(hypervisorPath string) string {
+		return filepath.Join("/run", storagePathSuffix, filepath.Base(hypervisorPath))
+	}
+)
+
+// gcSandbox gathers what is known of a sandbox found on the host.
+type gcSandbox struct {
+	id    string
+	dirs  []string
+	state *persistapi.SandboxState
+	cs    map[string]persistapi.ContainerState
+}
+
+// processAlive tells if a process is still running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	err := syscall.Kill(pid, syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
+
+// unescapeMountPoint decodes the octal escapes of a mountinfo mount point.
+func unescapeMountPoint(s string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(s)
+}
+
+// mountsUnder returns the mount points found under dir, dir included,
+// deepest first.
+func mountsUnder(dir string) ([]string, error) {
+	f, err := os.Open(procMountInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir = filepath.Clean(dir)
+
+	var mounts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), " ")
+		if len(fields) < 5 {
+			continue
+		}
+
+		m := unescapeMountPoint(fields[4])
+		if m == dir || strings.HasPrefix(m, dir+"/") {
+			mounts = append(mounts, m)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(mounts, func(i, j int) bool {
+		return len(mounts[i]) > len(mounts[j])
+	})
+
+	return mounts, nil
+}
+
+// unmountAndRemove lazily unmounts everything mounted under dir and removes
+// it.
+func unmountAndRemove(dir string) error {
+	mounts, err := mountsUnder(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounts {
+		if err := syscall.Unmount(m, syscall.MNT_DETACH); err != nil && err != syscall.EINVAL {
+			return fmt.Errorf("failed to unmount %s: %v", m, err)
+		}
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// gcNetwork removes what the sandbox added to its network namespace, and
+// the namespace itself when virtcontainers created it.
+func gcNetwork(ctx context.Context, s *Sandbox) error {
+	if s.networkNS.NetNsCreated {
+		n := &Network{}
+		return n.Remove(ctx, &s.networkNS, nil)
+	}
+
+	// Network.Remove expects the namespaces virtcontainers did not create
+	// to be gone with the sandbox, detach the endpoints from the ones left.
+	return doNetNS(s.networkNS.NetNsPath, func(_ ns.NetNS) error {
+		for _, endpoint := range s.networkNS.Endpoints {
+			if endpoint.NetworkPair() == nil {
+				continue
+			}
+
+			if err := xDisconnectVMNetwork(endpoint); err != nil {
+				return err
+			}
+		}
+
+		if ifb, err := netlink.LinkByName("ifb0"); err == nil {
+			return netlink.LinkDel(ifb)
+		}
+
+		return nil
+	})
+}
+
+// gcAcrnUUID marks the UUID of an ACRN VM as free.
+func gcAcrnUUID(driver persistapi.PersistDriver, uuid string) error {
+	relPath := filepath.Join(UUIDPathSuffix, uuidFile)
+
+	data, err := driver.GlobalRead(relPath)
+	if err != nil {
+		return err
+	}
+
+	var info AcrnInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return err
+	}
+
+	info.UUIDAvailability[acrnUUIDsToIdx[uuid]] = UUIDFree
+
+	data, err = json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	return driver.GlobalWrite(relPath, data)
+}
+
+// listSandboxDirs adds the entries of dir to the sandboxes found so far.
+func listSandboxDirs(dir string, sandboxes map[string]*gcSandbox) error {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+
+		id := file.Name()
+		if sandboxes[id] == nil {
+			sandboxes[id] = &gcSandbox{id: id}
+		}
+		sandboxes[id].dirs = append(sandboxes[id].dirs, filepath.Join(dir, id))
+	}
+
+	return nil
+}
+
+// hasDir tells if the sandbox has a directory in parent.
+func (s *gcSandbox) hasDir(parent string) bool {
+	for _, dir := range s.dirs {
+		if filepath.Dir(dir) == filepath.Clean(parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orphaned tells if a sandbox was left behind by a dead shim or hypervisor.
+func (s *gcSandbox) orphaned(inUse map[string]bool) bool {
+	if inUse[s.id] {
+		return false
+	}
+
+	if s.state == nil {
+		// Without state, only the artifacts of a sandbox not updated for
+		// a while are considered leaked.
+		for _, dir := range s.dirs {
+			fi, err := os.Stat(dir)
+			if err != nil || time.Since(fi.ModTime()) < gcGracePeriod {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for cid := range s.cs {
+		if inUse[cid] {
+			return false
+		}
+	}
+
+	hs := s.state.HypervisorState
+	if processAlive(hs.Pid) {
+		return false
+	}
+
+	if hs.Pid <= 0 {
+		// Not started yet, or stopped
+		for _, dir := range s.dirs {
+			if fi, err := os.Stat(dir); err == nil && time.Since(fi.ModTime()) < gcGracePeriod {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// collect removes the artifacts of an orphaned sandbox, or only reports
+// them in dry run mode.
+func (s *gcSandbox) collect(ctx context.Context, driver persistapi.PersistDriver, dryRun bool) []GCArtifact {
+	var artifacts []GCArtifact
+
+	add := func(kind, path string, remove func() error) {
+		artifact := GCArtifact{
+			SandboxID: s.id,
+			Kind:      kind,
+			Path:      path,
+		}
+
+		if !dryRun {
+			if err := remove(); err != nil {
+				artifact.Error = err.Error()
+			} else {
+				artifact.Removed = true
+			}
+		}
+
+		virtLog.WithFields(logrus.Fields{
+			"sandbox": s.id,
+			"kind":    kind,
+			"path":    path,
+			"removed": artifact.Removed,
+			"error":   artifact.Error,
+		}).Info("garbage collected sandbox artifact")
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	if s.state != nil {
+		config := s.state.Config
+
+		sandbox := &Sandbox{id: s.id}
+		sandbox.loadNetwork(s.state.Network)
+		if netNsPath := sandbox.networkNS.NetNsPath; netNsPath != "" {
+			if _, err := os.Stat(netNsPath); err == nil {
+				add(GCNetwork, netNsPath, func() error {
+					return gcNetwork(ctx, sandbox)
+				})
+			}
+		}
+
+		if HypervisorType(config.HypervisorType) == AcrnHypervisor && s.state.HypervisorState.UUID != "" {
+			add(GCAcrnUUID, s.state.HypervisorState.UUID, func() error {
+				return gcAcrnUUID(driver, s.state.HypervisorState.UUID)
+			})
+		}
+
+		if HypervisorType(config.HypervisorType) == FirecrackerHypervisor {
+			jail := filepath.Join(firecrackerJailDir(config.HypervisorConfig.HypervisorPath), s.id)
+			if _, err := os.Stat(jail); err == nil {
+				add(GCJail, jail, func() error {
+					return unmountAndRemove(jail)
+				})
+			}
+		}
+	}
+
+	for _, dir := range s.dirs {
+		dir := dir
+
+		kind := GCVMStorage
+		switch filepath.Dir(dir) {
+		case filepath.Clean(driver.RunStoragePath()):
+			// Removed last, the state being what identifies the sandbox
+			continue
+		case filepath.Clean(kataHostSharedDir()):
+			kind = GCSharedDir
+		case filepath.Clean(firecrackerJailDir(string(FirecrackerHypervisor))):
+			if s.state != nil {
+				// Already collected from the state
+				continue
+			}
+			kind = GCJail
+		}
+
+		add(kind, dir, func() error {
+			return unmountAndRemove(dir)
+		})
+	}
+
+	sandboxDir := filepath.Join(driver.RunStoragePath(), s.id)
+	if _, err := os.Stat(sandboxDir); err == nil {
+		add(GCSandboxStorage, sandboxDir, func() error {
+			return driver.Destroy(s.id)
+		})
+	}
+
+	return artifacts
+}
+
+// GCSandboxes collects the artifacts of the sandboxes left behind when their
+// shim or hypervisor crashed: their storage directories, shared directories
+// and mounts, network namespace and devices, ACRN UUID and firecracker jail.
+// A sandbox is orphaned when it is not in use and its hypervisor is not
+// running.
+func GCSandboxes(ctx context.Context, config GCConfig) ([]GCArtifact, error) {
+	span, ctx := trace(ctx, "GCSandboxes")
+	defer span.Finish()
+
+	driver, err := persist.GetDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxes := make(map[string]*gcSandbox)
+
+	for _, dir := range []string{
+		driver.RunStoragePath(),
+		driver.RunVMStoragePath(),
+		kataHostSharedDir(),
+		firecrackerJailDir(string(FirecrackerHypervisor)),
+	} {
+		if err := listSandboxDirs(dir, sandboxes); err != nil {
+			return nil, err
+		}
+	}
+
+	inUse := make(map[string]bool)
+	for _, id := range config.InUse {
+		inUse[id] = true
+	}
+
+	var ids []string
+	for id := range sandboxes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	artifacts := []GCArtifact{}
+
+	for _, id := range ids {
+		s := sandboxes[id]
+
+		// A new driver for each sandbox as the fs one accumulates the
+		// containers it reads.
+		store, err := persist.GetDriver()
+		if err != nil {
+			return nil, err
+		}
+
+		if ss, cs, err := store.FromDisk(id); err == nil {
+			s.state = &ss
+			s.cs = cs
+		} else if !s.hasDir(driver.RunStoragePath()) {
+			// Not a sandbox: the VMs of the factory, its template and
+			// cache, only live in the VM storage.
+			continue
+		}
+
+		if !s.orphaned(inUse) {
+			continue
+		}
+
+		artifacts = append(artifacts, s.collect(ctx, driver, config.DryRun)...)
+	}
+
+	return artifacts, nil
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+)
+
+func gcArtifactsOf(artifacts []GCArtifact, sandboxID string) map[string]GCArtifact {
+	found := make(map[string]GCArtifact)
+	for _, a := range artifacts {
+		if a.SandboxID == sandboxID {
+			found[a.Kind] = a
+		}
+	}
+
+	return found
+}
+
+func TestMountsUnder(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcMountInfo := procMountInfo
+	defer func() {
+		procMountInfo = savedProcMountInfo
+	}()
+
+	procMountInfo = filepath.Join(dir, "mountinfo")
+	err = ioutil.WriteFile(procMountInfo, []byte(`22 1 8:1 / / rw - ext4 /dev/sda1 rw
+30 22 0:5 / /run/sbx rw - tmpfs tmpfs rw
+31 30 8:1 /a /run/sbx/mounts/a rw - ext4 /dev/sda1 rw
+32 30 8:1 /b /run/sbx/mounts/with\040space rw - ext4 /dev/sda1 rw
+33 22 8:1 /c /run/sbx2 rw - ext4 /dev/sda1 rw
+`), 0600)
+	assert.NoError(err)
+
+	mounts, err := mountsUnder("/run/sbx/")
+	assert.NoError(err)
+	assert.Equal([]string{"/run/sbx/mounts/with space", "/run/sbx/mounts/a", "/run/sbx"}, mounts)
+
+	mounts, err = mountsUnder("/run/none")
+	assert.NoError(err)
+	assert.Empty(mounts)
+}
+
+func TestGCSandboxes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedGracePeriod := gcGracePeriod
+	savedKataHostSharedDir := kataHostSharedDir
+	savedFirecrackerJailDir := firecrackerJailDir
+	defer func() {
+		gcGracePeriod = savedGracePeriod
+		kataHostSharedDir = savedKataHostSharedDir
+		firecrackerJailDir = savedFirecrackerJailDir
+	}()
+
+	// Only the sandboxes of this test are old or have a dead hypervisor
+	gcGracePeriod = time.Hour
+	kataHostSharedDir = func() string {
+		return filepath.Join(dir, "shared")
+	}
+	firecrackerJailDir = func(hypervisorPath string) string {
+		return filepath.Join(dir, filepath.Base(hypervisorPath))
+	}
+
+	cmd := exec.Command("true")
+	assert.NoError(cmd.Run())
+	deadPid := cmd.Process.Pid
+
+	driver, err := persist.GetDriver()
+	assert.NoError(err)
+
+	persistSandbox := func(id string, pid int, hType HypervisorType, uuid string) {
+		store, err := persist.GetDriver()
+		assert.NoError(err)
+
+		err = store.ToDisk(persistapi.SandboxState{
+			SandboxContainer: id,
+			HypervisorState: persistapi.HypervisorState{
+				Pid:  pid,
+				UUID: uuid,
+			},
+			Config: persistapi.SandboxConfig{
+				HypervisorType: string(hType),
+				HypervisorConfig: persistapi.HypervisorConfig{
+					HypervisorPath: "/usr/bin/firecracker",
+				},
+			},
+		}, map[string]persistapi.ContainerState{
+			id + "-container": {},
+		})
+		assert.NoError(err)
+
+		assert.NoError(os.MkdirAll(filepath.Join(driver.RunVMStoragePath(), id), DirMode))
+	}
+
+	persistSandbox("gc-orphan", deadPid, FirecrackerHypervisor, "")
+	assert.NoError(os.MkdirAll(filepath.Join(kataHostSharedDir(), "gc-orphan", "shared"), DirMode))
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "firecracker", "gc-orphan", "root"), DirMode))
+
+	persistSandbox("gc-alive", os.Getpid(), QemuHypervisor, "")
+	persistSandbox("gc-in-use", deadPid, QemuHypervisor, "")
+
+	acrnUUID := acrnIdxToUUIDs[3]
+	persistSandbox("gc-acrn", deadPid, AcrnHypervisor, acrnUUID)
+	var info AcrnInfo
+	info.UUIDAvailability[3] = UUIDBusy
+	data, err := json.Marshal(info)
+	assert.NoError(err)
+	assert.NoError(driver.GlobalWrite(filepath.Join(UUIDPathSuffix, uuidFile), data))
+
+	// Leftovers without any state
+	old := time.Now().Add(-2 * time.Hour)
+	for _, d := range []string{driver.RunStoragePath(), driver.RunVMStoragePath()} {
+		oldDir := filepath.Join(d, "gc-stateless-old")
+		assert.NoError(os.MkdirAll(oldDir, DirMode))
+		assert.NoError(os.Chtimes(oldDir, old, old))
+	}
+
+	// The VMs of the factory are not sandboxes
+	factoryDir := filepath.Join(driver.RunVMStoragePath(), "gc-factory-vm")
+	assert.NoError(os.MkdirAll(factoryDir, DirMode))
+	assert.NoError(os.Chtimes(factoryDir, old, old))
+	defer os.RemoveAll(factoryDir)
+
+	newDir := filepath.Join(driver.RunStoragePath(), "gc-stateless-new")
+	assert.NoError(os.MkdirAll(newDir, DirMode))
+
+	config := GCConfig{
+		InUse:  []string{"gc-in-use-container"},
+		DryRun: true,
+	}
+
+	artifacts, err := GCSandboxes(context.Background(), config)
+	assert.NoError(err)
+
+	orphan := gcArtifactsOf(artifacts, "gc-orphan")
+	assert.Len(orphan, 4)
+	for _, kind := range []string{GCSandboxStorage, GCVMStorage, GCSharedDir, GCJail} {
+		assert.Contains(orphan, kind)
+		assert.False(orphan[kind].Removed)
+		assert.True(dirExists(orphan[kind].Path), kind)
+	}
+	assert.Equal(filepath.Join(dir, "firecracker", "gc-orphan"), orphan[GCJail].Path)
+
+	assert.Empty(gcArtifactsOf(artifacts, "gc-alive"))
+	assert.Empty(gcArtifactsOf(artifacts, "gc-in-use"))
+	assert.Empty(gcArtifactsOf(artifacts, "gc-stateless-new"))
+	assert.Contains(gcArtifactsOf(artifacts, "gc-stateless-old"), GCVMStorage)
+	assert.Contains(gcArtifactsOf(artifacts, "gc-stateless-old"), GCSandboxStorage)
+	assert.Empty(gcArtifactsOf(artifacts, "gc-factory-vm"))
+	assert.Contains(gcArtifactsOf(artifacts, "gc-acrn"), GCAcrnUUID)
+
+	config.DryRun = false
+	artifacts, err = GCSandboxes(context.Background(), config)
+	assert.NoError(err)
+
+	for _, id := range []string{"gc-orphan", "gc-stateless-old", "gc-acrn"} {
+		for kind, a := range gcArtifactsOf(artifacts, id) {
+			assert.True(a.Removed, "%s %s", id, kind)
+			assert.Empty(a.Error)
+			if kind != GCAcrnUUID {
+				assert.False(dirExists(a.Path), a.Path)
+			}
+		}
+	}
+
+	data, err = driver.GlobalRead(filepath.Join(UUIDPathSuffix, uuidFile))
+	assert.NoError(err)
+	assert.NoError(json.Unmarshal(data, &info))
+	assert.Equal(uint8(UUIDFree), info.UUIDAvailability[3])
+
+	for _, id := range []string{"gc-alive", "gc-in-use"} {
+		assert.True(dirExists(filepath.Join(driver.RunStoragePath(), id)))
+		assert.NoError(driver.Destroy(id))
+		os.RemoveAll(filepath.Join(driver.RunVMStoragePath(), id))
+	}
+	assert.True(dirExists(newDir))
+	assert.True(dirExists(factoryDir))
+	os.RemoveAll(newDir)
+}
+
+func dirExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
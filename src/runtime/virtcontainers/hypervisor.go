@@ -63,6 +63,10 @@ const (
 
 	defaultBlockDriver = config.VirtioSCSI
 
+	// defaultEntropyPeriod is the rate limiting period of the entropy
+	// source, in milliseconds, when only the bytes per period are set.
+	defaultEntropyPeriod = 1000
+
 	defaultSocketName        = "kata.sock"
 	defaultSocketDeviceID    = "channel0"
 	defaultSocketChannelName = "agent.channel.0"
@@ -141,6 +145,9 @@ const (
 	// hybridVirtioVsockDev is a hybrid virtio-vsock device supported
 	// only on certain hypervisors, like firecracker.
 	hybridVirtioVsockDev
+
+	// charDev is a host character device passed through to the guest.
+	charDev
 )
 
 type memoryDevice struct {
@@ -307,6 +314,19 @@ type HypervisorConfig struct {
 	// entropy (/dev/random, /dev/urandom or real hardware RNG device)
 	EntropySource string
 
+	// EntropyMaxBytes is the number of bytes the guest can read from
+	// the entropy source per EntropyPeriod, 0 meaning no limit.
+	EntropyMaxBytes uint32
+
+	// EntropyPeriod is the rate limiting period of the entropy source,
+	// in milliseconds.
+	EntropyPeriod uint32
+
+	// EntropyReseedInterval is the interval, in seconds, between two
+	// reseeds of the guest random number generator with entropy from
+	// the entropy source, 0 meaning no periodic reseed.
+	EntropyReseedInterval uint32
+
 	// Shared file system type:
 	//   - virtio-9p (default)
 	//   - virtio-fs
@@ -324,6 +344,18 @@ type HypervisorConfig struct {
 	// File based memory backend root directory
 	FileBackedMemRootDir string
 
+	// CPUPlacementPolicy selects how the hypervisor threads are placed on
+	// the host CPUs and NUMA nodes.
+	CPUPlacementPolicy CPUPlacementPolicy
+
+	// HousekeepingCPUs is the host CPU list where the hypervisor threads
+	// other than the vCPUs run with the NUMA CPU placement policy.
+	// It defaults to the online CPUs not used by the sandbox.
+	HousekeepingCPUs string
+
+	// memoryHostNodes are the host NUMA nodes the VM memory is bound to.
+	memoryHostNodes []int
+
 	// customAssets is a map of assets.
 	// Each value in that map takes precedence over the configured assets.
 	// For example, if there is a value for the "kernel" key in this map,
@@ -406,6 +438,10 @@ type HypervisorConfig struct {
 	// related folders, sockets and device nodes should be.
 	VhostUserStorePath string
 
+	// PassthroughCharDevices are the glob patterns of the host character
+	// devices passed through to the guest.
+	PassthroughCharDevices []string
+
 	// GuestHookPath is the path within the VM that will be used for 'drop-in' hooks
 	GuestHookPath string
 
@@ -459,6 +495,14 @@ func (conf *HypervisorConfig) valid() error {
 		return err
 	}
 
+	if !conf.CPUPlacementPolicy.valid() {
+		return fmt.Errorf("Invalid CPU placement policy %q", conf.CPUPlacementPolicy)
+	}
+
+	if _, err := parseCPUList(conf.HousekeepingCPUs); err != nil {
+		return err
+	}
+
 	if conf.NumVCPUs == 0 {
 		conf.NumVCPUs = defaultVCPUs
 	}
@@ -483,6 +527,10 @@ func (conf *HypervisorConfig) valid() error {
 		conf.Msize9p = defaultMsize9p
 	}
 
+	if conf.EntropyMaxBytes > 0 && conf.EntropyPeriod == 0 {
+		conf.EntropyPeriod = defaultEntropyPeriod
+	}
+
 	return nil
 }
 
@@ -808,4 +856,10 @@ type hypervisor interface {
 
 	// check if hypervisor supports built-in rate limiter.
 	isRateLimiterBuiltin() bool
+
+	// setBlockIOThrottle limits the I/O rate of a hotplugged block drive.
+	setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error
+
+	// setMemoryHostNodes binds the VM memory to other host NUMA nodes.
+	setMemoryHostNodes(nodes []int) error
 }
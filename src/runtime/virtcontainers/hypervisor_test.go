@@ -180,6 +180,11 @@ func TestHypervisorConfigDefaults(t *testing.T) {
 	}
 
 	assert.Exactly(hypervisorConfig, hypervisorConfigDefaultsExpected)
+
+	// The entropy rate limiting period defaults when only the bytes are set
+	hypervisorConfig.EntropyMaxBytes = 1024
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Equal(uint32(defaultEntropyPeriod), hypervisorConfig.EntropyPeriod)
 }
 
 func TestAppendParams(t *testing.T) {
@@ -456,3 +461,22 @@ func TestGenerateVMSocket(t *testing.T) {
 	assert.NotZero(vsock.ContextID)
 	assert.NotZero(vsock.Port)
 }
+
+func TestHypervisorConfigCPUPlacement(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:         fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:          fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath:     fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		CPUPlacementPolicy: NUMACPUPlacement,
+		HousekeepingCPUs:   "0-1",
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+
+	hypervisorConfig.HousekeepingCPUs = "1-0"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.HousekeepingCPUs = ""
+	hypervisorConfig.CPUPlacementPolicy = "foo"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
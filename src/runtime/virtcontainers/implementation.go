@@ -178,3 +178,8 @@ func (impl *VCImpl) ListRoutes(ctx context.Context, sandboxID string) ([]*vcType
 func (impl *VCImpl) CleanupContainer(ctx context.Context, sandboxID, containerID string, force bool) error {
 	return CleanupContainer(ctx, sandboxID, containerID, force)
 }
+
+// GCSandboxes implements the VC function of the same name.
+func (impl *VCImpl) GCSandboxes(ctx context.Context, config GCConfig) ([]GCArtifact, error) {
+	return GCSandboxes(ctx, config)
+}
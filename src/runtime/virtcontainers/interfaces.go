@@ -55,6 +55,8 @@ type VC interface {
 	ListRoutes(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error)
 
 	CleanupContainer(ctx context.Context, sandboxID, containerID string, force bool) error
+
+	GCSandboxes(ctx context.Context, config GCConfig) ([]GCArtifact, error)
 }
 
 // VCSandbox is the Sandbox interface
@@ -62,6 +64,8 @@ type VC interface {
 type VCSandbox interface {
 	Annotations(key string) (string, error)
 	GetNetNs() string
+	GetHypervisorPid() (int, error)
+	GetHookAnnotations() map[string]string
 	GetAllContainers() []VCContainer
 	GetAnnotations() map[string]string
 	GetContainer(containerID string) VCContainer
@@ -70,6 +74,8 @@ type VCSandbox interface {
 
 	Start() error
 	Stop(force bool) error
+	Pause() error
+	Resume() error
 	Release() error
 	Monitor() (chan error, error)
 	Delete() error
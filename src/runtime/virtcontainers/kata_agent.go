@@ -26,6 +26,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	vccgroups "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cgroups"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
 	ns "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/nsenter"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
@@ -74,7 +75,11 @@ var (
 	kataBlkCCWDevType           = "blk-ccw"
 	kataSCSIDevType             = "scsi"
 	kataNvdimmDevType           = "nvdimm"
+	kataVfioDevType             = "vfio"
+	kataCharSerialDevType       = "char-serial"
+	kataCharVsockDevType        = "char-vsock"
 	kataVirtioFSDevType         = "virtio-fs"
+	kataOverlayFSDevType        = "overlayfs"
 	sharedDir9pOptions          = []string{"trans=virtio,version=9p2000.L,cache=mmap", "nodev"}
 	sharedDirVirtioFSOptions    = []string{}
 	sharedDirVirtioFSDaxOptions = "dax"
@@ -87,6 +92,11 @@ var (
 	GuestDNSFile                = "/etc/resolv.conf"
 )
 
+// kataVirtioFSPCIPathOption prefixes the driver option giving the PCI path
+// of the hotplugged virtio-fs device of a storage, for the agent to wait
+// for the device before mounting it.
+const kataVirtioFSPCIPathOption = "pci_path="
+
 const (
 	agentTraceModeDynamic  = "dynamic"
 	agentTraceModeStatic   = "static"
@@ -182,6 +192,18 @@ var kataGuestSandboxStorageDir = func() string {
 	return filepath.Join(defaultKataGuestSandboxDir, "storage")
 }
 
+// kataGuestLayersDir is where the layer images shared by the containers
+// are mounted inside the guest.
+func kataGuestLayersDir() string {
+	return filepath.Join(kataGuestSandboxDir(), "layers")
+}
+
+// kataGuestScratchDir holds the writable directories of the containers
+// whose rootfs is an overlay of layer images.
+func kataGuestScratchDir() string {
+	return filepath.Join(kataGuestSandboxDir(), "rootfs")
+}
+
 func ephemeralPath() string {
 	if rootless.IsRootless() {
 		return filepath.Join(kataGuestSandboxDir(), kataEphemeralDevType)
@@ -200,6 +222,7 @@ type KataAgentConfig struct {
 	TraceMode         string
 	TraceType         string
 	KernelModules     []string
+	HealthCheck       AgentHealthCheckConfig
 }
 
 // KataAgentState is the structure describing the data stored from this
@@ -213,9 +236,12 @@ type kataAgent struct {
 	shim  shim
 	proxy proxy
 
-	// lock protects the client pointer
+	// lock protects the client pointers
 	sync.Mutex
 	client *kataclient.AgentClient
+	// checkClient is the connection the liveness of a long live connected
+	// agent is checked on.
+	checkClient *kataclient.AgentClient
 
 	reqHandlers    map[string]reqFunc
 	state          KataAgentState
@@ -225,8 +251,14 @@ type kataAgent struct {
 	dead           bool
 	kmodules       []string
 
-	vmSocket interface{}
-	ctx      context.Context
+	vmSocket     interface{}
+	ctx          context.Context
+	checkTimeout time.Duration
+
+	// sandboxID and connected are only used to report the agent
+	// connections as sandbox events.
+	sandboxID string
+	connected bool
 }
 
 func (k *kataAgent) trace(name string) (opentracing.Span, context.Context) {
@@ -320,6 +352,7 @@ func (k *kataAgent) handleTraceSettings(config KataAgentConfig) bool {
 func (k *kataAgent) init(ctx context.Context, sandbox *Sandbox, config interface{}) (disableVMShutdown bool, err error) {
 	// save
 	k.ctx = sandbox.ctx
+	k.sandboxID = sandbox.id
 
 	span, _ := k.trace("init")
 	defer span.Finish()
@@ -329,6 +362,10 @@ func (k *kataAgent) init(ctx context.Context, sandbox *Sandbox, config interface
 		disableVMShutdown = k.handleTraceSettings(c)
 		k.keepConn = c.LongLiveConn
 		k.kmodules = c.KernelModules
+		if err := c.HealthCheck.Valid(); err != nil {
+			return false, err
+		}
+		k.checkTimeout = c.HealthCheck.withDefaults().Timeout
 	default:
 		return false, vcTypes.ErrInvalidConfigType
 	}
@@ -534,9 +571,34 @@ func cmdToKataProcess(cmd types.Cmd) (process *grpc.Process, err error) {
 			GID:            gid,
 			AdditionalGids: extraGids,
 		},
-		Args: cmd.Args,
-		Env:  cmdEnvsToStringSlice(cmd.Envs),
-		Cwd:  cmd.WorkDir,
+		Args:            cmd.Args,
+		Env:             cmdEnvsToStringSlice(cmd.Envs),
+		Cwd:             cmd.WorkDir,
+		NoNewPrivileges: cmd.NoNewPrivileges,
+		ApparmorProfile: cmd.ApparmorProfile,
+		SelinuxLabel:    cmd.SelinuxLabel,
+	}
+
+	if cmd.Capabilities != nil {
+		process.Capabilities = &grpc.LinuxCapabilities{
+			Bounding:    cmd.Capabilities.Bounding,
+			Effective:   cmd.Capabilities.Effective,
+			Inheritable: cmd.Capabilities.Inheritable,
+			Permitted:   cmd.Capabilities.Permitted,
+			Ambient:     cmd.Capabilities.Ambient,
+		}
+	}
+
+	for _, rlimit := range cmd.Rlimits {
+		process.Rlimits = append(process.Rlimits, grpc.POSIXRlimit{
+			Type: rlimit.Type,
+			Hard: rlimit.Hard,
+			Soft: rlimit.Soft,
+		})
+	}
+
+	if cmd.OOMScoreAdj != nil {
+		process.OOMScoreAdj = int64(*cmd.OOMScoreAdj)
 	}
 
 	return process, nil
@@ -894,6 +956,7 @@ func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 		SandboxId:     sandbox.id,
 		GuestHookPath: sandbox.config.HypervisorConfig.GuestHookPath,
 		KernelModules: kmodules,
+		Sysctls:       sandbox.config.GuestSysctls,
 	}
 
 	_, err = k.sendReq(req)
@@ -1087,10 +1150,46 @@ func (k *kataAgent) replaceOCIMountsForStorages(spec *specs.Spec, volumeStorages
 	return nil
 }
 
+// guestOCIHooks returns the OCI hooks which are expected to run in the
+// container namespaces, and must then be forwarded to the agent. The agent
+// runs the createContainer hooks as prestart hooks, once the container
+// namespaces have been created, and the startContainer hooks right before
+// executing the container process.
+func guestOCIHooks(hooks *specs.Hooks) *grpc.Hooks {
+	if hooks == nil {
+		return nil
+	}
+
+	guestHooks := func(hooks []specs.Hook) []grpc.Hook {
+		var grpcHooks []grpc.Hook
+		for _, hook := range hooks {
+			h := grpc.Hook{
+				Path: hook.Path,
+				Args: hook.Args,
+				Env:  hook.Env,
+			}
+			if hook.Timeout != nil {
+				h.Timeout = int64(*hook.Timeout)
+			}
+			grpcHooks = append(grpcHooks, h)
+		}
+		return grpcHooks
+	}
+
+	if len(hooks.CreateContainer) == 0 && len(hooks.StartContainer) == 0 {
+		return nil
+	}
+
+	return &grpc.Hooks{
+		Prestart:       guestHooks(hooks.CreateContainer),
+		StartContainer: guestHooks(hooks.StartContainer),
+	}
+}
+
 func (k *kataAgent) constraintGRPCSpec(grpcSpec *grpc.Spec, passSeccomp bool) {
-	// Disable Hooks since they have been handled on the host and there is
-	// no reason to send them to the agent. It would make no sense to try
-	// to apply them on the guest.
+	// Drop the hooks of the spec, the prestart, createRuntime, poststart
+	// and poststop hooks run on the host. The ones expected to run in the
+	// container namespaces are set afterwards from guestOCIHooks().
 	grpcSpec.Hooks = nil
 
 	// Pass seccomp only if disable_guest_seccomp is set to false in
@@ -1136,7 +1235,7 @@ func (k *kataAgent) constraintGRPCSpec(grpcSpec *grpc.Spec, passSeccomp bool) {
 	// on the guest.
 	var tmpNamespaces []grpc.LinuxNamespace
 	for _, ns := range grpcSpec.Linux.Namespaces {
-		switch ns.Type {
+		switch specs.LinuxNamespaceType(ns.Type) {
 		case specs.CgroupNamespace:
 		case specs.NetworkNamespace:
 		default:
@@ -1220,7 +1319,7 @@ func (k *kataAgent) appendBlockDevice(dev ContainerDevice, c *Container) *grpc.D
 		kataDevice.Id = d.DevNo
 	case config.VirtioBlock:
 		kataDevice.Type = kataBlkDevType
-		kataDevice.Id = d.PCIAddr
+		kataDevice.Id = d.PCIPath.String()
 		kataDevice.VmPath = d.VirtPath
 	case config.VirtioSCSI:
 		kataDevice.Type = kataSCSIDevType
@@ -1245,15 +1344,77 @@ func (k *kataAgent) appendVhostUserBlkDevice(dev ContainerDevice, c *Container)
 	kataDevice := &grpc.Device{
 		ContainerPath: dev.ContainerPath,
 		Type:          kataBlkDevType,
-		Id:            d.PCIAddr,
+		Id:            d.PCIPath.String(),
 	}
 
 	return kataDevice
 }
 
-func (k *kataAgent) appendDevices(deviceList []*grpc.Device, c *Container) []*grpc.Device {
-	var kataDevice *grpc.Device
+// appendVfioDevice builds the agent device of a VFIO group. Each function
+// of the group is passed as a "hostID=guestPciPath" option, from which the
+// agent finds the guest IOMMU group of the device.
+func (k *kataAgent) appendVfioDevice(dev ContainerDevice, c *Container) *grpc.Device {
+	device := c.sandbox.devManager.GetDeviceByID(dev.ID)
+
+	devList, ok := device.GetDeviceInfo().([]*config.VFIODev)
+	if !ok || devList == nil {
+		k.Logger().WithField("device", device).Error("malformed vfio device")
+		return nil
+	}
+
+	kataDevice := &grpc.Device{
+		ContainerPath: dev.ContainerPath,
+		Type:          kataVfioDevType,
+		Id:            filepath.Base(dev.ContainerPath),
+	}
+
+	for _, vfioDev := range devList {
+		if vfioDev.GuestPciPath.IsNil() {
+			k.Logger().WithField("vfio-device-ID", vfioDev.ID).
+				Warn("unknown guest PCI path, VFIO device not passed to the agent")
+			return nil
+		}
+
+		hostID := vfioDev.BDF
+		if vfioDev.Type == config.VFIODeviceMediatedType {
+			hostID = filepath.Base(vfioDev.SysfsDev)
+		}
+
+		kataDevice.Options = append(kataDevice.Options, fmt.Sprintf("%s=%s", hostID, vfioDev.GuestPciPath))
+	}
 
+	return kataDevice
+}
+
+func (k *kataAgent) appendCharDevice(dev ContainerDevice, c *Container) *grpc.Device {
+	device := c.sandbox.devManager.GetDeviceByID(dev.ID)
+
+	d, ok := device.GetDeviceInfo().(*config.CharDev)
+	if !ok || d == nil {
+		k.Logger().WithField("device", device).Error("malformed char device")
+		return nil
+	}
+
+	kataDevice := &grpc.Device{
+		ContainerPath: dev.ContainerPath,
+	}
+
+	switch d.Transport {
+	case config.CharDevVirtioSerial:
+		kataDevice.Type = kataCharSerialDevType
+		kataDevice.Id = d.PortName
+	case config.CharDevVsock:
+		kataDevice.Type = kataCharVsockDevType
+		kataDevice.Id = strconv.FormatUint(uint64(d.VsockPort), 10)
+	default:
+		k.Logger().WithField("device", d.ID).Error("unknown char device transport")
+		return nil
+	}
+
+	return kataDevice
+}
+
+func (k *kataAgent) appendDevices(deviceList []*grpc.Device, c *Container) []*grpc.Device {
 	for _, dev := range c.devices {
 		device := c.sandbox.devManager.GetDeviceByID(dev.ID)
 		if device == nil {
@@ -1261,11 +1422,17 @@ func (k *kataAgent) appendDevices(deviceList []*grpc.Device, c *Container) []*gr
 			return nil
 		}
 
+		var kataDevice *grpc.Device
+
 		switch device.DeviceType() {
 		case config.DeviceBlock:
 			kataDevice = k.appendBlockDevice(dev, c)
 		case config.VhostUserBlk:
 			kataDevice = k.appendVhostUserBlkDevice(dev, c)
+		case config.DeviceVFIO:
+			kataDevice = k.appendVfioDevice(dev, c)
+		case config.DeviceChar:
+			kataDevice = k.appendCharDevice(dev, c)
 		}
 
 		if kataDevice == nil {
@@ -1324,10 +1491,10 @@ func (k *kataAgent) buildContainerRootfs(sandbox *Sandbox, c *Container, rootPat
 			rootfs.Source = blockDrive.DevNo
 		case sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioBlock:
 			rootfs.Driver = kataBlkDevType
-			if blockDrive.PCIAddr == "" {
+			if blockDrive.PCIPath.IsNil() {
 				rootfs.Source = blockDrive.VirtPath
 			} else {
-				rootfs.Source = blockDrive.PCIAddr
+				rootfs.Source = blockDrive.PCIPath.String()
 			}
 
 		case sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioSCSI:
@@ -1370,6 +1537,60 @@ func (k *kataAgent) buildContainerRootfs(sandbox *Sandbox, c *Container, rootPat
 	return nil, nil
 }
 
+// buildContainerLayers returns the storages of the layer images the
+// container rootfs is made of, followed by the storage of their overlay
+// mounted on rootPath.
+func (k *kataAgent) buildContainerLayers(sandbox *Sandbox, c *Container, rootPath string) ([]*grpc.Storage, error) {
+	var storages []*grpc.Storage
+	var lowerDirs []string
+
+	for _, devID := range c.state.LayerDeviceIDs {
+		device := sandbox.devManager.GetDeviceByID(devID)
+		if device == nil {
+			k.Logger().WithField("device", devID).Error("failed to find device by id")
+			return nil, fmt.Errorf("failed to find device by id %q", devID)
+		}
+
+		blockDrive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+		if !ok || blockDrive == nil {
+			k.Logger().Error("malformed block drive")
+			return nil, fmt.Errorf("malformed block drive")
+		}
+
+		// The layers are mounted once in the guest, the agent keeping
+		// track of the containers using them.
+		layer := &grpc.Storage{
+			Driver:     kataNvdimmDevType,
+			Source:     fmt.Sprintf("/dev/pmem%s", blockDrive.NvdimmID),
+			Fstype:     blockDrive.Format,
+			Options:    []string{"dax", "ro"},
+			MountPoint: filepath.Join(kataGuestLayersDir(), devID),
+		}
+		storages = append(storages, layer)
+		lowerDirs = append(lowerDirs, layer.MountPoint)
+	}
+
+	scratchDir := filepath.Join(kataGuestScratchDir(), c.id)
+	storages = append(storages, &grpc.Storage{
+		Driver: kataOverlayFSDevType,
+		Source: "overlay",
+		Fstype: "overlay",
+		Options: []string{
+			"lowerdir=" + strings.Join(lowerDirs, ":"),
+			"upperdir=" + filepath.Join(scratchDir, "upper"),
+			"workdir=" + filepath.Join(scratchDir, "work"),
+		},
+		MountPoint: rootPath,
+	})
+
+	// Ensure container mount destination exists
+	if err := os.MkdirAll(filepath.Join(getMountPath(c.sandbox.id), c.id, c.rootfsSuffix), DirMode); err != nil {
+		return nil, err
+	}
+
+	return storages, nil
+}
+
 func (k *kataAgent) hasAgentDebugConsole(sandbox *Sandbox) bool {
 	for _, p := range sandbox.config.HypervisorConfig.KernelParams {
 		if p.Key == "agent.debug_console" {
@@ -1401,7 +1622,13 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 		}
 	}()
 
-	if rootfs, err = k.buildContainerRootfs(sandbox, c, rootPathParent); err != nil {
+	if len(c.state.LayerDeviceIDs) > 0 {
+		var layers []*grpc.Storage
+		if layers, err = k.buildContainerLayers(sandbox, c, rootPath); err != nil {
+			return nil, err
+		}
+		ctrStorages = append(ctrStorages, layers...)
+	} else if rootfs, err = k.buildContainerRootfs(sandbox, c, rootPathParent); err != nil {
 		return nil, err
 	} else if rootfs != nil {
 		// Add rootfs to the list of container storage.
@@ -1458,6 +1685,13 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 
 	ctrStorages = append(ctrStorages, volumeStorages...)
 
+	// The virtio-fs share options are meant for the runtime only.
+	for i, m := range ociSpec.Mounts {
+		if share, _, options := virtioFSShareOptions(m.Options); share {
+			ociSpec.Mounts[i].Options = options
+		}
+	}
+
 	grpcSpec, err := grpc.OCItoGRPC(ociSpec)
 	if err != nil {
 		return nil, err
@@ -1474,6 +1708,10 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 	// irrelevant information to the agent.
 	k.constraintGRPCSpec(grpcSpec, passSeccomp)
 
+	// The createContainer and startContainer hooks have to run in the
+	// container namespaces, hence inside the guest.
+	grpcSpec.Hooks = guestOCIHooks(ociSpec.Hooks)
+
 	req := &grpc.CreateContainerRequest{
 		ContainerId:  c.id,
 		ExecId:       c.id,
@@ -1588,10 +1826,10 @@ func (k *kataAgent) handleDeviceBlockVolume(c *Container, device api.Device) (*g
 		vol.Source = blockDrive.DevNo
 	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioBlock:
 		vol.Driver = kataBlkDevType
-		if blockDrive.PCIAddr == "" {
+		if blockDrive.PCIPath.IsNil() {
 			vol.Source = blockDrive.VirtPath
 		} else {
-			vol.Source = blockDrive.PCIAddr
+			vol.Source = blockDrive.PCIPath.String()
 		}
 	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioMmio:
 		vol.Driver = kataMmioBlkDevType
@@ -1618,11 +1856,35 @@ func (k *kataAgent) handleVhostUserBlkVolume(c *Container, device api.Device) (*
 	}
 
 	vol.Driver = kataBlkDevType
-	vol.Source = d.PCIAddr
+	vol.Source = d.PCIPath.String()
+
+	// The backend rejects the writes to a read-only volume, mount it
+	// read-only rather than failing the writes.
+	if d.ReadOnly {
+		vol.Options = []string{"bind", "ro"}
+	}
 
 	return vol, nil
 }
 
+// handleVhostUserFSVolume handles volume that is a host directory shared
+// through its own virtio-fs device, mounted by the agent with its tag.
+func (k *kataAgent) handleVhostUserFSVolume(c *Container, device api.Device) (*grpc.Storage, error) {
+	d, ok := device.GetDeviceInfo().(*config.VhostUserDeviceAttrs)
+	if !ok || d == nil {
+		k.Logger().Error("malformed vhost-user fs device")
+		return nil, fmt.Errorf("malformed vhost-user fs device")
+	}
+
+	return &grpc.Storage{
+		Driver:        kataVirtioFSDevType,
+		DriverOptions: []string{kataVirtioFSPCIPathOption + d.PCIPath.String()},
+		Source:        d.Tag,
+		Fstype:        typeVirtioFS,
+		Options:       []string{"nodev"},
+	}, nil
+}
+
 // handleBlockVolumes handles volumes that are block devices files
 // by passing the block devices as Storage to the agent.
 func (k *kataAgent) handleBlockVolumes(c *Container) ([]*grpc.Storage, error) {
@@ -1654,6 +1916,8 @@ func (k *kataAgent) handleBlockVolumes(c *Container) ([]*grpc.Storage, error) {
 			vol, err = k.handleDeviceBlockVolume(c, device)
 		case config.VhostUserBlk:
 			vol, err = k.handleVhostUserBlkVolume(c, device)
+		case config.VhostUserFS:
+			vol, err = k.handleVhostUserFSVolume(c, device)
 		default:
 			k.Logger().Error("Unknown device type")
 			continue
@@ -1910,6 +2174,16 @@ func (k *kataAgent) connect() error {
 	k.installReqFunc(client)
 	k.client = client
 
+	if k.keepConn {
+		if sink := events.SinkFromContext(k.ctx); sink != nil {
+			sink.Send(&events.AgentConnected{
+				SandboxId: k.sandboxID,
+				Reconnect: k.connected,
+			})
+		}
+		k.connected = true
+	}
+
 	return nil
 }
 
@@ -1920,6 +2194,10 @@ func (k *kataAgent) disconnect() error {
 	k.Lock()
 	defer k.Unlock()
 
+	if err := k.closeCheckClient(); err != nil {
+		return err
+	}
+
 	if k.client == nil {
 		return nil
 	}
@@ -1939,13 +2217,72 @@ func (k *kataAgent) check() error {
 	span, _ := k.trace("check")
 	defer span.Finish()
 
-	_, err := k.sendReq(&grpc.CheckRequest{})
+	var err error
+	if k.keepConn {
+		err = k.checkOnCheckClient()
+	} else {
+		_, err = k.sendReq(&grpc.CheckRequest{})
+	}
 	if err != nil {
 		err = fmt.Errorf("Failed to check if grpc server is working: %s", err)
 	}
 	return err
 }
 
+// checkOnCheckClient checks a long live connected agent on a connection of
+// its own: resetting it after failed checks must not fail the requests in
+// flight on the shared connection, the WaitProcess ones among them.
+func (k *kataAgent) checkOnCheckClient() error {
+	k.Lock()
+	if k.dead {
+		k.Unlock()
+		return errors.New("Dead agent")
+	}
+
+	if k.checkClient == nil {
+		client, err := kataclient.NewAgentClient(k.ctx, k.state.URL, k.proxyBuiltIn)
+		if err != nil {
+			k.Unlock()
+			return err
+		}
+		k.checkClient = client
+	}
+	client := k.checkClient
+	k.Unlock()
+
+	ctx, cancel := k.getReqContext(grpcCheckRequest)
+	defer cancel()
+
+	_, err := client.HealthClient.Check(ctx, &grpc.CheckRequest{})
+	return err
+}
+
+func (k *kataAgent) resetCheck() error {
+	span, _ := k.trace("resetCheck")
+	defer span.Finish()
+
+	k.Lock()
+	defer k.Unlock()
+
+	return k.closeCheckClient()
+}
+
+// closeCheckClient closes the check connection, with the agent lock held.
+func (k *kataAgent) closeCheckClient() error {
+	if k.checkClient == nil {
+		return nil
+	}
+
+	err := k.checkClient.Close()
+	k.checkClient = nil
+
+	if err != nil && grpcStatus.Convert(err).Code() != codes.Canceled {
+		return err
+	}
+
+	return nil
+}
+
 func (k *kataAgent) waitProcess(c *Container, processID string) (int32, error) {
 	span, _ := k.trace("waitProcess")
 	defer span.Finish()
@@ -2097,7 +2434,11 @@ func (k *kataAgent) getReqContext(reqName string) (ctx context.Context, cancel c
 	case grpcWaitProcessRequest:
 		// Wait has no timeout
 	case grpcCheckRequest:
-		ctx, cancel = context.WithTimeout(ctx, checkRequestTimeout)
+		timeout := k.checkTimeout
+		if timeout == 0 {
+			timeout = checkRequestTimeout
+		}
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 	default:
 		ctx, cancel = context.WithTimeout(ctx, defaultRequestTimeout)
 	}
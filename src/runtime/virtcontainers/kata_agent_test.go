@@ -43,7 +43,7 @@ var (
 	testBlockDeviceCtrPath = "testBlockDeviceCtrPath"
 	testDevNo              = "testDevNo"
 	testNvdimmID           = "testNvdimmID"
-	testPCIAddr            = "04/02"
+	testPCIPath, _         = types.PciPathFromString("04/02")
 	testSCSIAddr           = "testSCSIAddr"
 	testVirtPath           = "testVirtPath"
 )
@@ -360,6 +360,55 @@ func TestKataAgentSendReq(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestKataAgentCheckConnection(t *testing.T) {
+	assert := assert.New(t)
+
+	proxy := mock.ProxyGRPCMock{
+		GRPCImplementer: &gRPCProxy{},
+		GRPCRegister:    gRPCRegister,
+	}
+
+	sockDir, err := testGenerateKataProxySockDir()
+	assert.NoError(err)
+	defer os.RemoveAll(sockDir)
+
+	testKataProxyURL := fmt.Sprintf(testKataProxyURLTempl, sockDir)
+	assert.NoError(proxy.Start(testKataProxyURL))
+	defer proxy.Stop()
+
+	k := &kataAgent{
+		ctx:      context.Background(),
+		keepConn: true,
+		state: KataAgentState{
+			URL: testKataProxyURL,
+		},
+	}
+	defer k.disconnect()
+
+	_, err = k.sendReq(&pb.WaitProcessRequest{})
+	assert.NoError(err)
+	client := k.client
+
+	// The agent is checked on a connection of its own
+	assert.NoError(k.check())
+	assert.NotNil(k.checkClient)
+	assert.True(k.checkClient != client)
+
+	// which is reset alone
+	assert.NoError(k.resetCheck())
+	assert.Nil(k.checkClient)
+	assert.True(k.client == client)
+	_, err = k.sendReq(&pb.WaitProcessRequest{})
+	assert.NoError(err)
+
+	assert.NoError(k.check())
+	assert.NotNil(k.checkClient)
+
+	assert.NoError(k.disconnect())
+	assert.Nil(k.checkClient)
+	assert.Nil(k.client)
+}
+
 func TestHandleEphemeralStorage(t *testing.T) {
 	k := kataAgent{}
 	var ociMounts []specs.Mount
@@ -442,13 +491,13 @@ func TestHandleDeviceBlockVolume(t *testing.T) {
 			BlockDeviceDriver: config.VirtioBlock,
 			inputDev: &drivers.BlockDevice{
 				BlockDrive: &config.BlockDrive{
-					PCIAddr:  testPCIAddr,
+					PCIPath:  testPCIPath,
 					VirtPath: testVirtPath,
 				},
 			},
 			resultVol: &pb.Storage{
 				Driver: kataBlkDevType,
-				Source: testPCIAddr,
+				Source: testPCIPath.String(),
 			},
 		},
 		{
@@ -521,14 +570,16 @@ func TestHandleBlockVolume(t *testing.T) {
 	bDevID := "MockDeviceBlock"
 	vDestination := "/VhostUserBlk/destination"
 	bDestination := "/DeviceBlock/destination"
-	vPCIAddr := "0001:01"
-	bPCIAddr := "0002:01"
+	vPCIPath, err := types.PciPathFromString("01/02")
+	assert.NoError(t, err)
+	bPCIPath, err := types.PciPathFromString("01/03")
+	assert.NoError(t, err)
 
 	vDev := drivers.NewVhostUserBlkDevice(&config.DeviceInfo{ID: vDevID})
 	bDev := drivers.NewBlockDevice(&config.DeviceInfo{ID: bDevID})
 
-	vDev.VhostUserDeviceAttrs = &config.VhostUserDeviceAttrs{PCIAddr: vPCIAddr}
-	bDev.BlockDrive = &config.BlockDrive{PCIAddr: bPCIAddr}
+	vDev.VhostUserDeviceAttrs = &config.VhostUserDeviceAttrs{PCIPath: vPCIPath}
+	bDev.BlockDrive = &config.BlockDrive{PCIPath: bPCIPath}
 
 	var devices []api.Device
 	devices = append(devices, vDev, bDev)
@@ -546,7 +597,7 @@ func TestHandleBlockVolume(t *testing.T) {
 	mounts = append(mounts, vMount, bMount)
 
 	tmpDir := "/vhost/user/dir"
-	dm := manager.NewDeviceManager(manager.VirtioBlock, true, tmpDir, devices)
+	dm := manager.NewDeviceManager(manager.VirtioBlock, true, tmpDir, nil, devices)
 
 	sConfig := SandboxConfig{}
 	sConfig.HypervisorConfig.BlockDeviceDriver = manager.VirtioBlock
@@ -569,20 +620,118 @@ func TestHandleBlockVolume(t *testing.T) {
 		Fstype:     "bind",
 		Options:    []string{"bind"},
 		Driver:     kataBlkDevType,
-		Source:     vPCIAddr,
+		Source:     vPCIPath.String(),
 	}
 	bStorage := &pb.Storage{
 		MountPoint: bDestination,
 		Fstype:     "bind",
 		Options:    []string{"bind"},
 		Driver:     kataBlkDevType,
-		Source:     bPCIAddr,
+		Source:     bPCIPath.String(),
 	}
 
 	assert.Equal(t, vStorage, volumeStorages[0], "Error while handle VhostUserBlk type block volume")
 	assert.Equal(t, bStorage, volumeStorages[1], "Error while handle BlockDevice type block volume")
 }
 
+func TestHandleVhostUserFSVolume(t *testing.T) {
+	assert := assert.New(t)
+	k := kataAgent{}
+
+	pciPath, err := types.PciPathFromString("02/01")
+	assert.NoError(err)
+
+	dev := drivers.NewVhostUserFSDevice(&config.DeviceInfo{ID: "share"})
+	dev.Tag = "fs-share"
+	dev.PCIPath = pciPath
+
+	vol, err := k.handleVhostUserFSVolume(&Container{}, dev)
+	assert.NoError(err)
+	assert.Equal(&pb.Storage{
+		Driver:        kataVirtioFSDevType,
+		DriverOptions: []string{kataVirtioFSPCIPathOption + "02/01"},
+		Source:        "fs-share",
+		Fstype:        typeVirtioFS,
+		Options:       []string{"nodev"},
+	}, vol)
+
+	_, err = k.handleVhostUserFSVolume(&Container{}, drivers.NewBlockDevice(&config.DeviceInfo{}))
+	assert.Error(err)
+}
+
+func TestBuildContainerLayers(t *testing.T) {
+	assert := assert.New(t)
+	k := kataAgent{}
+
+	dir, err := ioutil.TempDir("", "kata-layers")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	kataHostSharedDirSaved := kataHostSharedDir
+	kataHostSharedDir = func() string {
+		return dir
+	}
+	defer func() {
+		kataHostSharedDir = kataHostSharedDirSaved
+	}()
+
+	appDev := drivers.NewBlockDevice(&config.DeviceInfo{ID: "app"})
+	appDev.BlockDrive = &config.BlockDrive{Format: "erofs", NvdimmID: "1", Pmem: true}
+	baseDev := drivers.NewBlockDevice(&config.DeviceInfo{ID: "base"})
+	baseDev.BlockDrive = &config.BlockDrive{Format: "ext4", NvdimmID: "2", Pmem: true}
+
+	sandbox := &Sandbox{
+		id:         "100",
+		devManager: manager.NewDeviceManager(manager.VirtioBlock, false, "", nil, []api.Device{appDev, baseDev}),
+	}
+	c := &Container{
+		id:           "200",
+		sandbox:      sandbox,
+		rootfsSuffix: "rootfs",
+		state: types.ContainerState{
+			LayerDeviceIDs: []string{"app", "base"},
+		},
+	}
+
+	rootPath := filepath.Join(kataGuestSharedDir(), c.id, c.rootfsSuffix)
+	storages, err := k.buildContainerLayers(sandbox, c, rootPath)
+	assert.NoError(err)
+	assert.Equal([]*pb.Storage{
+		{
+			Driver:     kataNvdimmDevType,
+			Source:     "/dev/pmem1",
+			Fstype:     "erofs",
+			Options:    []string{"dax", "ro"},
+			MountPoint: filepath.Join(kataGuestLayersDir(), "app"),
+		},
+		{
+			Driver:     kataNvdimmDevType,
+			Source:     "/dev/pmem2",
+			Fstype:     "ext4",
+			Options:    []string{"dax", "ro"},
+			MountPoint: filepath.Join(kataGuestLayersDir(), "base"),
+		},
+		{
+			Driver: kataOverlayFSDevType,
+			Source: "overlay",
+			Fstype: "overlay",
+			Options: []string{
+				"lowerdir=" + filepath.Join(kataGuestLayersDir(), "app") + ":" + filepath.Join(kataGuestLayersDir(), "base"),
+				"upperdir=" + filepath.Join(kataGuestScratchDir(), c.id, "upper"),
+				"workdir=" + filepath.Join(kataGuestScratchDir(), c.id, "work"),
+			},
+			MountPoint: rootPath,
+		},
+	}, storages)
+
+	_, err = os.Stat(filepath.Join(getMountPath(sandbox.id), c.id, c.rootfsSuffix))
+	assert.NoError(err)
+
+	c.state.LayerDeviceIDs = []string{"missing"}
+	_, err = k.buildContainerLayers(sandbox, c, rootPath)
+	assert.Error(err)
+}
+
 func TestAppendDevicesEmptyContainerDeviceList(t *testing.T) {
 	k := kataAgent{}
 
@@ -592,7 +741,7 @@ func TestAppendDevicesEmptyContainerDeviceList(t *testing.T) {
 
 	c := &Container{
 		sandbox: &Sandbox{
-			devManager: manager.NewDeviceManager("virtio-scsi", false, "", nil),
+			devManager: manager.NewDeviceManager("virtio-scsi", false, "", nil, nil),
 		},
 		devices: ctrDevices,
 	}
@@ -612,7 +761,7 @@ func TestAppendDevices(t *testing.T) {
 				ID: id,
 			},
 			BlockDrive: &config.BlockDrive{
-				PCIAddr: testPCIAddr,
+				PCIPath: testPCIPath,
 			},
 		},
 	}
@@ -625,7 +774,7 @@ func TestAppendDevices(t *testing.T) {
 
 	c := &Container{
 		sandbox: &Sandbox{
-			devManager: manager.NewDeviceManager("virtio-blk", false, "", ctrDevices),
+			devManager: manager.NewDeviceManager("virtio-blk", false, "", nil, ctrDevices),
 			config:     sandboxConfig,
 		},
 	}
@@ -639,7 +788,7 @@ func TestAppendDevices(t *testing.T) {
 		{
 			Type:          kataBlkDevType,
 			ContainerPath: testBlockDeviceCtrPath,
-			Id:            testPCIAddr,
+			Id:            testPCIPath.String(),
 		},
 	}
 	updatedDevList := k.appendDevices(devList, c)
@@ -659,7 +808,7 @@ func TestAppendVhostUserBlkDevices(t *testing.T) {
 			},
 			VhostUserDeviceAttrs: &config.VhostUserDeviceAttrs{
 				Type:    config.VhostUserBlk,
-				PCIAddr: testPCIAddr,
+				PCIPath: testPCIPath,
 			},
 		},
 	}
@@ -673,7 +822,7 @@ func TestAppendVhostUserBlkDevices(t *testing.T) {
 	testVhostUserStorePath := "/test/vhost/user/store/path"
 	c := &Container{
 		sandbox: &Sandbox{
-			devManager: manager.NewDeviceManager("virtio-blk", true, testVhostUserStorePath, ctrDevices),
+			devManager: manager.NewDeviceManager("virtio-blk", true, testVhostUserStorePath, nil, ctrDevices),
 			config:     sandboxConfig,
 		},
 	}
@@ -687,7 +836,7 @@ func TestAppendVhostUserBlkDevices(t *testing.T) {
 		{
 			Type:          kataBlkDevType,
 			ContainerPath: testBlockDeviceCtrPath,
-			Id:            testPCIAddr,
+			Id:            testPCIPath.String(),
 		},
 	}
 	updatedDevList := k.appendDevices(devList, c)
@@ -696,6 +845,69 @@ func TestAppendVhostUserBlkDevices(t *testing.T) {
 		updatedDevList, expected)
 }
 
+func TestAppendVfioDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	// IOMMU group 2 holds two functions of the same device
+	devicesDir := filepath.Join(tmpDir, "2", "devices")
+	assert.NoError(os.MkdirAll(devicesDir, DirMode))
+	for _, bdf := range []string{"0000:00:1c.0", "0000:00:1c.1"} {
+		_, err = os.Create(filepath.Join(devicesDir, bdf))
+		assert.NoError(err)
+	}
+
+	savedIOMMUPath := config.SysIOMMUPath
+	config.SysIOMMUPath = tmpDir
+	defer func() {
+		config.SysIOMMUPath = savedIOMMUPath
+	}()
+
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil, nil)
+	path := filepath.Join(vfioPath, "2")
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      path,
+		ContainerPath: path,
+		DevType:       "c",
+	})
+	assert.NoError(err)
+
+	c := &Container{
+		sandbox: &Sandbox{
+			hypervisor: &mockHypervisor{},
+			devManager: dm,
+			config:     &SandboxConfig{},
+			ctx:        context.Background(),
+		},
+		devices: []ContainerDevice{
+			{
+				ID:            device.DeviceID(),
+				ContainerPath: path,
+			},
+		},
+	}
+
+	k := kataAgent{}
+
+	// Not hotplugged yet, the guest PCI path is unknown.
+	assert.Empty(k.appendDevices([]*pb.Device{}, c))
+
+	assert.NoError(device.Attach(c.sandbox))
+
+	expected := []*pb.Device{
+		{
+			Type:          kataVfioDevType,
+			ContainerPath: path,
+			Id:            "2",
+			Options:       []string{"00:1c.0=02/01", "00:1c.1=02/02"},
+		},
+	}
+	assert.Equal(expected, k.appendDevices([]*pb.Device{}, c))
+}
+
 func TestConstraintGRPCSpec(t *testing.T) {
 	assert := assert.New(t)
 	expectedCgroupPath := "/foo/bar"
@@ -709,11 +921,11 @@ func TestConstraintGRPCSpec(t *testing.T) {
 			Seccomp: &pb.LinuxSeccomp{},
 			Namespaces: []pb.LinuxNamespace{
 				{
-					Type: specs.NetworkNamespace,
+					Type: string(specs.NetworkNamespace),
 					Path: "/abc/123",
 				},
 				{
-					Type: specs.MountNamespace,
+					Type: string(specs.MountNamespace),
 					Path: "/abc/123",
 				},
 			},
@@ -772,6 +984,38 @@ func TestConstraintGRPCSpec(t *testing.T) {
 	assert.Empty(g.Linux.Devices)
 }
 
+func TestGuestOCIHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(guestOCIHooks(nil))
+	assert.Nil(guestOCIHooks(&specs.Hooks{
+		Prestart: []specs.Hook{{Path: "/usr/bin/prestart"}},
+	}))
+
+	timeout := 5
+	hooks := &specs.Hooks{
+		Prestart:        []specs.Hook{{Path: "/usr/bin/prestart"}},
+		CreateRuntime:   []specs.Hook{{Path: "/usr/bin/create-runtime"}},
+		CreateContainer: []specs.Hook{{Path: "/usr/bin/create-container", Args: []string{"create-container", "arg"}}},
+		StartContainer:  []specs.Hook{{Path: "/usr/bin/start-container", Env: []string{"FOO=bar"}, Timeout: &timeout}},
+		Poststop:        []specs.Hook{{Path: "/usr/bin/poststop"}},
+	}
+
+	expected := &pb.Hooks{
+		Prestart: []pb.Hook{
+			{Path: "/usr/bin/create-container", Args: []string{"create-container", "arg"}},
+		},
+		StartContainer: []pb.Hook{
+			{Path: "/usr/bin/start-container", Env: []string{"FOO=bar"}, Timeout: 5},
+		},
+	}
+	assert.Equal(expected, guestOCIHooks(hooks))
+
+	hooks.CreateContainer = nil
+	expected.Prestart = nil
+	assert.Equal(expected, guestOCIHooks(hooks))
+}
+
 func TestHandleShm(t *testing.T) {
 	assert := assert.New(t)
 	k := kataAgent{}
@@ -842,11 +1086,11 @@ func TestHandlePidNamespace(t *testing.T) {
 		Linux: &pb.Linux{
 			Namespaces: []pb.LinuxNamespace{
 				{
-					Type: specs.NetworkNamespace,
+					Type: string(specs.NetworkNamespace),
 					Path: "/abc/123",
 				},
 				{
-					Type: specs.MountNamespace,
+					Type: string(specs.MountNamespace),
 					Path: "/abc/123",
 				},
 			},
@@ -867,7 +1111,7 @@ func TestHandlePidNamespace(t *testing.T) {
 	}
 
 	utsNs := pb.LinuxNamespace{
-		Type: specs.UTSNamespace,
+		Type: string(specs.UTSNamespace),
 		Path: "",
 	}
 
@@ -954,6 +1198,33 @@ func TestCmdToKataProcess(t *testing.T) {
 	cmd1.SupplementaryGroups = []string{"4000"}
 	_, err = cmdToKataProcess(cmd1)
 	assert.Nil(err)
+
+	oomScoreAdj := 500
+	cmd1 = cmd
+	cmd1.Capabilities = &specs.LinuxCapabilities{
+		Bounding:  []string{"CAP_KILL"},
+		Effective: []string{"CAP_KILL"},
+	}
+	cmd1.Rlimits = []specs.POSIXRlimit{
+		{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 512},
+	}
+	cmd1.NoNewPrivileges = true
+	cmd1.ApparmorProfile = "kata-default"
+	cmd1.SelinuxLabel = "system_u:system_r:container_t:s0"
+	cmd1.OOMScoreAdj = &oomScoreAdj
+	process, err := cmdToKataProcess(cmd1)
+	assert.Nil(err)
+	assert.Equal(&pb.LinuxCapabilities{
+		Bounding:  []string{"CAP_KILL"},
+		Effective: []string{"CAP_KILL"},
+	}, process.Capabilities)
+	assert.Equal([]pb.POSIXRlimit{
+		{Type: "RLIMIT_NOFILE", Hard: 1024, Soft: 512},
+	}, process.Rlimits)
+	assert.True(process.NoNewPrivileges)
+	assert.Equal("kata-default", process.ApparmorProfile)
+	assert.Equal("system_u:system_r:container_t:s0", process.SelinuxLabel)
+	assert.Equal(int64(500), process.OOMScoreAdj)
 }
 
 func TestAgentCreateContainer(t *testing.T) {
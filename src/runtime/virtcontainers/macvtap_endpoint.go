@@ -10,6 +10,7 @@ import (
 	"os"
 
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
 
 // MacvtapEndpoint represents a macvtap endpoint
@@ -18,7 +19,7 @@ type MacvtapEndpoint struct {
 	EndpointType       EndpointType
 	VMFds              []*os.File
 	VhostFds           []*os.File
-	PCIAddr            string
+	PCIPath            types.PciPath
 	RxRateLimiter      bool
 	TxRateLimiter      bool
 }
@@ -92,14 +93,14 @@ func (endpoint *MacvtapEndpoint) HotDetach(h hypervisor, netNsCreated bool, netN
 	return fmt.Errorf("MacvtapEndpoint does not support Hot detach")
 }
 
-// PciAddr returns the PCI address of the endpoint.
-func (endpoint *MacvtapEndpoint) PciAddr() string {
-	return endpoint.PCIAddr
+// PciPath returns the PCI path of the endpoint.
+func (endpoint *MacvtapEndpoint) PciPath() types.PciPath {
+	return endpoint.PCIPath
 }
 
-// SetPciAddr sets the PCI address of the endpoint.
-func (endpoint *MacvtapEndpoint) SetPciAddr(pciAddr string) {
-	endpoint.PCIAddr = pciAddr
+// SetPciPath sets the PCI path of the endpoint.
+func (endpoint *MacvtapEndpoint) SetPciPath(pciPath types.PciPath) {
+	endpoint.PCIPath = pciPath
 }
 
 // NetworkPair returns the network pair of the endpoint.
@@ -112,7 +113,7 @@ func (endpoint *MacvtapEndpoint) save() persistapi.NetworkEndpoint {
 		Type: string(endpoint.Type()),
 
 		Macvtap: &persistapi.MacvtapEndpoint{
-			PCIAddr: endpoint.PCIAddr,
+			PCIPath: endpoint.PCIPath.String(),
 		},
 	}
 }
@@ -120,7 +121,7 @@ func (endpoint *MacvtapEndpoint) load(s persistapi.NetworkEndpoint) {
 	endpoint.EndpointType = MacvtapEndpointType
 
 	if s.Macvtap != nil {
-		endpoint.PCIAddr = s.Macvtap.PCIAddr
+		endpoint.PCIPath, _ = types.PciPathFromString(s.Macvtap.PCIPath)
 	}
 }
 
@@ -8,18 +8,23 @@ package virtcontainers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
 
 type mockHypervisor struct {
-	mockPid int
+	mockPid  int
+	vfioSlot int
 }
 
 func (m *mockHypervisor) capabilities() types.Capabilities {
-	return types.Capabilities{}
+	var caps types.Capabilities
+	caps.SetVMPauseSupport()
+	return caps
 }
 
 func (m *mockHypervisor) hypervisorConfig() HypervisorConfig {
@@ -66,6 +71,15 @@ func (m *mockHypervisor) hotplugAddDevice(devInfo interface{}, devType deviceTyp
 	case memoryDev:
 		memdev := devInfo.(*memoryDevice)
 		return memdev.sizeMB, nil
+	case vfioDev:
+		// Plug the VFIO devices on the first bridge.
+		m.vfioSlot++
+		bridge := types.NewBridge(types.PCI, "mock-bridge", nil, 2)
+		path, err := bridge.PciPath(fmt.Sprintf("%02x", m.vfioSlot))
+		if err != nil {
+			return nil, err
+		}
+		devInfo.(*config.VFIODev).GuestPciPath = path
 	}
 	return nil, nil
 }
@@ -132,3 +146,11 @@ func (m *mockHypervisor) generateSocket(id string, useVsock bool) (interface{},
 func (m *mockHypervisor) isRateLimiterBuiltin() bool {
 	return false
 }
+
+func (m *mockHypervisor) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	return nil
+}
+
+func (m *mockHypervisor) setMemoryHostNodes(nodes []int) error {
+	return nil
+}
@@ -6,33 +6,164 @@
 package virtcontainers
 
 import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/client"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 const (
 	defaultCheckInterval = 1 * time.Second
 	watcherChannelSize   = 128
+
+	// maxAgentCheckBackoff caps the delay between two agent checks with
+	// the reconnect recovery policy.
+	maxAgentCheckBackoff = 30 * time.Second
+
+	// consoleDumpTimeout is how long the guest console is read for with
+	// the dump-console recovery policy.
+	consoleDumpTimeout = 2 * time.Second
 )
 
+// AgentRecoveryPolicy is what the sandbox monitor does when the agent
+// health check fails.
+type AgentRecoveryPolicy string
+
+const (
+	// AgentRecoveryKill reports the failure to the watchers, which then
+	// tear the sandbox down.
+	AgentRecoveryKill AgentRecoveryPolicy = "kill"
+
+	// AgentRecoveryReconnect closes the connection the agent is checked on
+	// after each failed check, and delays the next check with an exponential backoff. The
+	// failure is reported once the failure threshold is reached.
+	AgentRecoveryReconnect AgentRecoveryPolicy = "reconnect"
+
+	// AgentRecoveryDumpConsole logs the guest console output before
+	// reporting the failure.
+	AgentRecoveryDumpConsole AgentRecoveryPolicy = "dump-console"
+)
+
+func (p AgentRecoveryPolicy) valid() bool {
+	switch p {
+	case "", AgentRecoveryKill, AgentRecoveryReconnect, AgentRecoveryDumpConsole:
+		return true
+	}
+
+	return false
+}
+
+// AgentHealthCheckConfig configures how the sandbox monitor checks the
+// agent liveness, and what it does when the agent does not answer.
+type AgentHealthCheckConfig struct {
+	// Interval between two checks, defaults to 1 second.
+	Interval time.Duration
+
+	// Timeout of a single check, defaults to 30 seconds.
+	Timeout time.Duration
+
+	// FailureThreshold is the number of consecutive failed checks after
+	// which the agent is reported dead, defaults to 1.
+	FailureThreshold uint32
+
+	// Recovery is the policy applied on failed checks, defaults to
+	// AgentRecoveryKill.
+	Recovery AgentRecoveryPolicy
+}
+
+// Valid checks the health check configuration.
+func (c AgentHealthCheckConfig) Valid() error {
+	if c.Interval < 0 || c.Timeout < 0 {
+		return fmt.Errorf("Invalid agent health check interval %v or timeout %v", c.Interval, c.Timeout)
+	}
+
+	if !c.Recovery.valid() {
+		return fmt.Errorf("Invalid agent recovery policy %q", c.Recovery)
+	}
+
+	return nil
+}
+
+func (c AgentHealthCheckConfig) withDefaults() AgentHealthCheckConfig {
+	if c.Interval == 0 {
+		c.Interval = defaultCheckInterval
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = checkRequestTimeout
+	}
+
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = 1
+	}
+
+	if c.Recovery == "" {
+		c.Recovery = AgentRecoveryKill
+	}
+
+	return c
+}
+
+// MonitorStatus describes the state of the sandbox monitor.
+type MonitorStatus struct {
+	// Running is true when the sandbox is being watched.
+	Running bool
+
+	// AgentHealthy is false when the last agent check failed.
+	AgentHealthy bool
+
+	// ConsecutiveFailures is the number of agent checks which failed
+	// since the last successful one.
+	ConsecutiveFailures uint32
+
+	// Reconnects is the number of times the agent check connection has been
+	// reset by the reconnect recovery policy.
+	Reconnects uint32
+
+	LastCheck time.Time
+	LastError string
+}
+
 type monitor struct {
 	sync.Mutex
 
 	sandbox       *Sandbox
 	checkInterval time.Duration
+	healthCheck   AgentHealthCheckConfig
 	watchers      []chan error
 	wg            sync.WaitGroup
 	running       bool
 	stopCh        chan bool
+
+	// agent health, protected by the monitor lock
+	status         MonitorStatus
+	nextAgentCheck time.Time
+
+	// paused is true while the VM of the sandbox is paused, the agent
+	// not answering then.
+	paused bool
 }
 
 func newMonitor(s *Sandbox) *monitor {
+	var healthCheck AgentHealthCheckConfig
+	if c, ok := s.config.AgentConfig.(KataAgentConfig); ok {
+		healthCheck = c.HealthCheck
+	}
+	healthCheck = healthCheck.withDefaults()
+
 	return &monitor{
 		sandbox:       s,
 		checkInterval: defaultCheckInterval,
+		healthCheck:   healthCheck,
 		stopCh:        make(chan bool, 1),
+		status:        MonitorStatus{AgentHealthy: true},
 	}
 }
 
@@ -47,17 +178,21 @@ func (m *monitor) newWatcher() (chan error, error) {
 		m.running = true
 		m.wg.Add(1)
 
-		// create and start agent watcher
+		// create and start agent watcher, the hypervisor and the agent
+		// being checked at their own interval
 		go func() {
 			tick := time.NewTicker(m.checkInterval)
+			agentTick := time.NewTicker(m.healthCheck.Interval)
 			for {
 				select {
 				case <-m.stopCh:
 					tick.Stop()
+					agentTick.Stop()
 					m.wg.Done()
 					return
 				case <-tick.C:
 					m.watchHypervisor()
+				case <-agentTick.C:
 					m.watchAgent()
 				}
 			}
@@ -77,6 +212,11 @@ func (m *monitor) notify(err error) {
 		return
 	}
 
+	m.sandbox.emitEvent(&events.MonitorError{
+		SandboxId: m.sandbox.id,
+		Message:   err.Error(),
+	})
+
 	// a watcher is not supposed to close the channel
 	// but just in case...
 	defer func() {
@@ -127,11 +267,139 @@ func (m *monitor) stop() {
 	}
 }
 
+func (m *monitor) getStatus() MonitorStatus {
+	m.Lock()
+	defer m.Unlock()
+
+	status := m.status
+	status.Running = m.running
+
+	return status
+}
+
+// pause stops the agent checks while the VM of the sandbox is paused.
+func (m *monitor) pause() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.paused = true
+}
+
+// resume restarts the agent checks once the VM of the sandbox is resumed,
+// forgetting the failures of the checks which raced with the pause.
+func (m *monitor) resume() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.paused = false
+	m.status.AgentHealthy = true
+	m.status.ConsecutiveFailures = 0
+	m.status.LastError = ""
+	m.nextAgentCheck = time.Time{}
+}
+
 func (m *monitor) watchAgent() {
+	m.Lock()
+	skip := m.paused || time.Now().Before(m.nextAgentCheck)
+	m.Unlock()
+
+	if skip {
+		return
+	}
+
 	err := m.sandbox.agent.check()
+
+	m.Lock()
+	if m.paused {
+		m.Unlock()
+		return
+	}
+	m.status.LastCheck = time.Now()
+	if err == nil {
+		m.status.AgentHealthy = true
+		m.status.ConsecutiveFailures = 0
+		m.status.LastError = ""
+		m.nextAgentCheck = time.Time{}
+		m.Unlock()
+		return
+	}
+
+	m.status.AgentHealthy = false
+	m.status.ConsecutiveFailures++
+	m.status.LastError = err.Error()
+	failures := m.status.ConsecutiveFailures
+	m.Unlock()
+
+	logger := m.sandbox.Logger().WithError(err).WithFields(logrus.Fields{
+		"failures":  failures,
+		"threshold": m.healthCheck.FailureThreshold,
+		"recovery":  m.healthCheck.Recovery,
+	})
+
+	if failures < m.healthCheck.FailureThreshold {
+		logger.Warn("agent health check failed")
+
+		if m.healthCheck.Recovery == AgentRecoveryReconnect {
+			m.reconnectAgent(failures)
+		}
+		return
+	}
+
+	logger.Error("agent is not responding")
+
+	if m.healthCheck.Recovery == AgentRecoveryDumpConsole {
+		m.dumpConsole()
+	}
+
+	// TODO: define and export error types
+	m.notify(errors.Wrapf(err, "failed to ping agent"))
+}
+
+// reconnectAgent drops the connection the agent is checked on, so that the
+// next check opens a new one, and delays that check with an exponential
+// backoff. The connection of the other requests is left alone.
+func (m *monitor) reconnectAgent(failures uint32) {
+	if err := m.sandbox.agent.resetCheck(); err != nil {
+		m.sandbox.Logger().WithError(err).Warn("failed to close agent check connection")
+	}
+
+	backoff := m.healthCheck.Interval
+	for i := uint32(1); i < failures && backoff < maxAgentCheckBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxAgentCheckBackoff {
+		backoff = maxAgentCheckBackoff
+	}
+
+	m.Lock()
+	m.status.Reconnects++
+	m.nextAgentCheck = time.Now().Add(backoff)
+	m.Unlock()
+}
+
+// dumpConsole logs what the guest writes on its console for a little
+// while, to help understanding why the agent stopped answering.
+func (m *monitor) dumpConsole() {
+	logger := m.sandbox.Logger()
+
+	console, err := m.sandbox.hypervisor.getSandboxConsole(m.sandbox.id)
+	if err != nil || console == "" || strings.HasPrefix(console, client.HybridVSockScheme) {
+		logger.WithError(err).Warn("guest console not available")
+		return
+	}
+
+	conn, err := net.DialTimeout("unix", console, consoleDumpTimeout)
 	if err != nil {
-		// TODO: define and export error types
-		m.notify(errors.Wrapf(err, "failed to ping agent"))
+		logger.WithError(err).WithField("console-socket", console).Warn("failed to open guest console")
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(consoleDumpTimeout))
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		logger.WithField("vmconsole", scanner.Text()).Error("guest console dump")
 	}
 }
 
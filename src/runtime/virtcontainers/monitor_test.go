@@ -8,6 +8,7 @@ package virtcontainers
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,3 +59,174 @@ func TestMonitorClosedChannel(t *testing.T) {
 
 	m.stop()
 }
+
+// checkAgent is an agent whose health check fails until it is told
+// otherwise.
+type checkAgent struct {
+	noopAgent
+	checkErr error
+	resets   int
+}
+
+func (a *checkAgent) check() error {
+	return a.checkErr
+}
+
+func (a *checkAgent) disconnect() error {
+	panic("the shared agent connection must not be closed")
+}
+
+func (a *checkAgent) resetCheck() error {
+	a.resets++
+	return nil
+}
+
+func TestMonitorAgentFailureThreshold(t *testing.T) {
+	assert := assert.New(t)
+
+	agent := &checkAgent{checkErr: errors.New("timeout")}
+	s := &Sandbox{
+		id:         testSandboxID,
+		agent:      agent,
+		hypervisor: &mockHypervisor{},
+		config: &SandboxConfig{
+			AgentConfig: KataAgentConfig{
+				HealthCheck: AgentHealthCheckConfig{
+					FailureThreshold: 3,
+				},
+			},
+		},
+	}
+
+	m := newMonitor(s)
+	ch, err := m.newWatcher()
+	assert.NoError(err)
+	defer m.stop()
+
+	// Stop the ticker loop, the checks are driven by the test.
+	m.stopCh <- true
+	m.wg.Wait()
+
+	m.watchAgent()
+	m.watchAgent()
+	assert.Empty(ch)
+
+	status := m.getStatus()
+	assert.False(status.AgentHealthy)
+	assert.Equal(uint32(2), status.ConsecutiveFailures)
+	assert.Equal("timeout", status.LastError)
+
+	// A successful check resets the failures
+	agent.checkErr = nil
+	m.watchAgent()
+	status = m.getStatus()
+	assert.True(status.AgentHealthy)
+	assert.Zero(status.ConsecutiveFailures)
+
+	agent.checkErr = errors.New("timeout")
+	for i := 0; i < 3; i++ {
+		m.watchAgent()
+	}
+	assert.Error(<-ch)
+	assert.Zero(agent.resets)
+}
+
+func TestMonitorAgentPaused(t *testing.T) {
+	assert := assert.New(t)
+
+	agent := &checkAgent{checkErr: errors.New("timeout")}
+	s := &Sandbox{
+		id:         testSandboxID,
+		agent:      agent,
+		hypervisor: &mockHypervisor{},
+		config: &SandboxConfig{
+			AgentConfig: KataAgentConfig{
+				HealthCheck: AgentHealthCheckConfig{
+					FailureThreshold: 2,
+				},
+			},
+		},
+	}
+
+	m := newMonitor(s)
+	ch, err := m.newWatcher()
+	assert.NoError(err)
+	defer m.stop()
+
+	// Stop the ticker loop, the checks are driven by the test.
+	m.stopCh <- true
+	m.wg.Wait()
+
+	m.watchAgent()
+	assert.Equal(uint32(1), m.getStatus().ConsecutiveFailures)
+
+	// The agent is not checked while the VM is paused
+	m.pause()
+	for i := 0; i < 3; i++ {
+		m.watchAgent()
+	}
+	assert.Empty(ch)
+	assert.Equal(uint32(1), m.getStatus().ConsecutiveFailures)
+
+	// and its failures are forgotten once it is resumed
+	m.resume()
+	status := m.getStatus()
+	assert.True(status.AgentHealthy)
+	assert.Zero(status.ConsecutiveFailures)
+
+	m.watchAgent()
+	assert.Empty(ch)
+}
+
+func TestMonitorAgentReconnect(t *testing.T) {
+	assert := assert.New(t)
+
+	agent := &checkAgent{checkErr: errors.New("timeout")}
+	s := &Sandbox{
+		id:         testSandboxID,
+		agent:      agent,
+		hypervisor: &mockHypervisor{},
+		config: &SandboxConfig{
+			AgentConfig: KataAgentConfig{
+				HealthCheck: AgentHealthCheckConfig{
+					FailureThreshold: 3,
+					Recovery:         AgentRecoveryReconnect,
+				},
+			},
+		},
+	}
+
+	m := newMonitor(s)
+
+	m.watchAgent()
+	assert.Equal(1, agent.resets)
+
+	// The next check is delayed
+	m.watchAgent()
+	assert.Equal(1, agent.resets)
+	assert.Equal(uint32(1), m.getStatus().ConsecutiveFailures)
+
+	m.nextAgentCheck = time.Time{}
+	m.watchAgent()
+	assert.Equal(2, agent.resets)
+	assert.True(m.nextAgentCheck.Sub(m.getStatus().LastCheck) >= 2*defaultCheckInterval)
+
+	status := m.getStatus()
+	assert.Equal(uint32(2), status.Reconnects)
+	assert.Equal(uint32(2), status.ConsecutiveFailures)
+}
+
+func TestAgentHealthCheckConfigValid(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(AgentHealthCheckConfig{}.Valid())
+	assert.NoError(AgentHealthCheckConfig{Recovery: AgentRecoveryDumpConsole}.Valid())
+	assert.Error(AgentHealthCheckConfig{Recovery: "restart"}.Valid())
+	assert.Error(AgentHealthCheckConfig{Interval: -time.Second}.Valid())
+
+	c := AgentHealthCheckConfig{}.withDefaults()
+	assert.Equal(defaultCheckInterval, c.Interval)
+	assert.Equal(checkRequestTimeout, c.Timeout)
+	assert.Equal(uint32(1), c.FailureThreshold)
+	assert.Equal(AgentRecoveryKill, c.Recovery)
+}
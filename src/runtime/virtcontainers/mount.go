@@ -303,7 +303,8 @@ type Mount struct {
 
 	// BlockDeviceID represents block device that is attached to the
 	// VM in case this mount is a block device file or a directory
-	// backed by a block device.
+	// backed by a block device. It is also the virtio-fs device sharing
+	// the mount when the mount has its own virtio-fs device.
 	BlockDeviceID string
 }
 
@@ -982,7 +982,7 @@ func generateVCNetworkStructures(networkNS NetworkNamespace) ([]*vcTypes.Interfa
 			Mtu:         uint64(endpoint.Properties().Iface.MTU),
 			RawFlags:    noarp,
 			HwAddr:      endpoint.HardwareAddr(),
-			PciAddr:     endpoint.PciAddr(),
+			PciAddr:     endpoint.PciPath().String(),
 		}
 
 		ifaces = append(ifaces, &ifc)
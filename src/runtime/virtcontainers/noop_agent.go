@@ -51,6 +51,11 @@ func (n *noopAgent) disconnect() error {
 	return nil
 }
 
+// resetCheck is the Noop agent check connection closer. It does nothing.
+func (n *noopAgent) resetCheck() error {
+	return nil
+}
+
 // exec is the Noop agent command execution implementation. It does nothing.
 func (n *noopAgent) exec(sandbox *Sandbox, c Container, cmd types.Cmd) (*Process, error) {
 	return nil, nil
@@ -46,8 +46,9 @@ func (s *Sandbox) dumpState(ss *persistapi.SandboxState, cs map[string]persistap
 		}
 		state.State = string(cont.state.State)
 		state.Rootfs = persistapi.RootfsState{
-			BlockDeviceID: cont.state.BlockDeviceID,
-			FsType:        cont.state.Fstype,
+			BlockDeviceID:  cont.state.BlockDeviceID,
+			FsType:         cont.state.Fstype,
+			LayerDeviceIDs: cont.state.LayerDeviceIDs,
 		}
 		state.CgroupPath = cont.state.CgroupPath
 		cs[id] = state
@@ -199,6 +200,7 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		SystemdCgroup:       sconfig.SystemdCgroup,
 		SandboxCgroupOnly:   sconfig.SandboxCgroupOnly,
 		DisableGuestSeccomp: sconfig.DisableGuestSeccomp,
+		GuestSysctls:        sconfig.GuestSysctls,
 		Cgroups:             sconfig.Cgroups,
 	}
 
@@ -230,6 +232,9 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		MemoryPath:              sconfig.HypervisorConfig.MemoryPath,
 		DevicesStatePath:        sconfig.HypervisorConfig.DevicesStatePath,
 		EntropySource:           sconfig.HypervisorConfig.EntropySource,
+		EntropyMaxBytes:         sconfig.HypervisorConfig.EntropyMaxBytes,
+		EntropyPeriod:           sconfig.HypervisorConfig.EntropyPeriod,
+		EntropyReseedInterval:   sconfig.HypervisorConfig.EntropyReseedInterval,
 		SharedFS:                sconfig.HypervisorConfig.SharedFS,
 		VirtioFSDaemon:          sconfig.HypervisorConfig.VirtioFSDaemon,
 		VirtioFSCache:           sconfig.HypervisorConfig.VirtioFSCache,
@@ -243,6 +248,9 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		MemPrealloc:             sconfig.HypervisorConfig.MemPrealloc,
 		HugePages:               sconfig.HypervisorConfig.HugePages,
 		FileBackedMemRootDir:    sconfig.HypervisorConfig.FileBackedMemRootDir,
+		CPUPlacementPolicy:      string(sconfig.HypervisorConfig.CPUPlacementPolicy),
+		HousekeepingCPUs:        sconfig.HypervisorConfig.HousekeepingCPUs,
+		MemoryHostNodes:         sconfig.HypervisorConfig.memoryHostNodes,
 		Realtime:                sconfig.HypervisorConfig.Realtime,
 		Mlock:                   sconfig.HypervisorConfig.Mlock,
 		DisableNestingChecks:    sconfig.HypervisorConfig.DisableNestingChecks,
@@ -255,6 +263,7 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 		DisableVhostNet:         sconfig.HypervisorConfig.DisableVhostNet,
 		EnableVhostUserStore:    sconfig.HypervisorConfig.EnableVhostUserStore,
 		VhostUserStorePath:      sconfig.HypervisorConfig.VhostUserStorePath,
+		PassthroughCharDevices:  sconfig.HypervisorConfig.PassthroughCharDevices,
 		GuestHookPath:           sconfig.HypervisorConfig.GuestHookPath,
 		VMid:                    sconfig.HypervisorConfig.VMid,
 		RxRateLimiterMaxRate:    sconfig.HypervisorConfig.RxRateLimiterMaxRate,
@@ -332,10 +341,11 @@ func (s *Sandbox) loadState(ss persistapi.SandboxState) {
 
 func (c *Container) loadContState(cs persistapi.ContainerState) {
 	c.state = types.ContainerState{
-		State:         types.StateString(cs.State),
-		BlockDeviceID: cs.Rootfs.BlockDeviceID,
-		Fstype:        cs.Rootfs.FsType,
-		CgroupPath:    cs.CgroupPath,
+		State:          types.StateString(cs.State),
+		BlockDeviceID:  cs.Rootfs.BlockDeviceID,
+		Fstype:         cs.Rootfs.FsType,
+		LayerDeviceIDs: cs.Rootfs.LayerDeviceIDs,
+		CgroupPath:     cs.CgroupPath,
 	}
 }
 
@@ -490,6 +500,7 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		SystemdCgroup:       savedConf.SystemdCgroup,
 		SandboxCgroupOnly:   savedConf.SandboxCgroupOnly,
 		DisableGuestSeccomp: savedConf.DisableGuestSeccomp,
+		GuestSysctls:        savedConf.GuestSysctls,
 		Cgroups:             savedConf.Cgroups,
 	}
 
@@ -522,6 +533,9 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		MemoryPath:              hconf.MemoryPath,
 		DevicesStatePath:        hconf.DevicesStatePath,
 		EntropySource:           hconf.EntropySource,
+		EntropyMaxBytes:         hconf.EntropyMaxBytes,
+		EntropyPeriod:           hconf.EntropyPeriod,
+		EntropyReseedInterval:   hconf.EntropyReseedInterval,
 		SharedFS:                hconf.SharedFS,
 		VirtioFSDaemon:          hconf.VirtioFSDaemon,
 		VirtioFSCache:           hconf.VirtioFSCache,
@@ -535,6 +549,9 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		MemPrealloc:             hconf.MemPrealloc,
 		HugePages:               hconf.HugePages,
 		FileBackedMemRootDir:    hconf.FileBackedMemRootDir,
+		CPUPlacementPolicy:      CPUPlacementPolicy(hconf.CPUPlacementPolicy),
+		HousekeepingCPUs:        hconf.HousekeepingCPUs,
+		memoryHostNodes:         hconf.MemoryHostNodes,
 		Realtime:                hconf.Realtime,
 		Mlock:                   hconf.Mlock,
 		DisableNestingChecks:    hconf.DisableNestingChecks,
@@ -547,6 +564,7 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 		DisableVhostNet:         hconf.DisableVhostNet,
 		EnableVhostUserStore:    hconf.EnableVhostUserStore,
 		VhostUserStorePath:      hconf.VhostUserStorePath,
+		PassthroughCharDevices:  hconf.PassthroughCharDevices,
 		GuestHookPath:           hconf.GuestHookPath,
 		VMid:                    hconf.VMid,
 		RxRateLimiterMaxRate:    hconf.RxRateLimiterMaxRate,
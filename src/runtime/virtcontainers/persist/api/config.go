@@ -86,6 +86,18 @@ type HypervisorConfig struct {
 	// entropy (/dev/random, /dev/urandom or real hardware RNG device)
 	EntropySource string
 
+	// EntropyMaxBytes is the number of bytes the guest can read from
+	// the entropy source per EntropyPeriod, 0 meaning no limit.
+	EntropyMaxBytes uint32
+
+	// EntropyPeriod is the rate limiting period of the entropy source,
+	// in milliseconds.
+	EntropyPeriod uint32
+
+	// EntropyReseedInterval is the interval, in seconds, between two
+	// reseeds of the guest random number generator.
+	EntropyReseedInterval uint32
+
 	// Shared file system type:
 	//   - virtio-9p (default)
 	//   - virtio-fs
@@ -103,6 +115,18 @@ type HypervisorConfig struct {
 	// File based memory backend root directory
 	FileBackedMemRootDir string
 
+	// CPUPlacementPolicy selects how the hypervisor threads are placed on
+	// the host CPUs and NUMA nodes.
+	CPUPlacementPolicy string
+
+	// HousekeepingCPUs is the host CPU list where the hypervisor threads
+	// other than the vCPUs run with the NUMA CPU placement policy.
+	HousekeepingCPUs string
+
+	// MemoryHostNodes are the host NUMA nodes the VM memory is bound to
+	// with the NUMA CPU placement policy.
+	MemoryHostNodes []int
+
 	// BlockDeviceCacheSet specifies cache-related options will be set to block devices or not.
 	BlockDeviceCacheSet bool
 
@@ -176,6 +200,10 @@ type HypervisorConfig struct {
 	// related folders, sockets and device nodes should be.
 	VhostUserStorePath string
 
+	// PassthroughCharDevices are the glob patterns of the host character
+	// devices passed through to the guest.
+	PassthroughCharDevices []string
+
 	// GuestHookPath is the path within the VM that will be used for 'drop-in' hooks
 	GuestHookPath string
 
@@ -262,6 +290,9 @@ type SandboxConfig struct {
 
 	DisableGuestSeccomp bool
 
+	// GuestSysctls are the non-namespaced sysctls applied to the guest kernel
+	GuestSysctls map[string]string
+
 	// Experimental enables experimental features
 	Experimental []string
 
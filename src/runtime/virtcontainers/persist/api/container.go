@@ -64,6 +64,10 @@ type RootfsState struct {
 
 	// RootFStype is file system of the rootfs incase it is block device
 	FsType string
+
+	// LayerDeviceIDs represents the container rootfs layer devices IDs
+	// when made of layer images
+	LayerDeviceIDs []string
 }
 
 // Process gathers data related to a container process.
@@ -26,8 +26,11 @@ type BlockDrive struct {
 	// MmioAddr is used to identify the slot at which the drive is attached (order?).
 	MmioAddr string
 
-	// PCIAddr is the PCI address used to identify the slot at which the drive is attached.
-	PCIAddr string
+	// PCIPath is the PCI path used to identify the slot at which the drive is attached.
+	PCIPath string
+
+	// PCIAddr is the PCIPath of version 2, see SandboxState.Upgrade.
+	PCIAddr string `json:",omitempty"`
 
 	// SCSI Address of the block device, in case the device is attached using SCSI driver
 	// SCSI address is in the format SCSI-Id:LUN
@@ -60,6 +63,28 @@ type VFIODev struct {
 
 	// Sysfsdev of VFIO mediated device
 	SysfsDev string
+
+	// GuestPciPath is the PCI path of the device in the guest
+	GuestPciPath string
+}
+
+// CharDev represents a host character device passed through to the guest
+type CharDev struct {
+	ID       string
+	HostPath string
+
+	// Transport is the transport the device is passed through with:
+	// virtio-serial or vsock
+	Transport string
+
+	// PortName is the name of the virtio-serial port in the guest
+	PortName string
+
+	// SocketPath is the host socket of the virtio-serial port
+	SocketPath string
+
+	// VsockPort is the vsock port the agent listens on for the device
+	VsockPort uint32
 }
 
 // VhostUserDeviceAttrs represents data shared by most vhost-user devices
@@ -71,12 +96,28 @@ type VhostUserDeviceAttrs struct {
 	// MacAddress is only meaningful for vhost user net device
 	MacAddress string
 
-	// PCIAddr is the PCI address used to identify the slot at which the drive is attached.
+	// PCIPath is the PCI path used to identify the slot at which the drive is attached.
 	// It is only meaningful for vhost user block devices
-	PCIAddr string
+	PCIPath string
+
+	// PCIAddr is the PCIPath of version 2, see SandboxState.Upgrade.
+	PCIAddr string `json:",omitempty"`
 
 	// Block index of the device if assigned
 	Index int
+
+	// ReadOnly tells if the backend of a vhost user block device only
+	// serves reads
+	ReadOnly bool
+
+	// Tag is the tag the guest mounts a vhost user fs device with
+	Tag string
+
+	// Cache is the cache mode of the daemon of a vhost user fs device
+	Cache string
+
+	// DaemonPid is the pid of the daemon of a vhost user fs device
+	DaemonPid int
 }
 
 // DeviceState is sandbox level resource which represents host devices
@@ -116,5 +157,8 @@ type DeviceState struct {
 
 	// VhostUserDeviceAttrs is specific for vhost-user device driver
 	VhostUserDev *VhostUserDeviceAttrs `json:",omitempty"`
+
+	// CharDev is specific for passthrough character device driver
+	CharDev *CharDev `json:",omitempty"`
 	// ============ end device driver specific data ===========
 }
@@ -48,7 +48,10 @@ type PhysicalEndpoint struct {
 type MacvtapEndpoint struct {
 	// This is for showing information.
 	// Remove this field won't impact anything.
-	PCIAddr string
+	PCIPath string
+
+	// PCIAddr is the PCIPath of version 2, see SandboxState.Upgrade.
+	PCIAddr string `json:",omitempty"`
 }
 
 type TapEndpoint struct {
@@ -75,7 +78,10 @@ type VhostUserEndpoint struct {
 	// This is for showing information.
 	// Remove these fields won't impact anything.
 	IfaceName string
-	PCIAddr   string
+	PCIPath   string
+
+	// PCIAddr is the PCIPath of version 2, see SandboxState.Upgrade.
+	PCIAddr string `json:",omitempty"`
 }
 
 // NetworkEndpoint contains network interface information
@@ -15,5 +15,43 @@ const (
 	// If you can't be sure if the change in persistapi package
 	// requires a bump of CurPersistVersion or not, do it for peace!
 	// --@WeiZhang555
-	CurPersistVersion uint = 2
+	CurPersistVersion uint = 3
 )
+
+// Upgrade converts the sandbox state saved by an older runtime to
+// CurPersistVersion.
+func (ss *SandboxState) Upgrade() {
+	if ss.PersistVersion >= CurPersistVersion {
+		return
+	}
+
+	// Version 2 saved the PCI paths, in the same "bridge/device" form,
+	// as PCIAddr.
+	if ss.PersistVersion < 3 {
+		for _, d := range ss.Devices {
+			if d.BlockDrive != nil {
+				d.BlockDrive.PCIPath, d.BlockDrive.PCIAddr = upgradePCIAddr(d.BlockDrive.PCIPath, d.BlockDrive.PCIAddr)
+			}
+			if d.VhostUserDev != nil {
+				d.VhostUserDev.PCIPath, d.VhostUserDev.PCIAddr = upgradePCIAddr(d.VhostUserDev.PCIPath, d.VhostUserDev.PCIAddr)
+			}
+		}
+		for _, e := range ss.Network.Endpoints {
+			if e.Macvtap != nil {
+				e.Macvtap.PCIPath, e.Macvtap.PCIAddr = upgradePCIAddr(e.Macvtap.PCIPath, e.Macvtap.PCIAddr)
+			}
+			if e.VhostUser != nil {
+				e.VhostUser.PCIPath, e.VhostUser.PCIAddr = upgradePCIAddr(e.VhostUser.PCIPath, e.VhostUser.PCIAddr)
+			}
+		}
+	}
+
+	ss.PersistVersion = CurPersistVersion
+}
+
+func upgradePCIAddr(pciPath, pciAddr string) (string, string) {
+	if pciPath == "" {
+		return pciAddr, ""
+	}
+	return pciPath, ""
+}
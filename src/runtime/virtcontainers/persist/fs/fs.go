@@ -187,6 +187,7 @@ func (fs *FS) FromDisk(sid string) (persistapi.SandboxState, map[string]persista
 	if err := json.NewDecoder(f).Decode(fs.sandboxState); err != nil {
 		return ss, nil, err
 	}
+	fs.sandboxState.Upgrade()
 
 	// walk sandbox dir and find container
 	files, err := ioutil.ReadDir(sandboxDir)
@@ -7,7 +7,9 @@ package fs
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
@@ -182,3 +184,48 @@ func TestGlobalReadWrite(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, out)
 }
+
+func TestFsUpgradeVersion2(t *testing.T) {
+	defer initTestDir()()
+
+	fs, err := getFsDriver()
+	assert.Nil(t, err)
+	assert.NotNil(t, fs)
+
+	id := "test-fs-upgrade"
+	sandboxDir, err := fs.sandboxDir(id)
+	assert.Nil(t, err)
+	assert.Nil(t, os.MkdirAll(sandboxDir, dirMode))
+
+	// state saved by a version 2 runtime, with the PCI paths as PCIAddr
+	state := `{
+		"PersistVersion": 2,
+		"SandboxContainer": "test-fs-upgrade",
+		"Devices": [
+			{"ID": "blk", "BlockDrive": {"PCIAddr": "02/03"}},
+			{"ID": "vhost", "VhostUserDev": {"PCIAddr": "02/04"}}
+		],
+		"Network": {"Endpoints": [
+			{"Type": "macvtap", "Macvtap": {"PCIAddr": "02/05"}},
+			{"Type": "vhost-user", "VhostUser": {"IfaceName": "eth0", "PCIAddr": "02/06"}}
+		]}
+	}`
+	err = ioutil.WriteFile(filepath.Join(sandboxDir, persistFile), []byte(state), fileMode)
+	assert.Nil(t, err)
+
+	ss, _, err := fs.FromDisk(id)
+	assert.Nil(t, err)
+	assert.Equal(t, persistapi.CurPersistVersion, ss.PersistVersion)
+
+	assert.Len(t, ss.Devices, 2)
+	assert.Equal(t, "02/03", ss.Devices[0].BlockDrive.PCIPath)
+	assert.Empty(t, ss.Devices[0].BlockDrive.PCIAddr)
+	assert.Equal(t, "02/04", ss.Devices[1].VhostUserDev.PCIPath)
+	assert.Empty(t, ss.Devices[1].VhostUserDev.PCIAddr)
+
+	assert.Len(t, ss.Network.Endpoints, 2)
+	assert.Equal(t, "02/05", ss.Network.Endpoints[0].Macvtap.PCIPath)
+	assert.Empty(t, ss.Network.Endpoints[0].Macvtap.PCIAddr)
+	assert.Equal(t, "02/06", ss.Network.Endpoints[1].VhostUser.PCIPath)
+	assert.Empty(t, ss.Network.Endpoints[1].VhostUser.PCIAddr)
+}
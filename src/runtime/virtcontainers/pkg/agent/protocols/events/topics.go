@@ -0,0 +1,37 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package events
+
+// Topics of the kata events, published under the containerd namespace of
+// the sandbox.
+const (
+	SandboxBootedTopic  = "/kata/sandbox/booted"
+	SandboxResizedTopic = "/kata/sandbox/resized"
+	DeviceHotplugTopic  = "/kata/device/hotplug"
+	AgentConnectedTopic = "/kata/agent/connected"
+	MonitorErrorTopic   = "/kata/monitor/error"
+)
+
+// Hotplug actions of the DeviceHotplug event.
+const (
+	HotplugAdd    = "add"
+	HotplugRemove = "remove"
+)
+
+// Topic returns the topic the event is published on.
+func (*SandboxBooted) Topic() string { return SandboxBootedTopic }
+
+// Topic returns the topic the event is published on.
+func (*SandboxResized) Topic() string { return SandboxResizedTopic }
+
+// Topic returns the topic the event is published on.
+func (*DeviceHotplug) Topic() string { return DeviceHotplugTopic }
+
+// Topic returns the topic the event is published on.
+func (*AgentConnected) Topic() string { return AgentConnectedTopic }
+
+// Topic returns the topic the event is published on.
+func (*MonitorError) Topic() string { return MonitorErrorTopic }
@@ -1377,10 +1377,13 @@ type CreateSandboxRequest struct {
 	// that the agent will search for OCI hooks to run within the guest.
 	GuestHookPath string `protobuf:"bytes,6,opt,name=guest_hook_path,json=guestHookPath,proto3" json:"guest_hook_path,omitempty"`
 	// This field is the list of kernel modules to be loaded in the guest kernel.
-	KernelModules        []*KernelModule `protobuf:"bytes,7,rep,name=kernel_modules,json=kernelModules,proto3" json:"kernel_modules,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	KernelModules []*KernelModule `protobuf:"bytes,7,rep,name=kernel_modules,json=kernelModules,proto3" json:"kernel_modules,omitempty"`
+	// This field is the list of non-namespaced sysctls to be applied once
+	// to the guest kernel, before any container is created.
+	Sysctls              map[string]string `protobuf:"bytes,8,rep,name=sysctls,proto3" json:"sysctls,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *CreateSandboxRequest) Reset()      { *m = CreateSandboxRequest{} }
@@ -2493,6 +2496,7 @@ func init() {
 	proto.RegisterType((*TtyWinResizeRequest)(nil), "grpc.TtyWinResizeRequest")
 	proto.RegisterType((*KernelModule)(nil), "grpc.KernelModule")
 	proto.RegisterType((*CreateSandboxRequest)(nil), "grpc.CreateSandboxRequest")
+	proto.RegisterMapType((map[string]string)(nil), "grpc.CreateSandboxRequest.SysctlsEntry")
 	proto.RegisterType((*DestroySandboxRequest)(nil), "grpc.DestroySandboxRequest")
 	proto.RegisterType((*Interfaces)(nil), "grpc.Interfaces")
 	proto.RegisterType((*Routes)(nil), "grpc.Routes")
@@ -2524,196 +2528,198 @@ func init() {
 }
 
 var fileDescriptor_c1460208c38ccf5e = []byte{
-	// 3020 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3a, 0x4b, 0x73, 0x1b, 0xc7,
-	0xd1, 0xc6, 0x83, 0x24, 0xd0, 0x00, 0x08, 0x62, 0x49, 0x51, 0x10, 0x64, 0xf3, 0x93, 0xd7, 0xb6,
-	0x4c, 0x7f, 0xfe, 0x0c, 0xda, 0xb2, 0xeb, 0x93, 0x1f, 0xe5, 0xa8, 0x44, 0x8a, 0x26, 0x69, 0x5b,
-	0x26, 0xbd, 0x94, 0xca, 0xa9, 0xa4, 0x92, 0xad, 0xe5, 0xee, 0x08, 0x18, 0x13, 0xbb, 0xb3, 0x9e,
-	0x99, 0xa5, 0x08, 0x27, 0x95, 0xca, 0x29, 0xb9, 0xe5, 0x96, 0xdc, 0xf2, 0x07, 0x52, 0xb9, 0xe5,
-	0x98, 0x6b, 0x0e, 0xae, 0x9c, 0x72, 0xcc, 0x29, 0x15, 0xeb, 0x27, 0xe4, 0x17, 0xa4, 0xe6, 0xb5,
-	0x0f, 0x00, 0x84, 0x63, 0x16, 0xab, 0x72, 0x41, 0x6d, 0xf7, 0xf4, 0xf4, 0x6b, 0x66, 0x7a, 0xba,
-	0x7b, 0x00, 0x9f, 0x0f, 0x30, 0x1f, 0x26, 0x27, 0x7d, 0x9f, 0x84, 0x5b, 0xa7, 0x1e, 0xf7, 0xde,
-	0xf0, 0x49, 0xc4, 0x3d, 0x1c, 0x21, 0xca, 0xa6, 0x60, 0x46, 0xfd, 0x2d, 0x6f, 0x80, 0x22, 0xbe,
-	0x15, 0x53, 0xc2, 0x89, 0x4f, 0x46, 0x4c, 0x7d, 0x31, 0x85, 0xee, 0x4b, 0xc0, 0xaa, 0x0e, 0x68,
-	0xec, 0xf7, 0x0e, 0xaf, 0x86, 0x31, 0xf1, 0xb1, 0x62, 0xdb, 0xfb, 0xf9, 0xd5, 0x30, 0x9c, 0xc3,
-	0x45, 0xcf, 0x38, 0x1d, 0x6c, 0xf1, 0x71, 0x8c, 0x98, 0xfa, 0xd5, 0xd2, 0x6f, 0x0e, 0x08, 0x19,
-	0x8c, 0x90, 0xe2, 0x72, 0x92, 0x3c, 0xd9, 0x42, 0x61, 0xcc, 0xc7, 0x6a, 0xd0, 0xfe, 0x7d, 0x19,
-	0xd6, 0x77, 0x28, 0xf2, 0x38, 0xda, 0x31, 0xdc, 0x1c, 0xf4, 0x55, 0x82, 0x18, 0xb7, 0x5e, 0x84,
-	0x66, 0x2a, 0xc1, 0xc5, 0x41, 0xb7, 0x74, 0xab, 0xb4, 0x59, 0x77, 0x1a, 0x29, 0xee, 0x20, 0xb0,
-	0xae, 0xc3, 0x12, 0x3a, 0x47, 0xbe, 0x18, 0x2d, 0xcb, 0xd1, 0x45, 0x01, 0x1e, 0x04, 0xd6, 0x5b,
-	0xd0, 0x60, 0x9c, 0xe2, 0x68, 0xe0, 0x26, 0x0c, 0xd1, 0x6e, 0xe5, 0x56, 0x69, 0xb3, 0x71, 0x67,
-	0xa5, 0x2f, 0xdc, 0xdb, 0x3f, 0x96, 0x03, 0x8f, 0x19, 0xa2, 0x0e, 0xb0, 0xf4, 0xdb, 0xba, 0x0d,
-	0x4b, 0x01, 0x3a, 0xc3, 0x3e, 0x62, 0xdd, 0xea, 0xad, 0xca, 0x66, 0xe3, 0x4e, 0x53, 0x91, 0x3f,
-	0x90, 0x48, 0xc7, 0x0c, 0x5a, 0xaf, 0x41, 0x8d, 0x71, 0x42, 0xbd, 0x01, 0x62, 0xdd, 0x05, 0x49,
-	0xd8, 0x32, 0x7c, 0x25, 0xd6, 0x49, 0x87, 0xad, 0xe7, 0xa1, 0x72, 0xb8, 0x73, 0xd0, 0x5d, 0x94,
-	0xd2, 0x41, 0x53, 0xc5, 0xc8, 0x77, 0x04, 0xda, 0x7a, 0x09, 0x5a, 0xcc, 0x8b, 0x82, 0x13, 0x72,
-	0xee, 0xc6, 0x38, 0x88, 0x58, 0x77, 0xe9, 0x56, 0x69, 0xb3, 0xe6, 0x34, 0x35, 0xf2, 0x48, 0xe0,
-	0xec, 0xf7, 0xe1, 0xda, 0x31, 0xf7, 0x28, 0xbf, 0x84, 0x77, 0xec, 0xc7, 0xb0, 0xee, 0xa0, 0x90,
-	0x9c, 0x5d, 0xca, 0xb5, 0x5d, 0x58, 0xe2, 0x38, 0x44, 0x24, 0xe1, 0xd2, 0xb5, 0x2d, 0xc7, 0x80,
-	0xf6, 0x1f, 0x4b, 0x60, 0xed, 0x9e, 0x23, 0xff, 0x88, 0x12, 0x1f, 0x31, 0xf6, 0x5f, 0x5a, 0xae,
-	0x57, 0x61, 0x29, 0x56, 0x0a, 0x74, 0xab, 0x92, 0x5c, 0xaf, 0x82, 0xd1, 0xca, 0x8c, 0xda, 0x5f,
-	0xc2, 0xda, 0x31, 0x1e, 0x44, 0xde, 0xe8, 0x0a, 0xf5, 0x5d, 0x87, 0x45, 0x26, 0x79, 0x4a, 0x55,
-	0x5b, 0x8e, 0x86, 0xec, 0x23, 0xb0, 0xbe, 0xf0, 0x30, 0xbf, 0x3a, 0x49, 0xf6, 0x1b, 0xb0, 0x5a,
-	0xe0, 0xc8, 0x62, 0x12, 0x31, 0x24, 0x15, 0xe0, 0x1e, 0x4f, 0x98, 0x64, 0xb6, 0xe0, 0x68, 0xc8,
-	0x46, 0xb0, 0xf6, 0x29, 0x66, 0x86, 0x1c, 0x7d, 0x1f, 0x15, 0xd6, 0x61, 0xf1, 0x09, 0xa1, 0xa1,
-	0xc7, 0x8d, 0x06, 0x0a, 0xb2, 0x2c, 0xa8, 0x7a, 0x74, 0xc0, 0xba, 0x95, 0x5b, 0x95, 0xcd, 0xba,
-	0x23, 0xbf, 0xc5, 0xae, 0x9c, 0x10, 0xa3, 0xf5, 0x7a, 0x11, 0x9a, 0xda, 0xef, 0xee, 0x08, 0x33,
-	0x2e, 0xe5, 0x34, 0x9d, 0x86, 0xc6, 0x89, 0x39, 0x36, 0x81, 0xf5, 0xc7, 0x71, 0x70, 0xc9, 0x03,
-	0x7f, 0x07, 0xea, 0x14, 0x31, 0x92, 0x50, 0x71, 0x4c, 0xcb, 0x72, 0xdd, 0xd7, 0xd4, 0xba, 0x7f,
-	0x8a, 0xa3, 0xe4, 0xdc, 0x31, 0x63, 0x4e, 0x46, 0xa6, 0x8f, 0x10, 0x67, 0x97, 0x39, 0x42, 0xef,
-	0xc3, 0xb5, 0x23, 0x2f, 0x61, 0x97, 0xd1, 0xd5, 0xfe, 0x40, 0x1c, 0x3f, 0x96, 0x84, 0x97, 0x9a,
-	0xfc, 0x87, 0x12, 0xd4, 0x76, 0xe2, 0xe4, 0x31, 0xf3, 0x06, 0xc8, 0xfa, 0x1f, 0x68, 0x70, 0xc2,
-	0xbd, 0x91, 0x9b, 0x08, 0x50, 0x92, 0x57, 0x1d, 0x90, 0x28, 0x45, 0x20, 0xdc, 0x8e, 0xa8, 0x1f,
-	0x27, 0x9a, 0xa2, 0x7c, 0xab, 0xb2, 0x59, 0x75, 0x1a, 0x0a, 0xa7, 0x48, 0xfa, 0xb0, 0x2a, 0xc7,
-	0x5c, 0x1c, 0xb9, 0xa7, 0x88, 0x46, 0x68, 0x14, 0x92, 0x00, 0xc9, 0xfd, 0x5b, 0x75, 0x3a, 0x72,
-	0xe8, 0x20, 0xfa, 0x24, 0x1d, 0xb0, 0xfe, 0x17, 0x3a, 0x29, 0xbd, 0x38, 0x94, 0x92, 0xba, 0x2a,
-	0xa9, 0xdb, 0x9a, 0xfa, 0xb1, 0x46, 0xdb, 0xbf, 0x80, 0xe5, 0x47, 0x43, 0x4a, 0x38, 0x1f, 0xe1,
-	0x68, 0xf0, 0xc0, 0xe3, 0x9e, 0x88, 0x1e, 0x31, 0xa2, 0x98, 0x04, 0x4c, 0x6b, 0x6b, 0x40, 0xeb,
-	0x75, 0xe8, 0x70, 0x45, 0x8b, 0x02, 0xd7, 0xd0, 0x94, 0x25, 0xcd, 0x4a, 0x3a, 0x70, 0xa4, 0x89,
-	0x5f, 0x81, 0xe5, 0x8c, 0x58, 0xc4, 0x1f, 0xad, 0x6f, 0x2b, 0xc5, 0x3e, 0xc2, 0x21, 0xb2, 0xcf,
-	0xa4, 0xaf, 0xe4, 0x22, 0x5b, 0xaf, 0x43, 0x3d, 0xf3, 0x43, 0x49, 0xee, 0x90, 0x65, 0xb5, 0x43,
-	0x8c, 0x3b, 0x9d, 0x5a, 0xea, 0x94, 0x0f, 0xa1, 0xcd, 0x53, 0xc5, 0xdd, 0xc0, 0xe3, 0x5e, 0x71,
-	0x53, 0x15, 0xad, 0x72, 0x96, 0x79, 0x01, 0xb6, 0x3f, 0x80, 0xfa, 0x11, 0x0e, 0x98, 0x12, 0xdc,
-	0x85, 0x25, 0x3f, 0xa1, 0x14, 0x45, 0xdc, 0x98, 0xac, 0x41, 0x6b, 0x0d, 0x16, 0x46, 0x38, 0xc4,
-	0x5c, 0x9b, 0xa9, 0x00, 0x9b, 0x00, 0x3c, 0x44, 0x21, 0xa1, 0x63, 0xe9, 0xb0, 0x35, 0x58, 0xc8,
-	0x2f, 0xae, 0x02, 0xac, 0x9b, 0x50, 0x0f, 0xbd, 0xf3, 0x74, 0x51, 0xc5, 0x48, 0x2d, 0xf4, 0xce,
-	0x95, 0xf2, 0x5d, 0x58, 0x7a, 0xe2, 0xe1, 0x91, 0x1f, 0x71, 0xed, 0x15, 0x03, 0x66, 0x02, 0xab,
-	0x79, 0x81, 0x7f, 0x29, 0x43, 0x43, 0x49, 0x54, 0x0a, 0xaf, 0xc1, 0x82, 0xef, 0xf9, 0xc3, 0x54,
-	0xa4, 0x04, 0xac, 0xdb, 0x46, 0x91, 0x72, 0x3e, 0x08, 0x67, 0x9a, 0x1a, 0xd5, 0xb6, 0x00, 0xd8,
-	0x53, 0x2f, 0xd6, 0xba, 0x55, 0x2e, 0x20, 0xae, 0x0b, 0x1a, 0xa5, 0xee, 0xdb, 0xd0, 0x54, 0xfb,
-	0x4e, 0x4f, 0xa9, 0x5e, 0x30, 0xa5, 0xa1, 0xa8, 0xd4, 0xa4, 0x97, 0xa0, 0x95, 0x30, 0xe4, 0x0e,
-	0x31, 0xa2, 0x1e, 0xf5, 0x87, 0xe3, 0xee, 0x82, 0xba, 0x23, 0x13, 0x86, 0xf6, 0x0d, 0xce, 0xba,
-	0x03, 0x0b, 0x22, 0xfc, 0xb1, 0xee, 0xa2, 0xbc, 0x8e, 0x9f, 0xcf, 0xb3, 0x94, 0xa6, 0xf6, 0xe5,
-	0xef, 0x6e, 0xc4, 0xe9, 0xd8, 0x51, 0xa4, 0xbd, 0x77, 0x01, 0x32, 0xa4, 0xb5, 0x02, 0x95, 0x53,
-	0x34, 0xd6, 0xe7, 0x50, 0x7c, 0x0a, 0xe7, 0x9c, 0x79, 0xa3, 0xc4, 0x78, 0x5d, 0x01, 0xef, 0x97,
-	0xdf, 0x2d, 0xd9, 0x3e, 0xb4, 0xb7, 0x47, 0xa7, 0x98, 0xe4, 0xa6, 0xaf, 0xc1, 0x42, 0xe8, 0x7d,
-	0x49, 0xa8, 0xf1, 0xa4, 0x04, 0x24, 0x16, 0x47, 0x84, 0x1a, 0x16, 0x12, 0xb0, 0x96, 0xa1, 0x4c,
-	0x62, 0xe9, 0xaf, 0xba, 0x53, 0x26, 0x71, 0x26, 0xa8, 0x9a, 0x13, 0x64, 0xff, 0xa3, 0x0a, 0x90,
-	0x49, 0xb1, 0x1c, 0xe8, 0x61, 0xe2, 0x32, 0x44, 0x45, 0x0a, 0xe2, 0x9e, 0x8c, 0x39, 0x62, 0x2e,
-	0x45, 0x7e, 0x42, 0x19, 0x3e, 0x13, 0xeb, 0x27, 0xcc, 0xbe, 0xa6, 0xcc, 0x9e, 0xd0, 0xcd, 0xb9,
-	0x8e, 0xc9, 0xb1, 0x9a, 0xb7, 0x2d, 0xa6, 0x39, 0x66, 0x96, 0x75, 0x00, 0xd7, 0x32, 0x9e, 0x41,
-	0x8e, 0x5d, 0x79, 0x1e, 0xbb, 0xd5, 0x94, 0x5d, 0x90, 0xb1, 0xda, 0x85, 0x55, 0x4c, 0xdc, 0xaf,
-	0x12, 0x94, 0x14, 0x18, 0x55, 0xe6, 0x31, 0xea, 0x60, 0xf2, 0xb9, 0x9c, 0x90, 0xb1, 0x39, 0x82,
-	0x1b, 0x39, 0x2b, 0xc5, 0x71, 0xcf, 0x31, 0xab, 0xce, 0x63, 0xb6, 0x9e, 0x6a, 0x25, 0xe2, 0x41,
-	0xc6, 0xf1, 0x63, 0x58, 0xc7, 0xc4, 0x7d, 0xea, 0x61, 0x3e, 0xc9, 0x6e, 0xe1, 0x3b, 0x8c, 0x14,
-	0x97, 0x6e, 0x91, 0x97, 0x32, 0x32, 0x44, 0x74, 0x50, 0x30, 0x72, 0xf1, 0x3b, 0x8c, 0x7c, 0x28,
-	0x27, 0x64, 0x6c, 0xee, 0x43, 0x07, 0x93, 0x49, 0x6d, 0x96, 0xe6, 0x31, 0x69, 0x63, 0x52, 0xd4,
-	0x64, 0x1b, 0x3a, 0x0c, 0xf9, 0x9c, 0xd0, 0xfc, 0x26, 0xa8, 0xcd, 0x63, 0xb1, 0xa2, 0xe9, 0x53,
-	0x1e, 0xf6, 0x8f, 0xa1, 0xb9, 0x9f, 0x0c, 0x10, 0x1f, 0x9d, 0xa4, 0xc1, 0xe0, 0xca, 0xe2, 0x8f,
-	0xfd, 0xaf, 0x32, 0x34, 0x76, 0x06, 0x94, 0x24, 0x71, 0x21, 0x26, 0xab, 0x43, 0x3a, 0x19, 0x93,
-	0x25, 0x89, 0x8c, 0xc9, 0x8a, 0xf8, 0x1d, 0x68, 0x86, 0xf2, 0xe8, 0x6a, 0x7a, 0x15, 0x87, 0x3a,
-	0x53, 0x87, 0xda, 0x69, 0x84, 0xb9, 0x60, 0xd6, 0x07, 0x88, 0x71, 0xc0, 0xf4, 0x1c, 0x15, 0x8e,
-	0xda, 0x3a, 0x23, 0x34, 0x21, 0xda, 0xa9, 0xc7, 0x69, 0xb4, 0x7e, 0x0b, 0x1a, 0x27, 0xc2, 0x49,
-	0x7a, 0x42, 0x21, 0x18, 0x65, 0xde, 0x73, 0xe0, 0x24, 0x3b, 0x84, 0xfb, 0xd0, 0x1a, 0x2a, 0x97,
-	0xe9, 0x49, 0x6a, 0x0f, 0xbd, 0xa4, 0x2d, 0xc9, 0xec, 0xed, 0xe7, 0x3d, 0xab, 0x16, 0xa0, 0x39,
-	0xcc, 0xa1, 0x7a, 0xc7, 0xd0, 0x99, 0x22, 0x99, 0x11, 0x83, 0x36, 0xf3, 0x31, 0xa8, 0x71, 0xc7,
-	0x52, 0x82, 0xf2, 0x33, 0xf3, 0x71, 0xe9, 0x37, 0x65, 0x68, 0x7e, 0x86, 0xf8, 0x53, 0x42, 0x4f,
-	0x95, 0xbe, 0x16, 0x54, 0x23, 0x2f, 0x44, 0x9a, 0xa3, 0xfc, 0xb6, 0x6e, 0x40, 0x8d, 0x9e, 0xab,
-	0x00, 0xa2, 0xd7, 0x73, 0x89, 0x9e, 0xcb, 0xc0, 0x60, 0xbd, 0x00, 0x40, 0xcf, 0xdd, 0xd8, 0xf3,
-	0x4f, 0x91, 0xf6, 0x60, 0xd5, 0xa9, 0xd3, 0xf3, 0x23, 0x85, 0x10, 0x5b, 0x81, 0x9e, 0xbb, 0x88,
-	0x52, 0x42, 0x99, 0x8e, 0x55, 0x35, 0x7a, 0xbe, 0x2b, 0x61, 0x3d, 0x37, 0xa0, 0x24, 0x8e, 0x51,
-	0x20, 0x63, 0xb4, 0x9c, 0xfb, 0x40, 0x21, 0x84, 0x54, 0x6e, 0xa4, 0x2e, 0x2a, 0xa9, 0x3c, 0x93,
-	0xca, 0x33, 0xa9, 0x4b, 0x6a, 0x26, 0xcf, 0x4b, 0xe5, 0xa9, 0xd4, 0x9a, 0x92, 0xca, 0x73, 0x52,
-	0x79, 0x26, 0xb5, 0x6e, 0xe6, 0x6a, 0xa9, 0xf6, 0xaf, 0x4b, 0xb0, 0x3e, 0x99, 0xf8, 0xe9, 0x34,
-	0xf5, 0x1d, 0x68, 0xfa, 0x72, 0xbd, 0x0a, 0x7b, 0xb2, 0x33, 0xb5, 0x92, 0x4e, 0xc3, 0xcf, 0x6d,
-	0xe3, 0xbb, 0xd0, 0x8a, 0x94, 0x83, 0xd3, 0xad, 0x59, 0xc9, 0xd6, 0x25, 0xef, 0x7b, 0xa7, 0x19,
-	0xe5, 0x20, 0x3b, 0x00, 0xeb, 0x0b, 0x8a, 0x39, 0x3a, 0xe6, 0x14, 0x79, 0xe1, 0x55, 0x14, 0x20,
-	0x16, 0x54, 0x65, 0xb6, 0x52, 0x91, 0xf9, 0xb5, 0xfc, 0xb6, 0x5f, 0x85, 0xd5, 0x82, 0x14, 0x6d,
-	0xeb, 0x0a, 0x54, 0x46, 0x28, 0x92, 0xdc, 0x5b, 0x8e, 0xf8, 0xb4, 0x3d, 0xe8, 0x38, 0xc8, 0x0b,
-	0xae, 0x4e, 0x1b, 0x2d, 0xa2, 0x92, 0x89, 0xd8, 0x04, 0x2b, 0x2f, 0x42, 0xab, 0x62, 0xb4, 0x2e,
-	0xe5, 0xb4, 0x3e, 0x84, 0xce, 0xce, 0x88, 0x30, 0x74, 0xcc, 0x03, 0x1c, 0x5d, 0x45, 0xc5, 0xf4,
-	0x33, 0x58, 0x7d, 0xc4, 0xc7, 0x5f, 0x08, 0x66, 0x0c, 0x7f, 0x8d, 0xae, 0xc8, 0x3e, 0x4a, 0x9e,
-	0x1a, 0xfb, 0x28, 0x79, 0x2a, 0x8a, 0x25, 0x9f, 0x8c, 0x92, 0x30, 0x92, 0x47, 0xa1, 0xe5, 0x68,
-	0xc8, 0xde, 0x86, 0xa6, 0xca, 0xa1, 0x1f, 0x92, 0x20, 0x19, 0xa1, 0x99, 0x67, 0x70, 0x03, 0x20,
-	0xf6, 0xa8, 0x17, 0x22, 0x8e, 0xa8, 0xda, 0x43, 0x75, 0x27, 0x87, 0xb1, 0x7f, 0x57, 0x86, 0x35,
-	0xd5, 0x12, 0x39, 0x56, 0x9d, 0x00, 0x63, 0x42, 0x0f, 0x6a, 0x43, 0xc2, 0x78, 0x8e, 0x61, 0x0a,
-	0x0b, 0x15, 0x83, 0xc8, 0x70, 0x13, 0x9f, 0x85, 0x3e, 0x45, 0x65, 0x7e, 0x9f, 0x62, 0xaa, 0x13,
-	0x51, 0x9d, 0xee, 0x44, 0x88, 0xd3, 0x66, 0x88, 0xb0, 0x3a, 0xe3, 0x75, 0xa7, 0xae, 0x31, 0x07,
-	0x81, 0x75, 0x1b, 0xda, 0x03, 0xa1, 0xa5, 0x3b, 0x24, 0xe4, 0xd4, 0x8d, 0x3d, 0x3e, 0x94, 0x47,
-	0xbd, 0xee, 0xb4, 0x24, 0x7a, 0x9f, 0x90, 0xd3, 0x23, 0x8f, 0x0f, 0xad, 0xf7, 0x60, 0x59, 0xa7,
-	0x81, 0xa1, 0x74, 0x11, 0xd3, 0x97, 0x9f, 0x3e, 0x45, 0x79, 0xef, 0x39, 0xad, 0xd3, 0x1c, 0xc4,
-	0xec, 0xeb, 0x70, 0xed, 0x01, 0x62, 0x9c, 0x92, 0x71, 0xd1, 0x31, 0xf6, 0x0f, 0x00, 0x0e, 0x22,
-	0x8e, 0xe8, 0x13, 0xcf, 0x47, 0xcc, 0x7a, 0x33, 0x0f, 0xe9, 0xe4, 0x68, 0xa5, 0xaf, 0x3a, 0x52,
-	0xe9, 0x80, 0x93, 0xa3, 0xb1, 0xfb, 0xb0, 0xe8, 0x90, 0x44, 0x84, 0xa3, 0x97, 0xcd, 0x97, 0x9e,
-	0xd7, 0xd4, 0xf3, 0x24, 0xd2, 0xd1, 0x63, 0xf6, 0xbe, 0x29, 0x61, 0x33, 0x76, 0x7a, 0x89, 0xfa,
-	0x50, 0xc7, 0x06, 0xa7, 0xa3, 0xca, 0xb4, 0xe8, 0x8c, 0xc4, 0xfe, 0x00, 0x56, 0x15, 0x27, 0xc5,
-	0xd9, 0xb0, 0x79, 0x19, 0x16, 0xa9, 0x51, 0xa3, 0x94, 0xb5, 0xa2, 0x34, 0x91, 0x1e, 0x13, 0xfe,
-	0x10, 0x15, 0x75, 0x66, 0x88, 0xf1, 0xc7, 0x2a, 0x74, 0xc4, 0x40, 0x81, 0xa7, 0xfd, 0x11, 0x34,
-	0xef, 0x3b, 0x47, 0x9f, 0x21, 0x3c, 0x18, 0x9e, 0x88, 0xe8, 0xf9, 0xff, 0x45, 0x58, 0x1b, 0x6c,
-	0x69, 0x6d, 0x73, 0x43, 0x4e, 0x81, 0xce, 0xfe, 0x18, 0xd6, 0xef, 0x07, 0x41, 0x1e, 0x65, 0xb4,
-	0x7e, 0x13, 0xea, 0x51, 0x8e, 0x5d, 0xee, 0xce, 0x2a, 0x50, 0x67, 0x44, 0xf6, 0x4f, 0x60, 0xf5,
-	0x30, 0x1a, 0xe1, 0x08, 0xed, 0x1c, 0x3d, 0x7e, 0x88, 0xd2, 0x58, 0x64, 0x41, 0x55, 0xe4, 0x6c,
-	0x92, 0x47, 0xcd, 0x91, 0xdf, 0xe2, 0x70, 0x46, 0x27, 0xae, 0x1f, 0x27, 0x4c, 0xf7, 0xa3, 0x16,
-	0xa3, 0x93, 0x9d, 0x38, 0x61, 0xe2, 0x72, 0x11, 0xc9, 0x05, 0x89, 0x46, 0x63, 0x79, 0x42, 0x6b,
-	0xce, 0x92, 0x1f, 0x27, 0x87, 0xd1, 0x68, 0x6c, 0xff, 0x9f, 0xac, 0xc0, 0x11, 0x0a, 0x1c, 0x2f,
-	0x0a, 0x48, 0xf8, 0x00, 0x9d, 0xe5, 0x24, 0xa4, 0xd5, 0x9e, 0x89, 0x44, 0xdf, 0x94, 0xa0, 0x79,
-	0x7f, 0x80, 0x22, 0xfe, 0x00, 0x71, 0x0f, 0x8f, 0x64, 0x45, 0x77, 0x86, 0x28, 0xc3, 0x24, 0xd2,
-	0xc7, 0xcd, 0x80, 0xa2, 0x20, 0xc7, 0x11, 0xe6, 0x6e, 0xe0, 0xa1, 0x90, 0x44, 0x92, 0x4b, 0xcd,
-	0x01, 0x81, 0x7a, 0x20, 0x31, 0xd6, 0xab, 0xd0, 0x56, 0xfd, 0x42, 0x77, 0xe8, 0x45, 0xc1, 0x48,
-	0x1c, 0x74, 0xd5, 0x3f, 0x59, 0x56, 0xe8, 0x7d, 0x8d, 0xb5, 0x5e, 0x83, 0x15, 0x7d, 0x0c, 0x33,
-	0xca, 0xaa, 0xa4, 0x6c, 0x6b, 0x7c, 0x81, 0x34, 0x89, 0x63, 0x42, 0x39, 0x73, 0x19, 0xf2, 0x7d,
-	0x12, 0xc6, 0xba, 0x1c, 0x6a, 0x1b, 0xfc, 0xb1, 0x42, 0xdb, 0x03, 0x58, 0xdd, 0x13, 0x76, 0x6a,
-	0x4b, 0xb2, 0x6d, 0xb5, 0x1c, 0xa2, 0xd0, 0x3d, 0x19, 0x11, 0xff, 0xd4, 0x15, 0xc1, 0x51, 0x7b,
-	0x58, 0x24, 0x5c, 0xdb, 0x02, 0x79, 0x8c, 0xbf, 0x96, 0x95, 0xbf, 0xa0, 0x1a, 0x12, 0x1e, 0x8f,
-	0x92, 0x81, 0x1b, 0x53, 0x72, 0x82, 0xb4, 0x89, 0xed, 0x10, 0x85, 0xfb, 0x0a, 0x7f, 0x24, 0xd0,
-	0xf6, 0x9f, 0x4b, 0xb0, 0x56, 0x94, 0xa4, 0x43, 0xfd, 0x16, 0xac, 0x15, 0x45, 0xe9, 0xeb, 0x5f,
-	0xa5, 0x97, 0x9d, 0xbc, 0x40, 0x95, 0x08, 0xdc, 0x85, 0x96, 0x6c, 0x22, 0xbb, 0x81, 0xe2, 0x54,
-	0x4c, 0x7a, 0xf2, 0xeb, 0xe2, 0x34, 0xbd, 0xfc, 0x2a, 0xbd, 0x07, 0x37, 0xb4, 0xf9, 0xee, 0xb4,
-	0xda, 0x6a, 0x43, 0xac, 0x6b, 0x82, 0x87, 0x13, 0xda, 0x7f, 0x0a, 0xdd, 0x0c, 0xb5, 0x3d, 0x96,
-	0xc8, 0x6c, 0x33, 0xaf, 0x4e, 0x18, 0x7b, 0x3f, 0x08, 0xa8, 0x3c, 0x25, 0x55, 0x67, 0xd6, 0x90,
-	0x7d, 0x0f, 0xae, 0x1f, 0x23, 0xae, 0xbc, 0xe1, 0x71, 0x5d, 0x89, 0x28, 0x66, 0x2b, 0x50, 0x39,
-	0x46, 0xbe, 0x34, 0xbe, 0xe2, 0x88, 0x4f, 0xb1, 0x01, 0x1f, 0x33, 0xe4, 0x4b, 0x2b, 0x2b, 0x8e,
-	0xfc, 0xb6, 0xff, 0x54, 0x82, 0x25, 0x1d, 0x9c, 0xc5, 0x05, 0x13, 0x50, 0x7c, 0x86, 0xa8, 0xde,
-	0x7a, 0x1a, 0xb2, 0x5e, 0x81, 0x65, 0xf5, 0xe5, 0x92, 0x98, 0x63, 0x92, 0x86, 0xfc, 0x96, 0xc2,
-	0x1e, 0x2a, 0xa4, 0xec, 0x0f, 0xca, 0xf6, 0x97, 0xae, 0x34, 0x35, 0x24, 0x9b, 0x7c, 0x4c, 0x9c,
-	0x70, 0x19, 0xe2, 0xeb, 0x8e, 0x86, 0xc4, 0x56, 0x37, 0xfc, 0x16, 0x24, 0x3f, 0x03, 0x8a, 0xad,
-	0x1e, 0x92, 0x24, 0xe2, 0x6e, 0x4c, 0x70, 0xc4, 0x75, 0x4c, 0x07, 0x89, 0x3a, 0x12, 0x18, 0xfb,
-	0x57, 0x25, 0x58, 0x54, 0x3d, 0x72, 0x51, 0xdb, 0xa6, 0x37, 0x6b, 0x19, 0xcb, 0x2c, 0x45, 0xca,
-	0x52, 0xb7, 0xa9, 0xfc, 0x16, 0xe7, 0xf8, 0x2c, 0x54, 0xf7, 0x83, 0x56, 0xed, 0x2c, 0x94, 0x17,
-	0xc3, 0x2b, 0xb0, 0x9c, 0x5d, 0xd0, 0x72, 0x5c, 0xa9, 0xd8, 0x4a, 0xb1, 0x92, 0xec, 0x42, 0x4d,
-	0xed, 0x1f, 0x8a, 0x92, 0x3e, 0xed, 0x0f, 0xaf, 0x40, 0x25, 0x49, 0x95, 0x11, 0x9f, 0x02, 0x33,
-	0x48, 0xaf, 0x76, 0xf1, 0x69, 0xdd, 0x86, 0x65, 0x2f, 0x08, 0xb0, 0x98, 0xee, 0x8d, 0xf6, 0x70,
-	0x90, 0x1e, 0xd2, 0x22, 0xd6, 0xfe, 0x6b, 0x09, 0xda, 0x3b, 0x24, 0x1e, 0x7f, 0x84, 0x47, 0x28,
-	0x17, 0x41, 0xa4, 0x92, 0xfa, 0x66, 0x17, 0xdf, 0x22, 0x5b, 0x7d, 0x82, 0x47, 0x48, 0x1d, 0x2d,
-	0xb5, 0xb2, 0x35, 0x81, 0x90, 0xc7, 0xca, 0x0c, 0xa6, 0x6d, 0xb7, 0x96, 0x1a, 0x7c, 0x48, 0x02,
-	0x99, 0x97, 0x07, 0x98, 0xba, 0x69, 0x93, 0xad, 0xe5, 0x2c, 0x05, 0x98, 0xca, 0x21, 0x6d, 0xc8,
-	0x82, 0xec, 0xf3, 0xe6, 0x0d, 0x59, 0x54, 0x18, 0x61, 0xc8, 0x3a, 0x2c, 0x92, 0x27, 0x4f, 0x18,
-	0xe2, 0x32, 0x83, 0xae, 0x38, 0x1a, 0x4a, 0xc3, 0x5c, 0x2d, 0x17, 0xe6, 0xae, 0xc1, 0xaa, 0x7c,
-	0x51, 0x78, 0x44, 0x3d, 0x1f, 0x47, 0x03, 0x73, 0x3d, 0xac, 0x81, 0x75, 0xcc, 0x49, 0x3c, 0x8d,
-	0xdd, 0x43, 0xfc, 0xf0, 0xf0, 0xe1, 0xee, 0x19, 0x8a, 0xb8, 0xc1, 0xbe, 0x01, 0x35, 0x83, 0xfa,
-	0x0f, 0xf2, 0xaa, 0x3b, 0xbf, 0xed, 0xe8, 0xc0, 0xaa, 0x6b, 0x74, 0x6b, 0x0f, 0xda, 0x13, 0x6f,
-	0x3e, 0x96, 0x6e, 0xda, 0xcc, 0x7e, 0x0a, 0xea, 0xad, 0xf7, 0xd5, 0x1b, 0x52, 0xdf, 0xbc, 0x21,
-	0xf5, 0x77, 0xc3, 0x98, 0x8f, 0xad, 0x5d, 0x58, 0x2e, 0xbe, 0x8e, 0x58, 0x37, 0x4d, 0x8e, 0x33,
-	0xe3, 0xcd, 0xe4, 0x42, 0x36, 0x7b, 0xd0, 0x9e, 0x78, 0x28, 0x31, 0xfa, 0xcc, 0x7e, 0x3f, 0xb9,
-	0x90, 0xd1, 0x3d, 0x68, 0xe4, 0x5e, 0x46, 0xac, 0xae, 0x62, 0x32, 0xfd, 0x58, 0x72, 0x21, 0x83,
-	0x1d, 0x68, 0x15, 0x1e, 0x2b, 0xac, 0x9e, 0xb6, 0x67, 0xc6, 0x0b, 0xc6, 0x85, 0x4c, 0xb6, 0xa1,
-	0x91, 0x7b, 0x33, 0x30, 0x5a, 0x4c, 0x3f, 0x4c, 0xf4, 0x6e, 0xcc, 0x18, 0xd1, 0xf1, 0x7b, 0x1f,
-	0x5a, 0x85, 0x0e, 0xbf, 0x51, 0x64, 0xd6, 0xeb, 0x42, 0xef, 0xe6, 0xcc, 0x31, 0xcd, 0x69, 0x0f,
-	0xda, 0x13, 0xfd, 0x7e, 0xe3, 0xdc, 0xd9, 0xcf, 0x00, 0x17, 0x9a, 0xf5, 0x89, 0x5c, 0xec, 0x5c,
-	0x39, 0x97, 0x5b, 0xec, 0xe9, 0xee, 0x7e, 0xef, 0xf9, 0xd9, 0x83, 0x5a, 0xab, 0x5d, 0x58, 0x2e,
-	0x36, 0xf6, 0x0d, 0xb3, 0x99, 0xed, 0xfe, 0xf9, 0x3b, 0xa7, 0xd0, 0xe3, 0xcf, 0x76, 0xce, 0xac,
-	0xd6, 0xff, 0x85, 0x8c, 0xee, 0x03, 0xe8, 0xe2, 0x2d, 0xc0, 0x51, 0xba, 0x64, 0x53, 0x45, 0x63,
-	0xba, 0x64, 0x33, 0x0a, 0xbd, 0x7b, 0x00, 0xaa, 0xe6, 0x0a, 0x48, 0xc2, 0xad, 0xeb, 0x46, 0x8d,
-	0x89, 0x42, 0xaf, 0xd7, 0x9d, 0x1e, 0x98, 0x62, 0x80, 0x28, 0xbd, 0x0c, 0x83, 0x0f, 0x01, 0xb2,
-	0x5a, 0xce, 0x30, 0x98, 0xaa, 0xee, 0xe6, 0xf8, 0xa0, 0x99, 0xaf, 0xdc, 0x2c, 0x6d, 0xeb, 0x8c,
-	0x6a, 0x6e, 0x0e, 0x8b, 0xf6, 0x44, 0x66, 0x5e, 0xdc, 0x6c, 0x93, 0x09, 0x7b, 0x6f, 0x2a, 0x3b,
-	0xb7, 0xee, 0x42, 0x33, 0x9f, 0x92, 0x1b, 0x2d, 0x66, 0xa4, 0xe9, 0xbd, 0x42, 0x5a, 0x6e, 0xdd,
-	0x83, 0xe5, 0x62, 0x3a, 0x6e, 0xe5, 0xce, 0xc5, 0x54, 0x92, 0xde, 0xd3, 0xcd, 0xa6, 0x1c, 0xf9,
-	0xdb, 0x00, 0x59, 0xda, 0x6e, 0xdc, 0x37, 0x95, 0xc8, 0x4f, 0x48, 0xdd, 0x83, 0xf6, 0x44, 0x3a,
-	0x6e, 0x2c, 0x9e, 0x9d, 0xa5, 0xcf, 0xf3, 0x7e, 0xfe, 0x5e, 0x30, 0x76, 0xcf, 0xb8, 0x2b, 0xe6,
-	0x85, 0xbf, 0xdc, 0x1d, 0x62, 0x76, 0xf1, 0xf4, 0xb5, 0x32, 0x2f, 0xfc, 0x15, 0x2a, 0x5f, 0x13,
-	0x75, 0x66, 0x95, 0xc3, 0xf3, 0x2e, 0x85, 0x62, 0x99, 0x68, 0xd6, 0x61, 0x66, 0xf1, 0x38, 0xcf,
-	0x1f, 0xf9, 0xda, 0xc4, 0xf8, 0x63, 0x46, 0xbd, 0xf2, 0x1d, 0xd1, 0x21, 0x5f, 0x7f, 0xe4, 0xa2,
-	0xc3, 0x8c, 0xb2, 0xe4, 0x42, 0x46, 0xfb, 0xd0, 0xde, 0x33, 0xa9, 0xa5, 0x4e, 0x7b, 0xb5, 0x3a,
-	0x33, 0xd2, 0xfc, 0x5e, 0x6f, 0xd6, 0x90, 0x3e, 0xa2, 0x9f, 0x40, 0x67, 0x2a, 0xe5, 0xb5, 0x36,
-	0xd2, 0xe6, 0xea, 0xcc, 0x5c, 0xf8, 0x42, 0xb5, 0x0e, 0x60, 0x65, 0x32, 0xe3, 0xb5, 0x5e, 0xd0,
-	0x8b, 0x3e, 0x3b, 0x13, 0xbe, 0x90, 0xd5, 0x7b, 0x50, 0x33, 0x19, 0x96, 0xa5, 0x9b, 0xd8, 0x13,
-	0x19, 0xd7, 0x85, 0x53, 0xef, 0x42, 0x23, 0x97, 0xa3, 0x98, 0x5d, 0x37, 0x9d, 0xb6, 0xf4, 0x74,
-	0xcf, 0xd9, 0xa0, 0xb7, 0xcf, 0xbf, 0xf9, 0x76, 0xe3, 0xb9, 0xbf, 0x7f, 0xbb, 0xf1, 0xdc, 0x2f,
-	0x9f, 0x6d, 0x94, 0xbe, 0x79, 0xb6, 0x51, 0xfa, 0xdb, 0xb3, 0x8d, 0xd2, 0x3f, 0x9f, 0x6d, 0x94,
-	0x7e, 0xf4, 0xd3, 0xef, 0xf9, 0x87, 0x19, 0x9a, 0x44, 0x1c, 0x87, 0x68, 0xeb, 0x0c, 0x53, 0x9e,
-	0x1b, 0x8a, 0x4f, 0x07, 0x53, 0xff, 0xa5, 0x11, 0x2a, 0x9c, 0x2c, 0x4a, 0xf8, 0xed, 0x7f, 0x07,
-	0x00, 0x00, 0xff, 0xff, 0x3f, 0x18, 0x6e, 0xe2, 0x43, 0x24, 0x00, 0x00,
+	// 3052 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x3a, 0xcd, 0x6f, 0x1b, 0xc7,
+	0xf5, 0xa1, 0x48, 0x49, 0xe4, 0x23, 0x29, 0x4a, 0x23, 0x59, 0xa6, 0x69, 0x47, 0x3f, 0x67, 0x93,
+	0xd8, 0xca, 0x2f, 0x8d, 0x94, 0x3a, 0x41, 0x9d, 0x38, 0x48, 0x0d, 0x4b, 0x56, 0x24, 0x25, 0x71,
+	0xa4, 0xac, 0x6c, 0xa4, 0x68, 0xd1, 0x2e, 0x56, 0xbb, 0x23, 0x6a, 0x22, 0xee, 0xce, 0x66, 0x66,
+	0x56, 0x96, 0xd2, 0xa2, 0xe8, 0xa9, 0xbd, 0xf5, 0x96, 0x63, 0xff, 0x81, 0xa2, 0xb7, 0x1e, 0x7b,
+	0xed, 0x21, 0xe8, 0xa9, 0xc7, 0x9e, 0x8a, 0xc6, 0x7f, 0x42, 0xff, 0x82, 0x62, 0xbe, 0xf6, 0x83,
+	0xa4, 0xe8, 0xc6, 0x10, 0xd0, 0x0b, 0xb1, 0xef, 0x63, 0xde, 0xd7, 0xcc, 0xbc, 0x79, 0xf3, 0x86,
+	0xf0, 0x79, 0x9f, 0x88, 0xe3, 0xf4, 0x70, 0x2d, 0xa0, 0xd1, 0xfa, 0x89, 0x2f, 0xfc, 0xb7, 0x02,
+	0x1a, 0x0b, 0x9f, 0xc4, 0x98, 0xf1, 0x11, 0x98, 0xb3, 0x60, 0xdd, 0xef, 0xe3, 0x58, 0xac, 0x27,
+	0x8c, 0x0a, 0x1a, 0xd0, 0x01, 0xd7, 0x5f, 0x5c, 0xa3, 0xd7, 0x14, 0x80, 0x6a, 0x7d, 0x96, 0x04,
+	0xbd, 0xbd, 0xcb, 0x11, 0x4c, 0x03, 0xa2, 0xc5, 0xf6, 0x7e, 0x75, 0x39, 0x02, 0x27, 0x48, 0x31,
+	0x23, 0x4e, 0xfa, 0xeb, 0xe2, 0x3c, 0xc1, 0x5c, 0xff, 0x1a, 0xed, 0xd7, 0xfb, 0x94, 0xf6, 0x07,
+	0x58, 0x4b, 0x39, 0x4c, 0x8f, 0xd6, 0x71, 0x94, 0x88, 0x73, 0x4d, 0x74, 0xfe, 0x30, 0x05, 0xcb,
+	0x9b, 0x0c, 0xfb, 0x02, 0x6f, 0x5a, 0x69, 0x2e, 0xfe, 0x2a, 0xc5, 0x5c, 0xa0, 0x57, 0xa0, 0x95,
+	0x69, 0xf0, 0x48, 0xd8, 0xad, 0xdc, 0xac, 0xac, 0x36, 0xdc, 0x66, 0x86, 0xdb, 0x0d, 0xd1, 0x55,
+	0x98, 0xc5, 0x67, 0x38, 0x90, 0xd4, 0x29, 0x45, 0x9d, 0x91, 0xe0, 0x6e, 0x88, 0x7e, 0x08, 0x4d,
+	0x2e, 0x18, 0x89, 0xfb, 0x5e, 0xca, 0x31, 0xeb, 0x56, 0x6f, 0x56, 0x56, 0x9b, 0x77, 0xe6, 0xd7,
+	0x64, 0x78, 0xd7, 0x0e, 0x14, 0xe1, 0x09, 0xc7, 0xcc, 0x05, 0x9e, 0x7d, 0xa3, 0x5b, 0x30, 0x1b,
+	0xe2, 0x53, 0x12, 0x60, 0xde, 0xad, 0xdd, 0xac, 0xae, 0x36, 0xef, 0xb4, 0x34, 0xfb, 0x43, 0x85,
+	0x74, 0x2d, 0x11, 0xbd, 0x01, 0x75, 0x2e, 0x28, 0xf3, 0xfb, 0x98, 0x77, 0xa7, 0x15, 0x63, 0xdb,
+	0xca, 0x55, 0x58, 0x37, 0x23, 0xa3, 0x1b, 0x50, 0xdd, 0xdb, 0xdc, 0xed, 0xce, 0x28, 0xed, 0x60,
+	0xb8, 0x12, 0x1c, 0xb8, 0x12, 0x8d, 0x5e, 0x85, 0x36, 0xf7, 0xe3, 0xf0, 0x90, 0x9e, 0x79, 0x09,
+	0x09, 0x63, 0xde, 0x9d, 0xbd, 0x59, 0x59, 0xad, 0xbb, 0x2d, 0x83, 0xdc, 0x97, 0x38, 0xe7, 0x1e,
+	0x5c, 0x39, 0x10, 0x3e, 0x13, 0x2f, 0x10, 0x1d, 0xe7, 0x09, 0x2c, 0xbb, 0x38, 0xa2, 0xa7, 0x2f,
+	0x14, 0xda, 0x2e, 0xcc, 0x0a, 0x12, 0x61, 0x9a, 0x0a, 0x15, 0xda, 0xb6, 0x6b, 0x41, 0xe7, 0x4f,
+	0x15, 0x40, 0x5b, 0x67, 0x38, 0xd8, 0x67, 0x34, 0xc0, 0x9c, 0xff, 0x8f, 0xa6, 0xeb, 0x36, 0xcc,
+	0x26, 0xda, 0x80, 0x6e, 0xed, 0x66, 0x25, 0x9f, 0x05, 0x6b, 0x95, 0xa5, 0x3a, 0x5f, 0xc2, 0xd2,
+	0x01, 0xe9, 0xc7, 0xfe, 0xe0, 0x12, 0xed, 0x5d, 0x86, 0x19, 0xae, 0x64, 0x2a, 0x53, 0xdb, 0xae,
+	0x81, 0x9c, 0x7d, 0x40, 0x5f, 0xf8, 0x44, 0x5c, 0x9e, 0x26, 0xe7, 0x2d, 0x58, 0x2c, 0x49, 0xe4,
+	0x09, 0x8d, 0x39, 0x56, 0x06, 0x08, 0x5f, 0xa4, 0x5c, 0x09, 0x9b, 0x76, 0x0d, 0xe4, 0x60, 0x58,
+	0xfa, 0x94, 0x70, 0xcb, 0x8e, 0xbf, 0x8f, 0x09, 0xcb, 0x30, 0x73, 0x44, 0x59, 0xe4, 0x0b, 0x6b,
+	0x81, 0x86, 0x10, 0x82, 0x9a, 0xcf, 0xfa, 0xbc, 0x5b, 0xbd, 0x59, 0x5d, 0x6d, 0xb8, 0xea, 0x5b,
+	0xae, 0xca, 0x21, 0x35, 0xc6, 0xae, 0x57, 0xa0, 0x65, 0xe2, 0xee, 0x0d, 0x08, 0x17, 0x4a, 0x4f,
+	0xcb, 0x6d, 0x1a, 0x9c, 0x1c, 0xe3, 0x50, 0x58, 0x7e, 0x92, 0x84, 0x2f, 0xb8, 0xe1, 0xef, 0x40,
+	0x83, 0x61, 0x4e, 0x53, 0x26, 0xb7, 0xe9, 0x94, 0x9a, 0xf7, 0x25, 0x3d, 0xef, 0x9f, 0x92, 0x38,
+	0x3d, 0x73, 0x2d, 0xcd, 0xcd, 0xd9, 0xcc, 0x16, 0x12, 0xfc, 0x45, 0xb6, 0xd0, 0x3d, 0xb8, 0xb2,
+	0xef, 0xa7, 0xfc, 0x45, 0x6c, 0x75, 0x3e, 0x90, 0xdb, 0x8f, 0xa7, 0xd1, 0x0b, 0x0d, 0xfe, 0x63,
+	0x05, 0xea, 0x9b, 0x49, 0xfa, 0x84, 0xfb, 0x7d, 0x8c, 0xfe, 0x0f, 0x9a, 0x82, 0x0a, 0x7f, 0xe0,
+	0xa5, 0x12, 0x54, 0xec, 0x35, 0x17, 0x14, 0x4a, 0x33, 0xc8, 0xb0, 0x63, 0x16, 0x24, 0xa9, 0xe1,
+	0x98, 0xba, 0x59, 0x5d, 0xad, 0xb9, 0x4d, 0x8d, 0xd3, 0x2c, 0x6b, 0xb0, 0xa8, 0x68, 0x1e, 0x89,
+	0xbd, 0x13, 0xcc, 0x62, 0x3c, 0x88, 0x68, 0x88, 0xd5, 0xfa, 0xad, 0xb9, 0x0b, 0x8a, 0xb4, 0x1b,
+	0x7f, 0x92, 0x11, 0xd0, 0xff, 0xc3, 0x42, 0xc6, 0x2f, 0x37, 0xa5, 0xe2, 0xae, 0x29, 0xee, 0x8e,
+	0xe1, 0x7e, 0x62, 0xd0, 0xce, 0xaf, 0x61, 0xee, 0xf1, 0x31, 0xa3, 0x42, 0x0c, 0x48, 0xdc, 0x7f,
+	0xe8, 0x0b, 0x5f, 0x66, 0x8f, 0x04, 0x33, 0x42, 0x43, 0x6e, 0xac, 0xb5, 0x20, 0x7a, 0x13, 0x16,
+	0x84, 0xe6, 0xc5, 0xa1, 0x67, 0x79, 0xa6, 0x14, 0xcf, 0x7c, 0x46, 0xd8, 0x37, 0xcc, 0xaf, 0xc3,
+	0x5c, 0xce, 0x2c, 0xf3, 0x8f, 0xb1, 0xb7, 0x9d, 0x61, 0x1f, 0x93, 0x08, 0x3b, 0xa7, 0x2a, 0x56,
+	0x6a, 0x92, 0xd1, 0x9b, 0xd0, 0xc8, 0xe3, 0x50, 0x51, 0x2b, 0x64, 0x4e, 0xaf, 0x10, 0x1b, 0x4e,
+	0xb7, 0x9e, 0x05, 0xe5, 0x43, 0xe8, 0x88, 0xcc, 0x70, 0x2f, 0xf4, 0x85, 0x5f, 0x5e, 0x54, 0x65,
+	0xaf, 0xdc, 0x39, 0x51, 0x82, 0x9d, 0x0f, 0xa0, 0xb1, 0x4f, 0x42, 0xae, 0x15, 0x77, 0x61, 0x36,
+	0x48, 0x19, 0xc3, 0xb1, 0xb0, 0x2e, 0x1b, 0x10, 0x2d, 0xc1, 0xf4, 0x80, 0x44, 0x44, 0x18, 0x37,
+	0x35, 0xe0, 0x50, 0x80, 0x47, 0x38, 0xa2, 0xec, 0x5c, 0x05, 0x6c, 0x09, 0xa6, 0x8b, 0x93, 0xab,
+	0x01, 0x74, 0x1d, 0x1a, 0x91, 0x7f, 0x96, 0x4d, 0xaa, 0xa4, 0xd4, 0x23, 0xff, 0x4c, 0x1b, 0xdf,
+	0x85, 0xd9, 0x23, 0x9f, 0x0c, 0x82, 0x58, 0x98, 0xa8, 0x58, 0x30, 0x57, 0x58, 0x2b, 0x2a, 0xfc,
+	0xeb, 0x14, 0x34, 0xb5, 0x46, 0x6d, 0xf0, 0x12, 0x4c, 0x07, 0x7e, 0x70, 0x9c, 0xa9, 0x54, 0x00,
+	0xba, 0x05, 0xd3, 0xb9, 0xba, 0x2c, 0x09, 0xe7, 0x96, 0x5a, 0xd3, 0xd6, 0x01, 0xf8, 0x53, 0x3f,
+	0x31, 0xb6, 0x55, 0x2f, 0x60, 0x6e, 0x48, 0x1e, 0x6d, 0xee, 0x3b, 0xd0, 0xd2, 0xeb, 0xce, 0x0c,
+	0xa9, 0x5d, 0x30, 0xa4, 0xa9, 0xb9, 0xf4, 0xa0, 0x57, 0xa1, 0x9d, 0x72, 0xec, 0x1d, 0x13, 0xcc,
+	0x7c, 0x16, 0x1c, 0x9f, 0x77, 0xa7, 0xf5, 0x19, 0x99, 0x72, 0xbc, 0x63, 0x71, 0xe8, 0x0e, 0x4c,
+	0xcb, 0xf4, 0xc7, 0xbb, 0x33, 0xea, 0x38, 0xbe, 0x51, 0x14, 0xa9, 0x5c, 0x5d, 0x53, 0xbf, 0x5b,
+	0xb1, 0x60, 0xe7, 0xae, 0x66, 0xed, 0xbd, 0x07, 0x90, 0x23, 0xd1, 0x3c, 0x54, 0x4f, 0xf0, 0xb9,
+	0xd9, 0x87, 0xf2, 0x53, 0x06, 0xe7, 0xd4, 0x1f, 0xa4, 0x36, 0xea, 0x1a, 0xb8, 0x37, 0xf5, 0x5e,
+	0xc5, 0x09, 0xa0, 0xb3, 0x31, 0x38, 0x21, 0xb4, 0x30, 0x7c, 0x09, 0xa6, 0x23, 0xff, 0x4b, 0xca,
+	0x6c, 0x24, 0x15, 0xa0, 0xb0, 0x24, 0xa6, 0xcc, 0x8a, 0x50, 0x00, 0x9a, 0x83, 0x29, 0x9a, 0xa8,
+	0x78, 0x35, 0xdc, 0x29, 0x9a, 0xe4, 0x8a, 0x6a, 0x05, 0x45, 0xce, 0x3f, 0x6b, 0x00, 0xb9, 0x16,
+	0xe4, 0x42, 0x8f, 0x50, 0x8f, 0x63, 0x26, 0x4b, 0x10, 0xef, 0xf0, 0x5c, 0x60, 0xee, 0x31, 0x1c,
+	0xa4, 0x8c, 0x93, 0x53, 0x39, 0x7f, 0xd2, 0xed, 0x2b, 0xda, 0xed, 0x21, 0xdb, 0xdc, 0xab, 0x84,
+	0x1e, 0xe8, 0x71, 0x1b, 0x72, 0x98, 0x6b, 0x47, 0xa1, 0x5d, 0xb8, 0x92, 0xcb, 0x0c, 0x0b, 0xe2,
+	0xa6, 0x26, 0x89, 0x5b, 0xcc, 0xc4, 0x85, 0xb9, 0xa8, 0x2d, 0x58, 0x24, 0xd4, 0xfb, 0x2a, 0xc5,
+	0x69, 0x49, 0x50, 0x75, 0x92, 0xa0, 0x05, 0x42, 0x3f, 0x57, 0x03, 0x72, 0x31, 0xfb, 0x70, 0xad,
+	0xe0, 0xa5, 0xdc, 0xee, 0x05, 0x61, 0xb5, 0x49, 0xc2, 0x96, 0x33, 0xab, 0x64, 0x3e, 0xc8, 0x25,
+	0x7e, 0x0c, 0xcb, 0x84, 0x7a, 0x4f, 0x7d, 0x22, 0x86, 0xc5, 0x4d, 0x3f, 0xc7, 0x49, 0x79, 0xe8,
+	0x96, 0x65, 0x69, 0x27, 0x23, 0xcc, 0xfa, 0x25, 0x27, 0x67, 0x9e, 0xe3, 0xe4, 0x23, 0x35, 0x20,
+	0x17, 0xf3, 0x00, 0x16, 0x08, 0x1d, 0xb6, 0x66, 0x76, 0x92, 0x90, 0x0e, 0xa1, 0x65, 0x4b, 0x36,
+	0x60, 0x81, 0xe3, 0x40, 0x50, 0x56, 0x5c, 0x04, 0xf5, 0x49, 0x22, 0xe6, 0x0d, 0x7f, 0x26, 0xc3,
+	0xf9, 0x19, 0xb4, 0x76, 0xd2, 0x3e, 0x16, 0x83, 0xc3, 0x2c, 0x19, 0x5c, 0x5a, 0xfe, 0x71, 0xfe,
+	0x3d, 0x05, 0xcd, 0xcd, 0x3e, 0xa3, 0x69, 0x52, 0xca, 0xc9, 0x7a, 0x93, 0x0e, 0xe7, 0x64, 0xc5,
+	0xa2, 0x72, 0xb2, 0x66, 0x7e, 0x17, 0x5a, 0x91, 0xda, 0xba, 0x86, 0x5f, 0xe7, 0xa1, 0x85, 0x91,
+	0x4d, 0xed, 0x36, 0xa3, 0x1c, 0x40, 0x6b, 0x00, 0x09, 0x09, 0xb9, 0x19, 0xa3, 0xd3, 0x51, 0xc7,
+	0x54, 0x84, 0x36, 0x45, 0xbb, 0x8d, 0xc4, 0x7e, 0xca, 0x8a, 0xf3, 0x50, 0x06, 0xc9, 0x0c, 0x28,
+	0x25, 0xa3, 0x3c, 0x7a, 0x2e, 0x1c, 0x66, 0xdf, 0x68, 0x07, 0xda, 0xc7, 0x3a, 0x64, 0x66, 0x90,
+	0x5e, 0x43, 0xaf, 0x1a, 0x4f, 0x72, 0x7f, 0xd7, 0x8a, 0x91, 0xd5, 0x13, 0xd0, 0x3a, 0x2e, 0xa0,
+	0x7a, 0x07, 0xb0, 0x30, 0xc2, 0x32, 0x26, 0x07, 0xad, 0x16, 0x73, 0x50, 0xf3, 0x0e, 0xd2, 0x8a,
+	0x8a, 0x23, 0x8b, 0x79, 0xe9, 0xf7, 0x53, 0xd0, 0xfa, 0x0c, 0x8b, 0xa7, 0x94, 0x9d, 0x68, 0x7b,
+	0x11, 0xd4, 0x62, 0x3f, 0xc2, 0x46, 0xa2, 0xfa, 0x46, 0xd7, 0xa0, 0xce, 0xce, 0x74, 0x02, 0x31,
+	0xf3, 0x39, 0xcb, 0xce, 0x54, 0x62, 0x40, 0x2f, 0x03, 0xb0, 0x33, 0x2f, 0xf1, 0x83, 0x13, 0x6c,
+	0x22, 0x58, 0x73, 0x1b, 0xec, 0x6c, 0x5f, 0x23, 0xe4, 0x52, 0x60, 0x67, 0x1e, 0x66, 0x8c, 0x32,
+	0x6e, 0x72, 0x55, 0x9d, 0x9d, 0x6d, 0x29, 0xd8, 0x8c, 0x0d, 0x19, 0x4d, 0x12, 0x1c, 0x76, 0xa7,
+	0xed, 0xd8, 0x87, 0x1a, 0x21, 0xb5, 0x0a, 0xab, 0x75, 0x46, 0x6b, 0x15, 0xb9, 0x56, 0x91, 0x6b,
+	0x9d, 0xd5, 0x23, 0x45, 0x51, 0xab, 0xc8, 0xb4, 0xd6, 0xb5, 0x56, 0x51, 0xd0, 0x2a, 0x72, 0xad,
+	0x0d, 0x3b, 0xd6, 0x68, 0x75, 0x7e, 0x57, 0x81, 0xe5, 0xe1, 0xc2, 0xcf, 0x94, 0xa9, 0xef, 0x42,
+	0x2b, 0x50, 0xf3, 0x55, 0x5a, 0x93, 0x0b, 0x23, 0x33, 0xe9, 0x36, 0x83, 0x1c, 0x40, 0x77, 0xa1,
+	0x1d, 0xeb, 0x00, 0x67, 0x4b, 0xb3, 0x9a, 0xcf, 0x4b, 0x31, 0xf6, 0x6e, 0x2b, 0x2e, 0x40, 0x4e,
+	0x08, 0xe8, 0x0b, 0x46, 0x04, 0x3e, 0x10, 0x0c, 0xfb, 0xd1, 0x65, 0x5c, 0x40, 0x10, 0xd4, 0x54,
+	0xb5, 0x52, 0x55, 0xf5, 0xb5, 0xfa, 0x76, 0x6e, 0xc3, 0x62, 0x49, 0x8b, 0xf1, 0x75, 0x1e, 0xaa,
+	0x03, 0x1c, 0x2b, 0xe9, 0x6d, 0x57, 0x7e, 0x3a, 0x3e, 0x2c, 0xb8, 0xd8, 0x0f, 0x2f, 0xcf, 0x1a,
+	0xa3, 0xa2, 0x9a, 0xab, 0x58, 0x05, 0x54, 0x54, 0x61, 0x4c, 0xb1, 0x56, 0x57, 0x0a, 0x56, 0xef,
+	0xc1, 0xc2, 0xe6, 0x80, 0x72, 0x7c, 0x20, 0x42, 0x12, 0x5f, 0xc6, 0x8d, 0xe9, 0x97, 0xb0, 0xf8,
+	0x58, 0x9c, 0x7f, 0x21, 0x85, 0x71, 0xf2, 0x35, 0xbe, 0x24, 0xff, 0x18, 0x7d, 0x6a, 0xfd, 0x63,
+	0xf4, 0xa9, 0xbc, 0x2c, 0x05, 0x74, 0x90, 0x46, 0xb1, 0xda, 0x0a, 0x6d, 0xd7, 0x40, 0xce, 0x06,
+	0xb4, 0x74, 0x0d, 0xfd, 0x88, 0x86, 0xe9, 0x00, 0x8f, 0xdd, 0x83, 0x2b, 0x00, 0x89, 0xcf, 0xfc,
+	0x08, 0x0b, 0xcc, 0xf4, 0x1a, 0x6a, 0xb8, 0x05, 0x8c, 0xf3, 0x4d, 0x15, 0x96, 0x74, 0x4b, 0xe4,
+	0x40, 0x77, 0x02, 0xac, 0x0b, 0x3d, 0xa8, 0x1f, 0x53, 0x2e, 0x0a, 0x02, 0x33, 0x58, 0x9a, 0x18,
+	0xc6, 0x56, 0x9a, 0xfc, 0x2c, 0xf5, 0x29, 0xaa, 0x93, 0xfb, 0x14, 0x23, 0x9d, 0x88, 0xda, 0x68,
+	0x27, 0x42, 0xee, 0x36, 0xcb, 0x44, 0xf4, 0x1e, 0x6f, 0xb8, 0x0d, 0x83, 0xd9, 0x0d, 0xd1, 0x2d,
+	0xe8, 0xf4, 0xa5, 0x95, 0xde, 0x31, 0xa5, 0x27, 0x5e, 0xe2, 0x8b, 0x63, 0xb5, 0xd5, 0x1b, 0x6e,
+	0x5b, 0xa1, 0x77, 0x28, 0x3d, 0xd9, 0xf7, 0xc5, 0x31, 0x7a, 0x1f, 0xe6, 0x4c, 0x19, 0x18, 0xa9,
+	0x10, 0xf1, 0xee, 0x6c, 0x71, 0x17, 0x15, 0xa3, 0xe7, 0xb6, 0x4f, 0x0a, 0x10, 0x47, 0x0f, 0x60,
+	0x96, 0x9f, 0xf3, 0x40, 0x0c, 0xb8, 0x39, 0xed, 0x6e, 0x9b, 0x0d, 0x3b, 0x26, 0x58, 0x6b, 0x07,
+	0x9a, 0x53, 0xa7, 0x5f, 0x3b, 0xae, 0x77, 0x0f, 0x5a, 0x45, 0xc2, 0xf3, 0x0a, 0xbf, 0x46, 0x31,
+	0xc1, 0x5e, 0x85, 0x2b, 0x0f, 0x31, 0x17, 0x8c, 0x9e, 0x97, 0x55, 0x39, 0x3f, 0x06, 0xd8, 0x8d,
+	0x05, 0x66, 0x47, 0x7e, 0x80, 0x39, 0x7a, 0xbb, 0x08, 0x99, 0xda, 0x6c, 0x7e, 0x4d, 0x37, 0xc4,
+	0x32, 0x82, 0x5b, 0xe0, 0x71, 0xd6, 0x60, 0xc6, 0xa5, 0xa9, 0xcc, 0x86, 0xaf, 0xd9, 0x2f, 0x33,
+	0xae, 0x65, 0xc6, 0x29, 0xa4, 0x6b, 0x68, 0xce, 0x8e, 0xbd, 0x41, 0xe7, 0xe2, 0xcc, 0x0a, 0x59,
+	0x83, 0x06, 0xb1, 0x38, 0x93, 0xd4, 0x46, 0x55, 0xe7, 0x2c, 0xce, 0x07, 0xb0, 0xa8, 0x25, 0x69,
+	0xc9, 0x56, 0xcc, 0x6b, 0x30, 0xc3, 0xac, 0x19, 0x95, 0xbc, 0x13, 0x66, 0x98, 0x0c, 0x4d, 0xc6,
+	0x43, 0x5e, 0xe8, 0x73, 0x47, 0x6c, 0x3c, 0x16, 0x61, 0x41, 0x12, 0x4a, 0x32, 0x9d, 0x8f, 0xa0,
+	0xf5, 0xc0, 0xdd, 0xff, 0x0c, 0x93, 0xfe, 0xf1, 0xa1, 0x4c, 0xde, 0x3f, 0x2a, 0xc3, 0xc6, 0x61,
+	0x64, 0xac, 0x2d, 0x90, 0xdc, 0x12, 0x9f, 0xf3, 0x31, 0x2c, 0x3f, 0x08, 0xc3, 0x22, 0xca, 0x5a,
+	0xfd, 0x36, 0x34, 0xe2, 0x82, 0xb8, 0xc2, 0x91, 0x59, 0xe2, 0xce, 0x99, 0x9c, 0x9f, 0xc3, 0xe2,
+	0x5e, 0x3c, 0x20, 0x31, 0xde, 0xdc, 0x7f, 0xf2, 0x08, 0x67, 0xa9, 0x10, 0x41, 0x4d, 0x96, 0x8c,
+	0x4a, 0x46, 0xdd, 0x55, 0xdf, 0x32, 0x37, 0xc4, 0x87, 0x5e, 0x90, 0xa4, 0xdc, 0xb4, 0xc3, 0x66,
+	0xe2, 0xc3, 0xcd, 0x24, 0xe5, 0xf2, 0x6c, 0x93, 0xb5, 0x0d, 0x8d, 0x07, 0xe7, 0x2a, 0x41, 0xd4,
+	0xdd, 0xd9, 0x20, 0x49, 0xf7, 0xe2, 0xc1, 0xb9, 0xf3, 0x03, 0xd5, 0x00, 0xc0, 0x38, 0x74, 0xfd,
+	0x38, 0xa4, 0xd1, 0x43, 0x7c, 0x5a, 0xd0, 0x90, 0x5d, 0x36, 0x6d, 0x22, 0xfc, 0xb6, 0x02, 0xad,
+	0x07, 0x7d, 0x1c, 0x8b, 0x87, 0x58, 0xf8, 0x64, 0xa0, 0x2e, 0x94, 0xa7, 0x98, 0x71, 0x42, 0x63,
+	0xb3, 0x3e, 0x2d, 0x28, 0xfb, 0x01, 0x24, 0x26, 0xc2, 0x0b, 0x7d, 0x1c, 0xd1, 0x58, 0x49, 0xa9,
+	0xbb, 0x20, 0x51, 0x0f, 0x15, 0x06, 0xdd, 0x86, 0x8e, 0x6e, 0x57, 0x7a, 0xc7, 0x7e, 0x1c, 0x0e,
+	0x30, 0xd3, 0x29, 0xa0, 0xe1, 0xce, 0x69, 0xf4, 0x8e, 0xc1, 0xa2, 0x37, 0x60, 0xde, 0x64, 0x81,
+	0x9c, 0xb3, 0xa6, 0x38, 0x3b, 0x06, 0x5f, 0x62, 0x4d, 0x93, 0x84, 0x32, 0xc1, 0x3d, 0x8e, 0x83,
+	0x80, 0x46, 0x89, 0xb9, 0x8d, 0x75, 0x2c, 0xfe, 0x40, 0xa3, 0x9d, 0x3e, 0x2c, 0x6e, 0x4b, 0x3f,
+	0x8d, 0x27, 0xf9, 0xb2, 0x9a, 0x8b, 0x70, 0xe4, 0x1d, 0x0e, 0x68, 0x70, 0xe2, 0xc9, 0xdc, 0x6c,
+	0x22, 0x2c, 0xeb, 0xbd, 0x0d, 0x89, 0x3c, 0x20, 0x5f, 0xab, 0xc6, 0x83, 0xe4, 0x3a, 0xa6, 0x22,
+	0x19, 0xa4, 0x7d, 0x2f, 0x61, 0xf4, 0x10, 0x1b, 0x17, 0x3b, 0x11, 0x8e, 0x76, 0x34, 0x7e, 0x5f,
+	0xa2, 0x9d, 0xbf, 0x54, 0x60, 0xa9, 0xac, 0xc9, 0x9c, 0x34, 0xeb, 0xb0, 0x54, 0x56, 0x65, 0xaa,
+	0x0f, 0x5d, 0xdd, 0x2e, 0x14, 0x15, 0xea, 0x3a, 0xe4, 0x2e, 0xb4, 0x55, 0x0f, 0xdb, 0x0b, 0xb5,
+	0xa4, 0x72, 0xcd, 0x55, 0x9c, 0x17, 0xb7, 0xe5, 0x17, 0x20, 0xf4, 0x3e, 0x5c, 0x33, 0xee, 0x7b,
+	0xa3, 0x66, 0xeb, 0x05, 0xb1, 0x6c, 0x18, 0x1e, 0x0d, 0x59, 0xff, 0x29, 0x74, 0x73, 0xd4, 0xc6,
+	0xb9, 0x42, 0xe6, 0x8b, 0x79, 0x71, 0xc8, 0xd9, 0x07, 0x61, 0xc8, 0xd4, 0x2e, 0xa9, 0xb9, 0xe3,
+	0x48, 0xce, 0x7d, 0xb8, 0x7a, 0x80, 0x85, 0x8e, 0x86, 0x2f, 0xcc, 0x45, 0x48, 0x0b, 0x9b, 0x87,
+	0xea, 0x01, 0x0e, 0x94, 0xf3, 0x55, 0x57, 0x7e, 0xca, 0x05, 0xf8, 0x84, 0xe3, 0x40, 0x79, 0x59,
+	0x75, 0xd5, 0xb7, 0xf3, 0xe7, 0x0a, 0xcc, 0x9a, 0xb3, 0x41, 0x9e, 0x6f, 0x21, 0x23, 0xa7, 0x98,
+	0x99, 0xa5, 0x67, 0x20, 0xd9, 0x90, 0xd1, 0x5f, 0x1e, 0x4d, 0x04, 0xa1, 0xd9, 0x89, 0xd3, 0xd6,
+	0xd8, 0x3d, 0x8d, 0x94, 0xc3, 0x75, 0xf7, 0xcd, 0x5c, 0x74, 0x0d, 0x24, 0xf1, 0x47, 0x5c, 0xee,
+	0xf0, 0x6e, 0xcd, 0xf4, 0x18, 0x15, 0x24, 0x97, 0xba, 0x95, 0x37, 0xad, 0xe4, 0x59, 0x50, 0x2e,
+	0xf5, 0x88, 0xa6, 0xb1, 0xf0, 0x12, 0x4a, 0x62, 0x61, 0x8e, 0x14, 0x50, 0xa8, 0x7d, 0x89, 0x71,
+	0x7e, 0x5b, 0x81, 0x19, 0xdd, 0xa2, 0x97, 0x57, 0xeb, 0xec, 0x60, 0x9f, 0x22, 0xaa, 0x48, 0x52,
+	0xba, 0x74, 0x26, 0x57, 0xdf, 0x72, 0x1f, 0x9f, 0x46, 0xfa, 0x78, 0x32, 0xa6, 0x9d, 0x46, 0xea,
+	0x5c, 0x7a, 0x1d, 0xe6, 0xf2, 0xfa, 0x40, 0xd1, 0xb5, 0x89, 0xed, 0x0c, 0xab, 0xd8, 0x2e, 0xb4,
+	0xd4, 0xf9, 0x89, 0xec, 0x28, 0x64, 0xed, 0xe9, 0x79, 0xa8, 0xa6, 0x99, 0x31, 0xf2, 0x53, 0x62,
+	0xfa, 0x59, 0x65, 0x21, 0x3f, 0xd1, 0x2d, 0x98, 0xf3, 0xc3, 0x90, 0xc8, 0xe1, 0xfe, 0x60, 0x9b,
+	0x84, 0xd9, 0x26, 0x2d, 0x63, 0x9d, 0xbf, 0x55, 0xa0, 0xb3, 0x49, 0x93, 0xf3, 0x8f, 0xc8, 0x00,
+	0x17, 0x32, 0x88, 0x32, 0xd2, 0x14, 0x16, 0xf2, 0x5b, 0x16, 0xcb, 0x47, 0x64, 0x80, 0xf5, 0xd6,
+	0xd2, 0x33, 0x5b, 0x97, 0x08, 0xb5, 0xad, 0x2c, 0x31, 0xeb, 0xfa, 0xb5, 0x35, 0xf1, 0x91, 0x6c,
+	0xf6, 0x5d, 0x83, 0x7a, 0x48, 0x98, 0x97, 0xf5, 0xf8, 0xda, 0xee, 0x6c, 0x48, 0x98, 0x22, 0x19,
+	0x47, 0xa6, 0x55, 0x9b, 0xb9, 0xe8, 0xc8, 0x8c, 0xc6, 0x48, 0x47, 0x96, 0x61, 0x86, 0x1e, 0x1d,
+	0x71, 0x2c, 0x54, 0x01, 0x5f, 0x75, 0x0d, 0x94, 0xa5, 0xb9, 0x7a, 0x21, 0xcd, 0x5d, 0x81, 0x45,
+	0xf5, 0xa0, 0xf1, 0x98, 0xf9, 0x01, 0x89, 0xfb, 0xf6, 0x78, 0x58, 0x02, 0x74, 0x20, 0x68, 0x32,
+	0x8a, 0xdd, 0xc6, 0x62, 0x6f, 0xef, 0xd1, 0xd6, 0x29, 0x8e, 0x85, 0xc5, 0xbe, 0x05, 0x75, 0x8b,
+	0xfa, 0x2f, 0xca, 0xba, 0x3b, 0xdf, 0x2c, 0x98, 0xc4, 0x6a, 0x5a, 0x04, 0x68, 0x1b, 0x3a, 0x43,
+	0x4f, 0x4e, 0xe8, 0x46, 0xb1, 0x92, 0x18, 0xee, 0xd7, 0xf6, 0x96, 0xd7, 0xf4, 0x13, 0xd6, 0x9a,
+	0x7d, 0xc2, 0x5a, 0xdb, 0x92, 0x4f, 0x58, 0x68, 0x0b, 0xe6, 0xca, 0x8f, 0x33, 0xe8, 0xba, 0x2d,
+	0xb1, 0xc6, 0x3c, 0xd9, 0x5c, 0x28, 0x66, 0x1b, 0x3a, 0x43, 0xef, 0x34, 0xd6, 0x9e, 0xf1, 0xcf,
+	0x37, 0x17, 0x0a, 0xba, 0x0f, 0xcd, 0xc2, 0xc3, 0x0c, 0xea, 0x6a, 0x21, 0xa3, 0x6f, 0x35, 0x17,
+	0x0a, 0xd8, 0x84, 0x76, 0xe9, 0xad, 0x04, 0xf5, 0x8c, 0x3f, 0x63, 0x1e, 0x50, 0x2e, 0x14, 0xb2,
+	0x01, 0xcd, 0xc2, 0x93, 0x85, 0xb5, 0x62, 0xf4, 0x5d, 0xa4, 0x77, 0x6d, 0x0c, 0xc5, 0xe4, 0xef,
+	0x1d, 0x68, 0x97, 0x1e, 0x18, 0xac, 0x21, 0xe3, 0x1e, 0x37, 0x7a, 0xd7, 0xc7, 0xd2, 0x8c, 0xa4,
+	0x6d, 0xe8, 0x0c, 0x3d, 0x37, 0xd8, 0xe0, 0x8e, 0x7f, 0x85, 0xb8, 0xd0, 0xad, 0x4f, 0x60, 0xae,
+	0x7c, 0x9b, 0x2c, 0x4c, 0xf6, 0xe8, 0xe3, 0x42, 0xef, 0xc6, 0x78, 0xa2, 0xb1, 0x6a, 0x0b, 0xe6,
+	0xca, 0xef, 0x0a, 0x56, 0xd8, 0xd8, 0xd7, 0x86, 0xc9, 0x2b, 0xa7, 0xf4, 0xc4, 0x90, 0xaf, 0x9c,
+	0x71, 0x2f, 0x0f, 0x17, 0x0a, 0x7a, 0x00, 0x60, 0xee, 0x8e, 0x21, 0x89, 0xb3, 0x29, 0x1b, 0xb9,
+	0xb3, 0xf6, 0xae, 0x8d, 0xa1, 0x18, 0x97, 0xee, 0x03, 0xe8, 0x2b, 0x5f, 0x48, 0x53, 0x81, 0xae,
+	0x5a, 0x33, 0x86, 0xee, 0x99, 0xbd, 0xee, 0x28, 0x61, 0x44, 0x00, 0x66, 0xec, 0x45, 0x04, 0x7c,
+	0x08, 0x90, 0x5f, 0x25, 0xad, 0x80, 0x91, 0xcb, 0xe5, 0x84, 0x18, 0xb4, 0x8a, 0x17, 0x47, 0x64,
+	0x7c, 0x1d, 0x73, 0x99, 0x9c, 0x20, 0xa2, 0x33, 0x54, 0x99, 0x97, 0x17, 0xdb, 0x70, 0xc1, 0xde,
+	0x1b, 0xa9, 0xce, 0xd1, 0x5d, 0x68, 0x15, 0x4b, 0x72, 0x6b, 0xc5, 0x98, 0x32, 0xbd, 0x57, 0x2a,
+	0xcb, 0xd1, 0x7d, 0x98, 0x2b, 0x97, 0xe3, 0xa8, 0xb0, 0x2f, 0x46, 0x8a, 0xf4, 0x9e, 0xe9, 0x75,
+	0x15, 0xd8, 0xdf, 0x01, 0xc8, 0xcb, 0x76, 0x1b, 0xbe, 0x91, 0x42, 0x7e, 0x48, 0xeb, 0x36, 0x74,
+	0x86, 0xca, 0x71, 0xeb, 0xf1, 0xf8, 0x2a, 0x7d, 0x52, 0xf4, 0x8b, 0xe7, 0x82, 0xf5, 0x7b, 0xcc,
+	0x59, 0x31, 0x29, 0xfd, 0x15, 0xce, 0x10, 0xbb, 0x8a, 0x47, 0x8f, 0x95, 0x49, 0xe9, 0xaf, 0x74,
+	0x97, 0xb4, 0x59, 0x67, 0xdc, 0x05, 0x73, 0xd2, 0xa1, 0x50, 0xbe, 0x26, 0xda, 0x79, 0x18, 0x7b,
+	0x79, 0x9c, 0x14, 0x8f, 0xe2, 0xdd, 0xc4, 0xc6, 0x63, 0xcc, 0x7d, 0xe5, 0x39, 0xd9, 0xa1, 0x78,
+	0xff, 0x28, 0x64, 0x87, 0x31, 0xd7, 0x92, 0x0b, 0x05, 0xed, 0x40, 0x67, 0xdb, 0x96, 0x96, 0xa6,
+	0xec, 0x35, 0xe6, 0x8c, 0x29, 0xf3, 0x7b, 0xbd, 0x71, 0x24, 0xb3, 0x45, 0x3f, 0x81, 0x85, 0x91,
+	0x92, 0x17, 0xad, 0x64, 0xbd, 0xdd, 0xb1, 0xb5, 0xf0, 0x85, 0x66, 0xed, 0xc2, 0xfc, 0x70, 0xc5,
+	0x8b, 0x5e, 0x36, 0x93, 0x3e, 0xbe, 0x12, 0xbe, 0x50, 0xd4, 0xfb, 0x50, 0xb7, 0x15, 0x16, 0x32,
+	0x3d, 0xf4, 0xa1, 0x8a, 0xeb, 0xc2, 0xa1, 0x77, 0xa1, 0x59, 0xa8, 0x51, 0xec, 0xaa, 0x1b, 0x2d,
+	0x5b, 0x7a, 0xa6, 0xe5, 0x6d, 0xd1, 0x1b, 0x67, 0xdf, 0x7e, 0xb7, 0xf2, 0xd2, 0x3f, 0xbe, 0x5b,
+	0x79, 0xe9, 0x37, 0xcf, 0x56, 0x2a, 0xdf, 0x3e, 0x5b, 0xa9, 0xfc, 0xfd, 0xd9, 0x4a, 0xe5, 0x5f,
+	0xcf, 0x56, 0x2a, 0x3f, 0xfd, 0xc5, 0xf7, 0xfc, 0xbf, 0x0e, 0x4b, 0x63, 0xf9, 0xa0, 0xb0, 0x7e,
+	0x4a, 0x98, 0x28, 0x90, 0xe4, 0xdf, 0x71, 0x86, 0xff, 0xca, 0x23, 0x4d, 0x38, 0x9c, 0x51, 0xf0,
+	0x3b, 0xff, 0x19, 0x00, 0x31, 0xec, 0xcc, 0xaf, 0xc2, 0x24, 0x00, 0x00,
 }
 
 func (m *CreateContainerRequest) Marshal() (dAtA []byte, err error) {
@@ -4417,6 +4423,25 @@ func (m *CreateSandboxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Sysctls) > 0 {
+		for k := range m.Sysctls {
+			v := m.Sysctls[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintAgent(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintAgent(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintAgent(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
 	if len(m.KernelModules) > 0 {
 		for iNdEx := len(m.KernelModules) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -6351,6 +6376,14 @@ func (m *CreateSandboxRequest) Size() (n int) {
 			n += 1 + l + sovAgent(uint64(l))
 		}
 	}
+	if len(m.Sysctls) > 0 {
+		for k, v := range m.Sysctls {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovAgent(uint64(len(k))) + 1 + len(v) + sovAgent(uint64(len(v)))
+			n += mapEntrySize + 1 + sovAgent(uint64(mapEntrySize))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -7345,6 +7378,16 @@ func (this *CreateSandboxRequest) String() string {
 		repeatedStringForKernelModules += strings.Replace(f.String(), "KernelModule", "KernelModule", 1) + ","
 	}
 	repeatedStringForKernelModules += "}"
+	keysForSysctls := make([]string, 0, len(this.Sysctls))
+	for k, _ := range this.Sysctls {
+		keysForSysctls = append(keysForSysctls, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForSysctls)
+	mapStringForSysctls := "map[string]string{"
+	for _, k := range keysForSysctls {
+		mapStringForSysctls += fmt.Sprintf("%v: %v,", k, this.Sysctls[k])
+	}
+	mapStringForSysctls += "}"
 	s := strings.Join([]string{`&CreateSandboxRequest{`,
 		`Hostname:` + fmt.Sprintf("%v", this.Hostname) + `,`,
 		`Dns:` + fmt.Sprintf("%v", this.Dns) + `,`,
@@ -7353,6 +7396,7 @@ func (this *CreateSandboxRequest) String() string {
 		`SandboxId:` + fmt.Sprintf("%v", this.SandboxId) + `,`,
 		`GuestHookPath:` + fmt.Sprintf("%v", this.GuestHookPath) + `,`,
 		`KernelModules:` + repeatedStringForKernelModules + `,`,
+		`Sysctls:` + mapStringForSysctls + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -13118,6 +13162,133 @@ func (m *CreateSandboxRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sysctls", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAgent
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAgent
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAgent
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sysctls == nil {
+				m.Sysctls = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAgent
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAgent
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthAgent
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthAgent
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAgent
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthAgent
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthAgent
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipAgent(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthAgent
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Sysctls[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAgent(dAtA[iNdEx:])
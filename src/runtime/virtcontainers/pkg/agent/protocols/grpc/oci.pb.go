@@ -424,7 +424,10 @@ type Hooks struct {
 	// Poststart is a list of hooks to be run after the container process is started.
 	Poststart []Hook `protobuf:"bytes,2,rep,name=Poststart,proto3" json:"Poststart"`
 	// Poststop is a list of hooks to be run after the container process exits.
-	Poststop             []Hook   `protobuf:"bytes,3,rep,name=Poststop,proto3" json:"Poststop"`
+	Poststop []Hook `protobuf:"bytes,3,rep,name=Poststop,proto3" json:"Poststop"`
+	// StartContainer is a list of hooks to be run in the container namespaces
+	// once the container is started, before the container process is executed.
+	StartContainer       []Hook   `protobuf:"bytes,4,rep,name=StartContainer,proto3" json:"StartContainer"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1509,139 +1512,140 @@ func init() {
 }
 
 var fileDescriptor_e42fef2823778fc8 = []byte{
-	// 2099 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x18, 0x4b, 0x73, 0x1b, 0x49,
-	0x39, 0x23, 0xc9, 0xb2, 0xd4, 0x8a, 0xf3, 0xe8, 0xcd, 0x66, 0x87, 0xb0, 0xa5, 0xf5, 0x0e, 0x29,
-	0x30, 0x90, 0xb5, 0x8b, 0x84, 0xc7, 0xb2, 0x3c, 0xaa, 0x64, 0x3b, 0x89, 0x55, 0x1b, 0x47, 0xa2,
-	0x65, 0x6f, 0x60, 0x0f, 0x5b, 0xd5, 0x1e, 0xb5, 0xa5, 0x5e, 0x8f, 0xa6, 0xa7, 0xba, 0x5b, 0x76,
-	0xcc, 0x09, 0x6e, 0x1c, 0xa9, 0xe2, 0x17, 0x70, 0x02, 0xfe, 0x01, 0xc5, 0x89, 0x1b, 0x29, 0x4e,
-	0x1c, 0xa9, 0xa2, 0x0a, 0x88, 0xef, 0xdc, 0x39, 0x52, 0x5f, 0x3f, 0x46, 0x2d, 0xc9, 0x81, 0x0d,
-	0x7b, 0x52, 0x7f, 0xcf, 0xee, 0xef, 0xfd, 0x8d, 0x50, 0x6f, 0xc4, 0xf5, 0x78, 0x7a, 0xb4, 0x99,
-	0x8a, 0xc9, 0xd6, 0x09, 0xd5, 0xf4, 0xbd, 0x54, 0xe4, 0x9a, 0xf2, 0x9c, 0x49, 0xb5, 0x04, 0x2b,
-	0x99, 0x6e, 0xd1, 0x11, 0xcb, 0xf5, 0x56, 0x21, 0x85, 0x16, 0xa9, 0xc8, 0x94, 0x3d, 0xa9, 0x2d,
-	0x91, 0xf2, 0x4d, 0x73, 0xc4, 0xb5, 0x91, 0x2c, 0xd2, 0x3b, 0xef, 0x05, 0x6a, 0x47, 0x62, 0x24,
-	0x2c, 0xdf, 0xd1, 0xf4, 0xd8, 0x40, 0x06, 0x30, 0x27, 0x2b, 0x74, 0xa7, 0x3d, 0x12, 0x62, 0x94,
-	0xb1, 0x19, 0xd7, 0x99, 0xa4, 0x45, 0xc1, 0xa4, 0xb2, 0xf4, 0xe4, 0x4f, 0x55, 0x54, 0x1b, 0x14,
-	0x2c, 0xc5, 0x31, 0x5a, 0xfd, 0x88, 0x49, 0xc5, 0x45, 0x1e, 0x47, 0xeb, 0xd1, 0x46, 0x93, 0x78,
-	0x10, 0x7f, 0x05, 0xad, 0xf6, 0xa5, 0x48, 0x99, 0x52, 0x71, 0x65, 0x3d, 0xda, 0x68, 0xdd, 0x5f,
-	0xdb, 0x84, 0x97, 0x6c, 0x3a, 0x24, 0xf1, 0x54, 0xdc, 0x46, 0x35, 0x22, 0x84, 0x8e, 0xab, 0x86,
-	0x0b, 0x59, 0x2e, 0xc0, 0x10, 0x83, 0xc7, 0x77, 0x50, 0x63, 0x4f, 0x28, 0x9d, 0xd3, 0x09, 0x8b,
-	0x6b, 0xe6, 0x8e, 0x12, 0xc6, 0x5f, 0x45, 0xf5, 0x7d, 0x31, 0xcd, 0xb5, 0x8a, 0x57, 0xd6, 0xab,
-	0x1b, 0xad, 0xfb, 0x2d, 0x2b, 0x6d, 0x70, 0xdb, 0xb5, 0x17, 0x7f, 0x7f, 0xe7, 0x0a, 0x71, 0x0c,
-	0xf8, 0x5d, 0xb4, 0xb2, 0x27, 0xc4, 0x89, 0x8a, 0xeb, 0xe6, 0x1e, 0xc7, 0x69, 0x50, 0xc4, 0x52,
-	0xf0, 0x0f, 0x50, 0xab, 0x93, 0xe7, 0x42, 0x53, 0xcd, 0x45, 0xae, 0xe2, 0x55, 0xa3, 0xf2, 0x8b,
-	0x96, 0x11, 0xac, 0xdd, 0x0c, 0xa8, 0x0f, 0x73, 0x2d, 0xcf, 0x49, 0xc8, 0x0f, 0x37, 0x3c, 0xe1,
-	0xf9, 0xf4, 0x79, 0xdc, 0x08, 0x6f, 0x30, 0x28, 0x62, 0x29, 0xe0, 0x94, 0x81, 0xc8, 0xa8, 0xe4,
-	0x2a, 0x6e, 0x86, 0x4e, 0x71, 0x48, 0xe2, 0xa9, 0xc0, 0xf8, 0x8c, 0xe7, 0x43, 0x71, 0xa6, 0x62,
-	0x14, 0x32, 0x3a, 0x24, 0xf1, 0xd4, 0x3b, 0x3f, 0x44, 0x37, 0x16, 0x5f, 0x85, 0x6f, 0xa0, 0xea,
-	0x09, 0x3b, 0x77, 0x01, 0x81, 0x23, 0xbe, 0x85, 0x56, 0x4e, 0x69, 0x36, 0x65, 0x26, 0x14, 0x4d,
-	0x62, 0x81, 0x0f, 0x2a, 0xef, 0x47, 0xc9, 0x1f, 0xaa, 0x65, 0x9c, 0xc0, 0xd3, 0x07, 0x4c, 0x4e,
-	0x78, 0x4e, 0x33, 0x23, 0xdc, 0x20, 0x25, 0x8c, 0xbf, 0x8e, 0x5a, 0x3b, 0x22, 0x57, 0x22, 0x63,
-	0x03, 0xfe, 0x53, 0xe6, 0x42, 0xda, 0xb4, 0x8f, 0xda, 0x16, 0xcf, 0x49, 0x48, 0xc5, 0x77, 0x51,
-	0xed, 0x50, 0x31, 0x39, 0x1f, 0x52, 0xc0, 0xb8, 0x98, 0x18, 0x2a, 0xc6, 0xa8, 0xd6, 0x91, 0x23,
-	0x15, 0xd7, 0xd6, 0xab, 0x1b, 0x4d, 0x62, 0xce, 0xf0, 0xf4, 0x87, 0xf9, 0xa9, 0x89, 0x66, 0x93,
-	0xc0, 0x11, 0x30, 0x3b, 0x67, 0x43, 0x13, 0xb5, 0x26, 0x81, 0x23, 0xfe, 0x1e, 0xba, 0xba, 0x43,
-	0x0b, 0x7a, 0xc4, 0x33, 0xae, 0x39, 0x83, 0x38, 0xc1, 0x2d, 0x6f, 0x05, 0xee, 0x0e, 0xc9, 0x64,
-	0x8e, 0x19, 0x7f, 0x03, 0xad, 0x92, 0x8c, 0x4f, 0xb8, 0x56, 0x71, 0xc3, 0xc4, 0xf7, 0xa6, 0x4b,
-	0xcb, 0xde, 0xa0, 0xfb, 0x63, 0x4b, 0x71, 0x8f, 0xf4, 0x7c, 0x78, 0x03, 0x5d, 0x7f, 0x2a, 0x9e,
-	0xb2, 0xb3, 0xbe, 0xe4, 0xa7, 0x3c, 0x63, 0x23, 0x66, 0x83, 0xd7, 0x20, 0x8b, 0x68, 0xe0, 0xec,
-	0x14, 0x05, 0x95, 0x13, 0x21, 0xfb, 0x52, 0x1c, 0xf3, 0x8c, 0x99, 0xe8, 0x35, 0xc9, 0x22, 0x1a,
-	0xaf, 0xa3, 0x56, 0xaf, 0xb7, 0x3f, 0x48, 0x85, 0x64, 0x9d, 0xe1, 0xa7, 0x71, 0x6b, 0x3d, 0xda,
-	0xa8, 0x92, 0x10, 0x85, 0x13, 0x74, 0x75, 0xc0, 0x32, 0xb0, 0xe6, 0x09, 0x3d, 0x62, 0x59, 0x7c,
-	0xd5, 0x28, 0x9a, 0xc3, 0x25, 0x0f, 0x50, 0x75, 0x5b, 0x3c, 0xc7, 0xb7, 0x51, 0x7d, 0x8f, 0xf1,
-	0xd1, 0x58, 0x9b, 0xa8, 0xad, 0x11, 0x07, 0x41, 0xd4, 0x9f, 0xf1, 0xa1, 0x1e, 0x9b, 0x68, 0xad,
-	0x11, 0x0b, 0x24, 0xb9, 0x0d, 0x0e, 0x38, 0xf6, 0xb0, 0xbb, 0xeb, 0x44, 0xe0, 0x08, 0x98, 0xc7,
-	0xdd, 0x5d, 0xc7, 0x0d, 0x47, 0xfc, 0x65, 0x74, 0xad, 0x33, 0x1c, 0x72, 0xc8, 0x2d, 0x9a, 0x3d,
-	0xe6, 0x43, 0x15, 0x57, 0xd7, 0xab, 0x1b, 0x6b, 0x64, 0x01, 0x0b, 0x99, 0x03, 0x3a, 0xc3, 0x1a,
-	0xf5, 0x70, 0xf2, 0x9b, 0x08, 0xdd, 0x5c, 0x8a, 0x0a, 0x48, 0x6c, 0x8b, 0x69, 0x3e, 0xe4, 0xf9,
-	0x28, 0x8e, 0x4c, 0xb4, 0x4b, 0x18, 0xbf, 0x8d, 0x9a, 0x0f, 0x8f, 0x8f, 0x59, 0xaa, 0xf9, 0x29,
-	0x64, 0x1a, 0x10, 0x67, 0x08, 0x70, 0x5d, 0x37, 0x1f, 0x33, 0xc9, 0x35, 0x3d, 0xca, 0x98, 0x79,
-	0x50, 0x93, 0x84, 0x28, 0x90, 0xef, 0x43, 0xde, 0x6a, 0xcd, 0x86, 0x2e, 0xbb, 0x66, 0x08, 0x68,
-	0x59, 0x9d, 0xc9, 0x11, 0x67, 0xb9, 0x76, 0x69, 0xe6, 0xc1, 0xa4, 0x8b, 0x5a, 0x41, 0x1a, 0x40,
-	0x7e, 0x1e, 0x9c, 0x17, 0xcc, 0xd5, 0x91, 0x39, 0x03, 0x6e, 0x8f, 0xca, 0xa1, 0xf1, 0x51, 0x8d,
-	0x98, 0x33, 0xe0, 0x06, 0xe2, 0xd8, 0x36, 0xb0, 0x1a, 0x31, 0xe7, 0x44, 0xa0, 0x15, 0xd3, 0x77,
-	0xe0, 0xb5, 0x43, 0xa6, 0x34, 0xcf, 0x4d, 0x81, 0x3a, 0x5d, 0x21, 0x0a, 0xa2, 0xa7, 0xc4, 0x54,
-	0xa6, 0xbe, 0x38, 0x1d, 0x04, 0x6a, 0x35, 0x5c, 0x5f, 0xb5, 0xd7, 0xc3, 0x19, 0xde, 0x2e, 0x0a,
-	0xdb, 0x9d, 0xac, 0x5d, 0x1e, 0x4c, 0xbe, 0x6d, 0xbb, 0x28, 0x48, 0xf5, 0xa9, 0x1e, 0xfb, 0x47,
-	0xc3, 0x19, 0x7c, 0x4d, 0x18, 0x1d, 0x8a, 0x3c, 0x3b, 0x37, 0x77, 0x34, 0x48, 0x09, 0x27, 0xbf,
-	0x8a, 0x5c, 0x5f, 0xc4, 0xf7, 0x50, 0xa3, 0x2f, 0x99, 0xd2, 0x54, 0x6a, 0x13, 0x91, 0xb2, 0x70,
-	0x81, 0xec, 0x6a, 0xa2, 0xe4, 0xc0, 0x9b, 0xa8, 0xd9, 0x17, 0x4a, 0x5b, 0xf6, 0xca, 0x2b, 0xd8,
-	0x67, 0x2c, 0x46, 0xbb, 0x01, 0x44, 0x61, 0x42, 0x76, 0xb9, 0x76, 0xc7, 0x91, 0x7c, 0x8c, 0x6a,
-	0x80, 0xbf, 0xd4, 0x1a, 0xdf, 0x36, 0x2a, 0xcb, 0x6d, 0xa3, 0x3a, 0x6b, 0x1b, 0x31, 0x5a, 0x3d,
-	0xe0, 0x13, 0x26, 0xa6, 0xda, 0x24, 0x64, 0x95, 0x78, 0x30, 0xf9, 0xdd, 0x8a, 0xeb, 0xd3, 0xf8,
-	0xfb, 0xa8, 0x75, 0xd8, 0xdd, 0xdd, 0xa7, 0x45, 0xc1, 0xf3, 0x91, 0x72, 0x46, 0xdf, 0x0a, 0xfa,
-	0x48, 0x49, 0x74, 0x0f, 0x0c, 0xd9, 0x41, 0xfa, 0x71, 0x20, 0x5d, 0xf9, 0xdf, 0xd2, 0x01, 0x3b,
-	0xde, 0x42, 0xf5, 0xc1, 0xb9, 0x4a, 0x75, 0xe6, 0xbc, 0x11, 0xb6, 0xaf, 0x4d, 0x4b, 0xb1, 0x23,
-	0xc6, 0xb1, 0xe1, 0xfb, 0xa8, 0x49, 0x98, 0x4d, 0x0d, 0x65, 0x4c, 0x9a, 0xbf, 0xac, 0xa4, 0x91,
-	0x19, 0x1b, 0x24, 0xdf, 0xce, 0x48, 0x8a, 0x69, 0xa1, 0x8c, 0x17, 0x57, 0x6c, 0xf2, 0x05, 0x28,
-	0xfc, 0x01, 0x42, 0x4f, 0xe9, 0x84, 0xa9, 0x82, 0x82, 0xda, 0xfa, 0x92, 0x0d, 0x25, 0xd1, 0xd9,
-	0x10, 0x70, 0x43, 0x2b, 0xdd, 0x65, 0xa7, 0x3c, 0x65, 0x7e, 0x54, 0xde, 0x0c, 0x04, 0x2d, 0xc5,
-	0xb7, 0x52, 0xc7, 0x87, 0xef, 0xa1, 0xd5, 0x01, 0x4b, 0x53, 0x31, 0x29, 0xdc, 0x90, 0xc4, 0x81,
-	0x88, 0xa3, 0x10, 0xcf, 0x82, 0xef, 0xa1, 0x9b, 0x90, 0xd3, 0xc7, 0xaa, 0x2f, 0x45, 0x41, 0x47,
-	0xb6, 0x82, 0x9a, 0xc6, 0x88, 0x65, 0x02, 0x18, 0xbb, 0x4f, 0xd5, 0x09, 0x1b, 0x82, 0x61, 0x30,
-	0x36, 0x4d, 0x5f, 0x08, 0x50, 0xf8, 0x2e, 0x5a, 0xf3, 0x79, 0x6f, 0x79, 0x5a, 0x86, 0x67, 0x1e,
-	0x89, 0xdb, 0x08, 0x99, 0xd2, 0x0d, 0xdb, 0x6e, 0x80, 0xc1, 0x5b, 0xa8, 0xd1, 0xcd, 0x35, 0xcb,
-	0xc8, 0x50, 0xc7, 0x6b, 0xc6, 0x88, 0x37, 0xc2, 0xa0, 0x3b, 0x12, 0x29, 0x99, 0xee, 0x7c, 0x17,
-	0xb5, 0x82, 0x80, 0xbe, 0xd6, 0x74, 0x7e, 0xa7, 0x5c, 0x03, 0x80, 0x69, 0x38, 0x9d, 0x4c, 0xbc,
-	0xa0, 0x05, 0x80, 0xc1, 0xaf, 0x0c, 0x97, 0x33, 0x7c, 0x82, 0xae, 0xcd, 0x27, 0xa3, 0x99, 0x16,
-	0x42, 0xe9, 0xb2, 0xf5, 0x3b, 0xc8, 0x24, 0x8b, 0xdf, 0x2d, 0xcb, 0x29, 0x10, 0xa2, 0x4c, 0xa3,
-	0x83, 0xe1, 0x5f, 0x35, 0x24, 0x73, 0x4e, 0xde, 0x77, 0xfa, 0xcb, 0xbc, 0x78, 0x55, 0xdb, 0x34,
-	0x19, 0x58, 0x99, 0xd5, 0x71, 0xf2, 0xeb, 0x08, 0xb5, 0x82, 0x54, 0x79, 0x55, 0xad, 0x1b, 0x5d,
-	0x95, 0x40, 0xd7, 0x2d, 0xb4, 0xb2, 0x4f, 0x3f, 0x15, 0x76, 0xbb, 0xa8, 0x12, 0x0b, 0x18, 0x2c,
-	0xcf, 0x85, 0x74, 0xd5, 0x6e, 0x01, 0xe8, 0x7c, 0x8f, 0x78, 0xc6, 0xf6, 0xc5, 0x90, 0x99, 0xec,
-	0x5f, 0x23, 0x25, 0xec, 0xe7, 0x5f, 0x7d, 0x69, 0xfe, 0xad, 0x96, 0xf3, 0x2f, 0xf9, 0x47, 0xc5,
-	0x99, 0x37, 0xab, 0xa9, 0xef, 0xcc, 0xb2, 0x3e, 0x5a, 0xaa, 0x5c, 0x4b, 0xb1, 0x05, 0xb6, 0x98,
-	0xfb, 0xb0, 0xab, 0xb2, 0x89, 0x90, 0xe7, 0x6e, 0x79, 0x0a, 0xab, 0xc5, 0x12, 0x88, 0x63, 0xc0,
-	0xeb, 0xa8, 0xba, 0xd3, 0x3f, 0x74, 0xeb, 0xd3, 0xb5, 0x70, 0xb1, 0xe9, 0x1f, 0x12, 0x20, 0xe1,
-	0x2f, 0xa1, 0x5a, 0x1f, 0xc6, 0xb1, 0x6d, 0x04, 0xd7, 0x03, 0x16, 0x40, 0x13, 0x43, 0x84, 0x6a,
-	0xdb, 0xce, 0x44, 0x7a, 0xd2, 0xed, 0x19, 0xe3, 0xe7, 0xab, 0xcd, 0x51, 0x88, 0x67, 0xc1, 0x8f,
-	0xd0, 0xb5, 0xbd, 0xe9, 0x88, 0x15, 0x74, 0xc4, 0x9e, 0xd8, 0x05, 0xc9, 0xb6, 0x83, 0x38, 0x10,
-	0x9a, 0x63, 0x70, 0x06, 0x2e, 0x48, 0xc1, 0xad, 0x4f, 0x99, 0x3e, 0x13, 0xf2, 0xc4, 0x6d, 0x66,
-	0xe1, 0xad, 0x8e, 0x42, 0x3c, 0x4b, 0xf2, 0x37, 0x9f, 0x05, 0xce, 0xf4, 0x5b, 0xd0, 0x9c, 0x27,
-	0xdc, 0xae, 0x32, 0x55, 0x62, 0x01, 0xc8, 0x4d, 0xc2, 0x14, 0x93, 0xa7, 0xb6, 0x07, 0x54, 0xec,
-	0xba, 0x14, 0xa0, 0x4c, 0x6e, 0x9e, 0xd1, 0xc2, 0x25, 0x85, 0x39, 0x43, 0xa6, 0x7f, 0xc8, 0x64,
-	0xce, 0x32, 0x97, 0x14, 0x0e, 0x82, 0xfd, 0xc0, 0x9e, 0x0e, 0x76, 0xfa, 0xc6, 0x33, 0x55, 0x32,
-	0x43, 0x40, 0xfd, 0x83, 0x74, 0xc1, 0x73, 0xf8, 0x76, 0xa9, 0x9b, 0xa1, 0x1e, 0x60, 0xf0, 0xd7,
-	0xd0, 0x8d, 0x5d, 0xae, 0x60, 0xd1, 0xe8, 0xf5, 0xf6, 0x3f, 0xe4, 0x59, 0xc6, 0xa4, 0x31, 0xb4,
-	0x41, 0x96, 0xf0, 0xc9, 0x9f, 0x23, 0xd4, 0xf0, 0x81, 0x83, 0xe7, 0x0c, 0xc6, 0x54, 0x9a, 0xc4,
-	0x01, 0xa5, 0x0e, 0x02, 0x93, 0x7f, 0x34, 0x15, 0x9a, 0x3a, 0xb3, 0x2c, 0x00, 0xdc, 0x7d, 0x26,
-	0xb9, 0x18, 0xba, 0xbd, 0xc2, 0x41, 0xb0, 0x63, 0x12, 0x46, 0x33, 0xcd, 0x27, 0x8c, 0x4c, 0x73,
-	0xf8, 0x71, 0xd6, 0x2d, 0xa2, 0x61, 0x79, 0xf3, 0x28, 0xa7, 0x69, 0xc5, 0x68, 0x5a, 0xc0, 0x82,
-	0xeb, 0x76, 0x8a, 0xa9, 0x72, 0x2b, 0xb6, 0x39, 0x03, 0x6e, 0x9f, 0x4d, 0xec, 0x6e, 0xdd, 0x24,
-	0xe6, 0x9c, 0x9c, 0xb9, 0x3d, 0xee, 0x99, 0xd9, 0x2e, 0x5d, 0xd5, 0x96, 0xd5, 0x18, 0x5d, 0x5a,
-	0x8d, 0x95, 0xb0, 0x1a, 0x6f, 0xa3, 0xba, 0x95, 0x75, 0x1d, 0xc4, 0x41, 0xe0, 0xf1, 0x27, 0x8c,
-	0x1e, 0x3b, 0x5a, 0xcd, 0xd0, 0x02, 0x4c, 0x72, 0x88, 0xde, 0x30, 0x17, 0x1f, 0x8c, 0xa5, 0xd0,
-	0x3a, 0x63, 0xff, 0xc7, 0xd5, 0x18, 0xd5, 0x08, 0xd5, 0xcc, 0xef, 0x68, 0x70, 0x4e, 0xfe, 0x55,
-	0x45, 0x57, 0xc3, 0x52, 0x08, 0xde, 0x17, 0xfd, 0x97, 0xf7, 0x55, 0x16, 0xdf, 0x87, 0x3b, 0xe8,
-	0x6a, 0xe8, 0x93, 0x4b, 0x26, 0x7a, 0x48, 0x76, 0x65, 0x33, 0x27, 0x82, 0x0f, 0xd1, 0x9b, 0xde,
-	0x3a, 0x98, 0x46, 0xdb, 0x85, 0x72, 0xba, 0x6a, 0x46, 0xd7, 0x17, 0x02, 0x5d, 0xf3, 0x5e, 0x70,
-	0xda, 0x2e, 0x97, 0xc6, 0xcf, 0xd0, 0x6d, 0x4f, 0x78, 0x26, 0xb9, 0x66, 0x33, 0xbd, 0x2b, 0x9f,
-	0x4d, 0xef, 0x2b, 0xc4, 0x43, 0xc5, 0x70, 0x63, 0xb7, 0xd7, 0x1f, 0x38, 0xc5, 0xf5, 0xd7, 0x54,
-	0x3c, 0x2f, 0x8e, 0x7f, 0x82, 0xde, 0x9a, 0xbb, 0x32, 0xd0, 0xbc, 0xfa, 0xd9, 0x34, 0xbf, 0x4a,
-	0x3e, 0x79, 0x17, 0x35, 0xcb, 0x0e, 0x79, 0x79, 0x9f, 0x49, 0x7e, 0xee, 0xbf, 0x55, 0xc2, 0x46,
-	0x0e, 0xbc, 0x9d, 0x2c, 0x13, 0x67, 0xee, 0xa3, 0xd8, 0x02, 0x9f, 0x7b, 0x36, 0xdd, 0x46, 0xf5,
-	0x4e, 0x6a, 0xfe, 0x1f, 0xb1, 0x7b, 0x99, 0x83, 0x92, 0xcc, 0x65, 0xa5, 0xeb, 0x90, 0xb0, 0xc9,
-	0xee, 0x64, 0x54, 0xa9, 0x72, 0x60, 0x7b, 0x10, 0x6f, 0x23, 0xd4, 0x97, 0x5c, 0x48, 0xfb, 0x19,
-	0x6c, 0x17, 0xd0, 0xb7, 0x17, 0x76, 0x11, 0x79, 0x4c, 0x53, 0xe6, 0xb8, 0xce, 0xfd, 0x12, 0x37,
-	0x93, 0x4a, 0x1e, 0x21, 0xbc, 0xdc, 0xd9, 0x61, 0x6e, 0xf6, 0xe9, 0x88, 0x29, 0x98, 0xf6, 0x76,
-	0x1e, 0x97, 0xf0, 0xcc, 0x73, 0xf6, 0x1b, 0xc8, 0x79, 0x6e, 0x0f, 0xdd, 0xbe, 0xfc, 0x4e, 0xf0,
-	0x13, 0x2c, 0x07, 0x7e, 0xae, 0xc3, 0xd9, 0xe8, 0x77, 0x74, 0x57, 0x4f, 0x25, 0x9c, 0xfc, 0x32,
-	0x72, 0x0e, 0xf0, 0x6b, 0xe0, 0x5d, 0xb4, 0xb6, 0xcb, 0x8e, 0xe9, 0x34, 0xd3, 0x9d, 0x34, 0xf8,
-	0x88, 0x9a, 0x47, 0x02, 0x57, 0x47, 0xa6, 0x63, 0xae, 0x59, 0xaa, 0xa7, 0x92, 0xf9, 0xef, 0x83,
-	0x79, 0x24, 0xfe, 0x26, 0x6a, 0xc0, 0x2e, 0x46, 0xb3, 0x4c, 0xb9, 0x32, 0x9d, 0xdb, 0x40, 0x2d,
-	0xc9, 0x7f, 0x8e, 0x78, 0xce, 0x84, 0xa3, 0xeb, 0xe1, 0x8b, 0x3a, 0x72, 0x04, 0x5e, 0xe8, 0xe6,
-	0x43, 0xf6, 0xdc, 0xf5, 0x72, 0x0b, 0x00, 0xf6, 0xa3, 0x72, 0x93, 0xab, 0x11, 0x0b, 0x80, 0xb5,
-	0xe6, 0x70, 0x70, 0x26, 0x5c, 0x03, 0x2a, 0x61, 0x7c, 0x0d, 0x55, 0x7a, 0x85, 0xfb, 0x66, 0xae,
-	0xf4, 0x8a, 0x64, 0xe2, 0x8d, 0xb7, 0x77, 0x83, 0x46, 0xb3, 0x5a, 0xb9, 0x8f, 0x64, 0x0b, 0xd8,
-	0xdc, 0x29, 0x47, 0xa1, 0xc9, 0x1d, 0xe3, 0x84, 0x2d, 0xf7, 0x6d, 0x64, 0x4d, 0x7b, 0x73, 0x79,
-	0xb9, 0xee, 0x48, 0xff, 0x35, 0x62, 0x18, 0x93, 0x6f, 0xa1, 0xb5, 0xb9, 0xb5, 0x15, 0xdc, 0xf8,
-	0xe4, 0xc1, 0x0e, 0x4d, 0xc7, 0x6c, 0x90, 0x8e, 0xd9, 0x84, 0x7a, 0x67, 0xcf, 0x21, 0xb7, 0x7f,
-	0x11, 0xbd, 0x78, 0xd9, 0xbe, 0xf2, 0xd7, 0x97, 0xed, 0x2b, 0xff, 0x7e, 0xd9, 0x8e, 0x7e, 0x76,
-	0xd1, 0x8e, 0x7e, 0x7b, 0xd1, 0x8e, 0x7e, 0x7f, 0xd1, 0x8e, 0xfe, 0x78, 0xd1, 0x8e, 0x5e, 0x5c,
-	0xb4, 0xa3, 0xbf, 0x5c, 0xb4, 0xa3, 0x7f, 0x5e, 0xb4, 0xa3, 0x8f, 0x3f, 0x79, 0xcd, 0x3f, 0x34,
-	0xa5, 0x9d, 0x5e, 0x5b, 0xa7, 0x5c, 0xea, 0x80, 0x54, 0x9c, 0x8c, 0x96, 0xfe, 0xeb, 0x04, 0xeb,
-	0x8e, 0xea, 0x06, 0x7e, 0xf0, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x9b, 0x19, 0xc3, 0xc2, 0x39,
-	0x15, 0x00, 0x00,
+	// 2114 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcd, 0x73, 0x1b, 0x49,
+	0x15, 0xdf, 0xd1, 0x97, 0xa5, 0x56, 0xe4, 0x24, 0xbd, 0xd9, 0xec, 0x10, 0xb6, 0xb4, 0xde, 0x21,
+	0x05, 0x06, 0xb2, 0x76, 0x91, 0xf0, 0x11, 0x96, 0x8f, 0x2a, 0x59, 0x4e, 0x62, 0xd5, 0xc6, 0x91,
+	0x68, 0xd9, 0x1b, 0xd8, 0xc3, 0x56, 0xb5, 0x47, 0x6d, 0xa9, 0xd7, 0xa3, 0xe9, 0xa9, 0x9e, 0x96,
+	0x1d, 0xef, 0x09, 0x6e, 0x1c, 0xf9, 0x17, 0x38, 0x01, 0xff, 0x01, 0xc5, 0x89, 0x1b, 0x29, 0xaa,
+	0xa8, 0xe2, 0x48, 0x15, 0x55, 0x40, 0x7c, 0xe7, 0xce, 0x91, 0x7a, 0xfd, 0x31, 0x6a, 0x49, 0x36,
+	0x6c, 0xe0, 0xa4, 0x7e, 0xef, 0xfd, 0xde, 0xeb, 0xee, 0xd7, 0xef, 0x6b, 0x84, 0xfa, 0x63, 0xae,
+	0x26, 0xb3, 0xa3, 0xad, 0x58, 0x4c, 0xb7, 0x4f, 0xa8, 0xa2, 0xef, 0xc7, 0x22, 0x55, 0x94, 0xa7,
+	0x4c, 0xe6, 0x2b, 0x74, 0x2e, 0xe3, 0x6d, 0x3a, 0x66, 0xa9, 0xda, 0xce, 0xa4, 0x50, 0x22, 0x16,
+	0x49, 0x6e, 0x56, 0xf9, 0xb6, 0x88, 0xf9, 0x96, 0x5e, 0xe2, 0xca, 0x58, 0x66, 0xf1, 0x9d, 0xf7,
+	0x3d, 0xb3, 0x63, 0x31, 0x16, 0x06, 0x77, 0x34, 0x3b, 0xd6, 0x94, 0x26, 0xf4, 0xca, 0x28, 0xdd,
+	0x69, 0x8f, 0x85, 0x18, 0x27, 0x6c, 0x8e, 0x3a, 0x93, 0x34, 0xcb, 0x98, 0xcc, 0x8d, 0x3c, 0xfa,
+	0x43, 0x19, 0x55, 0x86, 0x19, 0x8b, 0x71, 0x88, 0xd6, 0x3e, 0x62, 0x32, 0xe7, 0x22, 0x0d, 0x83,
+	0x8d, 0x60, 0xb3, 0x41, 0x1c, 0x89, 0xbf, 0x82, 0xd6, 0x06, 0x52, 0xc4, 0x2c, 0xcf, 0xc3, 0xd2,
+	0x46, 0xb0, 0xd9, 0xbc, 0xdf, 0xda, 0x82, 0x93, 0x6c, 0x59, 0x26, 0x71, 0x52, 0xdc, 0x46, 0x15,
+	0x22, 0x84, 0x0a, 0xcb, 0x1a, 0x85, 0x0c, 0x0a, 0x38, 0x44, 0xf3, 0xf1, 0x1d, 0x54, 0xdf, 0x13,
+	0xb9, 0x4a, 0xe9, 0x94, 0x85, 0x15, 0xbd, 0x47, 0x41, 0xe3, 0xaf, 0xa2, 0xda, 0xbe, 0x98, 0xa5,
+	0x2a, 0x0f, 0xab, 0x1b, 0xe5, 0xcd, 0xe6, 0xfd, 0xa6, 0xd1, 0xd6, 0xbc, 0x9d, 0xca, 0xcb, 0xbf,
+	0xbd, 0xfb, 0x06, 0xb1, 0x00, 0xfc, 0x1e, 0xaa, 0xee, 0x09, 0x71, 0x92, 0x87, 0xb5, 0x8d, 0x60,
+	0x8e, 0xd4, 0x2c, 0x62, 0x24, 0xf8, 0x07, 0xa8, 0xd9, 0x49, 0x53, 0xa1, 0xa8, 0xe2, 0x22, 0xcd,
+	0xc3, 0x35, 0x6d, 0xf2, 0x8b, 0x06, 0x08, 0xb7, 0xdd, 0xf2, 0xa4, 0x8f, 0x52, 0x25, 0xcf, 0x89,
+	0x8f, 0x87, 0x1d, 0x9e, 0xf2, 0x74, 0xf6, 0x22, 0xac, 0xfb, 0x3b, 0x68, 0x16, 0x31, 0x12, 0x70,
+	0xca, 0x50, 0x24, 0x54, 0xf2, 0x3c, 0x6c, 0xf8, 0x4e, 0xb1, 0x4c, 0xe2, 0xa4, 0x00, 0x7c, 0xce,
+	0xd3, 0x91, 0x38, 0xcb, 0x43, 0xe4, 0x03, 0x2d, 0x93, 0x38, 0xe9, 0x9d, 0x1f, 0xa2, 0x1b, 0xcb,
+	0xa7, 0xc2, 0x37, 0x50, 0xf9, 0x84, 0x9d, 0xdb, 0x07, 0x81, 0x25, 0xbe, 0x85, 0xaa, 0xa7, 0x34,
+	0x99, 0x31, 0xfd, 0x14, 0x0d, 0x62, 0x88, 0x0f, 0x4a, 0x0f, 0x83, 0xe8, 0x77, 0xe5, 0xe2, 0x9d,
+	0xc0, 0xd3, 0x07, 0x4c, 0x4e, 0x79, 0x4a, 0x13, 0xad, 0x5c, 0x27, 0x05, 0x8d, 0xbf, 0x8e, 0x9a,
+	0x5d, 0x91, 0xe6, 0x22, 0x61, 0x43, 0xfe, 0x19, 0xb3, 0x4f, 0xda, 0x30, 0x87, 0xda, 0x11, 0x2f,
+	0x88, 0x2f, 0xc5, 0x77, 0x51, 0xe5, 0x30, 0x67, 0x72, 0xf1, 0x49, 0x81, 0x63, 0xdf, 0x44, 0x4b,
+	0x31, 0x46, 0x95, 0x8e, 0x1c, 0xe7, 0x61, 0x65, 0xa3, 0xbc, 0xd9, 0x20, 0x7a, 0x0d, 0x47, 0x7f,
+	0x94, 0x9e, 0xea, 0xd7, 0x6c, 0x10, 0x58, 0x02, 0xa7, 0x7b, 0x36, 0xd2, 0xaf, 0xd6, 0x20, 0xb0,
+	0xc4, 0xdf, 0x43, 0xd7, 0xba, 0x34, 0xa3, 0x47, 0x3c, 0xe1, 0x8a, 0x33, 0x78, 0x27, 0xd8, 0xe5,
+	0x6d, 0xcf, 0xdd, 0xbe, 0x98, 0x2c, 0x80, 0xf1, 0x37, 0xd0, 0x1a, 0x49, 0xf8, 0x94, 0xab, 0x3c,
+	0xac, 0xeb, 0xf7, 0xbd, 0x69, 0xc3, 0xb2, 0x3f, 0xec, 0xfd, 0xd8, 0x48, 0xec, 0x21, 0x1d, 0x0e,
+	0x6f, 0xa2, 0xeb, 0xcf, 0xc4, 0x33, 0x76, 0x36, 0x90, 0xfc, 0x94, 0x27, 0x6c, 0xcc, 0xcc, 0xe3,
+	0xd5, 0xc9, 0x32, 0x1b, 0x90, 0x9d, 0x2c, 0xa3, 0x72, 0x2a, 0xe4, 0x40, 0x8a, 0x63, 0x9e, 0x30,
+	0xfd, 0x7a, 0x0d, 0xb2, 0xcc, 0xc6, 0x1b, 0xa8, 0xd9, 0xef, 0xef, 0x0f, 0x63, 0x21, 0x59, 0x67,
+	0xf4, 0x69, 0xd8, 0xdc, 0x08, 0x36, 0xcb, 0xc4, 0x67, 0xe1, 0x08, 0x5d, 0x1b, 0xb2, 0x04, 0x6e,
+	0xf3, 0x94, 0x1e, 0xb1, 0x24, 0xbc, 0xa6, 0x0d, 0x2d, 0xf0, 0xa2, 0x07, 0xa8, 0xbc, 0x23, 0x5e,
+	0xe0, 0xdb, 0xa8, 0xb6, 0xc7, 0xf8, 0x78, 0xa2, 0xf4, 0xab, 0xb5, 0x88, 0xa5, 0xe0, 0xd5, 0x9f,
+	0xf3, 0x91, 0x9a, 0xe8, 0xd7, 0x6a, 0x11, 0x43, 0x44, 0xa9, 0x79, 0x1c, 0x70, 0xec, 0x61, 0x6f,
+	0xd7, 0xaa, 0xc0, 0x12, 0x38, 0x4f, 0x7a, 0xbb, 0x16, 0x0d, 0x4b, 0xfc, 0x65, 0xb4, 0xde, 0x19,
+	0x8d, 0x38, 0xc4, 0x16, 0x4d, 0x9e, 0xf0, 0x51, 0x1e, 0x96, 0x37, 0xca, 0x9b, 0x2d, 0xb2, 0xc4,
+	0x85, 0xc8, 0x01, 0x9b, 0x7e, 0x8e, 0x3a, 0x3a, 0xfa, 0x55, 0x80, 0x6e, 0xae, 0xbc, 0x0a, 0x68,
+	0xec, 0x88, 0x59, 0x3a, 0xe2, 0xe9, 0x38, 0x0c, 0xf4, 0x6b, 0x17, 0x34, 0x7e, 0x07, 0x35, 0x1e,
+	0x1d, 0x1f, 0xb3, 0x58, 0xf1, 0x53, 0x88, 0x34, 0x10, 0xce, 0x19, 0xe0, 0xba, 0x5e, 0x3a, 0x61,
+	0x92, 0x2b, 0x7a, 0x94, 0x30, 0x7d, 0xa0, 0x06, 0xf1, 0x59, 0xa0, 0x3f, 0x80, 0xb8, 0x55, 0x8a,
+	0x8d, 0x6c, 0x74, 0xcd, 0x19, 0x50, 0xb2, 0x3a, 0xd3, 0x23, 0xce, 0x52, 0x65, 0xc3, 0xcc, 0x91,
+	0x51, 0x0f, 0x35, 0xbd, 0x30, 0x80, 0xf8, 0x3c, 0x38, 0xcf, 0x98, 0xcd, 0x23, 0xbd, 0x06, 0xde,
+	0x1e, 0x95, 0x23, 0xed, 0xa3, 0x0a, 0xd1, 0x6b, 0xe0, 0x0d, 0xc5, 0xb1, 0x29, 0x60, 0x15, 0xa2,
+	0xd7, 0x91, 0x40, 0x55, 0x5d, 0x77, 0xe0, 0xb4, 0x23, 0x96, 0x2b, 0x9e, 0xea, 0x04, 0xb5, 0xb6,
+	0x7c, 0x16, 0xbc, 0x5e, 0x2e, 0x66, 0x32, 0x76, 0xc9, 0x69, 0x29, 0x30, 0xab, 0x60, 0xfb, 0xb2,
+	0xd9, 0x1e, 0xd6, 0x70, 0x76, 0x91, 0x99, 0xea, 0x64, 0xee, 0xe5, 0xc8, 0xe8, 0xdb, 0xa6, 0x8a,
+	0x82, 0xd6, 0x80, 0xaa, 0x89, 0x3b, 0x34, 0xac, 0xc1, 0xd7, 0x84, 0xd1, 0x91, 0x48, 0x93, 0x73,
+	0xbd, 0x47, 0x9d, 0x14, 0x74, 0xf4, 0xa7, 0xc0, 0xd6, 0x45, 0x7c, 0x0f, 0xd5, 0x07, 0x92, 0xe5,
+	0x8a, 0x4a, 0xa5, 0x5f, 0xa4, 0x48, 0x5c, 0x10, 0xdb, 0x9c, 0x28, 0x10, 0x78, 0x0b, 0x35, 0x06,
+	0x22, 0x57, 0x06, 0x5e, 0xba, 0x02, 0x3e, 0x87, 0x68, 0xeb, 0x9a, 0x10, 0x59, 0x58, 0xbe, 0x02,
+	0x5e, 0x20, 0xf0, 0x43, 0xb4, 0x3e, 0x04, 0xb5, 0xae, 0x6b, 0x76, 0x61, 0xe5, 0x0a, 0x9d, 0x25,
+	0x5c, 0xf4, 0x31, 0xaa, 0x80, 0xf4, 0x52, 0x3f, 0xb8, 0x82, 0x53, 0x5a, 0x2d, 0x38, 0xe5, 0x79,
+	0xc1, 0x09, 0xd1, 0xda, 0x01, 0x9f, 0x32, 0x31, 0x53, 0x3a, 0x94, 0xcb, 0xc4, 0x91, 0xd1, 0x6f,
+	0xaa, 0xb6, 0xc2, 0xe3, 0xef, 0xa3, 0xe6, 0x61, 0x6f, 0x77, 0x9f, 0x66, 0x19, 0x4f, 0xc7, 0xb9,
+	0x75, 0xd7, 0x2d, 0xaf, 0x02, 0x15, 0x42, 0x7b, 0x4c, 0x1f, 0x0e, 0xda, 0x4f, 0x3c, 0xed, 0xd2,
+	0x7f, 0xd7, 0xf6, 0xe0, 0x78, 0x1b, 0xd5, 0x86, 0xe7, 0x79, 0xac, 0x12, 0xeb, 0x47, 0xbf, 0xf0,
+	0x6d, 0x19, 0x89, 0x69, 0x4e, 0x16, 0x86, 0xef, 0xa3, 0x06, 0x61, 0x26, 0xa8, 0x72, 0x7d, 0xa5,
+	0xc5, 0xcd, 0x0a, 0x19, 0x99, 0xc3, 0x20, 0x6c, 0xbb, 0x63, 0x29, 0x66, 0x59, 0xae, 0xbd, 0x58,
+	0x35, 0x61, 0xeb, 0xb1, 0xf0, 0x07, 0x08, 0x3d, 0xa3, 0x53, 0x96, 0x67, 0x14, 0xcc, 0xd6, 0x56,
+	0xee, 0x50, 0x08, 0xed, 0x1d, 0x3c, 0x34, 0x14, 0xe1, 0x5d, 0x76, 0xca, 0x63, 0xe6, 0x9a, 0xec,
+	0x4d, 0x4f, 0xd1, 0x48, 0x5c, 0x11, 0xb6, 0x38, 0x7c, 0x0f, 0xad, 0x0d, 0x59, 0x1c, 0x8b, 0x69,
+	0x66, 0xdb, 0x2b, 0xf6, 0x54, 0xac, 0x84, 0x38, 0x08, 0xbe, 0x87, 0x6e, 0x42, 0x36, 0x1c, 0xe7,
+	0x03, 0x29, 0x32, 0x3a, 0x36, 0xb9, 0xd7, 0xd0, 0x97, 0x58, 0x15, 0xc0, 0x65, 0xf7, 0x69, 0x7e,
+	0xc2, 0x46, 0x70, 0x31, 0x68, 0xb8, 0xba, 0xa2, 0x78, 0x2c, 0x7c, 0x17, 0xb5, 0x5c, 0xc6, 0x18,
+	0x4c, 0x53, 0x63, 0x16, 0x99, 0xb8, 0x8d, 0x90, 0x4e, 0x7a, 0xbf, 0x60, 0x7b, 0x1c, 0xbc, 0x8d,
+	0xea, 0xbd, 0x54, 0xb1, 0x84, 0x8c, 0x54, 0xd8, 0xd2, 0x97, 0x78, 0xd3, 0x7f, 0x74, 0x2b, 0x22,
+	0x05, 0xe8, 0xce, 0x77, 0x51, 0xd3, 0x7b, 0xd0, 0xd7, 0xea, 0xeb, 0xef, 0x16, 0x03, 0x04, 0x80,
+	0x46, 0xb3, 0xe9, 0xd4, 0x29, 0x1a, 0x02, 0x00, 0x6e, 0xd8, 0xb8, 0x1c, 0xf0, 0x09, 0x5a, 0x5f,
+	0x0c, 0x46, 0xdd, 0x67, 0x44, 0xae, 0x8a, 0xa6, 0x61, 0x29, 0x1d, 0x2c, 0x2e, 0x01, 0x8b, 0xfe,
+	0xe1, 0xb3, 0x74, 0x89, 0xe4, 0x9f, 0x99, 0x5a, 0xd6, 0x22, 0x7a, 0x1d, 0x3d, 0xb4, 0xf6, 0x8b,
+	0xb8, 0xb8, 0xaa, 0xe0, 0xea, 0x08, 0x2c, 0xcd, 0xf3, 0x38, 0xfa, 0x65, 0x80, 0x9a, 0x5e, 0xa8,
+	0x5c, 0x95, 0xeb, 0xda, 0x56, 0xc9, 0xb3, 0x75, 0x0b, 0x55, 0xf7, 0xe9, 0xa7, 0xc2, 0xcc, 0x25,
+	0x65, 0x62, 0x08, 0xcd, 0xe5, 0xa9, 0x90, 0x36, 0xdb, 0x0d, 0x01, 0x35, 0xf3, 0x31, 0x4f, 0xd8,
+	0xbe, 0x18, 0x31, 0x1d, 0xfd, 0x2d, 0x52, 0xd0, 0xae, 0x73, 0xd6, 0x56, 0x3a, 0xe7, 0x5a, 0xd1,
+	0x39, 0xa3, 0xbf, 0x97, 0xec, 0xf5, 0xe6, 0x39, 0xf5, 0x9d, 0x79, 0xd4, 0x07, 0x2b, 0x99, 0x6b,
+	0x24, 0x26, 0xc1, 0x96, 0x63, 0x1f, 0xa6, 0x5c, 0x36, 0x15, 0xf2, 0xdc, 0x8e, 0x5d, 0x7e, 0xb6,
+	0x18, 0x01, 0xb1, 0x00, 0xbc, 0x81, 0xca, 0xdd, 0xc1, 0xa1, 0x1d, 0xbc, 0xd6, 0xfd, 0x91, 0x68,
+	0x70, 0x48, 0x40, 0x84, 0xbf, 0x84, 0x2a, 0x03, 0x68, 0xe4, 0xa6, 0x10, 0x5c, 0xf7, 0x20, 0xc0,
+	0x26, 0x5a, 0x08, 0xd9, 0xb6, 0x93, 0x88, 0xf8, 0xa4, 0xd7, 0x0f, 0xab, 0x2b, 0xd9, 0x66, 0x25,
+	0xc4, 0x41, 0xf0, 0x63, 0xb4, 0xbe, 0x37, 0x1b, 0xb3, 0x8c, 0x8e, 0xd9, 0x53, 0x33, 0x5a, 0x99,
+	0x72, 0x10, 0x7a, 0x4a, 0x0b, 0x00, 0x57, 0xbb, 0x17, 0xb5, 0x60, 0xd7, 0x67, 0x4c, 0x9d, 0x09,
+	0x79, 0x12, 0xae, 0xad, 0xec, 0x6a, 0x25, 0xc4, 0x41, 0xa2, 0xbf, 0xba, 0x28, 0xb0, 0x57, 0xbf,
+	0x05, 0xc5, 0x79, 0xca, 0xcd, 0x10, 0x54, 0x26, 0x86, 0x80, 0xd8, 0x24, 0x2c, 0x67, 0xf2, 0xd4,
+	0xd4, 0x80, 0x92, 0x96, 0xf9, 0x2c, 0x1d, 0x9b, 0x67, 0x34, 0xb3, 0x41, 0xa1, 0xd7, 0x10, 0xe9,
+	0x1f, 0x32, 0x99, 0xb2, 0xc4, 0x06, 0x85, 0xa5, 0x60, 0xb2, 0x30, 0xab, 0x83, 0xee, 0x40, 0x7b,
+	0xa6, 0x4c, 0xe6, 0x0c, 0xc8, 0x7f, 0xd0, 0xce, 0x78, 0x0a, 0x5f, 0x3d, 0x35, 0x3d, 0x0e, 0x78,
+	0x1c, 0xfc, 0x35, 0x74, 0x63, 0x97, 0xe7, 0x30, 0xa2, 0xf4, 0xfb, 0xfb, 0x1f, 0xf2, 0x24, 0x61,
+	0x52, 0x5f, 0xb4, 0x4e, 0x56, 0xf8, 0xd1, 0x1f, 0x03, 0x54, 0x77, 0x0f, 0x07, 0xc7, 0x19, 0x4e,
+	0xa8, 0xd4, 0x81, 0x03, 0x46, 0x2d, 0x05, 0x57, 0xfe, 0xd1, 0x4c, 0x28, 0x6a, 0xaf, 0x65, 0x08,
+	0x40, 0x0f, 0x98, 0xe4, 0x62, 0x64, 0x27, 0x12, 0x4b, 0xc1, 0x74, 0x4a, 0x18, 0x4d, 0x14, 0x9f,
+	0x32, 0x32, 0x4b, 0xe1, 0xc7, 0xde, 0x6e, 0x99, 0x0d, 0x63, 0x9f, 0x63, 0x59, 0x4b, 0x55, 0x6d,
+	0x69, 0x89, 0x0b, 0xae, 0xeb, 0x66, 0xb3, 0xdc, 0x0e, 0xe7, 0x7a, 0x0d, 0xbc, 0x7d, 0x36, 0x35,
+	0x53, 0x79, 0x83, 0xe8, 0x75, 0x74, 0x66, 0x27, 0xc0, 0xe7, 0x7a, 0x2e, 0xb5, 0x59, 0x5b, 0x64,
+	0x63, 0x70, 0x69, 0x36, 0x96, 0xfc, 0x6c, 0xbc, 0x8d, 0x6a, 0x46, 0xd7, 0x56, 0x10, 0x4b, 0x81,
+	0xc7, 0x9f, 0x32, 0x7a, 0x6c, 0x65, 0x15, 0x2d, 0xf3, 0x38, 0xd1, 0x21, 0x7a, 0x53, 0x6f, 0x7c,
+	0x30, 0x91, 0x42, 0xa9, 0x84, 0xfd, 0x0f, 0x5b, 0x63, 0x54, 0x21, 0x54, 0x31, 0x37, 0xdd, 0xc1,
+	0x3a, 0xfa, 0x67, 0x19, 0x5d, 0xf3, 0x53, 0xc1, 0x3b, 0x5f, 0xf0, 0x1f, 0xce, 0x57, 0x5a, 0x3e,
+	0x1f, 0xee, 0xa0, 0x6b, 0xbe, 0x4f, 0x2e, 0xe9, 0xe8, 0xbe, 0xd8, 0xa6, 0xcd, 0x82, 0x0a, 0x3e,
+	0x44, 0x6f, 0xb9, 0xdb, 0x41, 0x37, 0xda, 0xc9, 0x72, 0x6b, 0xcb, 0x4c, 0x4c, 0x5f, 0xf0, 0x6c,
+	0x2d, 0x7a, 0xc1, 0x5a, 0xbb, 0x5c, 0x1b, 0x3f, 0x47, 0xb7, 0x9d, 0xe0, 0xb9, 0xe4, 0x8a, 0xcd,
+	0xed, 0x56, 0x3f, 0x9f, 0xdd, 0x2b, 0xd4, 0x7d, 0xc3, 0xb0, 0x63, 0xaf, 0x3f, 0x18, 0x5a, 0xc3,
+	0xb5, 0xd7, 0x34, 0xbc, 0xa8, 0x8e, 0x7f, 0x82, 0xde, 0x5e, 0xd8, 0xd2, 0xb3, 0xbc, 0xf6, 0xf9,
+	0x2c, 0x5f, 0xa5, 0x1f, 0xbd, 0x87, 0x1a, 0x45, 0x85, 0xbc, 0xbc, 0xce, 0x44, 0x3f, 0x73, 0x5f,
+	0x39, 0x7e, 0x21, 0x07, 0x6c, 0x27, 0x49, 0xc4, 0x99, 0xfd, 0x9c, 0x36, 0xc4, 0xff, 0xdd, 0x9b,
+	0x6e, 0xa3, 0x5a, 0x27, 0xd6, 0xff, 0xac, 0x98, 0xb9, 0xcc, 0x52, 0x51, 0x62, 0xa3, 0xd2, 0x56,
+	0x48, 0x98, 0x64, 0xbb, 0x09, 0xcd, 0xf3, 0xa2, 0x61, 0x3b, 0x12, 0xef, 0x20, 0x34, 0x90, 0x5c,
+	0x48, 0xf3, 0x01, 0x6d, 0x06, 0xd0, 0x77, 0x96, 0x66, 0x11, 0x79, 0x4c, 0x63, 0x66, 0x51, 0xe7,
+	0x6e, 0x88, 0x9b, 0x6b, 0x45, 0x8f, 0x11, 0x5e, 0xad, 0xec, 0xd0, 0x37, 0x07, 0x74, 0xcc, 0x72,
+	0xe8, 0xf6, 0xa6, 0x1f, 0x17, 0xf4, 0xdc, 0x73, 0xe6, 0xeb, 0xc9, 0x7a, 0x6e, 0x0f, 0xdd, 0xbe,
+	0x7c, 0x4f, 0xf0, 0x13, 0x0c, 0x07, 0xae, 0xaf, 0xc3, 0x5a, 0xdb, 0xb7, 0x72, 0x9b, 0x4f, 0x05,
+	0x1d, 0xfd, 0x22, 0xb0, 0x0e, 0x70, 0x63, 0xe0, 0x5d, 0xd4, 0xda, 0x65, 0xc7, 0x74, 0x96, 0xa8,
+	0x4e, 0xec, 0x7d, 0x7e, 0x2d, 0x32, 0x01, 0xd5, 0x91, 0xf1, 0x84, 0x2b, 0x16, 0xab, 0x99, 0x64,
+	0xee, 0xfb, 0x60, 0x91, 0x89, 0xbf, 0x89, 0xea, 0x30, 0x8b, 0xd1, 0x24, 0xc9, 0x6d, 0x9a, 0x2e,
+	0x4c, 0xa0, 0x46, 0xe4, 0x3e, 0x64, 0x1c, 0x32, 0xe2, 0xe8, 0xba, 0x7f, 0xa2, 0x8e, 0x1c, 0x83,
+	0x17, 0x7a, 0xe9, 0x88, 0xbd, 0xb0, 0xb5, 0xdc, 0x10, 0xc0, 0xfd, 0xa8, 0x98, 0xe4, 0x2a, 0xc4,
+	0x10, 0x70, 0x5b, 0xbd, 0x38, 0x38, 0x13, 0xb6, 0x00, 0x15, 0x34, 0x5e, 0x47, 0xa5, 0x7e, 0x66,
+	0xbf, 0xb6, 0x4b, 0xfd, 0x2c, 0x9a, 0xba, 0xcb, 0x9b, 0xbd, 0xc1, 0xa2, 0x1e, 0xad, 0xec, 0xe7,
+	0xb5, 0x21, 0x4c, 0xec, 0x14, 0xad, 0xb0, 0x41, 0x2c, 0x85, 0xb7, 0xed, 0xb7, 0x91, 0xb9, 0xda,
+	0x5b, 0xab, 0xc3, 0x75, 0x47, 0xba, 0xaf, 0x11, 0x0d, 0x8c, 0xbe, 0x85, 0x5a, 0x0b, 0x63, 0x2b,
+	0xb8, 0xf1, 0xe9, 0x83, 0x2e, 0x8d, 0x27, 0x6c, 0x18, 0x4f, 0xd8, 0x94, 0x3a, 0x67, 0x2f, 0x30,
+	0x77, 0x7e, 0x1e, 0xbc, 0x7c, 0xd5, 0x7e, 0xe3, 0x2f, 0xaf, 0xda, 0x6f, 0xfc, 0xeb, 0x55, 0x3b,
+	0xf8, 0xe9, 0x45, 0x3b, 0xf8, 0xf5, 0x45, 0x3b, 0xf8, 0xed, 0x45, 0x3b, 0xf8, 0xfd, 0x45, 0x3b,
+	0x78, 0x79, 0xd1, 0x0e, 0xfe, 0x7c, 0xd1, 0x0e, 0xfe, 0x71, 0xd1, 0x0e, 0x3e, 0xfe, 0xe4, 0x35,
+	0xff, 0x0a, 0x95, 0xa6, 0x7b, 0x6d, 0x9f, 0x72, 0xa9, 0x3c, 0x51, 0x76, 0x32, 0x5e, 0xf9, 0x97,
+	0x14, 0x6e, 0x77, 0x54, 0xd3, 0xf4, 0x83, 0x7f, 0x0f, 0x00, 0x90, 0x9b, 0x75, 0x43, 0x73, 0x15,
+	0x00, 0x00,
 }
 
 func (this *Spec) Equal(that interface{}) bool {
@@ -2065,6 +2069,14 @@ func (this *Hooks) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.StartContainer) != len(that1.StartContainer) {
+		return false
+	}
+	for i := range this.StartContainer {
+		if !this.StartContainer[i].Equal(&that1.StartContainer[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -3595,6 +3607,20 @@ func (m *Hooks) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.StartContainer) > 0 {
+		for iNdEx := len(m.StartContainer) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.StartContainer[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOci(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Poststop) > 0 {
 		for iNdEx := len(m.Poststop) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -5168,8 +5194,16 @@ func NewPopulatedHooks(r randyOci, easy bool) *Hooks {
 			this.Poststop[i] = *v21
 		}
 	}
+	if r.Intn(5) != 0 {
+		v22 := r.Intn(5)
+		this.StartContainer = make([]Hook, v22)
+		for i := 0; i < v22; i++ {
+			v23 := NewPopulatedHook(r, easy)
+			this.StartContainer[i] = *v23
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedOci(r, 4)
+		this.XXX_unrecognized = randUnrecognizedOci(r, 5)
 	}
 	return this
 }
@@ -5177,14 +5211,14 @@ func NewPopulatedHooks(r randyOci, easy bool) *Hooks {
 func NewPopulatedHook(r randyOci, easy bool) *Hook {
 	this := &Hook{}
 	this.Path = string(randStringOci(r))
-	v22 := r.Intn(10)
-	this.Args = make([]string, v22)
-	for i := 0; i < v22; i++ {
+	v24 := r.Intn(10)
+	this.Args = make([]string, v24)
+	for i := 0; i < v24; i++ {
 		this.Args[i] = string(randStringOci(r))
 	}
-	v23 := r.Intn(10)
-	this.Env = make([]string, v23)
-	for i := 0; i < v23; i++ {
+	v25 := r.Intn(10)
+	this.Env = make([]string, v25)
+	for i := 0; i < v25; i++ {
 		this.Env[i] = string(randStringOci(r))
 	}
 	this.Timeout = int64(r.Int63())
@@ -5199,26 +5233,26 @@ func NewPopulatedHook(r randyOci, easy bool) *Hook {
 
 func NewPopulatedLinux(r randyOci, easy bool) *Linux {
 	this := &Linux{}
-	if r.Intn(5) != 0 {
-		v24 := r.Intn(5)
-		this.UIDMappings = make([]LinuxIDMapping, v24)
-		for i := 0; i < v24; i++ {
-			v25 := NewPopulatedLinuxIDMapping(r, easy)
-			this.UIDMappings[i] = *v25
-		}
-	}
 	if r.Intn(5) != 0 {
 		v26 := r.Intn(5)
-		this.GIDMappings = make([]LinuxIDMapping, v26)
+		this.UIDMappings = make([]LinuxIDMapping, v26)
 		for i := 0; i < v26; i++ {
 			v27 := NewPopulatedLinuxIDMapping(r, easy)
-			this.GIDMappings[i] = *v27
+			this.UIDMappings[i] = *v27
 		}
 	}
 	if r.Intn(5) != 0 {
-		v28 := r.Intn(10)
-		this.Sysctl = make(map[string]string)
+		v28 := r.Intn(5)
+		this.GIDMappings = make([]LinuxIDMapping, v28)
 		for i := 0; i < v28; i++ {
+			v29 := NewPopulatedLinuxIDMapping(r, easy)
+			this.GIDMappings[i] = *v29
+		}
+	}
+	if r.Intn(5) != 0 {
+		v30 := r.Intn(10)
+		this.Sysctl = make(map[string]string)
+		for i := 0; i < v30; i++ {
 			this.Sysctl[randStringOci(r)] = randStringOci(r)
 		}
 	}
@@ -5227,33 +5261,33 @@ func NewPopulatedLinux(r randyOci, easy bool) *Linux {
 	}
 	this.CgroupsPath = string(randStringOci(r))
 	if r.Intn(5) != 0 {
-		v29 := r.Intn(5)
-		this.Namespaces = make([]LinuxNamespace, v29)
-		for i := 0; i < v29; i++ {
-			v30 := NewPopulatedLinuxNamespace(r, easy)
-			this.Namespaces[i] = *v30
+		v31 := r.Intn(5)
+		this.Namespaces = make([]LinuxNamespace, v31)
+		for i := 0; i < v31; i++ {
+			v32 := NewPopulatedLinuxNamespace(r, easy)
+			this.Namespaces[i] = *v32
 		}
 	}
 	if r.Intn(5) != 0 {
-		v31 := r.Intn(5)
-		this.Devices = make([]LinuxDevice, v31)
-		for i := 0; i < v31; i++ {
-			v32 := NewPopulatedLinuxDevice(r, easy)
-			this.Devices[i] = *v32
+		v33 := r.Intn(5)
+		this.Devices = make([]LinuxDevice, v33)
+		for i := 0; i < v33; i++ {
+			v34 := NewPopulatedLinuxDevice(r, easy)
+			this.Devices[i] = *v34
 		}
 	}
 	if r.Intn(5) != 0 {
 		this.Seccomp = NewPopulatedLinuxSeccomp(r, easy)
 	}
 	this.RootfsPropagation = string(randStringOci(r))
-	v33 := r.Intn(10)
-	this.MaskedPaths = make([]string, v33)
-	for i := 0; i < v33; i++ {
+	v35 := r.Intn(10)
+	this.MaskedPaths = make([]string, v35)
+	for i := 0; i < v35; i++ {
 		this.MaskedPaths[i] = string(randStringOci(r))
 	}
-	v34 := r.Intn(10)
-	this.ReadonlyPaths = make([]string, v34)
-	for i := 0; i < v34; i++ {
+	v36 := r.Intn(10)
+	this.ReadonlyPaths = make([]string, v36)
+	for i := 0; i < v36; i++ {
 		this.ReadonlyPaths[i] = string(randStringOci(r))
 	}
 	this.MountLabel = string(randStringOci(r))
@@ -5329,11 +5363,11 @@ func NewPopulatedLinuxDevice(r randyOci, easy bool) *LinuxDevice {
 func NewPopulatedLinuxResources(r randyOci, easy bool) *LinuxResources {
 	this := &LinuxResources{}
 	if r.Intn(5) != 0 {
-		v35 := r.Intn(5)
-		this.Devices = make([]LinuxDeviceCgroup, v35)
-		for i := 0; i < v35; i++ {
-			v36 := NewPopulatedLinuxDeviceCgroup(r, easy)
-			this.Devices[i] = *v36
+		v37 := r.Intn(5)
+		this.Devices = make([]LinuxDeviceCgroup, v37)
+		for i := 0; i < v37; i++ {
+			v38 := NewPopulatedLinuxDeviceCgroup(r, easy)
+			this.Devices[i] = *v38
 		}
 	}
 	if r.Intn(5) != 0 {
@@ -5349,11 +5383,11 @@ func NewPopulatedLinuxResources(r randyOci, easy bool) *LinuxResources {
 		this.BlockIO = NewPopulatedLinuxBlockIO(r, easy)
 	}
 	if r.Intn(5) != 0 {
-		v37 := r.Intn(5)
-		this.HugepageLimits = make([]LinuxHugepageLimit, v37)
-		for i := 0; i < v37; i++ {
-			v38 := NewPopulatedLinuxHugepageLimit(r, easy)
-			this.HugepageLimits[i] = *v38
+		v39 := r.Intn(5)
+		this.HugepageLimits = make([]LinuxHugepageLimit, v39)
+		for i := 0; i < v39; i++ {
+			v40 := NewPopulatedLinuxHugepageLimit(r, easy)
+			this.HugepageLimits[i] = *v40
 		}
 	}
 	if r.Intn(5) != 0 {
@@ -5455,44 +5489,44 @@ func NewPopulatedLinuxBlockIO(r randyOci, easy bool) *LinuxBlockIO {
 	this := &LinuxBlockIO{}
 	this.Weight = uint32(r.Uint32())
 	this.LeafWeight = uint32(r.Uint32())
-	if r.Intn(5) != 0 {
-		v39 := r.Intn(5)
-		this.WeightDevice = make([]LinuxWeightDevice, v39)
-		for i := 0; i < v39; i++ {
-			v40 := NewPopulatedLinuxWeightDevice(r, easy)
-			this.WeightDevice[i] = *v40
-		}
-	}
 	if r.Intn(5) != 0 {
 		v41 := r.Intn(5)
-		this.ThrottleReadBpsDevice = make([]LinuxThrottleDevice, v41)
+		this.WeightDevice = make([]LinuxWeightDevice, v41)
 		for i := 0; i < v41; i++ {
-			v42 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleReadBpsDevice[i] = *v42
+			v42 := NewPopulatedLinuxWeightDevice(r, easy)
+			this.WeightDevice[i] = *v42
 		}
 	}
 	if r.Intn(5) != 0 {
 		v43 := r.Intn(5)
-		this.ThrottleWriteBpsDevice = make([]LinuxThrottleDevice, v43)
+		this.ThrottleReadBpsDevice = make([]LinuxThrottleDevice, v43)
 		for i := 0; i < v43; i++ {
 			v44 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleWriteBpsDevice[i] = *v44
+			this.ThrottleReadBpsDevice[i] = *v44
 		}
 	}
 	if r.Intn(5) != 0 {
 		v45 := r.Intn(5)
-		this.ThrottleReadIOPSDevice = make([]LinuxThrottleDevice, v45)
+		this.ThrottleWriteBpsDevice = make([]LinuxThrottleDevice, v45)
 		for i := 0; i < v45; i++ {
 			v46 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleReadIOPSDevice[i] = *v46
+			this.ThrottleWriteBpsDevice[i] = *v46
 		}
 	}
 	if r.Intn(5) != 0 {
 		v47 := r.Intn(5)
-		this.ThrottleWriteIOPSDevice = make([]LinuxThrottleDevice, v47)
+		this.ThrottleReadIOPSDevice = make([]LinuxThrottleDevice, v47)
 		for i := 0; i < v47; i++ {
 			v48 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleWriteIOPSDevice[i] = *v48
+			this.ThrottleReadIOPSDevice[i] = *v48
+		}
+	}
+	if r.Intn(5) != 0 {
+		v49 := r.Intn(5)
+		this.ThrottleWriteIOPSDevice = make([]LinuxThrottleDevice, v49)
+		for i := 0; i < v49; i++ {
+			v50 := NewPopulatedLinuxThrottleDevice(r, easy)
+			this.ThrottleWriteIOPSDevice[i] = *v50
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5536,11 +5570,11 @@ func NewPopulatedLinuxNetwork(r randyOci, easy bool) *LinuxNetwork {
 	this := &LinuxNetwork{}
 	this.ClassID = uint32(r.Uint32())
 	if r.Intn(5) != 0 {
-		v49 := r.Intn(5)
-		this.Priorities = make([]LinuxInterfacePriority, v49)
-		for i := 0; i < v49; i++ {
-			v50 := NewPopulatedLinuxInterfacePriority(r, easy)
-			this.Priorities[i] = *v50
+		v51 := r.Intn(5)
+		this.Priorities = make([]LinuxInterfacePriority, v51)
+		for i := 0; i < v51; i++ {
+			v52 := NewPopulatedLinuxInterfacePriority(r, easy)
+			this.Priorities[i] = *v52
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5572,17 +5606,17 @@ func NewPopulatedLinuxInterfacePriority(r randyOci, easy bool) *LinuxInterfacePr
 func NewPopulatedLinuxSeccomp(r randyOci, easy bool) *LinuxSeccomp {
 	this := &LinuxSeccomp{}
 	this.DefaultAction = string(randStringOci(r))
-	v51 := r.Intn(10)
-	this.Architectures = make([]string, v51)
-	for i := 0; i < v51; i++ {
+	v53 := r.Intn(10)
+	this.Architectures = make([]string, v53)
+	for i := 0; i < v53; i++ {
 		this.Architectures[i] = string(randStringOci(r))
 	}
 	if r.Intn(5) != 0 {
-		v52 := r.Intn(5)
-		this.Syscalls = make([]LinuxSyscall, v52)
-		for i := 0; i < v52; i++ {
-			v53 := NewPopulatedLinuxSyscall(r, easy)
-			this.Syscalls[i] = *v53
+		v54 := r.Intn(5)
+		this.Syscalls = make([]LinuxSyscall, v54)
+		for i := 0; i < v54; i++ {
+			v55 := NewPopulatedLinuxSyscall(r, easy)
+			this.Syscalls[i] = *v55
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5605,18 +5639,18 @@ func NewPopulatedLinuxSeccompArg(r randyOci, easy bool) *LinuxSeccompArg {
 
 func NewPopulatedLinuxSyscall(r randyOci, easy bool) *LinuxSyscall {
 	this := &LinuxSyscall{}
-	v54 := r.Intn(10)
-	this.Names = make([]string, v54)
-	for i := 0; i < v54; i++ {
+	v56 := r.Intn(10)
+	this.Names = make([]string, v56)
+	for i := 0; i < v56; i++ {
 		this.Names[i] = string(randStringOci(r))
 	}
 	this.Action = string(randStringOci(r))
 	if r.Intn(5) != 0 {
-		v55 := r.Intn(5)
-		this.Args = make([]LinuxSeccompArg, v55)
-		for i := 0; i < v55; i++ {
-			v56 := NewPopulatedLinuxSeccompArg(r, easy)
-			this.Args[i] = *v56
+		v57 := r.Intn(5)
+		this.Args = make([]LinuxSeccompArg, v57)
+		for i := 0; i < v57; i++ {
+			v58 := NewPopulatedLinuxSeccompArg(r, easy)
+			this.Args[i] = *v58
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5653,9 +5687,9 @@ func randUTF8RuneOci(r randyOci) rune {
 	return rune(ru + 61)
 }
 func randStringOci(r randyOci) string {
-	v57 := r.Intn(100)
-	tmps := make([]rune, v57)
-	for i := 0; i < v57; i++ {
+	v59 := r.Intn(100)
+	tmps := make([]rune, v59)
+	for i := 0; i < v59; i++ {
 		tmps[i] = randUTF8RuneOci(r)
 	}
 	return string(tmps)
@@ -5677,11 +5711,11 @@ func randFieldOci(dAtA []byte, r randyOci, fieldNumber int, wire int) []byte {
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateOci(dAtA, uint64(key))
-		v58 := r.Int63()
+		v60 := r.Int63()
 		if r.Intn(2) == 0 {
-			v58 *= -1
+			v60 *= -1
 		}
-		dAtA = encodeVarintPopulateOci(dAtA, uint64(v58))
+		dAtA = encodeVarintPopulateOci(dAtA, uint64(v60))
 	case 1:
 		dAtA = encodeVarintPopulateOci(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -6009,6 +6043,12 @@ func (m *Hooks) Size() (n int) {
 			n += 1 + l + sovOci(uint64(l))
 		}
 	}
+	if len(m.StartContainer) > 0 {
+		for _, e := range m.StartContainer {
+			l = e.Size()
+			n += 1 + l + sovOci(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -6806,10 +6846,16 @@ func (this *Hooks) String() string {
 		repeatedStringForPoststop += strings.Replace(strings.Replace(f.String(), "Hook", "Hook", 1), `&`, ``, 1) + ","
 	}
 	repeatedStringForPoststop += "}"
+	repeatedStringForStartContainer := "[]Hook{"
+	for _, f := range this.StartContainer {
+		repeatedStringForStartContainer += strings.Replace(strings.Replace(f.String(), "Hook", "Hook", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForStartContainer += "}"
 	s := strings.Join([]string{`&Hooks{`,
 		`Prestart:` + repeatedStringForPrestart + `,`,
 		`Poststart:` + repeatedStringForPoststart + `,`,
 		`Poststop:` + repeatedStringForPoststop + `,`,
+		`StartContainer:` + repeatedStringForStartContainer + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -9168,6 +9214,40 @@ func (m *Hooks) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartContainer", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOci
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOci
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOci
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StartContainer = append(m.StartContainer, Hook{})
+			if err := m.StartContainer[len(m.StartContainer)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOci(dAtA[iNdEx:])
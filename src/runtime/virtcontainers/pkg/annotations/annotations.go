@@ -23,6 +23,18 @@ const (
 	SandboxConfigPathKey = kataAnnotationsPrefix + "config_path"
 )
 
+// Annotations passed to the OCI hooks through the container state
+const (
+	// HookNetNSPathKey is the annotation key to fetch the network namespace path of the sandbox.
+	HookNetNSPathKey = kataAnnotationsPrefix + "hook.netns_path"
+
+	// HookGuestCIDKey is the annotation key to fetch the vsock context ID of the sandbox VM.
+	HookGuestCIDKey = kataAnnotationsPrefix + "hook.guest_cid"
+
+	// HookSharedDirKey is the annotation key to fetch the host directory shared with the sandbox VM.
+	HookSharedDirKey = kataAnnotationsPrefix + "hook.shared_dir"
+)
+
 // Annotations related to Hypervisor configuration
 const (
 	//
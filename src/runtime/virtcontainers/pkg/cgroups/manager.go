@@ -331,3 +331,41 @@ func (m *Manager) RemoveDevice(device string) error {
 	m.Unlock()
 	return fmt.Errorf("device %v not found in the cgroup", device)
 }
+
+// SetBlkioThrottle sets the I/O limits of a block device, in bytes and
+// operations per second. Zero removes the limit.
+func (m *Manager) SetBlkioThrottle(device string, readBps, writeBps, readIOPS, writeIOPS uint64) error {
+	cgroups, err := m.GetCgroups()
+	if err != nil {
+		return err
+	}
+
+	ld, err := DeviceToCgroupDevice(device)
+	if err != nil {
+		return err
+	}
+
+	if ld.Type != 'b' {
+		return fmt.Errorf("%v is not a block device", device)
+	}
+
+	set := func(throttles []*configs.ThrottleDevice, rate uint64) []*configs.ThrottleDevice {
+		var res []*configs.ThrottleDevice
+		for _, t := range throttles {
+			if t.Major != ld.Major || t.Minor != ld.Minor {
+				res = append(res, t)
+			}
+		}
+		// A zero rate is written to remove the limit set before.
+		return append(res, configs.NewThrottleDevice(ld.Major, ld.Minor, rate))
+	}
+
+	m.Lock()
+	cgroups.BlkioThrottleReadBpsDevice = set(cgroups.BlkioThrottleReadBpsDevice, readBps)
+	cgroups.BlkioThrottleWriteBpsDevice = set(cgroups.BlkioThrottleWriteBpsDevice, writeBps)
+	cgroups.BlkioThrottleReadIOPSDevice = set(cgroups.BlkioThrottleReadIOPSDevice, readIOPS)
+	cgroups.BlkioThrottleWriteIOPSDevice = set(cgroups.BlkioThrottleWriteIOPSDevice, writeIOPS)
+	m.Unlock()
+
+	return m.Apply()
+}
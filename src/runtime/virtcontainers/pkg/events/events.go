@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package events defines the sandbox lifecycle events emitted by the
+// runtime, and the sinks they are sent to.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/sirupsen/logrus"
+
+	eventsapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/events"
+)
+
+var eventsLog = logrus.WithFields(logrus.Fields{
+	"source":    "virtcontainers",
+	"subsystem": "events",
+})
+
+// The events, defined by the events protocol.
+type (
+	SandboxBooted  = eventsapi.SandboxBooted
+	SandboxResized = eventsapi.SandboxResized
+	DeviceHotplug  = eventsapi.DeviceHotplug
+	AgentConnected = eventsapi.AgentConnected
+	MonitorError   = eventsapi.MonitorError
+)
+
+// Topics of the kata events, published under the containerd namespace of
+// the sandbox.
+const (
+	SandboxBootedTopic  = eventsapi.SandboxBootedTopic
+	SandboxResizedTopic = eventsapi.SandboxResizedTopic
+	DeviceHotplugTopic  = eventsapi.DeviceHotplugTopic
+	AgentConnectedTopic = eventsapi.AgentConnectedTopic
+	MonitorErrorTopic   = eventsapi.MonitorErrorTopic
+)
+
+// Hotplug actions of the DeviceHotplug event.
+const (
+	HotplugAdd    = eventsapi.HotplugAdd
+	HotplugRemove = eventsapi.HotplugRemove
+)
+
+// Event is a sandbox lifecycle event.
+type Event interface {
+	proto.Message
+
+	// Topic returns the topic the event is published on.
+	Topic() string
+}
+
+// Sink receives the events emitted by a sandbox. Send must not block.
+type Sink interface {
+	Send(e Event)
+}
+
+type contextKey struct{}
+
+var sinkContextKey = contextKey{}
+
+// WithSink returns a context carrying the sink the events of the sandboxes
+// created with this context are sent to.
+func WithSink(ctx context.Context, sink Sink) context.Context {
+	return context.WithValue(ctx, sinkContextKey, sink)
+}
+
+// SinkFromContext returns the sink carried by ctx, or nil if none.
+func SinkFromContext(ctx context.Context) Sink {
+	if ctx == nil {
+		return nil
+	}
+
+	if sink, ok := ctx.Value(sinkContextKey).(Sink); ok {
+		return sink
+	}
+	return nil
+}
+
+// record is a line of the events file.
+type record struct {
+	Time  time.Time `json:"time"`
+	Topic string    `json:"topic"`
+	Event Event     `json:"event"`
+}
+
+// FileSink appends the events as JSON lines to a file, created on the
+// first event. The events sent once it is closed are dropped.
+type FileSink struct {
+	path string
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+}
+
+// NewFileSink returns a sink appending the events to path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Send implements Sink.
+func (f *FileSink) Send(e Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+
+	if err := f.write(e); err != nil {
+		eventsLog.WithError(err).WithField("file", f.path).Error("failed to write event")
+	}
+}
+
+func (f *FileSink) write(e Event) error {
+	if f.file == nil {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		f.file = file
+	}
+
+	data, err := json.Marshal(record{
+		Time:  time.Now().UTC(),
+		Topic: e.Topic(),
+		Event: e,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the events file, for good.
+func (f *FileSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSink struct{}
+
+func (testSink) Send(e Event) {}
+
+func TestSinkFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(SinkFromContext(nil))
+	assert.Nil(SinkFromContext(context.Background()))
+
+	ctx := WithSink(context.Background(), testSink{})
+	assert.Equal(testSink{}, SinkFromContext(ctx))
+}
+
+func TestFileSink(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "events")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sandbox", "events.jsonl")
+	sink := NewFileSink(path)
+
+	// The file is only created on the first event.
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+
+	sink.Send(&SandboxBooted{SandboxId: "foo", BootTimeMs: 100})
+	sink.Send(&DeviceHotplug{SandboxId: "foo", DeviceId: "bar", DeviceType: "block", Action: HotplugAdd})
+	assert.NoError(sink.Close())
+
+	// Dropped once closed
+	sink.Send(&SandboxResized{SandboxId: "foo", Vcpus: 2})
+
+	f, err := os.Open(path)
+	assert.NoError(err)
+	defer f.Close()
+
+	type line struct {
+		Topic string                 `json:"topic"`
+		Event map[string]interface{} `json:"event"`
+	}
+
+	var lines []line
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var l line
+		assert.NoError(json.Unmarshal(scanner.Bytes(), &l))
+		lines = append(lines, l)
+	}
+	assert.NoError(scanner.Err())
+
+	assert.Equal([]line{
+		{
+			Topic: SandboxBootedTopic,
+			Event: map[string]interface{}{"sandbox_id": "foo", "boot_time_ms": float64(100)},
+		},
+		{
+			Topic: DeviceHotplugTopic,
+			Event: map[string]interface{}{"sandbox_id": "foo", "device_id": "bar", "device_type": "block", "action": "add"},
+		},
+	}, lines)
+}
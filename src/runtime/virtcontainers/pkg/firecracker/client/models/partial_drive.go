@@ -22,8 +22,10 @@ type PartialDrive struct {
 	DriveID *string `json:"drive_id"`
 
 	// Host level path for the guest drive
-	// Required: true
-	PathOnHost *string `json:"path_on_host"`
+	PathOnHost string `json:"path_on_host,omitempty"`
+
+	// rate limiter
+	RateLimiter *RateLimiter `json:"rate_limiter,omitempty"`
 }
 
 // Validate validates this partial drive
@@ -34,7 +36,7 @@ func (m *PartialDrive) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
-	if err := m.validatePathOnHost(formats); err != nil {
+	if err := m.validateRateLimiter(formats); err != nil {
 		res = append(res, err)
 	}
 
@@ -53,10 +55,19 @@ func (m *PartialDrive) validateDriveID(formats strfmt.Registry) error {
 	return nil
 }
 
-func (m *PartialDrive) validatePathOnHost(formats strfmt.Registry) error {
+func (m *PartialDrive) validateRateLimiter(formats strfmt.Registry) error {
 
-	if err := validate.Required("path_on_host", "body", m.PathOnHost); err != nil {
-		return err
+	if swag.IsZero(m.RateLimiter) { // not required
+		return nil
+	}
+
+	if m.RateLimiter != nil {
+		if err := m.RateLimiter.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("rate_limiter")
+			}
+			return err
+		}
 	}
 
 	return nil
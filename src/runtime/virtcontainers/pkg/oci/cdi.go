@@ -0,0 +1,456 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// CDIAnnotationPrefix is the prefix of the annotations listing the
+// Container Device Interface devices requested by a container. The value
+// is a comma separated list of fully qualified device names.
+const CDIAnnotationPrefix = "cdi.k8s.io/"
+
+// CDISpecDirs lists the directories the Container Device Interface specs
+// are loaded from. A device defined in a later directory overrides the one
+// with the same name from an earlier directory.
+var CDISpecDirs = []string{"/etc/cdi", "/var/run/cdi"}
+
+// cdiSpec is a Container Device Interface spec file. JSON being a subset of
+// YAML, both spec formats are decoded with the YAML decoder.
+type cdiSpec struct {
+	Version        string            `yaml:"cdiVersion"`
+	Kind           string            `yaml:"kind"`
+	Devices        []cdiDevice       `yaml:"devices"`
+	ContainerEdits cdiContainerEdits `yaml:"containerEdits"`
+}
+
+type cdiDevice struct {
+	Name           string            `yaml:"name"`
+	ContainerEdits cdiContainerEdits `yaml:"containerEdits"`
+}
+
+type cdiContainerEdits struct {
+	Env         []string        `yaml:"env"`
+	DeviceNodes []cdiDeviceNode `yaml:"deviceNodes"`
+	Mounts      []cdiMount      `yaml:"mounts"`
+	Hooks       []cdiHook       `yaml:"hooks"`
+}
+
+type cdiDeviceNode struct {
+	Path        string       `yaml:"path"`
+	HostPath    string       `yaml:"hostPath"`
+	Type        string       `yaml:"type"`
+	Major       *int64       `yaml:"major"`
+	Minor       *int64       `yaml:"minor"`
+	FileMode    *os.FileMode `yaml:"fileMode"`
+	Permissions string       `yaml:"permissions"`
+	UID         *uint32      `yaml:"uid"`
+	GID         *uint32      `yaml:"gid"`
+}
+
+type cdiMount struct {
+	HostPath      string   `yaml:"hostPath"`
+	ContainerPath string   `yaml:"containerPath"`
+	Type          string   `yaml:"type"`
+	Options       []string `yaml:"options"`
+}
+
+type cdiHook struct {
+	HookName string   `yaml:"hookName"`
+	Path     string   `yaml:"path"`
+	Args     []string `yaml:"args"`
+	Env      []string `yaml:"env"`
+	Timeout  *int     `yaml:"timeout"`
+}
+
+// cdiResolvedDevice is a device found in the CDI specs, along with the spec
+// defining it.
+type cdiResolvedDevice struct {
+	device *cdiDevice
+	spec   *cdiSpec
+}
+
+// parseCDIDevice splits a fully qualified CDI device name, formatted as
+// vendor.com/class=name, into its kind and name.
+func parseCDIDevice(device string) (string, string, error) {
+	i := strings.LastIndex(device, "=")
+	if i <= 0 || i == len(device)-1 {
+		return "", "", fmt.Errorf("invalid CDI device %q", device)
+	}
+
+	kind, name := device[:i], device[i+1:]
+
+	parts := strings.SplitN(kind, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid CDI device kind %q", kind)
+	}
+
+	return kind, name, nil
+}
+
+// isCDIDevice tells if a device path from the OCI spec is a CDI device
+// reference rather than a device node.
+func isCDIDevice(path string) bool {
+	if filepath.IsAbs(path) {
+		return false
+	}
+
+	_, _, err := parseCDIDevice(path)
+	return err == nil
+}
+
+// cdiDeviceRequests returns the CDI devices requested by the container,
+// from the CDI annotations and from the device list. The CDI references
+// are removed from the annotations and from the device list, not to be
+// resolved twice.
+func cdiDeviceRequests(spec *specs.Spec) []string {
+	var requests []string
+
+	var keys []string
+	for key := range spec.Annotations {
+		if strings.HasPrefix(key, CDIAnnotationPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, device := range strings.Split(spec.Annotations[key], ",") {
+			if device = strings.TrimSpace(device); device != "" {
+				requests = append(requests, device)
+			}
+		}
+		delete(spec.Annotations, key)
+	}
+
+	if spec.Linux != nil {
+		var devices []specs.LinuxDevice
+		for _, d := range spec.Linux.Devices {
+			if isCDIDevice(d.Path) {
+				requests = append(requests, d.Path)
+				continue
+			}
+			devices = append(devices, d)
+		}
+		spec.Linux.Devices = devices
+	}
+
+	return requests
+}
+
+// loadCDISpecs loads the CDI specs found in dirs and indexes their devices
+// by fully qualified name.
+func loadCDISpecs(dirs []string) (map[string]cdiResolvedDevice, error) {
+	devices := make(map[string]cdiResolvedDevice)
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			switch filepath.Ext(file.Name()) {
+			case ".json", ".yaml", ".yml":
+			default:
+				continue
+			}
+
+			path := filepath.Join(dir, file.Name())
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			spec := &cdiSpec{}
+			if err := yaml.Unmarshal(data, spec); err != nil {
+				return nil, fmt.Errorf("failed to parse CDI spec %s: %v", path, err)
+			}
+
+			if _, _, err := parseCDIDevice(spec.Kind + "=x"); err != nil {
+				return nil, fmt.Errorf("invalid CDI spec %s: %v", path, err)
+			}
+
+			for i := range spec.Devices {
+				name := spec.Kind + "=" + spec.Devices[i].Name
+				devices[name] = cdiResolvedDevice{
+					device: &spec.Devices[i],
+					spec:   spec,
+				}
+			}
+		}
+	}
+
+	return devices, nil
+}
+
+// applyCDIDeviceNode adds a CDI device node to the OCI spec device list, so
+// that it goes through the same path as any other container device, and
+// allows it in the device cgroup.
+func applyCDIDeviceNode(spec *specs.Spec, node cdiDeviceNode) error {
+	if node.Path == "" {
+		return fmt.Errorf("CDI device node path cannot be empty")
+	}
+
+	hostPath := node.HostPath
+	if hostPath == "" {
+		hostPath = node.Path
+	}
+
+	// The type and numbers missing from the spec are the ones of the
+	// host node, a fifo having no numbers.
+	if node.Type == "" || (node.Type != "p" && (node.Major == nil || node.Minor == nil)) {
+		var st unix.Stat_t
+		if err := unix.Stat(hostPath, &st); err != nil {
+			return fmt.Errorf("failed to stat CDI device node %s: %v", hostPath, err)
+		}
+
+		var devType string
+		switch st.Mode & unix.S_IFMT {
+		case unix.S_IFCHR:
+			devType = "c"
+		case unix.S_IFBLK:
+			devType = "b"
+		default:
+			return fmt.Errorf("CDI device node %s is not a device", hostPath)
+		}
+
+		if node.Type == "" {
+			node.Type = devType
+		}
+
+		if node.Major == nil {
+			major := int64(unix.Major(uint64(st.Rdev)))
+			node.Major = &major
+		}
+
+		if node.Minor == nil {
+			minor := int64(unix.Minor(uint64(st.Rdev)))
+			node.Minor = &minor
+		}
+	}
+
+	var major, minor int64
+	if node.Major != nil {
+		major = *node.Major
+	}
+	if node.Minor != nil {
+		minor = *node.Minor
+	}
+
+	if spec.Linux == nil {
+		spec.Linux = &specs.Linux{}
+	}
+
+	spec.Linux.Devices = append(spec.Linux.Devices, specs.LinuxDevice{
+		Path:     node.Path,
+		Type:     node.Type,
+		Major:    major,
+		Minor:    minor,
+		FileMode: node.FileMode,
+		UID:      node.UID,
+		GID:      node.GID,
+	})
+
+	access := node.Permissions
+	if access == "" {
+		access = "rwm"
+	}
+
+	if spec.Linux.Resources == nil {
+		spec.Linux.Resources = &specs.LinuxResources{}
+	}
+
+	spec.Linux.Resources.Devices = append(spec.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+		Allow:  true,
+		Type:   node.Type,
+		Major:  &major,
+		Minor:  &minor,
+		Access: access,
+	})
+
+	return nil
+}
+
+func applyCDIHook(spec *specs.Spec, hook cdiHook) error {
+	if spec.Hooks == nil {
+		spec.Hooks = &specs.Hooks{}
+	}
+
+	h := specs.Hook{
+		Path:    hook.Path,
+		Args:    hook.Args,
+		Env:     hook.Env,
+		Timeout: hook.Timeout,
+	}
+
+	switch hook.HookName {
+	case "prestart":
+		spec.Hooks.Prestart = append(spec.Hooks.Prestart, h)
+	case "createRuntime":
+		spec.Hooks.CreateRuntime = append(spec.Hooks.CreateRuntime, h)
+	case "createContainer":
+		spec.Hooks.CreateContainer = append(spec.Hooks.CreateContainer, h)
+	case "startContainer":
+		spec.Hooks.StartContainer = append(spec.Hooks.StartContainer, h)
+	case "poststart":
+		spec.Hooks.Poststart = append(spec.Hooks.Poststart, h)
+	case "poststop":
+		spec.Hooks.Poststop = append(spec.Hooks.Poststop, h)
+	default:
+		return fmt.Errorf("unknown CDI hook %q", hook.HookName)
+	}
+
+	return nil
+}
+
+func applyCDIContainerEdits(spec *specs.Spec, edits cdiContainerEdits) error {
+	if len(edits.Env) > 0 {
+		if spec.Process == nil {
+			spec.Process = &specs.Process{}
+		}
+		spec.Process.Env = append(spec.Process.Env, edits.Env...)
+	}
+
+	for _, node := range edits.DeviceNodes {
+		if err := applyCDIDeviceNode(spec, node); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range edits.Mounts {
+		mountType := m.Type
+		if mountType == "" {
+			mountType = "bind"
+		}
+
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Source:      m.HostPath,
+			Destination: m.ContainerPath,
+			Type:        mountType,
+			Options:     m.Options,
+		})
+	}
+
+	for _, hook := range edits.Hooks {
+		if err := applyCDIHook(spec, hook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyCDIDevices resolves the Container Device Interface devices requested
+// by the container of the bundle, either through the CDI annotations or as
+// device list entries, and applies their device nodes, mounts, environment
+// and hooks to the OCI spec. It has to be called before the container
+// configuration is built from the spec.
+//
+// The edited spec is saved to the bundle: the spec read again from the
+// bundle, when the containers are recovered for instance, is the one the
+// container was created with.
+func ApplyCDIDevices(bundlePath string, spec *specs.Spec) error {
+	applied, err := applyCDIDevices(spec)
+	if err != nil || !applied {
+		return err
+	}
+
+	configPath := filepath.Join(bundlePath, "config.json")
+
+	fi, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(bundlePath, ".config.json-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), fi.Mode()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), configPath)
+}
+
+// applyCDIDevices applies the CDI devices requested by the container, and
+// tells if there were any.
+func applyCDIDevices(spec *specs.Spec) (bool, error) {
+	requests := cdiDeviceRequests(spec)
+	if len(requests) == 0 {
+		return false, nil
+	}
+
+	devices, err := loadCDISpecs(CDISpecDirs)
+	if err != nil {
+		return false, err
+	}
+
+	appliedDevices := make(map[string]bool)
+	appliedSpecs := make(map[*cdiSpec]bool)
+
+	for _, request := range requests {
+		if _, _, err := parseCDIDevice(request); err != nil {
+			return false, err
+		}
+
+		if appliedDevices[request] {
+			continue
+		}
+
+		resolved, ok := devices[request]
+		if !ok {
+			return false, fmt.Errorf("unresolvable CDI device %s", request)
+		}
+
+		ociLog.WithField("device", request).Debug("applying CDI device")
+
+		if !appliedSpecs[resolved.spec] {
+			if err := applyCDIContainerEdits(spec, resolved.spec.ContainerEdits); err != nil {
+				return false, err
+			}
+			appliedSpecs[resolved.spec] = true
+		}
+
+		if err := applyCDIContainerEdits(spec, resolved.device.ContainerEdits); err != nil {
+			return false, fmt.Errorf("failed to apply CDI device %s: %v", request, err)
+		}
+		appliedDevices[request] = true
+	}
+
+	return true, nil
+}
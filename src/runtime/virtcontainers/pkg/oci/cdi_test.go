@@ -0,0 +1,187 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package oci
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCDIJSONSpec = `{
+	"cdiVersion": "0.2.0",
+	"kind": "vendor.com/device",
+	"devices": [
+		{
+			"name": "null",
+			"containerEdits": {
+				"deviceNodes": [
+					{"path": "/dev/vendor-null", "hostPath": "/dev/null"},
+					{"path": "/dev/vendor-zero", "hostPath": "/dev/zero", "type": "c"}
+				],
+				"env": ["VENDOR_DEVICE=null"]
+			}
+		},
+		{
+			"name": "missing",
+			"containerEdits": {
+				"deviceNodes": [
+					{"path": "/dev/vendor-missing"}
+				]
+			}
+		}
+	],
+	"containerEdits": {
+		"mounts": [
+			{"hostPath": "/opt/vendor/lib", "containerPath": "/usr/lib/vendor", "options": ["ro", "bind"]}
+		],
+		"hooks": [
+			{"hookName": "createRuntime", "path": "/opt/vendor/bin/hook", "args": ["hook", "create"]}
+		]
+	}
+}`
+
+const testCDIYAMLSpec = `cdiVersion: 0.2.0
+kind: vendor.com/vfio
+devices:
+- name: group0
+  containerEdits:
+    deviceNodes:
+    - path: /dev/vfio/0
+      type: c
+      major: 241
+      minor: 0
+      permissions: rw
+`
+
+func TestParseCDIDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	kind, name, err := parseCDIDevice("vendor.com/class=name")
+	assert.NoError(err)
+	assert.Equal("vendor.com/class", kind)
+	assert.Equal("name", name)
+
+	for _, device := range []string{"", "name", "vendor.com/class", "vendor.com/class=", "=name", "class=name", "/class=name"} {
+		_, _, err := parseCDIDevice(device)
+		assert.Error(err, device)
+	}
+
+	assert.False(isCDIDevice("/dev/null"))
+	assert.True(isCDIDevice("vendor.com/class=name"))
+}
+
+func TestApplyCDIDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cdi")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "vendor.json"), []byte(testCDIJSONSpec), 0644))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "vfio.yaml"), []byte(testCDIYAMLSpec), 0644))
+
+	savedCDISpecDirs := CDISpecDirs
+	defer func() { CDISpecDirs = savedCDISpecDirs }()
+	CDISpecDirs = []string{filepath.Join(dir, "nonexistent"), dir}
+
+	bundle := filepath.Join(dir, "bundle")
+	assert.NoError(os.Mkdir(bundle, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte("{}"), 0600))
+
+	spec := specs.Spec{
+		Annotations: map[string]string{
+			CDIAnnotationPrefix + "vendor": "vendor.com/device=null, vendor.com/device=null",
+			"io.katacontainers.foo":        "bar",
+		},
+		Process: &specs.Process{Env: []string{"PATH=/bin"}},
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{
+				{Path: "/dev/foo", Type: "c", Major: 1, Minor: 2},
+				{Path: "vendor.com/vfio=group0"},
+			},
+		},
+	}
+
+	assert.NoError(ApplyCDIDevices(bundle, &spec))
+
+	var nullMajor, nullMinor, zeroMajor, zeroMinor, vfioMajor, vfioMinor int64 = 1, 3, 1, 5, 241, 0
+
+	assert.Equal([]specs.LinuxDevice{
+		{Path: "/dev/foo", Type: "c", Major: 1, Minor: 2},
+		{Path: "/dev/vendor-null", Type: "c", Major: nullMajor, Minor: nullMinor},
+		{Path: "/dev/vendor-zero", Type: "c", Major: zeroMajor, Minor: zeroMinor},
+		{Path: "/dev/vfio/0", Type: "c", Major: vfioMajor, Minor: vfioMinor},
+	}, spec.Linux.Devices)
+
+	assert.Equal([]specs.LinuxDeviceCgroup{
+		{Allow: true, Type: "c", Major: &nullMajor, Minor: &nullMinor, Access: "rwm"},
+		{Allow: true, Type: "c", Major: &zeroMajor, Minor: &zeroMinor, Access: "rwm"},
+		{Allow: true, Type: "c", Major: &vfioMajor, Minor: &vfioMinor, Access: "rw"},
+	}, spec.Linux.Resources.Devices)
+
+	// The references are resolved once, and the edited spec saved to the
+	// bundle.
+	assert.Equal(map[string]string{"io.katacontainers.foo": "bar"}, spec.Annotations)
+
+	data, err := ioutil.ReadFile(filepath.Join(bundle, "config.json"))
+	assert.NoError(err)
+	var saved specs.Spec
+	assert.NoError(json.Unmarshal(data, &saved))
+	assert.Equal(spec, saved)
+
+	assert.Equal([]string{"PATH=/bin", "VENDOR_DEVICE=null"}, spec.Process.Env)
+
+	assert.Equal([]specs.Mount{
+		{Source: "/opt/vendor/lib", Destination: "/usr/lib/vendor", Type: "bind", Options: []string{"ro", "bind"}},
+	}, spec.Mounts)
+
+	assert.NotNil(spec.Hooks)
+	assert.Equal([]specs.Hook{
+		{Path: "/opt/vendor/bin/hook", Args: []string{"hook", "create"}},
+	}, spec.Hooks.CreateRuntime)
+
+	// The CDI devices go through the regular device path.
+	deviceInfos, err := containerDeviceInfos(spec)
+	assert.NoError(err)
+	assert.Len(deviceInfos, 4)
+
+	// Unknown devices and devices without a host node are errors.
+	for _, device := range []string{"vendor.com/device=unknown", "vendor.com/device=missing"} {
+		spec := specs.Spec{
+			Annotations: map[string]string{CDIAnnotationPrefix + "vendor": device},
+			Linux:       &specs.Linux{},
+		}
+		_, err := applyCDIDevices(&spec)
+		assert.Error(err, device)
+	}
+}
+
+func TestApplyCDIDevicesNoRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	savedCDISpecDirs := CDISpecDirs
+	defer func() { CDISpecDirs = savedCDISpecDirs }()
+
+	// The spec directories are not read, nor the bundle written, when no
+	// CDI device is requested.
+	CDISpecDirs = []string{"/dev/null"}
+
+	spec := specs.Spec{
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{{Path: "/dev/foo", Type: "c", Major: 1, Minor: 2}},
+		},
+	}
+
+	assert.NoError(ApplyCDIDevices("/nonexistent", &spec))
+	assert.Len(spec.Linux.Devices, 1)
+	assert.Nil(spec.Linux.Resources)
+}
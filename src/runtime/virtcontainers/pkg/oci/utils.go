@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	criContainerdAnnotations "github.com/containerd/cri-containerd/pkg/annotations"
 	crioAnnotations "github.com/cri-o/cri-o/pkg/annotations"
@@ -123,8 +124,17 @@ type RuntimeConfig struct {
 	//Determines kata processes are managed only in sandbox cgroup
 	SandboxCgroupOnly bool
 
+	//Determines if the sandboxes of a namespace share a single shim
+	SharedShim bool
+
+	//Time after which the shim kills an exec process, when not zero
+	ExecTimeout time.Duration
+
 	//Experimental features enabled
 	Experimental []exp.Feature
+
+	//Non-namespaced sysctls a pod is allowed to set on the guest kernel
+	GuestSysctlAllowlist []string
 }
 
 // AddKernelParam allows the addition of new kernel parameters to an existing
@@ -888,9 +898,124 @@ func SandboxConfig(ocispec specs.Spec, runtime RuntimeConfig, bundlePath, cid, c
 		return vc.SandboxConfig{}, err
 	}
 
+	if err := addGuestSysctls(&sandboxConfig, runtime.GuestSysctlAllowlist); err != nil {
+		return vc.SandboxConfig{}, err
+	}
+
 	return sandboxConfig, nil
 }
 
+// namespacedSysctls and namespacedSysctlPrefixes list the sysctls isolated
+// by the IPC, UTS and network namespaces of the containers.
+var namespacedSysctls = map[string]bool{
+	"kernel.msgmax":          true,
+	"kernel.msgmnb":          true,
+	"kernel.msgmni":          true,
+	"kernel.sem":             true,
+	"kernel.shmall":          true,
+	"kernel.shmmax":          true,
+	"kernel.shmmni":          true,
+	"kernel.shm_rmid_forced": true,
+	"kernel.domainname":      true,
+	"kernel.hostname":        true,
+}
+
+var namespacedSysctlPrefixes = []string{
+	"fs.mqueue.",
+	"net.",
+}
+
+// IsNamespacedSysctl returns true if the sysctl is isolated by one of the
+// container namespaces, hence can be safely applied per container.
+func IsNamespacedSysctl(sysctl string) bool {
+	if namespacedSysctls[sysctl] {
+		return true
+	}
+
+	for _, prefix := range namespacedSysctlPrefixes {
+		if strings.HasPrefix(sysctl, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sysctlAllowed checks the sysctl against the operator allowlist, whose
+// entries are either sysctl names or prefixes ending with '*'.
+func sysctlAllowed(sysctl string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if strings.HasSuffix(allowed, "*") {
+			if strings.HasPrefix(sysctl, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+		} else if sysctl == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// moveGuestSysctls moves the non-namespaced sysctls out of the container
+// spec, since they fail when the container is created, and returns them.
+func moveGuestSysctls(spec *specs.Spec) map[string]string {
+	if spec.Linux == nil || len(spec.Linux.Sysctl) == 0 {
+		return nil
+	}
+
+	namespaced := make(map[string]string)
+	guest := make(map[string]string)
+
+	for key, value := range spec.Linux.Sysctl {
+		if IsNamespacedSysctl(key) {
+			namespaced[key] = value
+		} else {
+			guest[key] = value
+		}
+	}
+
+	if len(guest) == 0 {
+		return nil
+	}
+
+	spec.Linux.Sysctl = namespaced
+
+	return guest
+}
+
+// addGuestSysctls checks the non-namespaced sysctls of the containers of the
+// sandbox against the allowlist, so that they get applied once to the guest
+// kernel when the sandbox starts.
+func addGuestSysctls(sandboxConfig *vc.SandboxConfig, allowlist []string) error {
+	guest := make(map[string]string)
+
+	for _, c := range sandboxConfig.Containers {
+		for key, value := range c.GuestSysctls {
+			if !sysctlAllowed(key, allowlist) {
+				return fmt.Errorf("Non-namespaced sysctl %q is not allowed on the guest kernel", key)
+			}
+
+			// The guest kernel is shared by all the containers.
+			if v, ok := guest[key]; ok && v != value {
+				return fmt.Errorf("Conflicting values %q and %q for non-namespaced sysctl %q", v, value, key)
+			}
+
+			guest[key] = value
+		}
+	}
+
+	if len(guest) == 0 {
+		return nil
+	}
+
+	ociLog.WithField("sysctls", guest).Info("Applying non-namespaced sysctls to the guest kernel")
+
+	sandboxConfig.GuestSysctls = guest
+
+	return nil
+}
+
 // ContainerConfig converts an OCI compatible runtime configuration
 // file to a virtcontainers container configuration structure.
 func ContainerConfig(ocispec specs.Spec, bundlePath, cid, console string, detach bool) (vc.ContainerConfig, error) {
@@ -935,9 +1060,10 @@ func ContainerConfig(ocispec specs.Spec, bundlePath, cid, console string, detach
 		Annotations: map[string]string{
 			vcAnnotations.BundlePathKey: bundlePath,
 		},
-		Mounts:      containerMounts(ocispec),
-		DeviceInfos: deviceInfos,
-		Resources:   *ocispec.Linux.Resources,
+		Mounts:       containerMounts(ocispec),
+		DeviceInfos:  deviceInfos,
+		Resources:    *ocispec.Linux.Resources,
+		GuestSysctls: moveGuestSysctls(&ocispec),
 
 		// This is a custom OCI spec modified at SetEphemeralStorageType()
 		// to support ephemeral storage and k8s empty dir.
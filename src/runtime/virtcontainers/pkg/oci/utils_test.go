@@ -872,3 +872,123 @@ func TestAddRuntimeAnnotations(t *testing.T) {
 	assert.Equal(config.NetworkConfig.DisableNewNetNs, true)
 	assert.Equal(config.NetworkConfig.InterworkingModel, vc.NetXConnectMacVtapModel)
 }
+
+func TestIsNamespacedSysctl(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsNamespacedSysctl("kernel.shmmax"))
+	assert.True(IsNamespacedSysctl("fs.mqueue.msg_max"))
+	assert.True(IsNamespacedSysctl("net.ipv4.ip_forward"))
+	assert.False(IsNamespacedSysctl("vm.max_map_count"))
+	assert.False(IsNamespacedSysctl("kernel.sched_rt_runtime_us"))
+}
+
+func TestAddGuestSysctls(t *testing.T) {
+	assert := assert.New(t)
+
+	newContainerConfig := func(sysctls map[string]string) vc.ContainerConfig {
+		spec := &specs.Spec{
+			Linux: &specs.Linux{Sysctl: sysctls},
+		}
+
+		return vc.ContainerConfig{
+			CustomSpec:   spec,
+			GuestSysctls: moveGuestSysctls(spec),
+		}
+	}
+
+	newConfig := func(sysctls map[string]string) vc.SandboxConfig {
+		return vc.SandboxConfig{
+			Containers: []vc.ContainerConfig{newContainerConfig(sysctls)},
+		}
+	}
+
+	// No sysctls at all
+	config := newConfig(nil)
+	assert.NoError(addGuestSysctls(&config, nil))
+	assert.Nil(config.GuestSysctls)
+
+	// Namespaced sysctls stay in the container spec
+	config = newConfig(map[string]string{"net.ipv4.ip_forward": "1"})
+	assert.NoError(addGuestSysctls(&config, nil))
+	assert.Nil(config.GuestSysctls)
+	assert.Equal(config.Containers[0].CustomSpec.Linux.Sysctl, map[string]string{"net.ipv4.ip_forward": "1"})
+
+	// Non-namespaced sysctls must be allowed
+	config = newConfig(map[string]string{"vm.max_map_count": "262144"})
+	assert.Error(addGuestSysctls(&config, nil))
+	assert.Error(addGuestSysctls(&config, []string{"vm.max_map_count_foo"}))
+
+	config = newConfig(map[string]string{
+		"net.ipv4.ip_forward":        "1",
+		"vm.max_map_count":           "262144",
+		"kernel.sched_rt_runtime_us": "-1",
+	})
+	assert.NoError(addGuestSysctls(&config, []string{"vm.max_map_count", "kernel.sched_*"}))
+	assert.Equal(config.GuestSysctls, map[string]string{
+		"vm.max_map_count":           "262144",
+		"kernel.sched_rt_runtime_us": "-1",
+	})
+	assert.Equal(config.Containers[0].CustomSpec.Linux.Sysctl, map[string]string{"net.ipv4.ip_forward": "1"})
+
+	// The sysctls of every container of the sandbox are applied
+	config = newConfig(map[string]string{"vm.max_map_count": "262144"})
+	config.Containers = append(config.Containers, newContainerConfig(map[string]string{
+		"vm.max_map_count":     "262144",
+		"vm.overcommit_memory": "1",
+		"kernel.shmmax":        "4096",
+	}))
+	assert.NoError(addGuestSysctls(&config, []string{"vm.*"}))
+	assert.Equal(config.GuestSysctls, map[string]string{
+		"vm.max_map_count":     "262144",
+		"vm.overcommit_memory": "1",
+	})
+	assert.Empty(config.Containers[0].CustomSpec.Linux.Sysctl)
+	assert.Equal(config.Containers[1].CustomSpec.Linux.Sysctl, map[string]string{"kernel.shmmax": "4096"})
+
+	// The containers cannot set different values on the guest kernel
+	config = newConfig(map[string]string{"vm.max_map_count": "262144"})
+	config.Containers = append(config.Containers, newContainerConfig(map[string]string{"vm.max_map_count": "65530"}))
+	assert.Error(addGuestSysctls(&config, []string{"vm.*"}))
+}
+
+func TestContainerConfigGuestSysctls(t *testing.T) {
+	assert := assert.New(t)
+
+	newSpec := func(sysctls map[string]string) specs.Spec {
+		return specs.Spec{
+			Process: &specs.Process{},
+			Root:    &specs.Root{Path: "rootfs"},
+			Linux: &specs.Linux{
+				Resources: &specs.LinuxResources{},
+				Sysctl:    sysctls,
+			},
+		}
+	}
+
+	// The namespaced sysctls of a container created in a running sandbox
+	// are applied per container
+	spec := newSpec(map[string]string{
+		"kernel.shmmax":          "4096",
+		"kernel.shm_rmid_forced": "1",
+		"net.ipv4.ip_forward":    "1",
+	})
+	config, err := ContainerConfig(spec, tempBundlePath, "43", "", false)
+	assert.NoError(err)
+	assert.Nil(config.GuestSysctls)
+	assert.Len(config.CustomSpec.Linux.Sysctl, 3)
+
+	// while the non-namespaced ones are left to the sandbox
+	spec = newSpec(map[string]string{
+		"kernel.shmmax":       "4096",
+		"net.ipv4.ip_forward": "1",
+		"vm.max_map_count":    "262144",
+	})
+	config, err = ContainerConfig(spec, tempBundlePath, "43", "", false)
+	assert.NoError(err)
+	assert.Equal(config.GuestSysctls, map[string]string{"vm.max_map_count": "262144"})
+	assert.Equal(config.CustomSpec.Linux.Sysctl, map[string]string{
+		"kernel.shmmax":       "4096",
+		"net.ipv4.ip_forward": "1",
+	})
+}
@@ -296,3 +296,12 @@ func (m *VCMock) CleanupContainer(ctx context.Context, sandboxID, containerID st
 	}
 	return fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
 }
+
+// GCSandboxes implements the VC function of the same name.
+func (m *VCMock) GCSandboxes(ctx context.Context, config vc.GCConfig) ([]vc.GCArtifact, error) {
+	if m.GCSandboxesFunc != nil {
+		return m.GCSandboxesFunc(ctx, config)
+	}
+
+	return nil, fmt.Errorf("%s: %s (%+v): config: %v", mockErrorPrefix, getSelf(), m, config)
+}
@@ -817,3 +817,30 @@ func TestVCMockListRoutes(t *testing.T) {
 	assert.Error(err)
 	assert.True(IsMockError(err))
 }
+
+func TestVCMockGCSandboxes(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &VCMock{}
+	assert.Nil(m.GCSandboxesFunc)
+
+	ctx := context.Background()
+	_, err := m.GCSandboxes(ctx, vc.GCConfig{})
+	assert.Error(err)
+	assert.True(IsMockError(err))
+
+	m.GCSandboxesFunc = func(ctx context.Context, config vc.GCConfig) ([]vc.GCArtifact, error) {
+		return []vc.GCArtifact{}, nil
+	}
+
+	artifacts, err := m.GCSandboxes(ctx, vc.GCConfig{DryRun: true})
+	assert.NoError(err)
+	assert.Empty(artifacts)
+
+	// reset
+	m.GCSandboxesFunc = nil
+
+	_, err = m.GCSandboxes(ctx, vc.GCConfig{})
+	assert.Error(err)
+	assert.True(IsMockError(err))
+}
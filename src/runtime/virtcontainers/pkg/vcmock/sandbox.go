@@ -42,6 +42,16 @@ func (s *Sandbox) GetNetNs() string {
 	return s.MockNetNs
 }
 
+// GetHypervisorPid implements the VCSandbox function of the same name.
+func (s *Sandbox) GetHypervisorPid() (int, error) {
+	return s.MockHypervisorPid, nil
+}
+
+// GetHookAnnotations implements the VCSandbox function of the same name.
+func (s *Sandbox) GetHookAnnotations() map[string]string {
+	return s.MockHookAnnotations
+}
+
 // GetAllContainers implements the VCSandbox function of the same name.
 func (s *Sandbox) GetAllContainers() []vc.VCContainer {
 	var ifa = make([]vc.VCContainer, len(s.MockContainers))
@@ -25,6 +25,9 @@ type Sandbox struct {
 	MockAnnotations map[string]string
 	MockContainers  []*Container
 	MockNetNs       string
+
+	MockHypervisorPid   int
+	MockHookAnnotations map[string]string
 }
 
 // Container is a fake Container type used for testing
@@ -75,4 +78,6 @@ type VCMock struct {
 	UpdateRoutesFunc     func(ctx context.Context, sandboxID string, routes []*vcTypes.Route) ([]*vcTypes.Route, error)
 	ListRoutesFunc       func(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error)
 	CleanupContainerFunc func(ctx context.Context, sandboxID, containerID string, force bool) error
+
+	GCSandboxesFunc func(ctx context.Context, config vc.GCConfig) ([]vc.GCArtifact, error)
 }
@@ -109,6 +109,10 @@ const (
 	qmpCapErrMsg  = "Failed to negoatiate QMP capabilities"
 	qmpExecCatCmd = "exec:cat"
 
+	// vhostUserReconnectTimeout is how many seconds QEMU waits before
+	// connecting again to a vhost-user backend which went away.
+	vhostUserReconnectTimeout = 1
+
 	scsiControllerID         = "scsi0"
 	rngID                    = "rng0"
 	vsockKernelOption        = "agent.use_vsock"
@@ -481,6 +485,7 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 	if err != nil {
 		return err
 	}
+	memory.HostNodes = q.config.memoryHostNodes
 
 	knobs := govmmQemu.Knobs{
 		NoUserConfig: true,
@@ -597,6 +602,8 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 	rngDev := config.RNGDev{
 		ID:       rngID,
 		Filename: q.config.EntropySource,
+		MaxBytes: q.config.EntropyMaxBytes,
+		Period:   q.config.EntropyPeriod,
 	}
 	qemuConfig.Devices, err = q.arch.appendRNGDevice(qemuConfig.Devices, rngDev)
 	if err != nil {
@@ -1089,7 +1096,12 @@ func (q *qemu) hotplugAddBlockDevice(drive *config.BlockDrive, op operation, dev
 			return err
 		}
 
-		if err = q.qmpMonitorCh.qmp.ExecuteNVDIMMDeviceAdd(q.qmpMonitorCh.ctx, drive.ID, drive.File, blocksize, &drive.Pmem); err != nil {
+		if drive.ReadOnly {
+			err = q.qmpMonitorCh.qmp.ExecuteReadOnlyNVDIMMDeviceAdd(q.qmpMonitorCh.ctx, drive.ID, drive.File, blocksize, &drive.Pmem)
+		} else {
+			err = q.qmpMonitorCh.qmp.ExecuteNVDIMMDeviceAdd(q.qmpMonitorCh.ctx, drive.ID, drive.File, blocksize, &drive.Pmem)
+		}
+		if err != nil {
 			q.Logger().WithError(err).Errorf("Failed to add NVDIMM device %s", drive.File)
 			return err
 		}
@@ -1146,8 +1158,10 @@ func (q *qemu) hotplugAddBlockDevice(drive *config.BlockDrive, op operation, dev
 			}
 		}()
 
-		// PCI address is in the format bridge-addr/device-addr eg. "03/02"
-		drive.PCIAddr = fmt.Sprintf("%02x", bridge.Addr) + "/" + addr
+		drive.PCIPath, err = bridge.PciPath(addr)
+		if err != nil {
+			return err
+		}
 
 		if err = q.qmpMonitorCh.qmp.ExecutePCIDeviceAdd(q.qmpMonitorCh.ctx, drive.ID, devID, driver, addr, bridge.ID, romFile, 0, true, defaultDisableModern); err != nil {
 			return err
@@ -1175,7 +1189,8 @@ func (q *qemu) hotplugAddBlockDevice(drive *config.BlockDrive, op operation, dev
 }
 
 func (q *qemu) hotplugAddVhostUserBlkDevice(vAttr *config.VhostUserDeviceAttrs, op operation, devID string) (err error) {
-	err = q.qmpMonitorCh.qmp.ExecuteCharDevUnixSocketAdd(q.qmpMonitorCh.ctx, vAttr.DevID, vAttr.SocketPath, false, false)
+	// QEMU connects to the backend again when it restarts.
+	err = q.qmpMonitorCh.qmp.ExecuteCharDevUnixSocketReconnectAdd(q.qmpMonitorCh.ctx, vAttr.DevID, vAttr.SocketPath, vhostUserReconnectTimeout)
 	if err != nil {
 		return err
 	}
@@ -1186,7 +1201,6 @@ func (q *qemu) hotplugAddVhostUserBlkDevice(vAttr *config.VhostUserDeviceAttrs,
 		}
 	}()
 
-	driver := "vhost-user-blk-pci"
 	addr, bridge, err := q.arch.addDeviceToBridge(vAttr.DevID, types.PCI)
 	if err != nil {
 		return err
@@ -1198,23 +1212,64 @@ func (q *qemu) hotplugAddVhostUserBlkDevice(vAttr *config.VhostUserDeviceAttrs,
 		}
 	}()
 
-	// PCI address is in the format bridge-addr/device-addr eg. "03/02"
-	vAttr.PCIAddr = fmt.Sprintf("%02x", bridge.Addr) + "/" + addr
+	vAttr.PCIPath, err = bridge.PciPath(addr)
+	if err != nil {
+		return err
+	}
 
-	if err = q.qmpMonitorCh.qmp.ExecutePCIVhostUserDevAdd(q.qmpMonitorCh.ctx, driver, devID, vAttr.DevID, addr, bridge.ID); err != nil {
+	if err = q.qmpMonitorCh.qmp.ExecutePCIVhostUserBlkDevAdd(q.qmpMonitorCh.ctx, devID, vAttr.DevID, addr, bridge.ID, vAttr.Queues); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// hotplugAddVhostUserFSDevice plugs a vhost-user-fs device, connected to the
+// virtio-fs daemon listening on the device socket. No DAX window is set up
+// for hotplugged devices, their large BAR hardly fits behind a PCI bridge.
+func (q *qemu) hotplugAddVhostUserFSDevice(vAttr *config.VhostUserDeviceAttrs, devID string) (err error) {
+	err = q.qmpMonitorCh.qmp.ExecuteCharDevUnixSocketAdd(q.qmpMonitorCh.ctx, vAttr.DevID, vAttr.SocketPath, false, false)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			q.qmpMonitorCh.qmp.ExecuteChardevDel(q.qmpMonitorCh.ctx, vAttr.DevID)
+		}
+	}()
+
+	addr, bridge, err := q.arch.addDeviceToBridge(vAttr.DevID, types.PCI)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			q.arch.removeDeviceFromBridge(vAttr.DevID)
+		}
+	}()
+
+	vAttr.PCIPath, err = bridge.PciPath(addr)
+	if err != nil {
+		return err
+	}
+
+	return q.qmpMonitorCh.qmp.ExecutePCIVhostUserFSDevAdd(q.qmpMonitorCh.ctx, devID, vAttr.DevID, vAttr.Tag, addr, bridge.ID)
+}
+
+// blockDriveDevID returns the ID of the device a drive is hotplugged with.
+func blockDriveDevID(drive *config.BlockDrive) string {
+	return "virtio-" + drive.ID
+}
+
 func (q *qemu) hotplugBlockDevice(drive *config.BlockDrive, op operation) error {
 	err := q.qmpSetup()
 	if err != nil {
 		return err
 	}
 
-	devID := "virtio-" + drive.ID
+	devID := blockDriveDevID(drive)
 
 	if op == addDevice {
 		err = q.hotplugAddBlockDevice(drive, op, devID)
@@ -1249,6 +1304,8 @@ func (q *qemu) hotplugVhostUserDevice(vAttr *config.VhostUserDeviceAttrs, op ope
 		switch vAttr.Type {
 		case config.VhostUserBlk:
 			return q.hotplugAddVhostUserBlkDevice(vAttr, op, devID)
+		case config.VhostUserFS:
+			return q.hotplugAddVhostUserFSDevice(vAttr, devID)
 		default:
 			return fmt.Errorf("Incorrect vhost-user device type found")
 		}
@@ -1306,12 +1363,20 @@ func (q *qemu) hotplugVFIODevice(device *config.VFIODev, op operation) (err erro
 
 			switch device.Type {
 			case config.VFIODeviceNormalType:
-				return q.qmpMonitorCh.qmp.ExecuteVFIODeviceAdd(q.qmpMonitorCh.ctx, devID, device.BDF, device.Bus, romFile)
+				err = q.qmpMonitorCh.qmp.ExecuteVFIODeviceAdd(q.qmpMonitorCh.ctx, devID, device.BDF, device.Bus, romFile)
 			case config.VFIODeviceMediatedType:
-				return q.qmpMonitorCh.qmp.ExecutePCIVFIOMediatedDeviceAdd(q.qmpMonitorCh.ctx, devID, device.SysfsDev, "", device.Bus, romFile)
+				err = q.qmpMonitorCh.qmp.ExecutePCIVFIOMediatedDeviceAdd(q.qmpMonitorCh.ctx, devID, device.SysfsDev, "", device.Bus, romFile)
 			default:
 				return fmt.Errorf("Incorrect VFIO device type found")
 			}
+			if err != nil {
+				return err
+			}
+
+			// QEMU picks the slot of the devices hotplugged on the root
+			// bus or on a PCIe root port.
+			device.GuestPciPath, err = q.qmpGuestPciPath(devID)
+			return err
 		}
 
 		addr, bridge, err := q.arch.addDeviceToBridge(devID, types.PCI)
@@ -1327,12 +1392,20 @@ func (q *qemu) hotplugVFIODevice(device *config.VFIODev, op operation) (err erro
 
 		switch device.Type {
 		case config.VFIODeviceNormalType:
-			return q.qmpMonitorCh.qmp.ExecutePCIVFIODeviceAdd(q.qmpMonitorCh.ctx, devID, device.BDF, addr, bridge.ID, romFile)
+			err = q.qmpMonitorCh.qmp.ExecutePCIVFIODeviceAdd(q.qmpMonitorCh.ctx, devID, device.BDF, addr, bridge.ID, romFile)
 		case config.VFIODeviceMediatedType:
-			return q.qmpMonitorCh.qmp.ExecutePCIVFIOMediatedDeviceAdd(q.qmpMonitorCh.ctx, devID, device.SysfsDev, addr, bridge.ID, romFile)
+			err = q.qmpMonitorCh.qmp.ExecutePCIVFIOMediatedDeviceAdd(q.qmpMonitorCh.ctx, devID, device.SysfsDev, addr, bridge.ID, romFile)
 		default:
 			return fmt.Errorf("Incorrect VFIO device type found")
 		}
+		if err != nil {
+			return err
+		}
+
+		device.GuestPciPath, err = bridge.PciPath(addr)
+		if err != nil {
+			return err
+		}
 	} else {
 		q.Logger().WithField("dev-id", devID).Info("Start hot-unplug VFIO device")
 
@@ -1350,6 +1423,50 @@ func (q *qemu) hotplugVFIODevice(device *config.VFIODev, op operation) (err erro
 	return nil
 }
 
+// qmpGuestPciPath returns the guest PCI path of the device devID, looking
+// for it from the root bus through the PCI bridges.
+func (q *qemu) qmpGuestPciPath(devID string) (types.PciPath, error) {
+	buses, err := q.qmpMonitorCh.qmp.ExecuteQueryPCI(q.qmpMonitorCh.ctx)
+	if err != nil {
+		return types.PciPath{}, err
+	}
+
+	for _, bus := range buses {
+		if bus.Bus != 0 {
+			continue
+		}
+
+		if slots := findPCIDevice(bus.Devices, devID); slots != nil {
+			return types.PciPathFromSlots(slots...)
+		}
+	}
+
+	return types.PciPath{}, fmt.Errorf("Device %s not found on the PCI buses", devID)
+}
+
+// findPCIDevice returns the slots leading to the device devID, nil if it
+// is not plugged on devices or behind them.
+func findPCIDevice(devices []govmmQemu.PCIDeviceInfo, devID string) []types.PciSlot {
+	for _, d := range devices {
+		slot, err := types.PciSlotFromInt(d.Slot)
+		if err != nil {
+			continue
+		}
+
+		if d.QdevID == devID {
+			return []types.PciSlot{slot}
+		}
+
+		if d.PCIBridge != nil {
+			if slots := findPCIDevice(d.PCIBridge.Devices, devID); slots != nil {
+				return append([]types.PciSlot{slot}, slots...)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (q *qemu) hotAddNetDevice(name, hardAddr string, VMFds, VhostFds []*os.File) error {
 	var (
 		VMFdNames    []string
@@ -1414,9 +1531,6 @@ func (q *qemu) hotplugNetDevice(endpoint Endpoint, op operation) (err error) {
 			}
 		}()
 
-		pciAddr := fmt.Sprintf("%02x/%s", bridge.Addr, addr)
-		endpoint.SetPciAddr(pciAddr)
-
 		var machine govmmQemu.Machine
 		machine, err = q.getQemuMachine()
 		if err != nil {
@@ -1426,6 +1540,14 @@ func (q *qemu) hotplugNetDevice(endpoint Endpoint, op operation) (err error) {
 			devNoHotplug := fmt.Sprintf("fe.%x.%x", bridge.Addr, addr)
 			return q.qmpMonitorCh.qmp.ExecuteNetCCWDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), devNoHotplug, int(q.config.NumVCPUs))
 		}
+
+		var pciPath types.PciPath
+		pciPath, err = bridge.PciPath(addr)
+		if err != nil {
+			return err
+		}
+		endpoint.SetPciPath(pciPath)
+
 		return q.qmpMonitorCh.qmp.ExecuteNetPCIDeviceAdd(q.qmpMonitorCh.ctx, tap.Name, devID, endpoint.HardwareAddr(), addr, bridge.ID, romFile, int(q.config.NumVCPUs), defaultDisableModern)
 
 	}
@@ -1444,6 +1566,58 @@ func (q *qemu) hotplugNetDevice(endpoint Endpoint, op operation) (err error) {
 	return nil
 }
 
+func (q *qemu) hotplugCharDevice(device *config.CharDev, op operation) (err error) {
+	err = q.qmpSetup()
+	if err != nil {
+		return err
+	}
+
+	devID := "virtserialport-" + device.ID
+
+	if op == removeDevice {
+		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, devID); err != nil {
+			return err
+		}
+
+		return q.qmpMonitorCh.qmp.ExecuteChardevDel(q.qmpMonitorCh.ctx, device.ID)
+	}
+
+	// QEMU listens on the chardev socket, the runtime connects to it to
+	// proxy the host device.
+	socketPath, err := q.charDevSocketPath(device.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = q.qmpMonitorCh.qmp.ExecuteCharDevUnixSocketAdd(q.qmpMonitorCh.ctx, device.ID, socketPath, false, true); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			q.qmpMonitorCh.qmp.ExecuteChardevDel(q.qmpMonitorCh.ctx, device.ID)
+		}
+	}()
+
+	portName := charDevPortNamePrefix + device.ID
+	if err = q.qmpMonitorCh.qmp.ExecuteVirtSerialPortAdd(q.qmpMonitorCh.ctx, devID, portName, device.ID); err != nil {
+		return err
+	}
+
+	device.Transport = config.CharDevVirtioSerial
+	device.PortName = portName
+	device.SocketPath = socketPath
+
+	return nil
+}
+
+// charDevSocketPath returns the path of the socket QEMU listens on for the
+// chardev id. The device IDs are unique within the sandbox, and short
+// enough to fit in the socket path length limit.
+func (q *qemu) charDevSocketPath(id string) (string, error) {
+	return utils.BuildSocketPath(q.store.RunVMStoragePath(), q.id, id+".sock")
+}
+
 func (q *qemu) hotplugDevice(devInfo interface{}, devType deviceType, op operation) (interface{}, error) {
 	switch devType {
 	case blockDev:
@@ -1464,6 +1638,9 @@ func (q *qemu) hotplugDevice(devInfo interface{}, devType deviceType, op operati
 	case vhostuserDev:
 		vAttr := devInfo.(*config.VhostUserDeviceAttrs)
 		return nil, q.hotplugVhostUserDevice(vAttr, op)
+	case charDev:
+		device := devInfo.(*config.CharDev)
+		return nil, q.hotplugCharDevice(device, op)
 	default:
 		return nil, fmt.Errorf("cannot hotplug device: unsupported device type '%v'", devType)
 	}
@@ -1673,7 +1850,7 @@ func (q *qemu) hotplugAddMemory(memDev *memoryDevice) (int, error) {
 		return 0, err
 	}
 
-	err = q.qmpMonitorCh.qmp.ExecHotplugMemory(q.qmpMonitorCh.ctx, memoryBack, "mem"+strconv.Itoa(memDev.slot), target, memDev.sizeMB, share)
+	err = q.qmpMonitorCh.qmp.ExecHotplugMemoryOnHostNodes(q.qmpMonitorCh.ctx, memoryBack, "mem"+strconv.Itoa(memDev.slot), target, memDev.sizeMB, share, q.config.memoryHostNodes)
 	if err != nil {
 		q.Logger().WithError(err).Error("hotplug memory")
 		return 0, err
@@ -2266,3 +2443,35 @@ func (q *qemu) generateSocket(id string, useVsock bool) (interface{}, error) {
 func (q *qemu) isRateLimiterBuiltin() bool {
 	return false
 }
+
+// setBlockIOThrottle sets the I/O limits of the device of a hotplugged drive.
+func (q *qemu) setBlockIOThrottle(drive *config.BlockDrive, throttle config.BlockIOThrottle) error {
+	span, _ := q.trace("setBlockIOThrottle")
+	defer span.Finish()
+
+	if q.config.BlockDeviceDriver == config.Nvdimm || drive.Pmem {
+		return fmt.Errorf("I/O throttling not supported by NVDIMM drive %s", drive.ID)
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	return q.qmpMonitorCh.qmp.ExecuteBlockSetIOThrottle(q.qmpMonitorCh.ctx, blockDriveDevID(drive),
+		throttle.ReadBps, throttle.WriteBps, throttle.ReadIOPS, throttle.WriteIOPS)
+}
+
+// setMemoryHostNodes binds the memory hotplugged from now on to the host
+// nodes, and moves the memory QEMU already allocated to them. The memory
+// backends created before keep their own policy for the pages they
+// allocate later.
+func (q *qemu) setMemoryHostNodes(nodes []int) error {
+	q.config.memoryHostNodes = nodes
+
+	pid := q.getPids()[0]
+	if pid <= 0 {
+		return nil
+	}
+
+	return migrateProcessMemory(pid, nodes)
+}
@@ -144,10 +144,17 @@ func (q *qemuAmd64) capabilities() types.Capabilities {
 		q.qemuMachine.Type == QemuQ35 ||
 		q.qemuMachine.Type == QemuVirt {
 		caps.SetBlockDeviceHotplugSupport()
+		caps.SetBlockIOThrottleSupport()
+		caps.SetCharDeviceHotplugSupport()
+		caps.SetFsHotplugSupport()
+		if q.nvdimmSupported() {
+			caps.SetNvdimmHotplugSupport()
+		}
 	}
 
 	caps.SetMultiQueueSupport()
 	caps.SetFsSharingSupport()
+	caps.SetVMPauseSupport()
 
 	return caps
 }
@@ -47,10 +47,12 @@ func TestQemuAmd64Capabilities(t *testing.T) {
 	amd64 = newTestQemu(assert, QemuQ35)
 	caps = amd64.capabilities()
 	assert.True(caps.IsBlockDeviceHotplugSupported())
+	assert.True(caps.IsCharDeviceHotplugSupported())
 
 	amd64 = newTestQemu(assert, QemuMicrovm)
 	caps = amd64.capabilities()
 	assert.False(caps.IsBlockDeviceHotplugSupported())
+	assert.False(caps.IsCharDeviceHotplugSupported())
 }
 
 func TestQemuAmd64Bridges(t *testing.T) {
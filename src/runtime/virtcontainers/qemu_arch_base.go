@@ -264,11 +264,29 @@ func (q *qemuArchBase) kernelParameters(debug bool) []Param {
 func (q *qemuArchBase) capabilities() types.Capabilities {
 	var caps types.Capabilities
 	caps.SetBlockDeviceHotplugSupport()
+	caps.SetBlockIOThrottleSupport()
 	caps.SetMultiQueueSupport()
 	caps.SetFsSharingSupport()
+	caps.SetCharDeviceHotplugSupport()
+	caps.SetFsHotplugSupport()
+	caps.SetVMPauseSupport()
+	if q.nvdimmSupported() {
+		caps.SetNvdimmHotplugSupport()
+	}
 	return caps
 }
 
+// nvdimmSupported tells if the machine is created with NVDIMM support,
+// which is needed to hotplug NVDIMM devices.
+func (q *qemuArchBase) nvdimmSupported() bool {
+	for _, opt := range strings.Split(q.qemuMachine.Options, ",") {
+		if opt == qemuNvdimmOption {
+			return true
+		}
+	}
+	return false
+}
+
 func (q *qemuArchBase) bridges(number uint32) {
 	for i := uint32(0); i < number; i++ {
 		q.Bridges = append(q.Bridges, types.NewBridge(types.PCI, fmt.Sprintf("%s-bridge-%d", types.PCI, i), make(map[uint32]string), 0))
@@ -662,6 +680,8 @@ func (q *qemuArchBase) appendRNGDevice(devices []govmmQemu.Device, rngDev config
 		govmmQemu.RngDevice{
 			ID:       rngDev.ID,
 			Filename: rngDev.Filename,
+			MaxBytes: uint(rngDev.MaxBytes),
+			Period:   uint(rngDev.Period),
 		},
 	)
 
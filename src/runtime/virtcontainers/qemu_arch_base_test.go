@@ -118,6 +118,14 @@ func TestQemuArchBaseCapabilities(t *testing.T) {
 
 	c := qemuArchBase.capabilities()
 	assert.True(c.IsBlockDeviceHotplugSupported())
+	assert.True(c.IsFsHotplugSupported())
+	assert.True(c.IsBlockIOThrottleSupported())
+	assert.True(c.IsVMPauseSupported())
+	assert.False(c.IsNvdimmHotplugSupported())
+
+	qemuArchBase.qemuMachine.Options = "accel=kvm,nvdimm"
+	c = qemuArchBase.capabilities()
+	assert.True(c.IsNvdimmHotplugSupported())
 }
 
 func TestQemuArchBaseBridges(t *testing.T) {
@@ -455,6 +463,27 @@ func TestQemuArchBaseAppendVFIODeviceWithVendorDeviceID(t *testing.T) {
 	testQemuArchBaseAppend(t, vfDevice, expectedOut)
 }
 
+func TestQemuArchBaseAppendRNGDevice(t *testing.T) {
+	assert := assert.New(t)
+	qemuArchBase := newQemuArchBase()
+
+	devices, err := qemuArchBase.appendRNGDevice(nil, config.RNGDev{
+		ID:       "rng0",
+		Filename: "/dev/urandom",
+		MaxBytes: 1024,
+		Period:   1000,
+	})
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{
+		govmmQemu.RngDevice{
+			ID:       "rng0",
+			Filename: "/dev/urandom",
+			MaxBytes: 1024,
+			Period:   1000,
+		},
+	}, devices)
+}
+
 func TestQemuArchBaseAppendSCSIController(t *testing.T) {
 	var devices []govmmQemu.Device
 	assert := assert.New(t)
@@ -85,10 +85,17 @@ func (q *qemuPPC64le) capabilities() types.Capabilities {
 	// pseries machine type supports hotplugging drives
 	if q.qemuMachine.Type == QemuPseries {
 		caps.SetBlockDeviceHotplugSupport()
+		caps.SetBlockIOThrottleSupport()
 	}
 
 	caps.SetMultiQueueSupport()
 	caps.SetFsSharingSupport()
+	caps.SetCharDeviceHotplugSupport()
+	caps.SetFsHotplugSupport()
+	caps.SetVMPauseSupport()
+	if q.nvdimmSupported() {
+		caps.SetNvdimmHotplugSupport()
+	}
 
 	return caps
 }
@@ -197,6 +197,8 @@ func (q *qemuS390x) appendRNGDevice(devices []govmmQemu.Device, rngDev config.RN
 		govmmQemu.RngDevice{
 			ID:       rngDev.ID,
 			Filename: rngDev.Filename,
+			MaxBytes: uint(rngDev.MaxBytes),
+			Period:   uint(rngDev.Period),
 			DevNo:    devno,
 		},
 	)
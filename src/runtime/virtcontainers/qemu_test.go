@@ -6,12 +6,16 @@
 package virtcontainers
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	govmmQemu "github.com/intel/govmm/qemu"
@@ -103,6 +107,15 @@ func TestQemuCreateSandbox(t *testing.T) {
 	assert.NoError(err)
 	assert.NoError(os.RemoveAll(parentDir))
 	assert.Exactly(qemuConfig, q.config)
+	assert.Empty(q.qemuConfig.Memory.HostNodes)
+
+	// The memory is bound to the host nodes by the memory backend.
+	sandbox.config.HypervisorConfig.memoryHostNodes = []int{1}
+	assert.NoError(os.MkdirAll(parentDir, DirMode))
+	err = q.createSandbox(context.Background(), sandbox.id, NetworkNamespace{}, &sandbox.config.HypervisorConfig, false)
+	assert.NoError(err)
+	assert.NoError(os.RemoveAll(parentDir))
+	assert.Equal([]int{1}, q.qemuConfig.Memory.HostNodes)
 }
 
 func TestQemuCreateSandboxMissingParentDirFail(t *testing.T) {
@@ -403,6 +416,95 @@ func TestQMPSetupShutdown(t *testing.T) {
 	assert.Nil(err)
 }
 
+// fakeQMPServer answers the QMP commands sent on path, and records them.
+func fakeQMPServer(t *testing.T, path string) (*[]map[string]interface{}, *sync.Mutex) {
+	l, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+
+	var lock sync.Mutex
+	var commands []map[string]interface{}
+
+	go func() {
+		defer l.Close()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				fmt.Fprintln(conn, `{"QMP": {"version": {"qemu": {"micro": 0, "minor": 0, "major": 5}, "package": ""}, "capabilities": []}}`)
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd map[string]interface{}
+					if json.Unmarshal(scanner.Bytes(), &cmd) != nil {
+						return
+					}
+
+					lock.Lock()
+					commands = append(commands, cmd)
+					lock.Unlock()
+
+					fmt.Fprintln(conn, `{"event": "RESET", "timestamp": {"seconds": 0, "microseconds": 0}}`)
+					fmt.Fprintln(conn, `{"return": {}}`)
+				}
+			}(conn)
+		}
+	}()
+
+	return &commands, &lock
+}
+
+func TestQemuSetBlockIOThrottle(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "qmp")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "qmp.sock")
+	commands, lock := fakeQMPServer(t, path)
+
+	q := &qemu{
+		ctx:    context.Background(),
+		config: newQemuConfig(),
+	}
+	q.qmpMonitorCh = qmpChannel{
+		ctx:  context.Background(),
+		path: path,
+	}
+	defer q.qmpShutdown()
+
+	// The command runs over the QMP connection of the hypervisor.
+	assert.NoError(q.qmpSetup())
+	qmp := q.qmpMonitorCh.qmp
+
+	drive := &config.BlockDrive{ID: "drive0"}
+	err = q.setBlockIOThrottle(drive, config.BlockIOThrottle{ReadBps: 1024, WriteIOPS: 100})
+	assert.NoError(err)
+	assert.Equal(qmp, q.qmpMonitorCh.qmp)
+
+	lock.Lock()
+	var args map[string]interface{}
+	for _, cmd := range *commands {
+		if cmd["execute"] == "block_set_io_throttle" {
+			args = cmd["arguments"].(map[string]interface{})
+		}
+	}
+	lock.Unlock()
+
+	assert.Equal("virtio-drive0", args["id"])
+	assert.Equal(float64(1024), args["bps_rd"])
+	assert.Equal(float64(0), args["bps_wr"])
+	assert.Equal(float64(100), args["iops_wr"])
+
+	drive.Pmem = true
+	err = q.setBlockIOThrottle(drive, config.BlockIOThrottle{ReadBps: 1024})
+	assert.Error(err)
+}
+
 func TestQemuCleanup(t *testing.T) {
 	assert := assert.New(t)
 
@@ -595,3 +697,30 @@ func TestQemuGetpids(t *testing.T) {
 	assert.True(pids[0] == 100)
 	assert.True(pids[1] == 200)
 }
+
+func TestFindPCIDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	devices := []govmmQemu.PCIDeviceInfo{
+		{Slot: 1, QdevID: "vfio-root"},
+		{
+			Slot:   2,
+			QdevID: "rp0",
+			PCIBridge: &govmmQemu.PCIBridgeInfo{
+				Devices: []govmmQemu.PCIDeviceInfo{
+					{Slot: 0, QdevID: "vfio-root-port"},
+				},
+			},
+		},
+	}
+
+	path, err := types.PciPathFromSlots(findPCIDevice(devices, "vfio-root")...)
+	assert.NoError(err)
+	assert.Equal("01", path.String())
+
+	path, err = types.PciPathFromSlots(findPCIDevice(devices, "vfio-root-port")...)
+	assert.NoError(err)
+	assert.Equal("02/00", path.String())
+
+	assert.Nil(findPCIDevice(devices, "none"))
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultReseedSource is used to reseed the guest random number
+	// generator when no entropy source is configured.
+	defaultReseedSource = "/dev/urandom"
+
+	// reseedDataSize is the number of bytes sent to the guest on each
+	// reseed.
+	reseedDataSize = 512
+)
+
+// RNGRateLimitSupported returns true if the hypervisor can rate limit
+// the entropy provided to the guest.
+func RNGRateLimitSupported(hType HypervisorType) bool {
+	switch hType {
+	case QemuHypervisor, FirecrackerHypervisor:
+		return true
+	}
+
+	return false
+}
+
+// readReseedData reads the data used to reseed the guest random number
+// generator from source.
+func readReseedData(source string) ([]byte, error) {
+	if source == "" {
+		source = defaultReseedSource
+	}
+
+	f, err := os.OpenFile(source, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, reseedDataSize)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// rngReseeder periodically reseeds the guest random number generator
+// through the agent.
+type rngReseeder struct {
+	sync.Mutex
+	agent    agent
+	source   string
+	interval time.Duration
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+	logger   *logrus.Entry
+}
+
+func newRNGReseeder(a agent, source string, interval time.Duration, logger *logrus.Entry) *rngReseeder {
+	return &rngReseeder{
+		agent:    a,
+		source:   source,
+		interval: interval,
+		logger:   logger.WithField("subsystem", "rng-reseeder"),
+	}
+}
+
+func (r *rngReseeder) reseed() error {
+	data, err := readReseedData(r.source)
+	if err != nil {
+		return err
+	}
+
+	return r.agent.reseedRNG(data)
+}
+
+func (r *rngReseeder) start() {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.stopCh != nil {
+		return
+	}
+
+	r.stopCh = make(chan struct{})
+	r.wg.Add(1)
+	go func(stopCh chan struct{}) {
+		defer r.wg.Done()
+
+		tick := time.NewTicker(r.interval)
+		defer tick.Stop()
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-tick.C:
+				if err := r.reseed(); err != nil {
+					r.logger.WithError(err).Warn("failed to reseed guest random number generator")
+				}
+			}
+		}
+	}(r.stopCh)
+}
+
+func (r *rngReseeder) stop() {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.stopCh == nil {
+		return
+	}
+
+	close(r.stopCh)
+	r.wg.Wait()
+	r.stopCh = nil
+}
+
+func (r *rngReseeder) running() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.stopCh != nil
+}
+
+// startRNGReseeder starts reseeding the guest random number generator, when
+// an interval is configured.
+func (s *Sandbox) startRNGReseeder() {
+	interval := s.config.HypervisorConfig.EntropyReseedInterval
+	if interval == 0 {
+		return
+	}
+
+	if s.rngReseeder == nil {
+		s.rngReseeder = newRNGReseeder(s.agent, s.config.HypervisorConfig.EntropySource, time.Duration(interval)*time.Second, s.Logger())
+	}
+	s.rngReseeder.start()
+}
+
+// stopRNGReseeder stops reseeding the guest random number generator, e.g.
+// while the agent cannot answer.
+func (s *Sandbox) stopRNGReseeder() {
+	if s.rngReseeder != nil {
+		s.rngReseeder.stop()
+	}
+}
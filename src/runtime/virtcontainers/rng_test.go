@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reseedAgent struct {
+	noopAgent
+	reseeds int32
+}
+
+func (a *reseedAgent) reseedRNG(data []byte) error {
+	if len(data) == reseedDataSize {
+		atomic.AddInt32(&a.reseeds, 1)
+	}
+	return nil
+}
+
+func TestRNGRateLimitSupported(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(RNGRateLimitSupported(QemuHypervisor))
+	assert.True(RNGRateLimitSupported(FirecrackerHypervisor))
+	assert.False(RNGRateLimitSupported(ClhHypervisor))
+	assert.False(RNGRateLimitSupported(MockHypervisor))
+}
+
+func TestReadReseedData(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := readReseedData("")
+	assert.NoError(err)
+	assert.Len(data, reseedDataSize)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	short := filepath.Join(tmpdir, "short")
+	assert.NoError(ioutil.WriteFile(short, []byte("foo"), 0600))
+	_, err = readReseedData(short)
+	assert.Error(err)
+
+	_, err = readReseedData(filepath.Join(tmpdir, "missing"))
+	assert.Error(err)
+}
+
+func TestRNGReseeder(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &reseedAgent{}
+	r := newRNGReseeder(a, "/dev/urandom", 10*time.Millisecond, virtLog)
+
+	r.start()
+	// starting twice is a no-op
+	r.start()
+
+	assert.Eventually(func() bool {
+		return atomic.LoadInt32(&a.reseeds) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	r.stop()
+	reseeds := atomic.LoadInt32(&a.reseeds)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(reseeds, atomic.LoadInt32(&a.reseeds))
+
+	// stopping twice is a no-op
+	r.stop()
+}
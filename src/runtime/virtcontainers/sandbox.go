@@ -11,10 +11,12 @@ import (
 	"io"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containerd/cgroups"
 	"github.com/containernetworking/plugins/pkg/ns"
@@ -36,6 +38,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	vccgroups "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cgroups"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/store"
@@ -60,6 +63,7 @@ type SandboxStatus struct {
 	HypervisorConfig HypervisorConfig
 	Agent            AgentType
 	ContainersStatus []ContainerStatus
+	Monitor          MonitorStatus
 
 	// Annotations allow clients to store arbitrary values,
 	// for example to add additional status values required
@@ -123,6 +127,10 @@ type SandboxConfig struct {
 
 	DisableGuestSeccomp bool
 
+	// GuestSysctls are the non-namespaced sysctls applied once to the
+	// guest kernel when the sandbox starts.
+	GuestSysctls map[string]string
+
 	// Experimental features enabled
 	Experimental []exp.Feature
 
@@ -214,6 +222,18 @@ type Sandbox struct {
 
 	cgroupMgr *vccgroups.Manager
 
+	// charDevProxies are the proxies of the passed through host
+	// character devices, by device ID.
+	charDevProxies map[string]*charDeviceProxy
+
+	// virtiofsDaemons are the virtio-fs daemons of the host directories
+	// shared through their own virtio-fs device, by device ID.
+	virtiofsDaemons map[string]Virtiofsd
+
+	// rngReseeder periodically reseeds the guest random number
+	// generator, when configured.
+	rngReseeder *rngReseeder
+
 	ctx context.Context
 }
 
@@ -267,6 +287,34 @@ func (s *Sandbox) GetNetNs() string {
 	return s.networkNS.NetNsPath
 }
 
+// GetHypervisorPid returns the PID of the hypervisor running the sandbox VM.
+func (s *Sandbox) GetHypervisorPid() (int, error) {
+	pids := s.hypervisor.getPids()
+	if len(pids) == 0 || pids[0] <= 0 {
+		return -1, fmt.Errorf("Invalid hypervisor PID: %+v", pids)
+	}
+
+	return pids[0], nil
+}
+
+// GetHookAnnotations returns the kata specific annotations describing the
+// sandbox VM, which are passed to the OCI hooks through the container state.
+func (s *Sandbox) GetHookAnnotations() map[string]string {
+	hookAnnotations := map[string]string{
+		annotations.HookNetNSPathKey: s.networkNS.NetNsPath,
+		annotations.HookSharedDirKey: getSharePath(s.id),
+	}
+
+	// Only a VM reached through a plain vsock has a context ID to report.
+	if agentURL, err := s.agent.getAgentURL(); err == nil {
+		if u, err := url.Parse(agentURL); err == nil && u.Scheme == types.VSockScheme {
+			hookAnnotations[annotations.HookGuestCIDKey] = u.Hostname()
+		}
+	}
+
+	return hookAnnotations
+}
+
 // GetAllContainers returns all containers.
 func (s *Sandbox) GetAllContainers() []VCContainer {
 	ifa := make([]VCContainer, len(s.containers))
@@ -327,6 +375,11 @@ func (s *Sandbox) Status() SandboxStatus {
 		})
 	}
 
+	var monitorStatus MonitorStatus
+	if s.monitor != nil {
+		monitorStatus = s.monitor.getStatus()
+	}
+
 	return SandboxStatus{
 		ID:               s.id,
 		State:            s.state,
@@ -334,6 +387,7 @@ func (s *Sandbox) Status() SandboxStatus {
 		HypervisorConfig: s.config.HypervisorConfig,
 		Agent:            s.config.AgentType,
 		ContainersStatus: contStatusList,
+		Monitor:          monitorStatus,
 		Annotations:      s.config.Annotations,
 	}
 }
@@ -348,6 +402,11 @@ func (s *Sandbox) Monitor() (chan error, error) {
 	if s.monitor == nil {
 		s.monitor = newMonitor(s)
 	}
+	// A sandbox fetched again, like by a restarted shim, starts
+	// reseeding the guest and proxying the host char devices again
+	// once monitored.
+	s.startRNGReseeder()
+	s.startCharDeviceProxies()
 	s.Unlock()
 
 	return s.monitor.newWatcher()
@@ -559,6 +618,12 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		sandboxConfig.HypervisorConfig.SELinuxProcessLabel = spec.Process.SelinuxLabel
 	}
 
+	if sandboxConfig.HypervisorConfig.CPUPlacementPolicy == NUMACPUPlacement {
+		if sandboxConfig.HypervisorConfig.memoryHostNodes, err = memoryHostNodes(sandboxConfig.Containers); err != nil {
+			return nil, err
+		}
+	}
+
 	if useOldStore(ctx) {
 		vcStore, err := store.NewVCSandboxStore(ctx, s.id)
 		if err != nil {
@@ -579,7 +644,8 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		}
 		s.devManager = deviceManager.NewDeviceManager(sandboxConfig.HypervisorConfig.BlockDeviceDriver,
 			sandboxConfig.HypervisorConfig.EnableVhostUserStore,
-			sandboxConfig.HypervisorConfig.VhostUserStorePath, devices)
+			sandboxConfig.HypervisorConfig.VhostUserStorePath,
+			sandboxConfig.HypervisorConfig.PassthroughCharDevices, devices)
 
 		// Load sandbox state. The hypervisor.createSandbox call, may need to access statei.
 		state, err := s.store.LoadState()
@@ -593,7 +659,8 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 	} else {
 		s.devManager = deviceManager.NewDeviceManager(sandboxConfig.HypervisorConfig.BlockDeviceDriver,
 			sandboxConfig.HypervisorConfig.EnableVhostUserStore,
-			sandboxConfig.HypervisorConfig.VhostUserStorePath, nil)
+			sandboxConfig.HypervisorConfig.VhostUserStorePath,
+			sandboxConfig.HypervisorConfig.PassthroughCharDevices, nil)
 
 		// Ignore the error. Restore can fail for a new sandbox
 		if err := s.Restore(); err != nil {
@@ -967,7 +1034,7 @@ func (s *Sandbox) AddInterface(inf *vcTypes.Interface) (*vcTypes.Interface, erro
 	}
 
 	// Add network for vm
-	inf.PciAddr = endpoint.PciAddr()
+	inf.PciAddr = endpoint.PciPath().String()
 	return s.agent.updateInterface(inf)
 }
 
@@ -1013,6 +1080,8 @@ func (s *Sandbox) startVM() (err error) {
 
 	s.Logger().Info("Starting VM")
 
+	bootStart := time.Now()
+
 	if err := s.network.Run(s.networkNS.NetNsPath, func() error {
 		if s.factory != nil {
 			vm, err := s.factory.GetVM(ctx, VMConfig{
@@ -1070,14 +1139,32 @@ func (s *Sandbox) startVM() (err error) {
 
 	s.Logger().Info("Agent started in the sandbox")
 
+	s.startRNGReseeder()
+
+	s.emitEvent(&events.SandboxBooted{
+		SandboxId:  s.id,
+		BootTimeMs: uint64(time.Since(bootStart) / time.Millisecond),
+	})
+
 	return nil
 }
 
+// emitEvent sends a lifecycle event to the sink of the sandbox context,
+// if any.
+func (s *Sandbox) emitEvent(e events.Event) {
+	if sink := events.SinkFromContext(s.ctx); sink != nil {
+		sink.Send(e)
+	}
+}
+
 // stopVM: stop the sandbox's VM
 func (s *Sandbox) stopVM() error {
 	span, _ := s.trace("stopVM")
 	defer span.Finish()
 
+	s.stopRNGReseeder()
+	s.rngReseeder = nil
+
 	s.Logger().Info("Stopping sandbox in the VM")
 	if err := s.agent.stopSandbox(s); err != nil {
 		s.Logger().WithError(err).WithField("sandboxid", s.id).Warning("Agent did not stop sandbox")
@@ -1129,10 +1216,31 @@ func (s *Sandbox) fetchContainers() error {
 	return nil
 }
 
+// checkGuestSysctls checks that the non-namespaced sysctls of a container
+// created in a running sandbox were already applied to the guest kernel,
+// which is only configured when the sandbox starts.
+func (s *Sandbox) checkGuestSysctls(contConfig *ContainerConfig) error {
+	for key, value := range contConfig.GuestSysctls {
+		v, ok := s.config.GuestSysctls[key]
+		if !ok {
+			return fmt.Errorf("Non-namespaced sysctl %q was not applied to the guest kernel when the sandbox started", key)
+		}
+		if v != value {
+			return fmt.Errorf("Non-namespaced sysctl %q was set to %q on the guest kernel, not %q", key, v, value)
+		}
+	}
+
+	return nil
+}
+
 // CreateContainer creates a new container in the sandbox
 // This should be called only when the sandbox is already created.
 // It will add new container config to sandbox.config.Containers
 func (s *Sandbox) CreateContainer(contConfig ContainerConfig) (VCContainer, error) {
+	if err := s.checkGuestSysctls(&contConfig); err != nil {
+		return nil, err
+	}
+
 	// Create the container.
 	c, err := newContainer(s, &contConfig)
 	if err != nil {
@@ -1461,6 +1569,98 @@ func (s *Sandbox) ResumeContainer(containerID string) error {
 	return nil
 }
 
+// Pause pauses the VM of the sandbox, its vCPUs using no more CPU time on
+// the host until it is resumed. The containers are left in their state.
+func (s *Sandbox) Pause() error {
+	span, _ := s.trace("pause")
+	defer span.Finish()
+
+	if err := s.state.ValidTransition(s.state.State, types.StatePaused); err != nil {
+		return err
+	}
+
+	caps := s.hypervisor.capabilities()
+	if !caps.IsVMPauseSupported() {
+		return fmt.Errorf("Pausing the VM is not supported by the hypervisor")
+	}
+
+	// The agent does not answer while the VM is paused
+	if s.monitor != nil {
+		s.monitor.pause()
+	}
+	s.stopRNGReseeder()
+
+	if err := s.hypervisor.pauseSandbox(); err != nil {
+		if s.monitor != nil {
+			s.monitor.resume()
+		}
+		s.startRNGReseeder()
+		return err
+	}
+
+	state := s.state.State
+	if err := s.setSandboxState(types.StatePaused); err != nil {
+		s.undoPause(state)
+		return err
+	}
+
+	if err := s.storeSandbox(); err != nil {
+		s.undoPause(state)
+		return err
+	}
+
+	s.Logger().Info("Sandbox is paused")
+
+	return nil
+}
+
+// undoPause resumes the VM of a sandbox which could not be recorded as
+// paused, and sets its state back.
+func (s *Sandbox) undoPause(state types.StateString) {
+	if err := s.hypervisor.resumeSandbox(); err != nil {
+		s.Logger().WithError(err).Error("failed to resume sandbox after pause failure")
+	}
+
+	s.state.State = state
+	if s.monitor != nil {
+		s.monitor.resume()
+	}
+	s.startRNGReseeder()
+}
+
+// Resume resumes the VM of a paused sandbox, and sets the guest time, which
+// stood still while the VM was paused.
+func (s *Sandbox) Resume() error {
+	span, _ := s.trace("resume")
+	defer span.Finish()
+
+	if err := s.state.ValidTransition(s.state.State, types.StateRunning); err != nil {
+		return err
+	}
+
+	if err := s.hypervisor.resumeSandbox(); err != nil {
+		return err
+	}
+
+	if err := s.setSandboxState(types.StateRunning); err != nil {
+		return err
+	}
+
+	if s.monitor != nil {
+		s.monitor.resume()
+	}
+	s.startRNGReseeder()
+
+	now := time.Now()
+	if err := s.agent.setGuestDateTime(now); err != nil {
+		s.Logger().WithError(err).WithField("time", now).Warn("failed to set guest time")
+	}
+
+	s.Logger().Info("Sandbox is resumed")
+
+	return s.storeSandbox()
+}
+
 // createContainers registers all containers to the proxy, create the
 // containers in the guest and starts one shim per container.
 func (s *Sandbox) createContainers() error {
@@ -1548,6 +1748,13 @@ func (s *Sandbox) Stop(force bool) error {
 		return err
 	}
 
+	// The containers are stopped through the agent
+	if s.state.State == types.StatePaused {
+		if err := s.Resume(); err != nil && !force {
+			return err
+		}
+	}
+
 	for _, c := range s.containers {
 		if err := c.stop(force); err != nil {
 			return err
@@ -1638,10 +1845,16 @@ func (s *Sandbox) unsetSandboxBlockIndex(index int) error {
 
 // HotplugAddDevice is used for add a device to sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType) (err error) {
 	span, _ := s.trace("HotplugAddDevice")
 	defer span.Finish()
 
+	defer func() {
+		if err == nil {
+			s.emitHotplugEvent(device, devType, events.HotplugAdd)
+		}
+	}()
+
 	if s.config.SandboxCgroupOnly {
 		// We are about to add a device to the hypervisor,
 		// the device cgroup MUST be updated since the hypervisor
@@ -1651,6 +1864,15 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 			s.Logger().WithError(err).WithField("device", hdev).
 				Warn("Could not add device to cgroup")
 		}
+
+		defer func() {
+			if err != nil {
+				if err := s.cgroupMgr.RemoveDevice(hdev); err != nil {
+					s.Logger().WithError(err).WithField("device", hdev).
+						Warn("Could not remove device from cgroup")
+				}
+			}
+		}()
 	}
 
 	switch devType {
@@ -1660,8 +1882,9 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
 		}
 
-		// adding a group of VFIO devices
-		for _, dev := range vfioDevices {
+		// adding a group of VFIO devices, the group being plugged as a
+		// whole or not at all
+		for i, dev := range vfioDevices {
 			if _, err := s.hypervisor.hotplugAddDevice(dev, vfioDev); err != nil {
 				s.Logger().
 					WithFields(logrus.Fields{
@@ -1669,6 +1892,13 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 						"vfio-device-ID":  dev.ID,
 						"vfio-device-BDF": dev.BDF,
 					}).WithError(err).Error("failed to hotplug VFIO device")
+
+				for j := i - 1; j >= 0; j-- {
+					if _, rollbackErr := s.hypervisor.hotplugRemoveDevice(vfioDevices[j], vfioDev); rollbackErr != nil {
+						s.Logger().WithField("vfio-device-ID", vfioDevices[j].ID).
+							WithError(rollbackErr).Error("failed to roll back VFIO device hotplug")
+					}
+				}
 				return err
 			}
 		}
@@ -1687,6 +1917,18 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 		}
 		_, err := s.hypervisor.hotplugAddDevice(vhostUserBlkDevice.VhostUserDeviceAttrs, vhostuserDev)
 		return err
+	case config.DeviceChar:
+		charDevice, ok := device.(*drivers.CharDevice)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		return s.attachCharDevice(charDevice)
+	case config.VhostUserFS:
+		vhostUserFSDevice, ok := device.(*drivers.VhostUserFSDevice)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		return s.attachVirtioFSShare(vhostUserFSDevice)
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -1694,10 +1936,28 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 	return nil
 }
 
+func (s *Sandbox) emitHotplugEvent(device api.Device, devType config.DeviceType, action string) {
+	// Generic devices are not hotplugged to the VM.
+	if devType == config.DeviceGeneric {
+		return
+	}
+
+	s.emitEvent(&events.DeviceHotplug{
+		SandboxId:  s.id,
+		DeviceId:   device.DeviceID(),
+		DeviceType: string(devType),
+		Action:     action,
+	})
+}
+
 // HotplugRemoveDevice is used for removing a device from sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugRemoveDevice(device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugRemoveDevice(device api.Device, devType config.DeviceType) (err error) {
 	defer func() {
+		if err == nil {
+			s.emitHotplugEvent(device, devType, events.HotplugRemove)
+		}
+
 		if s.config.SandboxCgroupOnly {
 			// Remove device from cgroup, the hypervisor
 			// should not have access to such device anymore.
@@ -1743,6 +2003,18 @@ func (s *Sandbox) HotplugRemoveDevice(device api.Device, devType config.DeviceTy
 		}
 		_, err := s.hypervisor.hotplugRemoveDevice(vhostUserDeviceAttrs, vhostuserDev)
 		return err
+	case config.DeviceChar:
+		charDevice, ok := device.(*drivers.CharDevice)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		return s.detachCharDevice(charDevice)
+	case config.VhostUserFS:
+		vhostUserFSDevice, ok := device.(*drivers.VhostUserFSDevice)
+		if !ok {
+			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
+		}
+		return s.detachVirtioFSShare(vhostUserFSDevice)
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -1824,6 +2096,12 @@ func (s *Sandbox) updateResources() error {
 	// Add default / rsvd memory for sandbox.
 	sandboxMemoryByte += int64(s.hypervisor.hypervisorConfig().MemorySize) << utils.MibToBytesShift
 
+	// The memory hotplugged below has to be bound to the host nodes of
+	// the containers.
+	if err := s.updateMemoryHostNodes(); err != nil {
+		return err
+	}
+
 	// Update VCPUs
 	s.Logger().WithField("cpus-sandbox", sandboxVCPUs).Debugf("Request to hypervisor to update vCPUs")
 	oldCPUs, newCPUs, err := s.hypervisor.resizeVCPUs(sandboxVCPUs)
@@ -1857,6 +2135,15 @@ func (s *Sandbox) updateResources() error {
 	if err := s.agent.onlineCPUMem(0, false); err != nil {
 		return err
 	}
+
+	if oldCPUs != newCPUs || updatedMemoryDevice.sizeMB != 0 {
+		s.emitEvent(&events.SandboxResized{
+			SandboxId: s.id,
+			Vcpus:     newCPUs,
+			MemoryMb:  newMemory,
+		})
+	}
+
 	return nil
 }
 
@@ -1903,11 +2190,20 @@ func (s *Sandbox) GetHypervisorType() string {
 }
 
 // cgroupsUpdate will:
-//  1) get the v1constraints cgroup associated with the stored cgroup path
-//  2) (re-)add hypervisor vCPU threads to the appropriate cgroup
-//  3) If we are managing sandbox cgroup, update the v1constraints cgroup size
+//  1. get the v1constraints cgroup associated with the stored cgroup path
+//  2. (re-)add hypervisor vCPU threads to the appropriate cgroup
+//  3. If we are managing sandbox cgroup, update the v1constraints cgroup size
+//  4. (re-)place the hypervisor threads on the host CPUs, if a CPU placement
+//     policy is configured
 func (s *Sandbox) cgroupsUpdate() error {
+	if err := s.cgroupsUpdateConstraints(); err != nil {
+		return err
+	}
+
+	return s.placeHypervisorThreads()
+}
 
+func (s *Sandbox) cgroupsUpdateConstraints() error {
 	// If Kata is configured for SandboxCgroupOnly, the VMM and its processes are already
 	// in the Kata sandbox cgroup (inherited). No need to move threads/processes, and we should
 	// rely on parent's cgroup CPU/memory values
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path"
@@ -22,8 +23,10 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
 	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/fs"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/events"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
@@ -523,6 +526,89 @@ func TestSandboxSetSandboxAndContainerState(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestSandboxPauseResume(t *testing.T) {
+	contID := "505"
+	contConfig := newTestContainerConfigNoop(contID)
+	hConfig := newHypervisorConfig(nil, nil)
+	assert := assert.New(t)
+
+	p, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NoopAgentType, NetworkConfig{}, []ContainerConfig{contConfig}, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	// Only a running sandbox can be paused
+	assert.Error(p.Pause())
+
+	assert.NoError(p.setSandboxState(types.StateRunning))
+	p.monitor = newMonitor(p)
+	p.config.HypervisorConfig.EntropyReseedInterval = 3600
+	p.startRNGReseeder()
+
+	assert.NoError(p.Pause())
+	assert.Equal(types.StatePaused, p.state.State)
+	assert.True(p.monitor.paused)
+	assert.False(p.rngReseeder.running())
+
+	p2, err := fetchSandbox(context.Background(), p.ID())
+	assert.NoError(err)
+	assert.Equal(types.StatePaused, p2.state.State)
+
+	assert.Error(p.Pause())
+
+	assert.NoError(p.Resume())
+	assert.Equal(types.StateRunning, p.state.State)
+	assert.False(p.monitor.paused)
+	assert.True(p.rngReseeder.running())
+
+	assert.Error(p.Resume())
+
+	// A paused sandbox is resumed to be stopped
+	assert.NoError(p.Pause())
+	assert.NoError(p.Stop(true))
+	assert.Equal(types.StateStopped, p.state.State)
+	assert.False(p.monitor.paused)
+
+	assert.NoError(p.Delete())
+}
+
+// failingStore fails to save the state of the sandbox.
+type failingStore struct {
+	persistapi.PersistDriver
+}
+
+func (f *failingStore) ToDisk(ss persistapi.SandboxState, cs map[string]persistapi.ContainerState) error {
+	return fmt.Errorf("failed to save sandbox %s", ss.State)
+}
+
+func TestSandboxPauseStoreFailure(t *testing.T) {
+	contID := "505"
+	contConfig := newTestContainerConfigNoop(contID)
+	hConfig := newHypervisorConfig(nil, nil)
+	assert := assert.New(t)
+
+	p, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NoopAgentType, NetworkConfig{}, []ContainerConfig{contConfig}, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	assert.NoError(p.setSandboxState(types.StateRunning))
+	p.monitor = newMonitor(p)
+	p.config.HypervisorConfig.EntropyReseedInterval = 3600
+	p.startRNGReseeder()
+
+	// A pause which is not saved is undone
+	store := p.newStore
+	p.newStore = &failingStore{store}
+	assert.Error(p.Pause())
+	assert.Equal(types.StateRunning, p.state.State)
+	assert.False(p.monitor.paused)
+	assert.True(p.rngReseeder.running())
+
+	p.newStore = store
+	assert.NoError(p.Pause())
+	assert.NoError(p.Stop(true))
+	assert.NoError(p.Delete())
+}
+
 func TestGetContainer(t *testing.T) {
 	containerIDs := []string{"abc", "123", "xyz", "rgb"}
 	containers := map[string]*Container{}
@@ -698,7 +784,7 @@ func TestSandboxAttachDevicesVFIO(t *testing.T) {
 		config.SysIOMMUPath = savedIOMMUPath
 	}()
 
-	dm := manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil)
+	dm := manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil, nil)
 	path := filepath.Join(vfioPath, testFDIOGroup)
 	deviceInfo := config.DeviceInfo{
 		HostPath:      path,
@@ -749,7 +835,7 @@ func TestSandboxAttachDevicesVhostUserBlk(t *testing.T) {
 	tmpDir, err := ioutil.TempDir("", "")
 	assert.Nil(t, err)
 	os.RemoveAll(tmpDir)
-	dm := manager.NewDeviceManager(manager.VirtioSCSI, true, tmpDir, nil)
+	dm := manager.NewDeviceManager(manager.VirtioSCSI, true, tmpDir, nil, nil)
 
 	vhostUserDevNodePath := filepath.Join(tmpDir, "/block/devices/")
 	vhostUserSockPath := filepath.Join(tmpDir, "/block/sockets/")
@@ -760,8 +846,9 @@ func TestSandboxAttachDevicesVhostUserBlk(t *testing.T) {
 	assert.Nil(t, err)
 	err = os.MkdirAll(vhostUserSockPath, dirMode)
 	assert.Nil(t, err)
-	_, err = os.Create(deviceSockPath)
+	l, err := net.Listen("unix", deviceSockPath)
 	assert.Nil(t, err)
+	defer l.Close()
 
 	// mknod requires root privilege, call mock function for non-root to
 	// get VhostUserBlk device type.
@@ -964,6 +1051,30 @@ func TestCreateContainer(t *testing.T) {
 	assert.Equal(t, len(s.config.Containers), 1, "Container config list length from sandbox structure should be 1")
 }
 
+func TestCreateContainerGuestSysctls(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		config: &SandboxConfig{
+			GuestSysctls: map[string]string{"vm.max_map_count": "262144"},
+		},
+	}
+
+	// The containers created in a running sandbox can only repeat the
+	// non-namespaced sysctls applied to the guest kernel
+	contConfig := newTestContainerConfigNoop("999")
+	assert.NoError(s.checkGuestSysctls(&contConfig))
+
+	contConfig.GuestSysctls = map[string]string{"vm.max_map_count": "262144"}
+	assert.NoError(s.checkGuestSysctls(&contConfig))
+
+	contConfig.GuestSysctls = map[string]string{"vm.max_map_count": "65530"}
+	assert.Error(s.checkGuestSysctls(&contConfig))
+
+	contConfig.GuestSysctls = map[string]string{"vm.overcommit_memory": "1"}
+	assert.Error(s.checkGuestSysctls(&contConfig))
+}
+
 func TestDeleteContainer(t *testing.T) {
 	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NoopAgentType, NetworkConfig{}, nil, nil)
 	assert.Nil(t, err, "VirtContainers should not allow empty sandboxes")
@@ -1271,7 +1382,7 @@ func TestAttachBlockDevice(t *testing.T) {
 		DevType:       "b",
 	}
 
-	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil)
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil, nil)
 	device, err := dm.NewDevice(deviceInfo)
 	assert.Nil(t, err)
 	_, ok := device.(*drivers.BlockDevice)
@@ -1316,6 +1427,55 @@ func TestAttachBlockDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+type testEventSink struct {
+	events []events.Event
+}
+
+func (s *testEventSink) Send(e events.Event) {
+	s.events = append(s.events, e)
+}
+
+func TestSandboxHotplugEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	sink := &testEventSink{}
+	sandbox := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{},
+		config:     &SandboxConfig{},
+		ctx:        events.WithSink(context.Background(), sink),
+	}
+
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil, nil)
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      "/dev/hda",
+		ContainerPath: "/dev/hda",
+		DevType:       "b",
+	})
+	assert.NoError(err)
+
+	assert.NoError(sandbox.HotplugAddDevice(device, config.DeviceBlock))
+	assert.NoError(sandbox.HotplugRemoveDevice(device, config.DeviceBlock))
+
+	// Generic devices are not hotplugged
+	assert.NoError(sandbox.HotplugAddDevice(device, config.DeviceGeneric))
+
+	assert.Equal([]events.Event{
+		&events.DeviceHotplug{
+			SandboxId:  testSandboxID,
+			DeviceId:   device.DeviceID(),
+			DeviceType: string(config.DeviceBlock),
+			Action:     events.HotplugAdd,
+		},
+		&events.DeviceHotplug{
+			SandboxId:  testSandboxID,
+			DeviceId:   device.DeviceID(),
+			DeviceType: string(config.DeviceBlock),
+			Action:     events.HotplugRemove,
+		},
+	}, sink.events)
+}
+
 func TestPreAddDevice(t *testing.T) {
 	hypervisor := &mockHypervisor{}
 
@@ -1327,7 +1487,7 @@ func TestPreAddDevice(t *testing.T) {
 		HypervisorConfig: hConfig,
 	}
 
-	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil)
+	dm := manager.NewDeviceManager(config.VirtioBlock, false, "", nil, nil)
 	// create a sandbox first
 	sandbox := &Sandbox{
 		id:         testSandboxID,
@@ -1399,6 +1559,56 @@ func TestGetNetNs(t *testing.T) {
 	assert.Equal(t, netNs, expected)
 }
 
+func TestGetHypervisorPid(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Sandbox{
+		hypervisor: &mockHypervisor{},
+	}
+
+	_, err := s.GetHypervisorPid()
+	assert.Error(err)
+
+	s.hypervisor = &mockHypervisor{mockPid: 1234}
+	pid, err := s.GetHypervisorPid()
+	assert.NoError(err)
+	assert.Equal(1234, pid)
+}
+
+func TestGetHookAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	s := Sandbox{
+		id: "test-sandbox",
+		networkNS: NetworkNamespace{
+			NetNsPath: "/foo/bar/ns/net",
+		},
+		agent: &kataAgent{
+			vmSocket: types.VSock{
+				ContextID: 3,
+				Port:      1024,
+			},
+		},
+	}
+
+	expected := map[string]string{
+		annotations.HookNetNSPathKey: "/foo/bar/ns/net",
+		annotations.HookGuestCIDKey:  "3",
+		annotations.HookSharedDirKey: getSharePath("test-sandbox"),
+	}
+	assert.Equal(expected, s.GetHookAnnotations())
+
+	// No context ID can be reported through a hybrid vsock.
+	s.agent = &kataAgent{
+		vmSocket: types.HybridVSock{
+			UdsPath: "/tmp/kata.hvsock",
+			Port:    1024,
+		},
+	}
+	delete(expected, annotations.HookGuestCIDKey)
+	assert.Equal(expected, s.GetHookAnnotations())
+}
+
 func TestStartNetworkMonitor(t *testing.T) {
 	if os.Getuid() != 0 {
 		t.Skip("Test disabled as requires root user")
@@ -113,3 +113,23 @@ func (b *Bridge) AddressFormatCCWForVirtServer(addr string) (string, error) {
 	}
 	return fmt.Sprintf("0.%x.%s", b.Addr, addr), nil
 }
+
+// PciPath returns the PCI path of the device plugged at addr, the slot in
+// hexadecimal, on the bridge. Bridges are plugged on the root bus.
+func (b *Bridge) PciPath(addr string) (PciPath, error) {
+	if b.Type != PCI && b.Type != PCIE {
+		return PciPath{}, fmt.Errorf("Expected a PCI bridge, got %s (%+v)", b.Type, b)
+	}
+
+	bridgeSlot, err := PciSlotFromInt(b.Addr)
+	if err != nil {
+		return PciPath{}, err
+	}
+
+	devSlot, err := PciSlotFromString(addr)
+	if err != nil {
+		return PciPath{}, err
+	}
+
+	return PciPathFromSlots(bridgeSlot, devSlot)
+}
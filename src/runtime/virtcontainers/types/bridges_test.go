@@ -58,3 +58,19 @@ func TestAddRemoveDeviceCCW(t *testing.T) {
 
 	testAddRemoveDevice(t, bridges[0])
 }
+
+func TestBridgePciPath(t *testing.T) {
+	assert := assert.New(t)
+
+	b := NewBridge(PCI, "rgb123", make(map[uint32]string), 2)
+	path, err := b.PciPath("1f")
+	assert.NoError(err)
+	assert.Equal("02/1f", path.String())
+
+	_, err = b.PciPath("20")
+	assert.Error(err)
+
+	b = NewBridge(CCW, "rgb123", make(map[uint32]string), 2)
+	_, err = b.PciPath("01")
+	assert.Error(err)
+}
@@ -10,6 +10,11 @@ const (
 	blockDeviceHotplugSupport
 	multiQueueSupport
 	fsSharingSupported
+	charDeviceHotplugSupport
+	nvdimmHotplugSupport
+	fsHotplugSupport
+	blockIOThrottleSupport
+	vmPauseSupport
 )
 
 // Capabilities describe a virtcontainers hypervisor capabilities
@@ -57,3 +62,58 @@ func (caps *Capabilities) IsFsSharingSupported() bool {
 func (caps *Capabilities) SetFsSharingSupport() {
 	caps.flags |= fsSharingSupported
 }
+
+// IsCharDeviceHotplugSupported tells if an hypervisor supports hotplugging
+// character devices as virtio-serial ports.
+func (caps *Capabilities) IsCharDeviceHotplugSupported() bool {
+	return caps.flags&charDeviceHotplugSupport != 0
+}
+
+// SetCharDeviceHotplugSupport sets the character device hotplugging capability to true.
+func (caps *Capabilities) SetCharDeviceHotplugSupport() {
+	caps.flags |= charDeviceHotplugSupport
+}
+
+// IsNvdimmHotplugSupported tells if an hypervisor supports hotplugging
+// NVDIMM devices.
+func (caps *Capabilities) IsNvdimmHotplugSupported() bool {
+	return caps.flags&nvdimmHotplugSupport != 0
+}
+
+// SetNvdimmHotplugSupport sets the NVDIMM device hotplugging capability to true.
+func (caps *Capabilities) SetNvdimmHotplugSupport() {
+	caps.flags |= nvdimmHotplugSupport
+}
+
+// IsFsHotplugSupported tells if an hypervisor supports hotplugging
+// vhost-user-fs devices.
+func (caps *Capabilities) IsFsHotplugSupported() bool {
+	return caps.flags&fsHotplugSupport != 0
+}
+
+// SetFsHotplugSupport sets the vhost-user-fs device hotplugging capability to true.
+func (caps *Capabilities) SetFsHotplugSupport() {
+	caps.flags |= fsHotplugSupport
+}
+
+// IsBlockIOThrottleSupported tells if an hypervisor supports limiting the
+// I/O rate of the hotplugged block drives.
+func (caps *Capabilities) IsBlockIOThrottleSupported() bool {
+	return caps.flags&blockIOThrottleSupport != 0
+}
+
+// SetBlockIOThrottleSupport sets the block drive I/O throttling capability to true.
+func (caps *Capabilities) SetBlockIOThrottleSupport() {
+	caps.flags |= blockIOThrottleSupport
+}
+
+// IsVMPauseSupported tells if an hypervisor supports pausing the VM, its
+// vCPUs then using no CPU time on the host.
+func (caps *Capabilities) IsVMPauseSupported() bool {
+	return caps.flags&vmPauseSupport != 0
+}
+
+// SetVMPauseSupport sets the VM pausing capability to true.
+func (caps *Capabilities) SetVMPauseSupport() {
+	caps.flags |= vmPauseSupport
+}
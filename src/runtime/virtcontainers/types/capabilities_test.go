@@ -34,3 +34,43 @@ func TestFsSharingCapability(t *testing.T) {
 	caps.SetFsSharingSupport()
 	assert.True(t, caps.IsFsSharingSupported())
 }
+
+func TestCharDeviceHotplugCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsCharDeviceHotplugSupported())
+	caps.SetCharDeviceHotplugSupport()
+	assert.True(t, caps.IsCharDeviceHotplugSupported())
+}
+
+func TestNvdimmHotplugCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsNvdimmHotplugSupported())
+	caps.SetNvdimmHotplugSupport()
+	assert.True(t, caps.IsNvdimmHotplugSupported())
+}
+
+func TestFsHotplugCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsFsHotplugSupported())
+	caps.SetFsHotplugSupport()
+	assert.True(t, caps.IsFsHotplugSupported())
+}
+
+func TestBlockIOThrottleCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsBlockIOThrottleSupported())
+	caps.SetBlockIOThrottleSupport()
+	assert.True(t, caps.IsBlockIOThrottleSupported())
+}
+
+func TestVMPauseCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsVMPauseSupported())
+	caps.SetVMPauseSupport()
+	assert.True(t, caps.IsVMPauseSupported())
+}
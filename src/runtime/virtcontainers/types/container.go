@@ -14,6 +14,10 @@ type ContainerState struct {
 	// File system of the rootfs incase it is block device
 	Fstype string `json:"fstype"`
 
+	// LayerDeviceIDs are the IDs of the pmem devices of the rootfs
+	// layers, in case the rootfs is made of layer images
+	LayerDeviceIDs []string `json:"layerDeviceIDs,omitempty"`
+
 	// CgroupPath is the cgroup hierarchy where sandbox's processes
 	// including the hypervisor are placed.
 	CgroupPath string `json:"cgroupPath,omitempty"`
@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pciSlotBits is the number of bits of a PCI slot number
+	pciSlotBits = 5
+	// maxPciSlot is the highest PCI slot number
+	maxPciSlot = (1 << pciSlotBits) - 1
+
+	// pciPathSeparator separates the slots of a PCI path
+	pciPathSeparator = "/"
+)
+
+// PciSlot is the slot of a device on a PCI bus. As devices are never
+// plugged as multifunction, the slot fully identifies the device on the
+// bus.
+type PciSlot struct {
+	slot uint8
+}
+
+// PciSlotFromString parses a PCI slot from its hexadecimal representation.
+func PciSlotFromString(s string) (PciSlot, error) {
+	v, err := strconv.ParseUint(s, 16, pciSlotBits)
+	if err != nil {
+		return PciSlot{}, fmt.Errorf("invalid PCI slot %q: %v", s, err)
+	}
+
+	return PciSlot{slot: uint8(v)}, nil
+}
+
+// PciSlotFromInt returns the PCI slot numbered v.
+func PciSlotFromInt(v int) (PciSlot, error) {
+	if v < 0 || v > maxPciSlot {
+		return PciSlot{}, fmt.Errorf("PCI slot 0x%x should be in range [0..0x%x]", v, maxPciSlot)
+	}
+
+	return PciSlot{slot: uint8(v)}, nil
+}
+
+func (slot PciSlot) String() string {
+	return fmt.Sprintf("%02x", slot.slot)
+}
+
+// PciPath is the path of a device from the root bus, as the list of the
+// slots leading to it: every slot but the last one is a bridge, and the
+// next slot is on the bus exposed by this bridge. It is formatted as the
+// hexadecimal slots separated by "/", e.g. "02/03" for the device at slot
+// 0x03 of the bridge at slot 0x02 of the root bus.
+//
+// The zero value is the nil path, used for devices whose location in the
+// guest is unknown.
+type PciPath struct {
+	slots []PciSlot
+}
+
+// PciPathFromSlots returns the PCI path made of slots.
+func PciPathFromSlots(slots ...PciSlot) (PciPath, error) {
+	if len(slots) == 0 {
+		return PciPath{}, fmt.Errorf("PCI path needs at least one slot")
+	}
+
+	return PciPath{slots: append([]PciSlot{}, slots...)}, nil
+}
+
+// PciPathFromString parses a PCI path, the empty string being the nil path.
+func PciPathFromString(s string) (PciPath, error) {
+	if s == "" {
+		return PciPath{}, nil
+	}
+
+	var slots []PciSlot
+	for _, token := range strings.Split(s, pciPathSeparator) {
+		slot, err := PciSlotFromString(token)
+		if err != nil {
+			return PciPath{}, fmt.Errorf("invalid PCI path %q: %v", s, err)
+		}
+		slots = append(slots, slot)
+	}
+
+	return PciPath{slots: slots}, nil
+}
+
+// IsNil tells if the PCI path is the nil path.
+func (p PciPath) IsNil() bool {
+	return len(p.slots) == 0
+}
+
+// Slots returns the slots of the PCI path, from the root bus.
+func (p PciPath) Slots() []PciSlot {
+	return append([]PciSlot{}, p.slots...)
+}
+
+func (p PciPath) String() string {
+	tokens := make([]string, 0, len(p.slots))
+	for _, slot := range p.slots {
+		tokens = append(tokens, slot.String())
+	}
+
+	return strings.Join(tokens, pciPathSeparator)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p PciPath) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *PciPath) UnmarshalText(text []byte) error {
+	path, err := PciPathFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = path
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPciSlot(t *testing.T) {
+	assert := assert.New(t)
+
+	slot, err := PciSlotFromString("1f")
+	assert.NoError(err)
+	assert.Equal("1f", slot.String())
+
+	slot, err = PciSlotFromInt(3)
+	assert.NoError(err)
+	assert.Equal("03", slot.String())
+
+	for _, s := range []string{"", "20", "ff", "xy", "-1"} {
+		_, err = PciSlotFromString(s)
+		assert.Error(err, s)
+	}
+
+	for _, v := range []int{-1, 32} {
+		_, err = PciSlotFromInt(v)
+		assert.Error(err, v)
+	}
+}
+
+func TestPciPath(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := PciPathFromString("")
+	assert.NoError(err)
+	assert.True(path.IsNil())
+	assert.Equal("", path.String())
+
+	path, err = PciPathFromString("02/1/0a")
+	assert.NoError(err)
+	assert.False(path.IsNil())
+	assert.Equal("02/01/0a", path.String())
+	assert.Len(path.Slots(), 3)
+
+	for _, s := range []string{"/", "02/", "02//03", "02/20"} {
+		_, err = PciPathFromString(s)
+		assert.Error(err, s)
+	}
+
+	bridge, _ := PciSlotFromInt(2)
+	dev, _ := PciSlotFromInt(3)
+	path, err = PciPathFromSlots(bridge, dev)
+	assert.NoError(err)
+	assert.Equal("02/03", path.String())
+
+	_, err = PciPathFromSlots()
+	assert.Error(err)
+}
+
+func TestPciPathJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	type device struct {
+		Path PciPath
+	}
+
+	path, err := PciPathFromString("02/03")
+	assert.NoError(err)
+
+	data, err := json.Marshal(device{Path: path})
+	assert.NoError(err)
+	assert.Equal(`{"Path":"02/03"}`, string(data))
+
+	var d device
+	assert.NoError(json.Unmarshal(data, &d))
+	assert.Equal(path, d.Path)
+
+	assert.Error(json.Unmarshal([]byte(`{"Path":"02/xx"}`), &d))
+}
@@ -299,6 +299,11 @@ type Cmd struct {
 	WorkDir      string
 	Console      string
 	Capabilities *specs.LinuxCapabilities
+	Rlimits      []specs.POSIXRlimit
+
+	ApparmorProfile string
+	SelinuxLabel    string
+	OOMScoreAdj     *int
 
 	Interactive     bool
 	Detach          bool
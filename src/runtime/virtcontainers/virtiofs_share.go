@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/store"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
+)
+
+// virtioFSShareHotplugSupported tells if the host directories can be shared
+// with the guest through their own hotplugged virtio-fs device. The guest
+// memory is only shared with the vhost-user backends, and the guest kernel
+// is only known to support virtio-fs, when the sandbox shared directory is
+// itself shared with virtio-fs.
+func (s *Sandbox) virtioFSShareHotplugSupported() bool {
+	hConfig := s.config.HypervisorConfig
+	caps := s.hypervisor.capabilities()
+
+	return caps.IsFsHotplugSupported() &&
+		hConfig.SharedFS == config.VirtioFS && hConfig.VirtioFSDaemon != ""
+}
+
+// attachVirtioFSShare starts the virtio-fs daemon sharing the host
+// directory of device with the guest, and hotplugs the vhost-user-fs device
+// it serves.
+func (s *Sandbox) attachVirtioFSShare(device *drivers.VhostUserFSDevice) (err error) {
+	attrs := &device.VhostUserDeviceAttrs
+
+	attrs.SocketPath, err = utils.BuildSocketPath(store.RunVMStoragePath(), s.id, attrs.DevID+".sock")
+	if err != nil {
+		return err
+	}
+
+	hConfig := s.config.HypervisorConfig
+	daemon := &virtiofsd{
+		path:       hConfig.VirtioFSDaemon,
+		socketPath: attrs.SocketPath,
+		cache:      attrs.Cache,
+		extraArgs:  hConfig.VirtioFSExtraArgs,
+		sourcePath: device.GetHostPath(),
+		debug:      hConfig.Debug,
+		ctx:        s.ctx,
+	}
+
+	if attrs.DaemonPid, err = daemon.Start(s.ctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			daemon.Stop()
+			attrs.DaemonPid = 0
+		}
+	}()
+
+	if _, err = s.hypervisor.hotplugAddDevice(attrs, vhostuserDev); err != nil {
+		return err
+	}
+
+	if s.virtiofsDaemons == nil {
+		s.virtiofsDaemons = make(map[string]Virtiofsd)
+	}
+	s.virtiofsDaemons[attrs.DevID] = daemon
+
+	return nil
+}
+
+// detachVirtioFSShare unplugs the vhost-user-fs device of a shared host
+// directory, and stops its virtio-fs daemon.
+func (s *Sandbox) detachVirtioFSShare(device *drivers.VhostUserFSDevice) error {
+	attrs := &device.VhostUserDeviceAttrs
+
+	if _, err := s.hypervisor.hotplugRemoveDevice(attrs, vhostuserDev); err != nil {
+		return err
+	}
+
+	daemon, ok := s.virtiofsDaemons[attrs.DevID]
+	if ok {
+		delete(s.virtiofsDaemons, attrs.DevID)
+	} else if attrs.DaemonPid != 0 {
+		// The daemon was started by another runtime process, like
+		// a shim which restarted since, and is found by its saved pid.
+		daemon = &virtiofsd{
+			PID:        attrs.DaemonPid,
+			socketPath: attrs.SocketPath,
+			ctx:        s.ctx,
+		}
+	} else {
+		return nil
+	}
+	attrs.DaemonPid = 0
+
+	return daemon.Stop()
+}
@@ -126,7 +126,9 @@ func (v *virtiofsd) Start(ctx context.Context) (int, error) {
 		v.wait = waitVirtiofsReady
 	}
 
-	return pid, socketFD.Close()
+	v.PID = cmd.Process.Pid
+
+	return v.PID, socketFD.Close()
 }
 
 func (v *virtiofsd) Stop() error {
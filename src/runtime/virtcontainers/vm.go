@@ -382,16 +382,9 @@ func (v *VM) OnlineCPUMemory() error {
 // and reseeds it.
 func (v *VM) ReseedRNG() error {
 	v.logger().Infof("reseed guest random number generator")
-	urandomDev := "/dev/urandom"
-	data := make([]byte, 512)
-	f, err := os.OpenFile(urandomDev, os.O_RDONLY, 0)
+	data, err := readReseedData(defaultReseedSource)
 	if err != nil {
-		v.logger().WithError(err).Warnf("fail to open %s", urandomDev)
-		return err
-	}
-	defer f.Close()
-	if _, err = f.Read(data); err != nil {
-		v.logger().WithError(err).Warnf("fail to read %s", urandomDev)
+		v.logger().WithError(err).Warnf("fail to read %s", defaultReseedSource)
 		return err
 	}
 